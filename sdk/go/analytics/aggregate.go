@@ -0,0 +1,525 @@
+package analytics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultTopEvents количество событий в TopEvents по умолчанию
+const DefaultTopEvents = 10
+
+// AggregatorConfig описывает, как извлекать метрики из AnalyticsEvent.Data.
+// Пустые поля заменяются значениями по умолчанию.
+type AggregatorConfig struct {
+	Days      int32          // Период в днях относительно Now (0 = все события)
+	Now       time.Time      // Момент расчета (по умолчанию time.Now())
+	Location  *time.Location // Часовой пояс для дат UserActivity и EventsToday (по умолчанию UTC)
+	TopEvents int            // Количество событий в TopEvents (по умолчанию DefaultTopEvents)
+
+	ResponseTimeKeys []string // Ключи Data со временем ответа в мс (response_time_ms, processing_time_ms, ...)
+	DomainKeys       []string // Ключи Data с ID домена (domain, domain_id)
+
+	// Типы событий воронки ConversionMetrics
+	SearchEvents   []string // поиск (search, search_query)
+	ResultEvents   []string // показ результатов (search_results, results_shown)
+	ActionEvents   []string // действия с результатами (action, click, purchase)
+	TemplateEvents []string // выполнение шаблонов (template_execute, execute_template)
+}
+
+// Aggregator вычисляет types.AnalyticsStats по потоку событий так же, как сервер.
+// Перцентили времени ответа оцениваются потоково (см. Quantile).
+//
+// Правила расчета:
+//   - TotalUsers — уникальные UserID за период, ActiveUsers — за последние 24 часа
+//   - TopEvents.Percentage — доля события в процентах (0-100)
+//   - событие считается ошибкой, если Data содержит непустой error,
+//     status равный error/failed или success = false
+//   - UserRetention — доля пользователей, активных более чем в один день
+//   - ThroughputRPM — событий со временем ответа в минуту за наблюдаемый интервал
+//
+// Пример использования:
+//
+//	agg := analytics.NewAggregator(analytics.AggregatorConfig{Days: 7})
+//	agg.AddAll(eventsResp.Events)
+//	stats := agg.Stats()
+type Aggregator struct {
+	config AggregatorConfig
+	since  time.Time
+
+	total       int32
+	today       int32
+	users       map[string]*userInfo
+	eventCounts map[string]int32
+	days        map[string]*dayInfo
+
+	searches, results, actions int32
+	templates, templateErrors  int32
+
+	requests, errors    int32
+	sumResponseTime     float64
+	p95, p99            *Quantile
+	firstSeen, lastSeen time.Time
+	domains             map[string]*domainInfo
+}
+
+type userInfo struct {
+	lastSeen time.Time
+	days     map[string]struct{}
+}
+
+type dayInfo struct {
+	users  map[string]struct{}
+	events int32
+}
+
+type domainInfo struct {
+	requests, errors       int32
+	timed                  int32
+	sumResponseTime        float64
+	cacheSamples, cacheHit int32
+	relevanceSamples       int32
+	sumRelevance           float64
+}
+
+// NewAggregator создает Aggregator
+func NewAggregator(config AggregatorConfig) *Aggregator {
+	if config.Now.IsZero() {
+		config.Now = time.Now()
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.TopEvents <= 0 {
+		config.TopEvents = DefaultTopEvents
+	}
+	if len(config.ResponseTimeKeys) == 0 {
+		config.ResponseTimeKeys = []string{"response_time_ms", "processing_time_ms", "duration_ms", "response_time"}
+	}
+	if len(config.DomainKeys) == 0 {
+		config.DomainKeys = []string{"domain", "domain_id"}
+	}
+	if len(config.SearchEvents) == 0 {
+		config.SearchEvents = []string{"search", "search_query"}
+	}
+	if len(config.ResultEvents) == 0 {
+		config.ResultEvents = []string{"search_results", "results_shown", "result_view"}
+	}
+	if len(config.ActionEvents) == 0 {
+		config.ActionEvents = []string{"action", "click", "purchase", "action_executed"}
+	}
+	if len(config.TemplateEvents) == 0 {
+		config.TemplateEvents = []string{"template_execute", "execute_template", "template_executed"}
+	}
+
+	a := &Aggregator{
+		config:      config,
+		users:       make(map[string]*userInfo),
+		eventCounts: make(map[string]int32),
+		days:        make(map[string]*dayInfo),
+		p95:         NewQuantile(0.95),
+		p99:         NewQuantile(0.99),
+		domains:     make(map[string]*domainInfo),
+	}
+	if config.Days > 0 {
+		a.since = config.Now.AddDate(0, 0, -int(config.Days))
+	}
+	return a
+}
+
+// Add учитывает событие. События вне периода Days пропускаются.
+func (a *Aggregator) Add(event types.AnalyticsEvent) {
+	ts, hasTime := ParseTimestamp(event.Timestamp)
+	if hasTime && !a.since.IsZero() && (ts.Before(a.since) || ts.After(a.config.Now)) {
+		return
+	}
+
+	a.total++
+	a.eventCounts[event.EventType]++
+
+	var day string
+	if hasTime {
+		local := ts.In(a.config.Location)
+		day = local.Format("2006-01-02")
+		if day == a.config.Now.In(a.config.Location).Format("2006-01-02") {
+			a.today++
+		}
+		d := a.days[day]
+		if d == nil {
+			d = &dayInfo{users: make(map[string]struct{})}
+			a.days[day] = d
+		}
+		d.events++
+		if event.UserID != "" {
+			d.users[event.UserID] = struct{}{}
+		}
+	}
+
+	if event.UserID != "" {
+		u := a.users[event.UserID]
+		if u == nil {
+			u = &userInfo{days: make(map[string]struct{})}
+			a.users[event.UserID] = u
+		}
+		if hasTime && ts.After(u.lastSeen) {
+			u.lastSeen = ts
+		}
+		if day != "" {
+			u.days[day] = struct{}{}
+		}
+	}
+
+	failed := isErrorEvent(event.Data)
+	switch {
+	case contains(a.config.SearchEvents, event.EventType):
+		a.searches++
+	case contains(a.config.ResultEvents, event.EventType):
+		a.results++
+	case contains(a.config.ActionEvents, event.EventType):
+		a.actions++
+	}
+	if contains(a.config.TemplateEvents, event.EventType) {
+		a.templates++
+		if failed {
+			a.templateErrors++
+		}
+	}
+
+	responseTime, timed := a.lookupNumber(event.Data, a.config.ResponseTimeKeys)
+	if timed {
+		a.requests++
+		a.sumResponseTime += responseTime
+		a.p95.Add(responseTime)
+		a.p99.Add(responseTime)
+		if failed {
+			a.errors++
+		}
+		if hasTime {
+			if a.firstSeen.IsZero() || ts.Before(a.firstSeen) {
+				a.firstSeen = ts
+			}
+			if ts.After(a.lastSeen) {
+				a.lastSeen = ts
+			}
+		}
+	}
+
+	if domain := a.lookupString(event.Data, a.config.DomainKeys); domain != "" {
+		d := a.domains[domain]
+		if d == nil {
+			d = &domainInfo{}
+			a.domains[domain] = d
+		}
+		d.requests++
+		if failed {
+			d.errors++
+		}
+		if timed {
+			d.timed++
+			d.sumResponseTime += responseTime
+		}
+		if hit, ok := event.Data["cache_hit"].(bool); ok {
+			d.cacheSamples++
+			if hit {
+				d.cacheHit++
+			}
+		}
+		if relevance, ok := toFloat(event.Data["relevance"]); ok {
+			d.relevanceSamples++
+			d.sumRelevance += relevance
+		}
+	}
+}
+
+// AddAll учитывает несколько событий
+func (a *Aggregator) AddAll(events []types.AnalyticsEvent) {
+	for _, event := range events {
+		a.Add(event)
+	}
+}
+
+// AddFrom читает события из r в формате JSON Lines или JSON массива
+// (например, из файла экспорта) и учитывает их. Возвращает количество событий.
+func (a *Aggregator) AddFrom(r io.Reader) (int, error) {
+	count := 0
+	err := DecodeEvents(r, func(event types.AnalyticsEvent) error {
+		a.Add(event)
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// Stats возвращает статистику в формате сервера
+func (a *Aggregator) Stats() *types.AnalyticsStats {
+	stats := &types.AnalyticsStats{
+		PeriodDays:  a.config.Days,
+		TotalEvents: a.total,
+		TotalUsers:  int32(len(a.users)),
+		EventsToday: a.today,
+	}
+
+	activeSince := a.config.Now.Add(-24 * time.Hour)
+	var retained int32
+	for _, u := range a.users {
+		if !u.lastSeen.IsZero() && !u.lastSeen.Before(activeSince) {
+			stats.ActiveUsers++
+		}
+		if len(u.days) > 1 {
+			retained++
+		}
+	}
+
+	stats.TopEvents = a.topEvents()
+	stats.UserActivity = a.userActivity()
+
+	stats.ConversionMetrics = &types.ConversionMetrics{
+		SearchToResult:  ratio(a.results, a.searches),
+		ResultToAction:  ratio(a.actions, a.results),
+		TemplateSuccess: ratio(a.templates-a.templateErrors, a.templates),
+		UserRetention:   ratio(retained, int32(len(a.users))),
+	}
+
+	if a.requests > 0 {
+		minutes := a.lastSeen.Sub(a.firstSeen).Minutes()
+		if minutes < 1 {
+			minutes = 1
+		}
+		stats.PerformanceMetrics = &types.PerformanceMetrics{
+			AvgResponseTimeMS: float32(a.sumResponseTime / float64(a.requests)),
+			P95ResponseTimeMS: float32(a.p95.Value()),
+			P99ResponseTimeMS: float32(a.p99.Value()),
+			ErrorRate:         ratio(a.errors, a.requests),
+			ThroughputRPM:     int32(math.Round(float64(a.requests) / minutes)),
+		}
+	}
+
+	if len(a.domains) > 0 {
+		stats.DomainBreakdown = make(map[string]*types.DomainMetrics, len(a.domains))
+		for id, d := range a.domains {
+			m := &types.DomainMetrics{
+				RequestsCount: d.requests,
+				ErrorCount:    d.errors,
+				SuccessRate:   ratio(d.requests-d.errors, d.requests),
+				CacheHitRate:  ratio(d.cacheHit, d.cacheSamples),
+			}
+			if d.timed > 0 {
+				m.AvgResponseTimeMS = float32(d.sumResponseTime / float64(d.timed))
+			}
+			if d.relevanceSamples > 0 {
+				m.RelevanceScore = float32(d.sumRelevance / float64(d.relevanceSamples))
+			}
+			stats.DomainBreakdown[id] = m
+		}
+	}
+	return stats
+}
+
+// Aggregate вычисляет статистику по срезу событий
+func Aggregate(events []types.AnalyticsEvent, config AggregatorConfig) *types.AnalyticsStats {
+	a := NewAggregator(config)
+	a.AddAll(events)
+	return a.Stats()
+}
+
+// topEvents возвращает самые частые события (при равенстве — по имени)
+func (a *Aggregator) topEvents() []types.TopEvent {
+	top := make([]types.TopEvent, 0, len(a.eventCounts))
+	for event, count := range a.eventCounts {
+		top = append(top, types.TopEvent{
+			Event:      event,
+			Count:      count,
+			Percentage: float32(math.Round(float64(count)/float64(a.total)*10000) / 100),
+		})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Event < top[j].Event
+	})
+	if len(top) > a.config.TopEvents {
+		top = top[:a.config.TopEvents]
+	}
+	return top
+}
+
+// userActivity возвращает активность по дням в хронологическом порядке
+func (a *Aggregator) userActivity() []types.UserActivityDay {
+	activity := make([]types.UserActivityDay, 0, len(a.days))
+	for day, d := range a.days {
+		activity = append(activity, types.UserActivityDay{
+			Date:        day,
+			ActiveUsers: int32(len(d.users)),
+			TotalEvents: d.events,
+		})
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Date < activity[j].Date })
+	return activity
+}
+
+func (a *Aggregator) lookupNumber(data map[string]interface{}, keys []string) (float64, bool) {
+	for _, key := range keys {
+		if v, ok := toFloat(data[key]); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func (a *Aggregator) lookupString(data map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if s, ok := data[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// isErrorEvent определяет, описывает ли событие ошибку
+func isErrorEvent(data map[string]interface{}) bool {
+	switch v := data["error"].(type) {
+	case string:
+		if v != "" {
+			return true
+		}
+	case bool:
+		if v {
+			return true
+		}
+	case map[string]interface{}:
+		return true
+	}
+	if status, ok := data["status"].(string); ok {
+		switch strings.ToLower(status) {
+		case "error", "failed", "failure":
+			return true
+		}
+	}
+	if success, ok := data["success"].(bool); ok && !success {
+		return true
+	}
+	return false
+}
+
+// ParseTimestamp разбирает AnalyticsEvent.Timestamp: RFC 3339 или Unix время
+// в секундах/миллисекундах
+func ParseTimestamp(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n), true
+		}
+		return time.Unix(n, 0), true
+	}
+	return time.Time{}, false
+}
+
+// DecodeEvents читает события из JSON массива или JSON Lines и вызывает fn для каждого
+func DecodeEvents(r io.Reader, fn func(types.AnalyticsEvent) error) error {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(br)
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("failed to read events array: %w", err)
+		}
+		for dec.More() {
+			var event types.AnalyticsEvent
+			if err := dec.Decode(&event); err != nil {
+				return fmt.Errorf("failed to decode event: %w", err)
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		var event types.AnalyticsEvent
+		if err := dec.Decode(&event); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
+
+// peekNonSpace возвращает первый непробельный байт, не извлекая его
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		br.ReadByte()
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func ratio(part, total int32) float32 {
+	if total == 0 {
+		return 0
+	}
+	return float32(part) / float32(total)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestQuantileAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	p95 := NewQuantile(0.95)
+	p99 := NewQuantile(0.99)
+
+	values := make([]float64, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		// Латентность с длинным хвостом
+		v := 50 + rng.ExpFloat64()*100
+		values = append(values, v)
+		p95.Add(v)
+		p99.Add(v)
+	}
+	sort.Float64s(values)
+
+	exact95 := values[int(0.95*float64(len(values)))-1]
+	exact99 := values[int(0.99*float64(len(values)))-1]
+	if math.Abs(p95.Value()-exact95)/exact95 > 0.03 {
+		t.Errorf("p95 estimate %.2f too far from exact %.2f", p95.Value(), exact95)
+	}
+	if math.Abs(p99.Value()-exact99)/exact99 > 0.05 {
+		t.Errorf("p99 estimate %.2f too far from exact %.2f", p99.Value(), exact99)
+	}
+	if p95.Count() != 20000 {
+		t.Errorf("Expected 20000 observations, got %d", p95.Count())
+	}
+}
+
+func TestQuantileSmallSample(t *testing.T) {
+	q := NewQuantile(0.5)
+	if q.Value() != 0 {
+		t.Errorf("Expected 0 for empty quantile, got %v", q.Value())
+	}
+	for _, v := range []float64{30, 10, 20} {
+		q.Add(v)
+	}
+	if q.Value() != 20 {
+		t.Errorf("Expected exact median 20, got %v", q.Value())
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	events := []types.AnalyticsEvent{
+		{EventType: "search", UserID: "u1", Timestamp: ts(time.Hour), Data: map[string]interface{}{"domain": "commerce", "response_time_ms": 100.0, "cache_hit": true, "relevance": 0.9}},
+		{EventType: "search", UserID: "u2", Timestamp: ts(2 * time.Hour), Data: map[string]interface{}{"domain": "commerce", "response_time_ms": 300.0, "cache_hit": false, "relevance": 0.7}},
+		{EventType: "search", UserID: "u1", Timestamp: ts(50 * time.Hour), Data: map[string]interface{}{"domain": "travel", "response_time_ms": 200.0, "status": "failed"}},
+		{EventType: "search_results", UserID: "u1", Timestamp: ts(time.Hour)},
+		{EventType: "search_results", UserID: "u2", Timestamp: ts(2 * time.Hour)},
+		{EventType: "purchase", UserID: "u1", Timestamp: ts(30 * time.Minute)},
+		{EventType: "template_execute", UserID: "u3", Timestamp: ts(72 * time.Hour), Data: map[string]interface{}{"processing_time_ms": "400"}},
+		{EventType: "template_execute", UserID: "u3", Timestamp: ts(72 * time.Hour), Data: map[string]interface{}{"error": "timeout", "processing_time_ms": 1000.0}},
+		// вне периода
+		{EventType: "search", UserID: "u4", Timestamp: ts(30 * 24 * time.Hour)},
+	}
+
+	stats := Aggregate(events, AggregatorConfig{Days: 7, Now: now})
+
+	if stats.PeriodDays != 7 || stats.TotalEvents != 8 || stats.TotalUsers != 3 {
+		t.Errorf("Unexpected totals: period=%d events=%d users=%d", stats.PeriodDays, stats.TotalEvents, stats.TotalUsers)
+	}
+	if stats.ActiveUsers != 2 {
+		t.Errorf("Expected 2 active users in last 24h, got %d", stats.ActiveUsers)
+	}
+	if stats.EventsToday != 5 {
+		t.Errorf("Expected 5 events today, got %d", stats.EventsToday)
+	}
+
+	if len(stats.TopEvents) == 0 || stats.TopEvents[0].Event != "search" || stats.TopEvents[0].Count != 3 || stats.TopEvents[0].Percentage != 37.5 {
+		t.Errorf("Unexpected top events: %+v", stats.TopEvents)
+	}
+
+	if len(stats.UserActivity) != 3 {
+		t.Fatalf("Expected 3 activity days, got %+v", stats.UserActivity)
+	}
+	last := stats.UserActivity[2]
+	if last.Date != "2024-03-10" || last.ActiveUsers != 2 || last.TotalEvents != 5 {
+		t.Errorf("Unexpected activity for today: %+v", last)
+	}
+
+	conv := stats.ConversionMetrics
+	if conv.SearchToResult != float32(2)/3 || conv.ResultToAction != 0.5 || conv.TemplateSuccess != 0.5 {
+		t.Errorf("Unexpected conversion metrics: %+v", conv)
+	}
+	if conv.UserRetention != float32(1)/3 {
+		t.Errorf("Expected retention 1/3 (u1 active on two days), got %v", conv.UserRetention)
+	}
+
+	perf := stats.PerformanceMetrics
+	if perf == nil {
+		t.Fatal("Expected performance metrics")
+	}
+	if perf.AvgResponseTimeMS != 400 || perf.ErrorRate != 0.4 {
+		t.Errorf("Unexpected performance metrics: %+v", perf)
+	}
+	if perf.P99ResponseTimeMS != 1000 {
+		t.Errorf("Expected p99 1000, got %v", perf.P99ResponseTimeMS)
+	}
+
+	commerce := stats.DomainBreakdown["commerce"]
+	if commerce == nil || commerce.RequestsCount != 2 || commerce.AvgResponseTimeMS != 200 || commerce.CacheHitRate != 0.5 || commerce.SuccessRate != 1 {
+		t.Errorf("Unexpected commerce metrics: %+v", commerce)
+	}
+	if math.Abs(float64(commerce.RelevanceScore)-0.8) > 1e-6 {
+		t.Errorf("Expected relevance 0.8, got %v", commerce.RelevanceScore)
+	}
+	if travel := stats.DomainBreakdown["travel"]; travel == nil || travel.ErrorCount != 1 || travel.SuccessRate != 0 {
+		t.Errorf("Unexpected travel metrics: %+v", travel)
+	}
+}
+
+func TestAggregatorAddFrom(t *testing.T) {
+	jsonl := `{"id":"1","event_type":"search","user_id":"u1","timestamp":"1710072000"}
+{"id":"2","event_type":"click","user_id":"u2","timestamp":"2024-03-10T12:00:00Z"}
+`
+	array := `[{"id":"1","event_type":"search"}, {"id":"2","event_type":"search"}]`
+
+	for name, input := range map[string]string{"jsonl": jsonl, "array": array} {
+		agg := NewAggregator(AggregatorConfig{})
+		n, err := agg.AddFrom(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: AddFrom failed: %v", name, err)
+		}
+		if n != 2 || agg.Stats().TotalEvents != 2 {
+			t.Errorf("%s: expected 2 events, got %d", name, n)
+		}
+	}
+}
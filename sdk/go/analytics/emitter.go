@@ -0,0 +1,559 @@
+// Package analytics содержит инструменты для работы с событиями аналитики:
+// асинхронную буферизованную отправку событий (Emitter) и расчет статистики
+// AnalyticsStats на стороне клиента (Aggregator).
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Значения EmitterConfig по умолчанию
+const (
+	DefaultQueueSize     = 1000
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = 5 * time.Second
+	DefaultWorkers       = 4
+)
+
+var (
+	// ErrClosed возвращается при отправке события в закрытый Emitter
+	ErrClosed = errors.New("analytics emitter is closed")
+	// ErrQueueFull возвращается, если очередь заполнена и событие отброшено
+	ErrQueueFull = errors.New("analytics queue is full")
+)
+
+// Sender отправляет одно событие на сервер. *client.Client реализует этот интерфейс.
+type Sender interface {
+	LogEvent(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error)
+}
+
+// SenderFunc позволяет использовать функцию как Sender
+type SenderFunc func(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error)
+
+// LogEvent вызывает f(ctx, req)
+func (f SenderFunc) LogEvent(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error) {
+	return f(ctx, req)
+}
+
+// OverflowPolicy определяет поведение при заполненной очереди
+type OverflowPolicy int
+
+const (
+	// OverflowBlock блокирует Emit до освобождения места или отмены context (back-pressure)
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest отбрасывает новое событие (Emit возвращает ErrQueueFull)
+	OverflowDropNewest
+	// OverflowDropOldest отбрасывает самое старое событие в очереди
+	OverflowDropOldest
+)
+
+// EmitterConfig конфигурация Emitter
+type EmitterConfig struct {
+	QueueSize     int            // Максимальный размер очереди (по умолчанию DefaultQueueSize)
+	BatchSize     int            // Размер очереди, при котором запускается отправка (по умолчанию DefaultBatchSize)
+	FlushInterval time.Duration  // Период отправки (по умолчанию DefaultFlushInterval)
+	Workers       int            // Количество параллельных запросов LogEvent (по умолчанию DefaultWorkers)
+	Overflow      OverflowPolicy // Поведение при заполненной очереди
+
+	// Повторы выполняет только Emitter: события отправляются с
+	// client.WithoutRetry, чтобы не умножать их на RetryConfig клиента.
+	MaxRetries        int           // Количество повторов отправки события (0 = без повторов)
+	InitialDelay      time.Duration // Начальная задержка между повторами (по умолчанию 100ms)
+	MaxDelay          time.Duration // Максимальная задержка между повторами (по умолчанию 5s)
+	BackoffMultiplier float64       // Множитель exponential backoff (по умолчанию 2)
+
+	// SpillDir директория для сохранения событий на диск. Если задана, события,
+	// которые не удалось отправить или поместить в очередь, сохраняются в нее
+	// и отправляются повторно при следующем создании Emitter. События,
+	// отклоненные сервером (ошибки валидации и авторизации), не сохраняются.
+	SpillDir string
+
+	// OnError вызывается для события, которое не удалось отправить после всех повторов
+	OnError func(req *types.LogEventRequest, err error)
+}
+
+// EmitterStats счетчики Emitter
+type EmitterStats struct {
+	Enqueued uint64 // событий принято в очередь
+	Sent     uint64 // событий успешно отправлено
+	Failed   uint64 // событий не отправлено после всех повторов
+	Dropped  uint64 // событий отброшено из-за переполнения или закрытия
+	Retries  uint64 // повторных попыток отправки
+	Spilled  uint64 // событий сохранено на диск
+	Restored uint64 // событий восстановлено с диска
+	Pending  int    // событий в очереди сейчас
+}
+
+// Emitter буферизует события аналитики и отправляет их в фоне.
+//
+// Пример использования:
+//
+//	emitter, err := analytics.NewEmitter(nexus, analytics.EmitterConfig{
+//		BatchSize:     50,
+//		FlushInterval: 2 * time.Second,
+//		MaxRetries:    3,
+//		Overflow:      analytics.OverflowDropOldest,
+//		SpillDir:      "/var/lib/app/analytics",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer emitter.Close(context.Background())
+//
+//	emitter.Emit(ctx, &types.LogEventRequest{EventType: "search", UserID: "user-1"})
+type Emitter struct {
+	sender Sender
+	config EmitterConfig
+	spill  *spillQueue
+
+	mu      sync.Mutex
+	queue   []*types.LogEventRequest
+	space   chan struct{} // закрывается при освобождении места в очереди
+	closed  bool
+	flushMu sync.Mutex // сериализует отправку
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	ctx     context.Context // context фоновой отправки, отменяется при Close
+	cancel  context.CancelFunc
+
+	enqueued atomic.Uint64
+	sent     atomic.Uint64
+	failed   atomic.Uint64
+	dropped  atomic.Uint64
+	retries  atomic.Uint64
+	spilled  atomic.Uint64
+	restored atomic.Uint64
+
+	// Восстановленные с диска события и число неотправленных событий
+	// каждого файла; файл удаляется, когда это число становится нулем
+	originMu sync.Mutex
+	origins  map[*types.LogEventRequest]string
+	unsent   map[string]int
+}
+
+// NewEmitter создает Emitter и запускает фоновую отправку.
+// Если задан SpillDir, ранее сохраненные на диск события ставятся в очередь.
+func NewEmitter(sender Sender, config EmitterConfig) (*Emitter, error) {
+	if sender == nil {
+		return nil, errors.New("analytics sender is nil")
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.BatchSize <= 0 || config.BatchSize > config.QueueSize {
+		config.BatchSize = minInt(DefaultBatchSize, config.QueueSize)
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = 100 * time.Millisecond
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 5 * time.Second
+	}
+	if config.BackoffMultiplier <= 1 {
+		config.BackoffMultiplier = 2
+	}
+
+	e := &Emitter{
+		sender:  sender,
+		config:  config,
+		space:   make(chan struct{}),
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+
+	if config.SpillDir != "" {
+		spill, err := newSpillQueue(config.SpillDir)
+		if err != nil {
+			return nil, err
+		}
+		e.spill = spill
+
+		files, err := spill.restore()
+		if err != nil {
+			return nil, err
+		}
+		e.origins = make(map[*types.LogEventRequest]string)
+		e.unsent = make(map[string]int)
+		for _, file := range files {
+			if len(file.events) == 0 {
+				spill.remove(file.path)
+				continue
+			}
+			for _, req := range file.events {
+				e.origins[req] = file.path
+			}
+			e.unsent[file.path] = len(file.events)
+			// Восстановленные события ставятся в очередь независимо от QueueSize
+			e.queue = append(e.queue, file.events...)
+			e.restored.Add(uint64(len(file.events)))
+		}
+	}
+
+	go e.loop()
+	if len(e.queue) > 0 {
+		e.requestFlush()
+	}
+	return e, nil
+}
+
+// Emit ставит событие в очередь. Поведение при заполненной очереди определяется
+// EmitterConfig.Overflow; при OverflowBlock ожидание прерывается отменой ctx.
+func (e *Emitter) Emit(ctx context.Context, req *types.LogEventRequest) error {
+	if req == nil {
+		return errors.New("analytics event is nil")
+	}
+
+	for {
+		e.mu.Lock()
+		if e.closed {
+			e.mu.Unlock()
+			return ErrClosed
+		}
+
+		if len(e.queue) < e.config.QueueSize {
+			e.queue = append(e.queue, req)
+			full := len(e.queue) >= e.config.BatchSize
+			e.mu.Unlock()
+
+			e.enqueued.Add(1)
+			if full {
+				e.requestFlush()
+			}
+			return nil
+		}
+
+		switch e.config.Overflow {
+		case OverflowDropOldest:
+			oldest := e.queue[0]
+			e.queue = append(e.queue[1:], req)
+			e.mu.Unlock()
+
+			e.enqueued.Add(1)
+			e.discard([]*types.LogEventRequest{oldest})
+			e.requestFlush()
+			return nil
+
+		case OverflowDropNewest:
+			e.mu.Unlock()
+			e.requestFlush()
+			if e.discard([]*types.LogEventRequest{req}) {
+				return nil
+			}
+			return ErrQueueFull
+
+		default:
+			space := e.space
+			e.mu.Unlock()
+			e.requestFlush()
+
+			select {
+			case <-space:
+			case <-ctx.Done():
+				return fmt.Errorf("%w: %v", ErrQueueFull, ctx.Err())
+			}
+		}
+	}
+}
+
+// Flush отправляет все события, находящиеся в очереди
+func (e *Emitter) Flush(ctx context.Context) error {
+	return e.flush(ctx)
+}
+
+// Close прекращает прием событий и отправляет оставшиеся. Если ctx истекает
+// раньше, неотправленные события сохраняются на диск (SpillDir) или отбрасываются.
+func (e *Emitter) Close(ctx context.Context) error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.notifySpace()
+	e.mu.Unlock()
+
+	// Ожидаем завершения фоновой отправки; при истечении ctx прерываем ее
+	close(e.stopCh)
+	select {
+	case <-e.doneCh:
+	case <-ctx.Done():
+		e.cancel()
+		<-e.doneCh
+	}
+	defer e.cancel()
+
+	err := e.flush(ctx)
+
+	// Оставшиеся события (если ctx истек до начала отправки)
+	e.mu.Lock()
+	rest := e.queue
+	e.queue = nil
+	e.mu.Unlock()
+	if len(rest) > 0 {
+		e.discard(rest)
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// Stats возвращает текущие счетчики
+func (e *Emitter) Stats() EmitterStats {
+	e.mu.Lock()
+	pending := len(e.queue)
+	e.mu.Unlock()
+
+	return EmitterStats{
+		Enqueued: e.enqueued.Load(),
+		Sent:     e.sent.Load(),
+		Failed:   e.failed.Load(),
+		Dropped:  e.dropped.Load(),
+		Retries:  e.retries.Load(),
+		Spilled:  e.spilled.Load(),
+		Restored: e.restored.Load(),
+		Pending:  pending,
+	}
+}
+
+// loop отправляет события по таймеру или при заполнении батча
+func (e *Emitter) loop() {
+	defer close(e.doneCh)
+
+	ticker := time.NewTicker(e.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+		case <-e.flushCh:
+		}
+		e.flush(e.ctx)
+	}
+}
+
+// requestFlush запрашивает внеочередную отправку
+func (e *Emitter) requestFlush() {
+	select {
+	case e.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// notifySpace будит ожидающие Emit. Вызывается под e.mu.
+func (e *Emitter) notifySpace() {
+	close(e.space)
+	e.space = make(chan struct{})
+}
+
+// flush забирает события из очереди батчами и отправляет их
+func (e *Emitter) flush(ctx context.Context) error {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	var failed int
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		e.mu.Lock()
+		n := minInt(len(e.queue), e.config.BatchSize)
+		if n == 0 {
+			e.mu.Unlock()
+			break
+		}
+		batch := make([]*types.LogEventRequest, n)
+		copy(batch, e.queue[:n])
+		e.queue = e.queue[n:]
+		e.notifySpace()
+		e.mu.Unlock()
+
+		failed += e.sendBatch(ctx, batch)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d analytics events", failed)
+	}
+	return nil
+}
+
+// sendBatch отправляет события параллельно (до Workers одновременно)
+// и возвращает количество неотправленных. Неотправленные события
+// сохраняются на диск, кроме отклоненных сервером.
+func (e *Emitter) sendBatch(ctx context.Context, batch []*types.LogEventRequest) int {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sent     []*types.LogEventRequest
+		failures []*types.LogEventRequest
+		rejected []*types.LogEventRequest
+	)
+	sem := make(chan struct{}, e.config.Workers)
+
+	for _, req := range batch {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(req *types.LogEventRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := e.send(ctx, req)
+			if err != nil && e.config.OnError != nil {
+				e.config.OnError(req, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				sent = append(sent, req)
+			case isRejected(err):
+				rejected = append(rejected, req)
+			default:
+				failures = append(failures, req)
+			}
+		}(req)
+	}
+	wg.Wait()
+
+	e.sent.Add(uint64(len(sent)))
+	e.settle(sent)
+	e.failed.Add(uint64(len(failures) + len(rejected)))
+	e.settle(rejected)
+	if len(failures) > 0 && e.spill != nil {
+		e.spillEvents(failures)
+	}
+	return len(failures) + len(rejected)
+}
+
+// send отправляет событие с повторами и exponential backoff
+func (e *Emitter) send(ctx context.Context, req *types.LogEventRequest) error {
+	var lastErr error
+	for attempt := 0; attempt <= e.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			e.retries.Add(1)
+			select {
+			case <-time.After(e.backoff(attempt - 1)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		_, err := e.sender.LogEvent(client.WithoutRetry(ctx), req)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !isRetryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// backoff вычисляет задержку перед повтором
+func (e *Emitter) backoff(attempt int) time.Duration {
+	delay := float64(e.config.InitialDelay) * math.Pow(e.config.BackoffMultiplier, float64(attempt))
+	if delay > float64(e.config.MaxDelay) {
+		delay = float64(e.config.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// discard сохраняет события на диск или учитывает их как отброшенные.
+// Возвращает true, если события сохранены.
+func (e *Emitter) discard(events []*types.LogEventRequest) bool {
+	if e.spill != nil && e.spillEvents(events) {
+		return true
+	}
+	e.dropped.Add(uint64(len(events)))
+	return false
+}
+
+// spillEvents сохраняет события на диск. Если запись не удалась,
+// восстановленные события остаются в исходных файлах.
+func (e *Emitter) spillEvents(events []*types.LogEventRequest) bool {
+	if err := e.spill.write(events); err != nil {
+		if e.config.OnError != nil {
+			for _, req := range events {
+				e.config.OnError(req, err)
+			}
+		}
+		return false
+	}
+	e.spilled.Add(uint64(len(events)))
+	e.settle(events)
+	return true
+}
+
+// settle отмечает восстановленные с диска события как обработанные и
+// удаляет файлы, все события которых обработаны
+func (e *Emitter) settle(events []*types.LogEventRequest) {
+	if e.spill == nil {
+		return
+	}
+	e.originMu.Lock()
+	defer e.originMu.Unlock()
+	for _, req := range events {
+		path, ok := e.origins[req]
+		if !ok {
+			continue
+		}
+		delete(e.origins, req)
+		e.unsent[path]--
+		if e.unsent[path] == 0 {
+			delete(e.unsent, path)
+			e.spill.remove(path)
+		}
+	}
+}
+
+// isRetryable определяет, имеет ли смысл повторять отправку
+func isRetryable(err error) bool {
+	return !isRejected(err) && !errors.Is(err, context.Canceled)
+}
+
+// isRejected определяет, отклонил ли сервер событие: ошибки валидации и
+// авторизации, а также прочие ответы 4xx, кроме 408 и 429. Такие события
+// не повторяются и не сохраняются на диск.
+func isRejected(err error) bool {
+	var detail *types.ErrorDetail
+	if errors.As(err, &detail) {
+		return detail.IsValidationError() || detail.IsAuthenticationError() || detail.IsAuthorizationError()
+	}
+	var httpErr *client.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 &&
+			httpErr.StatusCode != http.StatusRequestTimeout && httpErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
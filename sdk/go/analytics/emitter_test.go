@@ -0,0 +1,332 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// recordingSender запоминает отправленные события
+type recordingSender struct {
+	mu     sync.Mutex
+	events []string
+	fail   func(req *types.LogEventRequest) error
+	calls  atomic.Int32
+}
+
+func (s *recordingSender) LogEvent(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error) {
+	s.calls.Add(1)
+	if s.fail != nil {
+		if err := s.fail(req); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	s.events = append(s.events, req.EventType)
+	s.mu.Unlock()
+	return &types.LogEventResponse{EventID: "evt"}, nil
+}
+
+func (s *recordingSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEmitterFlushBySize(t *testing.T) {
+	sender := &recordingSender{}
+	emitter, err := NewEmitter(sender, EmitterConfig{BatchSize: 3, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+	defer emitter.Close(context.Background())
+
+	for i := 0; i < 2; i++ {
+		emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "search"})
+	}
+	time.Sleep(50 * time.Millisecond)
+	if sender.count() != 0 {
+		t.Errorf("Expected no events sent before batch is full, got %d", sender.count())
+	}
+
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "search"})
+	waitFor(t, func() bool { return sender.count() == 3 })
+
+	if stats := emitter.Stats(); stats.Enqueued != 3 || stats.Sent != 3 || stats.Pending != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestEmitterFlushByInterval(t *testing.T) {
+	sender := &recordingSender{}
+	emitter, err := NewEmitter(sender, EmitterConfig{BatchSize: 100, FlushInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+	defer emitter.Close(context.Background())
+
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "click"})
+	waitFor(t, func() bool { return sender.count() == 1 })
+}
+
+func TestEmitterRetry(t *testing.T) {
+	var attempts atomic.Int32
+	sender := &recordingSender{fail: func(req *types.LogEventRequest) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	}}
+	emitter, err := NewEmitter(sender, EmitterConfig{
+		FlushInterval: time.Hour,
+		MaxRetries:    3,
+		InitialDelay:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "purchase"})
+	if err := emitter.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	stats := emitter.Stats()
+	if stats.Sent != 1 || stats.Retries != 2 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestEmitterNoRetryOnValidationError(t *testing.T) {
+	var reported atomic.Int32
+	sender := &recordingSender{fail: func(req *types.LogEventRequest) error {
+		return &types.ErrorDetail{Code: "VALIDATION_FAILED", Type: "VALIDATION_ERROR", Message: "bad event"}
+	}}
+	emitter, err := NewEmitter(sender, EmitterConfig{
+		FlushInterval: time.Hour,
+		MaxRetries:    5,
+		InitialDelay:  time.Millisecond,
+		OnError:       func(req *types.LogEventRequest, err error) { reported.Add(1) },
+	})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "bad"})
+	if err := emitter.Close(context.Background()); err == nil {
+		t.Error("Expected Close to report failed events")
+	}
+	if sender.calls.Load() != 1 || reported.Load() != 1 {
+		t.Errorf("Expected 1 call and 1 reported error, got %d and %d", sender.calls.Load(), reported.Load())
+	}
+	if stats := emitter.Stats(); stats.Failed != 1 {
+		t.Errorf("Expected 1 failed event, got %+v", stats)
+	}
+}
+
+func TestEmitterOverflow(t *testing.T) {
+	block := make(chan struct{})
+	sender := SenderFunc(func(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error) {
+		select {
+		case <-block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &types.LogEventResponse{}, nil
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		emitter, _ := NewEmitter(sender, EmitterConfig{QueueSize: 2, BatchSize: 2, FlushInterval: time.Hour, Overflow: OverflowDropNewest})
+		emitter.mu.Lock() // заполняем очередь напрямую, не запуская отправку
+		emitter.queue = append(emitter.queue, &types.LogEventRequest{}, &types.LogEventRequest{})
+		emitter.mu.Unlock()
+
+		err := emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "x"})
+		if !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull, got %v", err)
+		}
+		if stats := emitter.Stats(); stats.Dropped != 1 {
+			t.Errorf("Expected 1 dropped event, got %+v", stats)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		emitter.Close(ctx)
+	})
+
+	t.Run("block", func(t *testing.T) {
+		emitter, _ := NewEmitter(sender, EmitterConfig{QueueSize: 1, BatchSize: 1, Workers: 1, FlushInterval: time.Hour})
+
+		// Первое событие забирается на отправку и блокирует sender,
+		// второе занимает очередь, третье ждет места
+		emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "1"})
+		waitFor(t, func() bool { return emitter.Stats().Pending == 0 })
+		emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "2"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := emitter.Emit(ctx, &types.LogEventRequest{EventType: "3"}); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull after context timeout, got %v", err)
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "4"}) }()
+		close(block)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Expected blocked Emit to succeed, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Blocked Emit was not released")
+		}
+
+		if err := emitter.Close(context.Background()); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+		if stats := emitter.Stats(); stats.Sent != 3 {
+			t.Errorf("Expected 3 sent events, got %+v", stats)
+		}
+	})
+}
+
+func TestEmitterSpillAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	down := SenderFunc(func(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error) {
+		return nil, errors.New("service unavailable")
+	})
+
+	emitter, err := NewEmitter(down, EmitterConfig{FlushInterval: time.Hour, SpillDir: dir})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "search", UserID: "user-1"})
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "purchase", UserID: "user-1"})
+	emitter.Close(context.Background())
+
+	if stats := emitter.Stats(); stats.Spilled != 2 {
+		t.Fatalf("Expected 2 spilled events, got %+v", stats)
+	}
+
+	sender := &recordingSender{}
+	restored, err := NewEmitter(sender, EmitterConfig{FlushInterval: time.Hour, SpillDir: dir})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+	waitFor(t, func() bool { return sender.count() == 2 })
+	restored.Close(context.Background())
+
+	if stats := restored.Stats(); stats.Restored != 2 || stats.Sent != 2 {
+		t.Errorf("Unexpected stats after restore: %+v", stats)
+	}
+
+	// Файлы удалены после восстановления
+	again, _ := NewEmitter(sender, EmitterConfig{FlushInterval: time.Hour, SpillDir: dir})
+	defer again.Close(context.Background())
+	if stats := again.Stats(); stats.Restored != 0 {
+		t.Errorf("Expected spill directory to be empty, restored %d", stats.Restored)
+	}
+}
+
+func TestEmitterKeepsSpillUntilSent(t *testing.T) {
+	dir := t.TempDir()
+	spill, _ := newSpillQueue(dir)
+	spill.write([]*types.LogEventRequest{{EventType: "search"}, {EventType: "purchase"}})
+
+	release := make(chan struct{})
+	sender := SenderFunc(func(ctx context.Context, req *types.LogEventRequest) (*types.LogEventResponse, error) {
+		<-release
+		return &types.LogEventResponse{}, nil
+	})
+	emitter, err := NewEmitter(sender, EmitterConfig{FlushInterval: time.Hour, SpillDir: dir})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+
+	// Пока события не отправлены, файл остается на диске
+	if files, _ := spill.restore(); len(files) != 1 || len(files[0].events) != 2 {
+		t.Errorf("Expected spill file to be kept until sent, got %+v", files)
+	}
+	close(release)
+	waitFor(t, func() bool { return emitter.Stats().Sent == 2 })
+	emitter.Close(context.Background())
+
+	if files, _ := spill.restore(); len(files) != 0 {
+		t.Errorf("Expected spill file to be removed after send, got %d files", len(files))
+	}
+}
+
+func TestEmitterDoesNotSpillRejected(t *testing.T) {
+	for name, rejection := range map[string]error{
+		"validation": &types.ErrorDetail{Code: "VALIDATION_FAILED", Type: "VALIDATION_ERROR"},
+		"auth":       &types.ErrorDetail{Code: "UNAUTHORIZED", Type: "AUTHENTICATION_ERROR"},
+		"http 400":   &client.HTTPError{StatusCode: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			sender := &recordingSender{fail: func(req *types.LogEventRequest) error { return rejection }}
+			emitter, err := NewEmitter(sender, EmitterConfig{FlushInterval: time.Hour, MaxRetries: 3, InitialDelay: time.Millisecond, SpillDir: dir})
+			if err != nil {
+				t.Fatalf("NewEmitter failed: %v", err)
+			}
+			emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "bad"})
+			emitter.Close(context.Background())
+
+			if stats := emitter.Stats(); stats.Failed != 1 || stats.Spilled != 0 || sender.calls.Load() != 1 {
+				t.Errorf("Expected rejected event to be dropped without retries, got %+v (%d calls)", stats, sender.calls.Load())
+			}
+			if files, _ := emitter.spill.restore(); len(files) != 0 {
+				t.Errorf("Expected no spill files, got %d", len(files))
+			}
+		})
+	}
+}
+
+func TestEmitterSingleRetryLayer(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	nexus := client.NewClient(client.Config{BaseURL: server.URL})
+	emitter, err := NewEmitter(nexus, EmitterConfig{FlushInterval: time.Hour, MaxRetries: 1, InitialDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+	emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "search"})
+	emitter.Close(context.Background())
+
+	// Один запрос и один повтор Emitter, без повторов RetryConfig клиента
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests.Load())
+	}
+}
+
+func TestEmitterClosed(t *testing.T) {
+	emitter, _ := NewEmitter(&recordingSender{}, EmitterConfig{})
+	emitter.Close(context.Background())
+
+	if err := emitter.Emit(context.Background(), &types.LogEventRequest{EventType: "x"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
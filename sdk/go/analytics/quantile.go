@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// Quantile оценивает перцентиль потока значений алгоритмом P²
+// (Jain & Chlamtac) без хранения самих значений: память O(1) независимо
+// от количества наблюдений. Для первых пяти значений результат точный.
+type Quantile struct {
+	p       float64
+	count   int
+	initial []float64
+
+	heights  [5]float64 // высоты маркеров
+	pos      [5]float64 // фактические позиции маркеров (1-based)
+	desired  [5]float64 // желаемые позиции маркеров
+	increase [5]float64 // приращения желаемых позиций
+}
+
+// NewQuantile создает оценщик перцентиля p (0 < p < 1), например 0.95 для p95
+func NewQuantile(p float64) *Quantile {
+	if p <= 0 || p >= 1 {
+		panic("analytics: quantile must be in (0, 1)")
+	}
+	return &Quantile{p: p, initial: make([]float64, 0, 5)}
+}
+
+// Add добавляет наблюдение
+func (q *Quantile) Add(x float64) {
+	q.count++
+	if q.count <= 5 {
+		q.initial = append(q.initial, x)
+		if q.count == 5 {
+			sort.Float64s(q.initial)
+			copy(q.heights[:], q.initial)
+			q.pos = [5]float64{1, 2, 3, 4, 5}
+			q.desired = [5]float64{1, 1 + 2*q.p, 1 + 4*q.p, 3 + 2*q.p, 5}
+			q.increase = [5]float64{0, q.p / 2, q.p, (1 + q.p) / 2, 1}
+		}
+		return
+	}
+
+	// Находим ячейку k, в которую попадает x, и корректируем крайние маркеры
+	var k int
+	switch {
+	case x < q.heights[0]:
+		q.heights[0] = x
+		k = 0
+	case x >= q.heights[4]:
+		q.heights[4] = x
+		k = 3
+	default:
+		for k = 0; k < 3; k++ {
+			if x < q.heights[k+1] {
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		q.pos[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.increase[i]
+	}
+
+	// Корректируем промежуточные маркеры
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.pos[i]
+		if (d >= 1 && q.pos[i+1]-q.pos[i] > 1) || (d <= -1 && q.pos[i-1]-q.pos[i] < -1) {
+			s := math.Copysign(1, d)
+			h := q.parabolic(i, s)
+			if q.heights[i-1] < h && h < q.heights[i+1] {
+				q.heights[i] = h
+			} else {
+				q.heights[i] = q.linear(i, s)
+			}
+			q.pos[i] += s
+		}
+	}
+}
+
+// Value возвращает текущую оценку перцентиля (0, если наблюдений нет)
+func (q *Quantile) Value() float64 {
+	if q.count == 0 {
+		return 0
+	}
+	if q.count <= 5 {
+		sorted := append([]float64(nil), q.initial...)
+		sort.Float64s(sorted)
+		idx := int(math.Ceil(q.p*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx]
+	}
+	return q.heights[2]
+}
+
+// Count возвращает количество наблюдений
+func (q *Quantile) Count() int {
+	return q.count
+}
+
+// parabolic вычисляет новую высоту маркера по формуле P²
+func (q *Quantile) parabolic(i int, s float64) float64 {
+	n, h := q.pos, q.heights
+	return h[i] + s/(n[i+1]-n[i-1])*((n[i]-n[i-1]+s)*(h[i+1]-h[i])/(n[i+1]-n[i])+
+		(n[i+1]-n[i]-s)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+}
+
+// linear вычисляет высоту маркера линейной интерполяцией
+func (q *Quantile) linear(i int, s float64) float64 {
+	j := i + int(s)
+	return q.heights[i] + s*(q.heights[j]-q.heights[i])/(q.pos[j]-q.pos[i])
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// spillFileExt расширение файлов дисковой очереди (JSON Lines)
+const spillFileExt = ".jsonl"
+
+// spillQueue хранит события на диске в виде файлов JSON Lines.
+// Каждый вызов write создает отдельный файл, что позволяет писать атомарно.
+type spillQueue struct {
+	dir string
+	mu  sync.Mutex
+	seq uint64
+}
+
+// newSpillQueue создает дисковую очередь в директории dir
+func newSpillQueue(dir string) (*spillQueue, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	return &spillQueue{dir: dir}, nil
+}
+
+// write сохраняет события в новый файл (через временный файл и rename)
+func (q *spillQueue) write(events []*types.LogEventRequest) error {
+	q.mu.Lock()
+	q.seq++
+	name := fmt.Sprintf("events-%020d-%06d%s", time.Now().UnixNano(), q.seq, spillFileExt)
+	q.mu.Unlock()
+
+	tmp, err := os.CreateTemp(q.dir, ".spill-*")
+	if err != nil {
+		return fmt.Errorf("failed to create spill file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, req := range events {
+		if err := enc.Encode(req); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(q.dir, name)); err != nil {
+		return fmt.Errorf("failed to commit spill file: %w", err)
+	}
+	return nil
+}
+
+// spillFile события одного файла дисковой очереди
+type spillFile struct {
+	path   string
+	events []*types.LogEventRequest
+}
+
+// restore читает все сохраненные файлы в порядке записи. Файлы не удаляются:
+// их удаляет remove после отправки событий, поэтому при аварийном завершении
+// события восстановятся снова. Поврежденные строки пропускаются.
+func (q *spillQueue) restore() ([]spillFile, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), "events-") && strings.HasSuffix(entry.Name(), spillFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var files []spillFile
+	for _, name := range names {
+		path := filepath.Join(q.dir, name)
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open spill file: %w", err)
+		}
+
+		spilled := spillFile{path: path}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			var req types.LogEventRequest
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+				continue
+			}
+			spilled.events = append(spilled.events, &req)
+		}
+		file.Close()
+		files = append(files, spilled)
+	}
+	return files, nil
+}
+
+// remove удаляет файл, все события которого отправлены или сохранены заново
+func (q *spillQueue) remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove spill file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Package classifier локально повторяет быструю классификацию запросов
+// по доменам: ключевые слова types.DomainConfig.Keywords сравниваются
+// со словами запроса после стемминга (русский и английский), а оценка
+// взвешивается приоритетом домена.
+//
+// Классификатор не обращается к серверу и позволяет проверить изменения
+// ключевых слов и приоритетов до их публикации:
+//
+//	c, _ := classifier.LoadDir("./config", classifier.Config{})
+//	result := c.Classify("хочу купить ноутбук")
+//	for _, d := range result.SelectedDomains {
+//		fmt.Println(d.DomainID, d.Confidence, d.Reason)
+//	}
+//
+// Семантические векторы и LLM-классификация сервера не воспроизводятся,
+// поэтому результат может отличаться для запросов без ключевых слов.
+package classifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Algorithm значение DomainAnalysisResult.AnalysisAlgorithm
+const Algorithm = "keyword_stemming"
+
+// Значения Config по умолчанию
+const (
+	DefaultThreshold      = 0.3
+	DefaultMaxDomains     = 3
+	DefaultSaturation     = 2
+	DefaultPriorityWeight = 0.3
+)
+
+// Config параметры классификации. Нулевые значения заменяются
+// значениями по умолчанию.
+type Config struct {
+	// Threshold минимальная уверенность выбора домена (0.0-1.0).
+	// Порог DomainConfig.MLModel.Threshold, если задан, имеет приоритет.
+	Threshold float32
+
+	// MaxDomains максимальное количество выбранных доменов
+	MaxDomains int
+
+	// Saturation количество совпавших ключевых слов, при котором
+	// релевантность домена достигает 1.0
+	Saturation int
+
+	// PriorityWeight доля уверенности, зависящая от DomainConfig.Priority
+	// (0.0-1.0): уверенность = релевантность * (1 - w + w*priority/100)
+	PriorityWeight float32
+
+	// IncludeDisabled учитывает выключенные домены наравне с включенными
+	IncludeDisabled bool
+}
+
+// Classifier классифицирует запросы по набору доменов.
+// Безопасен для одновременного использования.
+type Classifier struct {
+	config  Config
+	domains []*domain
+}
+
+// domain домен с подготовленными ключевыми словами
+type domain struct {
+	config   *types.DomainConfig
+	keywords []keyword
+}
+
+// keyword ключевое слово или фраза в виде последовательности основ
+type keyword struct {
+	text  string
+	stems []string
+}
+
+// New создает классификатор для доменов
+func New(domains []*types.DomainConfig, config Config) *Classifier {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.MaxDomains <= 0 {
+		config.MaxDomains = DefaultMaxDomains
+	}
+	if config.Saturation <= 0 {
+		config.Saturation = DefaultSaturation
+	}
+	if config.PriorityWeight <= 0 {
+		config.PriorityWeight = DefaultPriorityWeight
+	}
+	config.PriorityWeight = min(config.PriorityWeight, 1)
+
+	c := &Classifier{config: config}
+	for _, d := range domains {
+		prepared := &domain{config: d}
+		seen := make(map[string]bool)
+		for _, text := range d.Keywords {
+			stems := stemTokens(Tokenize(text))
+			key := strings.Join(stems, " ")
+			if len(stems) == 0 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			prepared.keywords = append(prepared.keywords, keyword{text: text, stems: stems})
+		}
+		c.domains = append(c.domains, prepared)
+	}
+	return c
+}
+
+// Domains возвращает домены классификатора
+func (c *Classifier) Domains() []*types.DomainConfig {
+	domains := make([]*types.DomainConfig, len(c.domains))
+	for i, d := range c.domains {
+		domains[i] = d.config
+	}
+	return domains
+}
+
+// Classify оценивает запрос для каждого домена. Домены с уверенностью
+// не ниже порога попадают в SelectedDomains (не более MaxDomains,
+// по убыванию уверенности), остальные - в RejectedDomains с причиной.
+// Confidence результата - уверенность лучшего выбранного домена.
+func (c *Classifier) Classify(query string) *types.DomainAnalysisResult {
+	stems := stemTokens(Tokenize(query))
+
+	var candidates, rejected []types.DomainSelection
+	for _, d := range c.domains {
+		sel := d.selection()
+		if !d.config.Enabled && !c.config.IncludeDisabled {
+			sel.Reason = "domain is disabled"
+			rejected = append(rejected, sel)
+			continue
+		}
+
+		matched := d.match(stems)
+		if len(matched) == 0 {
+			sel.Reason = "no keywords matched"
+			rejected = append(rejected, sel)
+			continue
+		}
+		sel.Relevance = min(float32(len(matched))/float32(c.config.Saturation), 1)
+		sel.Confidence = sel.Relevance * c.priorityFactor(d.config.Priority)
+		sel.Metadata = map[string]string{"matched_keywords": strings.Join(matched, ",")}
+
+		threshold := c.config.Threshold
+		if d.config.MLModel != nil && d.config.MLModel.Threshold > 0 {
+			threshold = d.config.MLModel.Threshold
+		}
+		if sel.Confidence < threshold {
+			sel.Reason = fmt.Sprintf("confidence %.2f below threshold %.2f (matched: %s)", sel.Confidence, threshold, strings.Join(matched, ", "))
+			rejected = append(rejected, sel)
+			continue
+		}
+		sel.Reason = "matched keywords: " + strings.Join(matched, ", ")
+		candidates = append(candidates, sel)
+	}
+
+	sortSelections(candidates)
+	result := &types.DomainAnalysisResult{AnalysisAlgorithm: Algorithm}
+	for i, sel := range candidates {
+		if i >= c.config.MaxDomains {
+			sel.Reason = fmt.Sprintf("exceeds max domains %d (%s)", c.config.MaxDomains, sel.Reason)
+			rejected = append(rejected, sel)
+			continue
+		}
+		result.SelectedDomains = append(result.SelectedDomains, sel)
+	}
+	if len(result.SelectedDomains) > 0 {
+		result.Confidence = result.SelectedDomains[0].Confidence
+	}
+	sortSelections(rejected)
+	result.RejectedDomains = rejected
+	return result
+}
+
+// priorityFactor переводит приоритет 0-100 в множитель уверенности
+func (c *Classifier) priorityFactor(priority int) float32 {
+	p := float32(max(0, min(priority, 100))) / 100
+	return 1 - c.config.PriorityWeight + c.config.PriorityWeight*p
+}
+
+// selection заполняет описательные поля DomainSelection
+func (d *domain) selection() types.DomainSelection {
+	return types.DomainSelection{
+		DomainID:     d.config.ID,
+		Name:         d.config.Name,
+		Type:         d.config.Type,
+		Priority:     int32(d.config.Priority),
+		Capabilities: d.config.Capabilities,
+	}
+}
+
+// match возвращает ключевые слова домена, найденные в запросе.
+// Фраза совпадает, если ее основы идут в запросе подряд.
+func (d *domain) match(stems []string) []string {
+	var matched []string
+	for _, kw := range d.keywords {
+		for i := 0; i+len(kw.stems) <= len(stems); i++ {
+			if equalStems(stems[i:i+len(kw.stems)], kw.stems) {
+				matched = append(matched, kw.text)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func equalStems(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortSelections упорядочивает домены по уверенности, приоритету и ID
+func sortSelections(list []types.DomainSelection) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.DomainID < b.DomainID
+	})
+}
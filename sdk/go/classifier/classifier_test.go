@@ -0,0 +1,126 @@
+package classifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestStem(t *testing.T) {
+	for word, want := range map[string]string{
+		"купить":        "куп",
+		"бронировать":   "бронирова",
+		"забронировать": "забронирова",
+		"отелей":        "отел",
+		"путешествия":   "путешеств",
+		"магазине":      "магазин",
+		"красивейший":   "красив",
+		"hotels":        "hotel",
+		"booking":       "book",
+		"generously":    "generous",
+		"happiness":     "happi",
+		"relational":    "relat",
+		"ponies":        "poni",
+		"42":            "42",
+	} {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+	if got := strings.Join(Tokenize("Ёлка, E-mail и 2 отеля!"), "|"); got != "елка|e|mail|и|2|отеля" {
+		t.Errorf("Unexpected tokens: %s", got)
+	}
+}
+
+var testDomains = []*types.DomainConfig{
+	{ID: "commerce", Enabled: true, Priority: 80, Keywords: []string{"купить", "цена", "магазин", "товар"}},
+	{ID: "travel", Enabled: true, Priority: 70, Keywords: []string{"отель", "бронировать", "путешествие", "book hotel"}},
+	{ID: "recipes", Enabled: true, Priority: 60, Keywords: []string{"рецепт", "готовить", "еда"}},
+	{ID: "archive", Enabled: false, Priority: 90, Keywords: []string{"отель"}},
+}
+
+func TestClassify(t *testing.T) {
+	c := New(testDomains, Config{})
+
+	result := c.Classify("Где купить путевку и забронировать отели подешевле?")
+	var got []string
+	for _, d := range result.SelectedDomains {
+		got = append(got, d.DomainID+":"+d.Reason)
+	}
+	want := []string{"commerce:matched keywords: купить", "travel:matched keywords: отель"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Unexpected selection:\n%s", strings.Join(got, "\n"))
+	}
+	// "забронировать" не совпадает с "бронировать": приставка меняет основу
+	if result.SelectedDomains[0].Relevance != 0.5 || result.Confidence != result.SelectedDomains[0].Confidence {
+		t.Errorf("Unexpected scores: %+v", result)
+	}
+	if result.AnalysisAlgorithm != Algorithm {
+		t.Errorf("Unexpected algorithm %q", result.AnalysisAlgorithm)
+	}
+
+	reasons := make(map[string]string)
+	for _, d := range result.RejectedDomains {
+		reasons[d.DomainID] = d.Reason
+	}
+	if reasons["archive"] != "domain is disabled" || reasons["recipes"] != "no keywords matched" {
+		t.Errorf("Unexpected rejections: %v", reasons)
+	}
+
+	// Приоритет разрешает равные совпадения, фразы совпадают целиком
+	result = New(testDomains, Config{MaxDomains: 1}).Classify("отель и магазин")
+	if len(result.SelectedDomains) != 1 || result.SelectedDomains[0].DomainID != "commerce" {
+		t.Errorf("Expected commerce to win by priority, got %+v", result.SelectedDomains)
+	}
+	result = c.Classify("I want to book hotels")
+	if len(result.SelectedDomains) != 1 || result.SelectedDomains[0].Metadata["matched_keywords"] != "book hotel" {
+		t.Errorf("Expected phrase match, got %+v", result.SelectedDomains)
+	}
+
+	// Порог ML-модели домена имеет приоритет над общим
+	strict := *testDomains[0]
+	strict.MLModel = &types.DomainMLModel{Threshold: 0.9}
+	result = New([]*types.DomainConfig{&strict}, Config{}).Classify("купить")
+	if len(result.SelectedDomains) != 0 || !strings.Contains(result.RejectedDomains[0].Reason, "below threshold 0.90") {
+		t.Errorf("Expected domain threshold to apply, got %+v", result)
+	}
+}
+
+type domainList []*types.DomainConfig
+
+func (l domainList) ListDomains(ctx context.Context) ([]*types.DomainConfig, error) {
+	return l, nil
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "domains.yaml"), []byte(`kind: Domain
+spec:
+  id: recipes
+  enabled: true
+  priority: 60
+  keywords: [рецепт, готовить]
+---
+kind: Prompt
+spec:
+  id: greeting
+  template: Hello
+`), 0o644)
+
+	c, err := LoadDir(dir, Config{})
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(c.Domains()) != 1 || c.Classify("рецепты борща").SelectedDomains[0].DomainID != "recipes" {
+		t.Errorf("Unexpected domains: %+v", c.Domains())
+	}
+
+	c, err = FromServer(context.Background(), domainList(testDomains), Config{})
+	if err != nil || len(c.Domains()) != len(testDomains) {
+		t.Errorf("FromServer: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/configsync"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DomainLister источник конфигураций доменов; реализуется *client.AdminClient
+type DomainLister interface {
+	ListDomains(ctx context.Context) ([]*types.DomainConfig, error)
+}
+
+// FromServer создает классификатор по доменам, настроенным на сервере
+func FromServer(ctx context.Context, lister DomainLister, config Config) (*Classifier, error) {
+	domains, err := lister.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(domains, config), nil
+}
+
+// LoadDir создает классификатор по манифестам Domain из каталога
+// (формат манифестов - см. configsync.Resource). Манифесты других типов
+// игнорируются.
+func LoadDir(dir string, config Config) (*Classifier, error) {
+	resources, err := configsync.LoadDir(dir, configsync.LoadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	domains, err := FromResources(resources)
+	if err != nil {
+		return nil, err
+	}
+	return New(domains, config), nil
+}
+
+// FromResources извлекает конфигурации доменов из манифестов
+func FromResources(resources []*configsync.Resource) ([]*types.DomainConfig, error) {
+	var domains []*types.DomainConfig
+	for _, res := range resources {
+		if res.Kind != configsync.KindDomain {
+			continue
+		}
+		data, err := json.Marshal(res.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode domain %q: %w", res.ID, err)
+		}
+		var d types.DomainConfig
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("failed to decode domain %q: %w", res.ID, err)
+		}
+		domains = append(domains, &d)
+	}
+	return domains, nil
+}
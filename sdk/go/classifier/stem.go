@@ -0,0 +1,375 @@
+package classifier
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize разбивает текст на слова в нижнем регистре. Разделителями
+// считаются все символы, кроме букв и цифр; ё заменяется на е.
+func Tokenize(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stem возвращает основу слова: русские слова обрабатываются алгоритмом
+// Snowball для русского языка, латинские - Porter2 (английский).
+// Остальные слова возвращаются без изменений. Ожидается слово
+// в нижнем регистре (см. Tokenize).
+func Stem(word string) string {
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return stemRussian(word)
+		}
+	}
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return word
+		}
+	}
+	return stemEnglish(word)
+}
+
+// stemTokens применяет Stem к каждому токену
+func stemTokens(tokens []string) []string {
+	stems := make([]string, len(tokens))
+	for i, t := range tokens {
+		stems[i] = Stem(t)
+	}
+	return stems
+}
+
+// Окончания русского Snowball-стеммера. Группы "после а/я" удаляются,
+// только если перед окончанием стоит а или я (сама буква остается).
+var (
+	ruGerund1      = []string{"вшись", "вши", "в"}
+	ruGerund2      = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+	ruAdjective    = []string{"ими", "ыми", "его", "ого", "ему", "ому", "ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	ruParticiple1  = []string{"ем", "нн", "вш", "ющ", "щ"}
+	ruParticiple2  = []string{"ивш", "ывш", "ующ"}
+	ruReflexive    = []string{"ся", "сь"}
+	ruVerb1        = []string{"ете", "йте", "ешь", "нно", "ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть", "й", "л", "н"}
+	ruVerb2        = []string{"ейте", "уйте", "ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую", "ю"}
+	ruNoun         = []string{"иями", "ями", "ами", "ией", "иям", "ием", "иях", "ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья", "а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я"}
+	ruSuperlative  = []string{"ейше", "ейш"}
+	ruDerivational = []string{"ость", "ост"}
+)
+
+func isRussianVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// stemRussian реализует Snowball-стеммер для русского языка
+func stemRussian(word string) string {
+	w := []rune(word)
+
+	// RV - часть слова после первой гласной, R2 - по правилам Snowball
+	rv := len(w)
+	for i, r := range w {
+		if isRussianVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := regionStart(w, 0, isRussianVowel)
+	r2 := regionStart(w, r1, isRussianVowel)
+
+	// Окончания удаляются только в пределах RV
+	region := w[rv:]
+	removeGroup := func(s []rune, after, plain []string) ([]rune, bool) {
+		n := max(ruSuffix(s, after, true), ruSuffix(s, plain, false))
+		return s[:len(s)-n], n > 0
+	}
+
+	// Шаг 1
+	if s, ok := removeGroup(region, ruGerund1, ruGerund2); ok {
+		region = s
+	} else {
+		if n := ruSuffix(region, ruReflexive, false); n > 0 {
+			region = region[:len(region)-n]
+		}
+		if n := ruSuffix(region, ruAdjective, false); n > 0 {
+			region = region[:len(region)-n]
+			region, _ = removeGroup(region, ruParticiple1, ruParticiple2)
+		} else if s, ok := removeGroup(region, ruVerb1, ruVerb2); ok {
+			region = s
+		} else if n := ruSuffix(region, ruNoun, false); n > 0 {
+			region = region[:len(region)-n]
+		}
+	}
+
+	// Шаг 2
+	if len(region) > 0 && region[len(region)-1] == 'и' {
+		region = region[:len(region)-1]
+	}
+
+	// Шаг 3: словообразовательные суффиксы в R2
+	if n := ruSuffix(region, ruDerivational, false); n > 0 && rv+len(region)-n >= r2 {
+		region = region[:len(region)-n]
+	}
+
+	// Шаг 4
+	if n := ruSuffix(region, ruSuperlative, false); n > 0 {
+		region = region[:len(region)-n]
+	}
+	switch {
+	case hasRunes(region, "нн"):
+		region = region[:len(region)-1]
+	case len(region) > 0 && region[len(region)-1] == 'ь':
+		region = region[:len(region)-1]
+	}
+
+	return string(w[:rv]) + string(region)
+}
+
+// ruSuffix возвращает длину самого длинного окончания из списка, которым
+// заканчивается s, или 0. Если afterAYa, перед окончанием должна стоять а или я.
+func ruSuffix(s []rune, suffixes []string, afterAYa bool) int {
+	best := 0
+	for _, suffix := range suffixes {
+		n := len([]rune(suffix))
+		if n <= best || !hasRunes(s, suffix) {
+			continue
+		}
+		if afterAYa {
+			if len(s) <= n || (s[len(s)-n-1] != 'а' && s[len(s)-n-1] != 'я') {
+				continue
+			}
+		}
+		best = n
+	}
+	return best
+}
+
+// hasRunes сообщает, заканчивается ли s на suffix
+func hasRunes(s []rune, suffix string) bool {
+	return strings.HasSuffix(string(s), suffix)
+}
+
+// regionStart возвращает начало региона R1 (или R2 при from = R1):
+// позицию после первой согласной, следующей за гласной
+func regionStart(w []rune, from int, vowel func(rune) bool) int {
+	for i := from + 1; i < len(w); i++ {
+		if !vowel(w[i]) && vowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+func isEnglishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
+
+// stemEnglish реализует стеммер Porter2 (английский Snowball)
+// без словаря исключений
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	w := []rune(word)
+
+	// Y, выполняющая роль согласной, помечается заглавной
+	for i, r := range w {
+		if r == 'y' && (i == 0 || isEnglishVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1 := regionStart(w, 0, isEnglishVowel)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			r1 = len([]rune(prefix))
+		}
+	}
+	r2 := regionStart(w, r1, isEnglishVowel)
+
+	e := &englishWord{w: w, r1: r1, r2: r2}
+	e.step1a()
+	e.step1b()
+	e.step1c()
+	e.step2()
+	e.step3()
+	e.step4()
+	e.step5()
+	return strings.ReplaceAll(string(e.w), "Y", "y")
+}
+
+// englishWord слово в процессе обработки Porter2
+type englishWord struct {
+	w      []rune
+	r1, r2 int
+}
+
+// suffix возвращает самый длинный суффикс из списка, которым заканчивается слово
+func (e *englishWord) suffix(list ...string) string {
+	best := ""
+	for _, s := range list {
+		if len(s) > len(best) && strings.HasSuffix(string(e.w), s) {
+			best = s
+		}
+	}
+	return best
+}
+
+// replace заменяет суффикс suffix на repl
+func (e *englishWord) replace(suffix, repl string) {
+	e.w = append(e.w[:len(e.w)-len(suffix)], []rune(repl)...)
+}
+
+// in сообщает, что суффикс suffix целиком лежит в регионе, начинающемся с start
+func (e *englishWord) in(suffix string, start int) bool {
+	return len(e.w)-len(suffix) >= start
+}
+
+func (e *englishWord) hasVowel(end int) bool {
+	for _, r := range e.w[:end] {
+		if isEnglishVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortSyllableAt сообщает, что слово w[:end] заканчивается коротким слогом
+func shortSyllableAt(w []rune, end int) bool {
+	if end == 2 {
+		return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+	}
+	if end < 3 {
+		return false
+	}
+	a, b, c := w[end-3], w[end-2], w[end-1]
+	return !isEnglishVowel(a) && isEnglishVowel(b) && !isEnglishVowel(c) && !strings.ContainsRune("wxY", c)
+}
+
+func (e *englishWord) step1a() {
+	switch s := e.suffix("sses", "ied", "ies", "us", "ss", "s"); s {
+	case "sses":
+		e.replace(s, "ss")
+	case "ied", "ies":
+		if len(e.w) > 4 {
+			e.replace(s, "i")
+		} else {
+			e.replace(s, "ie")
+		}
+	case "s":
+		if e.hasVowel(len(e.w) - 2) {
+			e.replace(s, "")
+		}
+	}
+}
+
+func (e *englishWord) step1b() {
+	switch s := e.suffix("eed", "eedly", "ed", "edly", "ing", "ingly"); s {
+	case "":
+	case "eed", "eedly":
+		if e.in(s, e.r1) {
+			e.replace(s, "ee")
+		}
+	default:
+		if !e.hasVowel(len(e.w) - len(s)) {
+			return
+		}
+		e.replace(s, "")
+		switch {
+		case e.suffix("at", "bl", "iz") != "":
+			e.w = append(e.w, 'e')
+		case e.suffix("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			e.w = e.w[:len(e.w)-1]
+		case e.r1 >= len(e.w) && shortSyllableAt(e.w, len(e.w)):
+			e.w = append(e.w, 'e')
+		}
+	}
+}
+
+func (e *englishWord) step1c() {
+	n := len(e.w)
+	if n > 2 && (e.w[n-1] == 'y' || e.w[n-1] == 'Y') && !isEnglishVowel(e.w[n-2]) {
+		e.w[n-1] = 'i'
+	}
+}
+
+var englishStep2 = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+	"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+	"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous",
+	"ousness": "ous", "iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+	"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+}
+
+func (e *englishWord) step2() {
+	s := ""
+	for suffix := range englishStep2 {
+		if len(suffix) > len(s) && strings.HasSuffix(string(e.w), suffix) {
+			s = suffix
+		}
+	}
+	if s == "" || !e.in(s, e.r1) {
+		return
+	}
+	before := len(e.w) - len(s)
+	switch s {
+	case "ogi":
+		if before == 0 || e.w[before-1] != 'l' {
+			return
+		}
+	case "li":
+		if before == 0 || !strings.ContainsRune("cdeghkmnrt", e.w[before-1]) {
+			return
+		}
+	}
+	e.replace(s, englishStep2[s])
+}
+
+func (e *englishWord) step3() {
+	s := e.suffix("tional", "ational", "alize", "icate", "iciti", "ical", "ful", "ness", "ative")
+	if s == "" || !e.in(s, e.r1) {
+		return
+	}
+	switch s {
+	case "tional":
+		e.replace(s, "tion")
+	case "ational":
+		e.replace(s, "ate")
+	case "alize":
+		e.replace(s, "al")
+	case "icate", "iciti", "ical":
+		e.replace(s, "ic")
+	case "ful", "ness":
+		e.replace(s, "")
+	case "ative":
+		if e.in(s, e.r2) {
+			e.replace(s, "")
+		}
+	}
+}
+
+func (e *englishWord) step4() {
+	s := e.suffix("al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+		"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion")
+	if s == "" || !e.in(s, e.r2) {
+		return
+	}
+	if s == "ion" {
+		before := len(e.w) - len(s)
+		if before == 0 || (e.w[before-1] != 's' && e.w[before-1] != 't') {
+			return
+		}
+	}
+	e.replace(s, "")
+}
+
+func (e *englishWord) step5() {
+	n := len(e.w)
+	switch {
+	case n > 0 && e.w[n-1] == 'e':
+		if e.in("e", e.r2) || (e.in("e", e.r1) && !shortSyllableAt(e.w, n-1)) {
+			e.w = e.w[:n-1]
+		}
+	case n > 1 && e.w[n-1] == 'l' && e.w[n-2] == 'l' && e.in("l", e.r2):
+		e.w = e.w[:n-1]
+	}
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+var (
+	// ErrConfirmationRequired возвращается, если у действия задан ConfirmText,
+	// а обработчик подтверждения не установлен
+	ErrConfirmationRequired = errors.New("action requires confirmation")
+	// ErrActionDeclined возвращается, если пользователь не подтвердил действие
+	ErrActionDeclined = errors.New("action declined")
+	// ErrInvalidAction возвращается, если метод или URL действия не прошли проверку
+	ErrInvalidAction = errors.New("invalid action")
+)
+
+// ConfirmFunc запрашивает подтверждение действия (action.ConfirmText) у пользователя.
+// Возвращает true, если действие подтверждено.
+type ConfirmFunc func(ctx context.Context, action *types.Action) (bool, error)
+
+// SetActionConfirmation устанавливает обработчик подтверждения действий.
+// Обработчик вызывается ExecuteAction для каждого действия с непустым ConfirmText.
+func (c *Client) SetActionConfirmation(confirm ConfirmFunc) {
+	c.confirmAction = confirm
+}
+
+// ExecuteAction выполняет действие результата (ResultItem.Actions): reserve_product,
+// purchase, add_to_cart, process_payment и т.д.
+//
+// Запрос отправляется с авторизацией и метаданными протокола. URL действия может быть
+// относительным (разрешается относительно BaseURL) или абсолютным, но только на хост BaseURL.
+// Если задан ConfirmText, перед запросом вызывается обработчик SetActionConfirmation;
+// без обработчика возвращается ErrConfirmationRequired.
+//
+// Для GET и DELETE параметры передаются в query string, для остальных методов - в теле запроса.
+// Действия с методами, отличными от GET, отправляются ровно один раз (без RetryConfig):
+// повтор после 5xx или обрыва соединения мог бы, например, повторно списать оплату.
+//
+// Пример использования:
+//
+//	client.SetActionConfirmation(func(ctx context.Context, a *types.Action) (bool, error) {
+//		return askUser(a.ConfirmText), nil
+//	})
+//
+//	action := result.Sections[0].Results[0].Actions[0]
+//	resp, err := client.ExecuteAction(ctx, &action, map[string]interface{}{"quantity": 1})
+//	if errors.Is(err, client.ErrActionDeclined) {
+//		return
+//	}
+func (c *Client) ExecuteAction(ctx context.Context, action *types.Action, params map[string]interface{}) (*types.ExecuteActionResponse, error) {
+	if action == nil {
+		return nil, fmt.Errorf("%w: action is nil", ErrInvalidAction)
+	}
+
+	method, path, err := c.resolveAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if action.ConfirmText != "" {
+		if c.confirmAction == nil {
+			return nil, fmt.Errorf("%w: %s", ErrConfirmationRequired, action.ConfirmText)
+		}
+		confirmed, err := c.confirmAction(ctx, action)
+		if err != nil {
+			return nil, fmt.Errorf("action confirmation failed: %w", err)
+		}
+		if !confirmed {
+			return nil, fmt.Errorf("%w: %s", ErrActionDeclined, action.Type)
+		}
+	}
+
+	var body interface{}
+	if method == http.MethodGet || method == http.MethodDelete {
+		path = withQueryParams(path, params)
+	} else {
+		body = &types.ExecuteActionRequest{
+			ActionType: action.Type,
+			Params:     params,
+			Metadata:   c.createRequestMetadata(),
+		}
+	}
+
+	if method != http.MethodGet {
+		ctx = WithoutRetry(ctx)
+	}
+	resp, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data     json.RawMessage         `json:"data"`
+		Metadata *types.ResponseMetadata `json:"metadata"`
+	}
+
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	actionResp := &types.ExecuteActionResponse{}
+	if len(result.Data) > 0 && string(result.Data) != "null" {
+		if err := json.Unmarshal(result.Data, actionResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal action response: %w", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal(result.Data, &data); err == nil {
+			actionResp.Data = data
+		}
+	}
+	if actionResp.ActionType == "" {
+		actionResp.ActionType = action.Type
+	}
+	actionResp.ResponseMetadata = result.Metadata
+
+	return actionResp, nil
+}
+
+// resolveAction проверяет метод и URL действия и возвращает путь относительно BaseURL
+func (c *Client) resolveAction(action *types.Action) (string, string, error) {
+	method := strings.ToUpper(action.Method)
+	switch method {
+	case "":
+		method = http.MethodPost
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return "", "", fmt.Errorf("%w: unsupported method %q", ErrInvalidAction, action.Method)
+	}
+
+	if action.URL == "" {
+		return "", "", fmt.Errorf("%w: empty url for action %q", ErrInvalidAction, action.Type)
+	}
+	target, err := url.Parse(action.URL)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidAction, err)
+	}
+	if target.User != nil || target.Opaque != "" {
+		return "", "", fmt.Errorf("%w: malformed url %q", ErrInvalidAction, action.URL)
+	}
+	if strings.Contains(target.Path, "..") {
+		return "", "", fmt.Errorf("%w: path traversal in url %q", ErrInvalidAction, action.URL)
+	}
+
+	if target.IsAbs() || target.Host != "" {
+		base, err := url.Parse(c.baseURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid base url: %w", err)
+		}
+		if !strings.EqualFold(target.Scheme, base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+			return "", "", fmt.Errorf("%w: url %q does not match base host %q", ErrInvalidAction, action.URL, base.Host)
+		}
+		path := target.RequestURI()
+		if basePath := strings.TrimSuffix(base.Path, "/"); basePath != "" && strings.HasPrefix(path, basePath+"/") {
+			path = strings.TrimPrefix(path, basePath)
+		}
+		return method, path, nil
+	}
+
+	path := target.RequestURI()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return method, path, nil
+}
+
+// withQueryParams добавляет параметры к query string пути
+func withQueryParams(path string, params map[string]interface{}) string {
+	if len(params) == 0 {
+		return path
+	}
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, fmt.Sprintf("%v", value))
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + values.Encode()
+}
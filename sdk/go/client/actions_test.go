@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestExecuteAction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/commerce/purchase" {
+			t.Errorf("Expected POST /api/v1/commerce/purchase, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("store") != "mvideo" {
+			t.Errorf("Expected store=mvideo in query, got %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+
+		var req types.ExecuteActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.ActionType != "purchase" || req.Metadata == nil || req.Params["quantity"] != float64(1) {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"status": "success", "result_id": "order-123", "total": 89990}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Token: "test-token"})
+	confirmed := false
+	client.SetActionConfirmation(func(ctx context.Context, action *types.Action) (bool, error) {
+		confirmed = true
+		return true, nil
+	})
+
+	action := &types.Action{
+		Type:        "purchase",
+		Method:      "POST",
+		URL:         "/api/v1/commerce/purchase?store=mvideo&product=iphone15",
+		ConfirmText: "Подтвердить покупку iPhone 15 за 89990 ₽?",
+	}
+
+	resp, err := client.ExecuteAction(context.Background(), action, map[string]interface{}{"quantity": 1})
+	if err != nil {
+		t.Fatalf("ExecuteAction failed: %v", err)
+	}
+	if !confirmed {
+		t.Error("Expected confirmation callback to be called")
+	}
+	if resp.Status != "success" || resp.ResultID != "order-123" || resp.ActionType != "purchase" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if resp.Data["total"] != float64(89990) {
+		t.Errorf("Expected total in data, got %v", resp.Data["total"])
+	}
+}
+
+func TestExecuteAction_Confirmation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server must not be called without confirmation")
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	action := &types.Action{Type: "process_payment", URL: "/api/v1/payment/process", ConfirmText: "Подтвердить оплату 89 ₽?"}
+
+	if _, err := client.ExecuteAction(context.Background(), action, nil); !errors.Is(err, ErrConfirmationRequired) {
+		t.Errorf("Expected ErrConfirmationRequired, got %v", err)
+	}
+
+	client.SetActionConfirmation(func(ctx context.Context, action *types.Action) (bool, error) {
+		return false, nil
+	})
+	if _, err := client.ExecuteAction(context.Background(), action, nil); !errors.Is(err, ErrActionDeclined) {
+		t.Errorf("Expected ErrActionDeclined, got %v", err)
+	}
+}
+
+func TestExecuteAction_Validation(t *testing.T) {
+	client := NewClient(Config{BaseURL: "https://api.nexus.dev"})
+
+	tests := []struct {
+		name   string
+		action types.Action
+	}{
+		{"foreign host", types.Action{Type: "purchase", URL: "https://evil.example.com/api/v1/commerce/purchase"}},
+		{"scheme mismatch", types.Action{Type: "purchase", URL: "http://api.nexus.dev/api/v1/commerce/purchase"}},
+		{"unsupported method", types.Action{Type: "purchase", Method: "TRACE", URL: "/api/v1/commerce/purchase"}},
+		{"empty url", types.Action{Type: "purchase"}},
+		{"path traversal", types.Action{Type: "purchase", URL: "/api/v1/../admin/domains"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.ExecuteAction(context.Background(), &tt.action, nil); !errors.Is(err, ErrInvalidAction) {
+				t.Errorf("Expected ErrInvalidAction, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteAction_GetParamsInQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("product") != "42" {
+			t.Errorf("Expected GET with product=42, got %s %s", r.Method, r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"data": {"status": "success"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	action := &types.Action{Type: "check_availability", Method: "get", URL: server.URL + "/api/v1/commerce/availability"}
+
+	if _, err := client.ExecuteAction(context.Background(), action, map[string]interface{}{"product": 42}); err != nil {
+		t.Fatalf("ExecuteAction failed: %v", err)
+	}
+}
+
+func TestExecuteAction_NoRetryForNonIdempotent(t *testing.T) {
+	var posts, gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			atomic.AddInt32(&posts, 1)
+		} else {
+			atomic.AddInt32(&gets, 1)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	retry := DefaultRetryConfig()
+	retry.InitialDelay = time.Millisecond
+	client := NewClient(Config{BaseURL: server.URL, RetryConfig: &retry})
+
+	purchase := &types.Action{Type: "purchase", Method: "POST", URL: "/api/v1/commerce/purchase"}
+	if _, err := client.ExecuteAction(context.Background(), purchase, nil); err == nil {
+		t.Fatal("Expected error for 503")
+	}
+	if posts != 1 {
+		t.Errorf("Expected exactly one POST, got %d", posts)
+	}
+
+	// GET действия идемпотентны и повторяются по RetryConfig
+	details := &types.Action{Type: "view_details", Method: "GET", URL: "/api/v1/commerce/products/1"}
+	client.ExecuteAction(context.Background(), details, nil)
+	if gets != int32(retry.MaxRetries+1) {
+		t.Errorf("Expected %d GET attempts, got %d", retry.MaxRetries+1, gets)
+	}
+}
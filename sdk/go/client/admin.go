@@ -296,6 +296,98 @@ func (ac *AdminClient) DeleteIntegration(ctx context.Context, id string) error {
 	return nil
 }
 
+// ListFrontendConfigs получает список конфигураций фронтенда
+func (ac *AdminClient) ListFrontendConfigs(ctx context.Context) ([]*types.FrontendConfig, error) {
+	resp, err := ac.client.doRequest(ctx, http.MethodGet, PathAPIV1AdminFrontendConfigs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list frontend configs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var configs []*types.FrontendConfig
+	if err := ac.client.parseResponse(resp, &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// GetFrontendConfig получает конфигурацию фронтенда по ID
+func (ac *AdminClient) GetFrontendConfig(ctx context.Context, id string) (*types.FrontendConfig, error) {
+	path := fmt.Sprintf("%s/%s", PathAPIV1AdminFrontendConfigs, id)
+	resp, err := ac.client.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frontend config %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	var config types.FrontendConfig
+	if err := ac.client.parseResponse(resp, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// CreateFrontendConfig создает новую конфигурацию фронтенда
+func (ac *AdminClient) CreateFrontendConfig(ctx context.Context, config *types.FrontendConfig) (*types.FrontendConfig, error) {
+	resp, err := ac.client.doRequest(ctx, http.MethodPost, PathAPIV1AdminFrontendConfigs, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create frontend config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var created types.FrontendConfig
+	if err := ac.client.parseResponse(resp, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateFrontendConfig обновляет конфигурацию фронтенда
+func (ac *AdminClient) UpdateFrontendConfig(ctx context.Context, id string, config *types.FrontendConfig) (*types.FrontendConfig, error) {
+	path := fmt.Sprintf("%s/%s", PathAPIV1AdminFrontendConfigs, id)
+	resp, err := ac.client.doRequest(ctx, http.MethodPut, path, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update frontend config %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	var updated types.FrontendConfig
+	if err := ac.client.parseResponse(resp, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteFrontendConfig удаляет конфигурацию фронтенда
+func (ac *AdminClient) DeleteFrontendConfig(ctx context.Context, id string) error {
+	path := fmt.Sprintf("%s/%s", PathAPIV1AdminFrontendConfigs, id)
+	resp, err := ac.client.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete frontend config %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if err := ac.client.parseResponse(resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetActiveFrontendConfig делает конфигурацию фронтенда активной
+func (ac *AdminClient) SetActiveFrontendConfig(ctx context.Context, id string) error {
+	path := fmt.Sprintf("%s/%s/active", PathAPIV1AdminFrontendConfigs, id)
+	resp, err := ac.client.doRequest(ctx, http.MethodPut, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to activate frontend config %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if err := ac.client.parseResponse(resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
 // GetVersion получает информацию о версии системы
 func (ac *AdminClient) GetVersion(ctx context.Context) (map[string]string, error) {
 	resp, err := ac.client.doRequest(ctx, http.MethodGet, PathAPIV1AdminVersion, nil)
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// BackupFormatVersion версия формата резервной копии
+const BackupFormatVersion = 1
+
+// AdminBackup резервная копия административной конфигурации
+type AdminBackup struct {
+	Version         int                        `json:"version"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	ServerVersion   map[string]string          `json:"server_version,omitempty"`
+	AIConfig        *types.AIConfig            `json:"ai_config,omitempty"`
+	Integrations    []*types.IntegrationConfig `json:"integrations"`
+	Domains         []*types.DomainConfig      `json:"domains"`
+	Prompts         []*types.PromptConfig      `json:"prompts"`
+	FrontendConfigs []*types.FrontendConfig    `json:"frontend_configs"`
+
+	// Encryption описывает шифрование секретов; nil, если секреты
+	// хранятся открытым текстом
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+}
+
+// ConflictPolicy определяет поведение при восстановлении ресурса,
+// который уже существует на сервере
+type ConflictPolicy string
+
+const (
+	// ConflictSkip оставляет существующий ресурс без изменений
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite заменяет существующий ресурс копией
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictRename создает ресурс под новым ID с суффиксом RenameSuffix
+	ConflictRename ConflictPolicy = "rename"
+)
+
+// DefaultRenameSuffix суффикс ID при ConflictRename по умолчанию
+const DefaultRenameSuffix = "-restored"
+
+// RestoreOptions параметры восстановления
+type RestoreOptions struct {
+	// Conflict политика для существующих ресурсов (по умолчанию ConflictSkip).
+	// AIConfig существует всегда и перезаписывается только при ConflictOverwrite.
+	Conflict ConflictPolicy
+
+	// IDMap заменяет ID ресурсов копии (старый ID -> новый ID). Ключ можно
+	// уточнить типом ресурса: "domain/commerce". Ссылки промптов на домены
+	// обновляются в соответствии с новыми ID.
+	IDMap map[string]string
+
+	// RenameSuffix добавляется к ID при ConflictRename
+	RenameSuffix string
+
+	// Passphrase расшифровывает секреты зашифрованной копии
+	Passphrase string
+
+	// DryRun вычисляет действия без изменения сервера
+	DryRun bool
+}
+
+// Действия восстановления
+const (
+	RestoreCreated     = "created"
+	RestoreOverwritten = "overwritten"
+	RestoreRenamed     = "renamed"
+	RestoreSkipped     = "skipped"
+	RestoreFailed      = "failed"
+)
+
+// RestoreItem результат восстановления одного ресурса
+type RestoreItem struct {
+	Kind     string `json:"kind"`
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id"`
+	Action   string `json:"action"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RestoreReport результат восстановления
+type RestoreReport struct {
+	Items  []RestoreItem `json:"items"`
+	DryRun bool          `json:"dry_run,omitempty"`
+}
+
+// Count возвращает количество ресурсов с указанным действием
+func (r *RestoreReport) Count(action string) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Backup выгружает домены, промпты, интеграции, конфигурацию AI
+// и конфигурации фронтенда
+func (ac *AdminClient) Backup(ctx context.Context) (*AdminBackup, error) {
+	backup := &AdminBackup{Version: BackupFormatVersion, CreatedAt: time.Now().UTC()}
+
+	var err error
+	if backup.AIConfig, err = ac.GetAIConfig(ctx); err != nil {
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if backup.Integrations, err = ac.ListIntegrations(ctx, ""); err != nil {
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if backup.Domains, err = ac.ListDomains(ctx); err != nil {
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if backup.Prompts, err = ac.ListPrompts(ctx, ""); err != nil {
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if backup.FrontendConfigs, err = ac.ListFrontendConfigs(ctx); err != nil {
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	// Версия сервера справочная, ее отсутствие не мешает копированию
+	if version, err := ac.GetVersion(ctx); err == nil {
+		backup.ServerVersion = version
+	}
+
+	sort.Slice(backup.Integrations, func(i, j int) bool { return backup.Integrations[i].ID < backup.Integrations[j].ID })
+	sort.Slice(backup.Domains, func(i, j int) bool { return backup.Domains[i].ID < backup.Domains[j].ID })
+	sort.Slice(backup.Prompts, func(i, j int) bool { return backup.Prompts[i].ID < backup.Prompts[j].ID })
+	sort.Slice(backup.FrontendConfigs, func(i, j int) bool { return backup.FrontendConfigs[i].ID < backup.FrontendConfigs[j].ID })
+	return backup, nil
+}
+
+// Restore восстанавливает копию на сервере. Ресурсы создаются в порядке
+// зависимостей: конфигурация AI, интеграции, домены, промпты, конфигурации
+// фронтенда. Ошибки отдельных ресурсов не прерывают восстановление;
+// они отражаются в отчете и возвращаются вместе.
+func (ac *AdminClient) Restore(ctx context.Context, backup *AdminBackup, opts RestoreOptions) (*RestoreReport, error) {
+	if backup.Version > BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", backup.Version)
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictSkip
+	}
+	switch opts.Conflict {
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q", opts.Conflict)
+	}
+	if opts.RenameSuffix == "" {
+		opts.RenameSuffix = DefaultRenameSuffix
+	}
+
+	if backup.Encryption != nil {
+		decrypted, err := backup.Decrypt(opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		backup = decrypted
+	}
+
+	r := &restorer{ac: ac, opts: opts, report: &RestoreReport{DryRun: opts.DryRun}, ids: make(map[string]string)}
+	if err := r.loadExisting(ctx); err != nil {
+		return nil, err
+	}
+
+	if backup.AIConfig != nil {
+		r.restoreAIConfig(ctx, backup.AIConfig)
+	}
+	for _, in := range backup.Integrations {
+		in := *in
+		r.restore(ctx, "integration", &in.ID, func(create bool) error {
+			if create {
+				_, err := ac.CreateIntegration(ctx, &in)
+				return err
+			}
+			_, err := ac.UpdateIntegration(ctx, in.ID, &in)
+			return err
+		})
+	}
+	for _, d := range backup.Domains {
+		d := *d
+		d.CreatedAt, d.UpdatedAt = "", ""
+		r.restore(ctx, "domain", &d.ID, func(create bool) error {
+			if create {
+				_, err := ac.CreateDomain(ctx, &d)
+				return err
+			}
+			_, err := ac.UpdateDomain(ctx, d.ID, &d)
+			return err
+		})
+	}
+	for _, p := range backup.Prompts {
+		p := *p
+		if target, ok := r.ids["domain/"+p.Domain]; ok {
+			p.Domain = target
+		}
+		r.restore(ctx, "prompt", &p.ID, func(create bool) error {
+			if create {
+				_, err := ac.CreatePrompt(ctx, &p)
+				return err
+			}
+			_, err := ac.UpdatePrompt(ctx, p.ID, &p)
+			return err
+		})
+	}
+	for _, fc := range backup.FrontendConfigs {
+		fc := *fc
+		fc.CreatedAt, fc.UpdatedAt = "", ""
+		r.restore(ctx, "frontend_config", &fc.ID, func(create bool) error {
+			var err error
+			if create {
+				_, err = ac.CreateFrontendConfig(ctx, &fc)
+			} else {
+				_, err = ac.UpdateFrontendConfig(ctx, fc.ID, &fc)
+			}
+			if err == nil && fc.Active {
+				err = ac.SetActiveFrontendConfig(ctx, fc.ID)
+			}
+			return err
+		})
+	}
+	return r.report, errors.Join(r.errs...)
+}
+
+// restorer хранит состояние восстановления
+type restorer struct {
+	ac       *AdminClient
+	opts     RestoreOptions
+	report   *RestoreReport
+	existing map[string]bool   // "kind/id" ресурсов сервера
+	ids      map[string]string // "kind/исходный ID" -> итоговый ID
+	errs     []error
+}
+
+// loadExisting получает ID существующих ресурсов для обнаружения конфликтов
+func (r *restorer) loadExisting(ctx context.Context) error {
+	r.existing = make(map[string]bool)
+
+	integrations, err := r.ac.ListIntegrations(ctx, "")
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	for _, in := range integrations {
+		r.existing["integration/"+in.ID] = true
+	}
+	domains, err := r.ac.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	for _, d := range domains {
+		r.existing["domain/"+d.ID] = true
+	}
+	prompts, err := r.ac.ListPrompts(ctx, "")
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	for _, p := range prompts {
+		r.existing["prompt/"+p.ID] = true
+	}
+	configs, err := r.ac.ListFrontendConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	for _, fc := range configs {
+		r.existing["frontend_config/"+fc.ID] = true
+	}
+	return nil
+}
+
+// restore восстанавливает один ресурс. id указывает на поле ID копии
+// ресурса и изменяется при переназначении и переименовании.
+func (r *restorer) restore(ctx context.Context, kind string, id *string, write func(create bool) error) {
+	item := RestoreItem{Kind: kind, SourceID: *id}
+	if mapped, ok := r.opts.IDMap[kind+"/"+*id]; ok && mapped != "" {
+		*id = mapped
+	} else if mapped, ok := r.opts.IDMap[*id]; ok && mapped != "" {
+		*id = mapped
+	}
+
+	create := !r.existing[kind+"/"+*id]
+	item.Action = RestoreCreated
+	if !create {
+		switch r.opts.Conflict {
+		case ConflictSkip:
+			item.Action = RestoreSkipped
+		case ConflictOverwrite:
+			item.Action = RestoreOverwritten
+		case ConflictRename:
+			*id = r.uniqueID(kind, *id)
+			create = true
+			item.Action = RestoreRenamed
+		}
+	}
+	item.TargetID = *id
+	// Пропущенный ресурс остается под своим ID на сервере
+	r.ids[kind+"/"+item.SourceID] = item.TargetID
+
+	if item.Action != RestoreSkipped && !r.opts.DryRun {
+		if err := write(create); err != nil {
+			item.Action = RestoreFailed
+			item.Error = err.Error()
+			r.errs = append(r.errs, fmt.Errorf("%s %s: %w", kind, item.SourceID, err))
+		} else {
+			r.existing[kind+"/"+*id] = true
+		}
+	}
+	r.report.Items = append(r.report.Items, item)
+}
+
+// uniqueID подбирает свободный ID с суффиксом
+func (r *restorer) uniqueID(kind, id string) string {
+	candidate := id + r.opts.RenameSuffix
+	for n := 2; r.existing[kind+"/"+candidate]; n++ {
+		candidate = fmt.Sprintf("%s%s-%d", id, r.opts.RenameSuffix, n)
+	}
+	return candidate
+}
+
+// restoreAIConfig восстанавливает единственную конфигурацию AI
+func (r *restorer) restoreAIConfig(ctx context.Context, config *types.AIConfig) {
+	item := RestoreItem{Kind: "ai_config", Action: RestoreSkipped}
+	if r.opts.Conflict == ConflictOverwrite {
+		item.Action = RestoreOverwritten
+		if !r.opts.DryRun {
+			if err := r.ac.UpdateAIConfig(ctx, config); err != nil {
+				item.Action = RestoreFailed
+				item.Error = err.Error()
+				r.errs = append(r.errs, fmt.Errorf("ai_config: %w", err))
+			}
+		}
+	}
+	r.report.Items = append(r.report.Items, item)
+}
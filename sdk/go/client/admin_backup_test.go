@@ -0,0 +1,313 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// adminStore in-memory реализация admin API для тестов
+type adminStore struct {
+	mu        sync.Mutex
+	aiConfig  json.RawMessage
+	resources map[string]map[string]json.RawMessage // путь коллекции -> id -> объект
+	requests  []string
+	fail      map[string]bool // "METHOD path" запросов, завершающихся ошибкой
+}
+
+func newAdminStore() *adminStore {
+	return &adminStore{
+		aiConfig: json.RawMessage(`{"provider":"openai","model":"gpt-4","api_key":"sk-secret"}`),
+		resources: map[string]map[string]json.RawMessage{
+			PathAPIV1AdminIntegrations:    {},
+			PathAPIV1AdminDomains:         {},
+			PathAPIV1AdminPrompts:         {},
+			PathAPIV1AdminFrontendConfigs: {},
+		},
+	}
+}
+
+func (s *adminStore) put(collection string, v interface{}) {
+	data, _ := json.Marshal(v)
+	var obj struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(data, &obj)
+	s.resources[collection][obj.ID] = data
+}
+
+func (s *adminStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	}
+	if s.fail[r.Method+" "+r.URL.Path] {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"code": "VALIDATION_FAILED", "message": "rejected"}}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case PathAPIV1AdminAIConfig:
+		if r.Method == http.MethodPut {
+			s.aiConfig, _ = io.ReadAll(r.Body)
+		}
+		w.Write(s.aiConfig)
+		return
+	case PathAPIV1AdminVersion:
+		w.Write([]byte(`{"version":"2.0.0"}`))
+		return
+	}
+
+	for collection, items := range s.resources {
+		if r.URL.Path == collection {
+			if r.Method == http.MethodPost {
+				body, _ := io.ReadAll(r.Body)
+				var obj struct {
+					ID string `json:"id"`
+				}
+				json.Unmarshal(body, &obj)
+				items[obj.ID] = body
+				w.Write(body)
+				return
+			}
+			ids := make([]string, 0, len(items))
+			for id := range items {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			list := make([]json.RawMessage, 0, len(ids))
+			for _, id := range ids {
+				list = append(list, items[id])
+			}
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, collection+"/") {
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, collection+"/"), "/active")
+			switch r.Method {
+			case http.MethodPut:
+				if !strings.HasSuffix(r.URL.Path, "/active") {
+					items[id], _ = io.ReadAll(r.Body)
+				}
+			case http.MethodDelete:
+				delete(items, id)
+			}
+			w.Write([]byte(`{}`))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func TestAdminBackupRestore(t *testing.T) {
+	source := newAdminStore()
+	source.put(PathAPIV1AdminIntegrations, types.IntegrationConfig{ID: "stripe", Credentials: map[string]string{"api_key": "sk_live"}})
+	source.put(PathAPIV1AdminDomains, types.DomainConfig{ID: "commerce", AuthConfig: map[string]string{"token": "t0ken"}, CreatedAt: "2025-01-01"})
+	source.put(PathAPIV1AdminPrompts, types.PromptConfig{ID: "greeting", Domain: "commerce", Template: "Hello"})
+	source.put(PathAPIV1AdminFrontendConfigs, types.FrontendConfig{ID: "dark", Active: true})
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+
+	ctx := context.Background()
+	backup, err := NewClient(Config{BaseURL: sourceServer.URL}).Admin().Backup(ctx)
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	if len(backup.Domains) != 1 || backup.ServerVersion["version"] != "2.0.0" || backup.AIConfig.APIKey != "sk-secret" {
+		t.Fatalf("Unexpected backup: %+v", backup)
+	}
+
+	encrypted, err := backup.Encrypt("correct horse")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	var archive bytes.Buffer
+	if err := WriteBackupArchive(&archive, encrypted); err != nil {
+		t.Fatalf("WriteBackupArchive failed: %v", err)
+	}
+	for _, secret := range []string{"sk_live", "t0ken", "sk-secret"} {
+		if bytes.Contains(gunzip(t, archive.Bytes()), []byte(secret)) {
+			t.Errorf("Archive contains plaintext secret %q", secret)
+		}
+	}
+	if backup.Domains[0].AuthConfig["token"] != "t0ken" {
+		t.Error("Encrypt must not modify the original backup")
+	}
+
+	restored, err := ReadBackupArchive(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadBackupArchive failed: %v", err)
+	}
+
+	// Целевое окружение уже содержит домен commerce
+	target := newAdminStore()
+	target.put(PathAPIV1AdminDomains, types.DomainConfig{ID: "commerce", Name: "Existing"})
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+	admin := NewClient(Config{BaseURL: targetServer.URL}).Admin()
+
+	if _, err := admin.Restore(ctx, restored, RestoreOptions{Passphrase: "wrong"}); !errors.Is(err, ErrBackupPassphrase) {
+		t.Fatalf("Expected passphrase error, got %v", err)
+	}
+
+	report, err := admin.Restore(ctx, restored, RestoreOptions{
+		Passphrase: "correct horse",
+		Conflict:   ConflictRename,
+		IDMap:      map[string]string{"integration/stripe": "payments"},
+	})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	var actions []string
+	for _, item := range report.Items {
+		actions = append(actions, item.Kind+":"+item.SourceID+"->"+item.TargetID+":"+item.Action)
+	}
+	want := []string{
+		"ai_config:->:skipped",
+		"integration:stripe->payments:created",
+		"domain:commerce->commerce-restored:renamed",
+		"prompt:greeting->greeting:created",
+		"frontend_config:dark->dark:created",
+	}
+	if strings.Join(actions, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Unexpected restore report:\n%s", strings.Join(actions, "\n"))
+	}
+
+	var domain types.DomainConfig
+	json.Unmarshal(target.resources[PathAPIV1AdminDomains]["commerce-restored"], &domain)
+	if domain.AuthConfig["token"] != "t0ken" || domain.CreatedAt != "" {
+		t.Errorf("Unexpected restored domain: %+v", domain)
+	}
+	var prompt types.PromptConfig
+	json.Unmarshal(target.resources[PathAPIV1AdminPrompts]["greeting"], &prompt)
+	if prompt.Domain != "commerce-restored" {
+		t.Errorf("Expected prompt to reference renamed domain, got %q", prompt.Domain)
+	}
+	if last := target.requests[len(target.requests)-1]; last != "PUT "+PathAPIV1AdminFrontendConfigs+"/dark/active" {
+		t.Errorf("Expected active frontend config to be activated, got %q", last)
+	}
+}
+
+func TestRestoreConflictPolicies(t *testing.T) {
+	backup := &AdminBackup{
+		Version:  BackupFormatVersion,
+		AIConfig: &types.AIConfig{Model: "gpt-4o"},
+		Domains:  []*types.DomainConfig{{ID: "commerce", Name: "From backup"}},
+	}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		policy ConflictPolicy
+		dryRun bool
+		name   string
+		writes int
+	}{
+		{ConflictSkip, false, "Existing", 0},
+		{ConflictOverwrite, true, "Existing", 0},
+		{ConflictOverwrite, false, "From backup", 2},
+	} {
+		store := newAdminStore()
+		store.put(PathAPIV1AdminDomains, types.DomainConfig{ID: "commerce", Name: "Existing"})
+		server := httptest.NewServer(store)
+
+		report, err := NewClient(Config{BaseURL: server.URL}).Admin().Restore(ctx, backup, RestoreOptions{Conflict: tt.policy, DryRun: tt.dryRun})
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: Restore failed: %v", tt.policy, err)
+		}
+		var domain types.DomainConfig
+		json.Unmarshal(store.resources[PathAPIV1AdminDomains]["commerce"], &domain)
+		if domain.Name != tt.name || len(store.requests) != tt.writes {
+			t.Errorf("%s (dry run %v): domain %q, writes %v", tt.policy, tt.dryRun, domain.Name, store.requests)
+		}
+		if tt.policy == ConflictOverwrite && report.Count(RestoreOverwritten) != 2 {
+			t.Errorf("%s: expected 2 overwritten items, got %+v", tt.policy, report.Items)
+		}
+	}
+
+	if _, err := NewClient(Config{BaseURL: "http://unused"}).Admin().Restore(ctx, backup, RestoreOptions{Conflict: "merge"}); err == nil {
+		t.Error("Expected error for unknown conflict policy")
+	}
+}
+
+func TestReadBackupArchiveDetectsTampering(t *testing.T) {
+	backup := &AdminBackup{Version: BackupFormatVersion, Prompts: []*types.PromptConfig{{ID: "p", Template: "Hello"}}}
+	var archive bytes.Buffer
+	if err := WriteBackupArchive(&archive, backup); err != nil {
+		t.Fatalf("WriteBackupArchive failed: %v", err)
+	}
+
+	// Переписываем архив с измененным prompts.json
+	var tampered bytes.Buffer
+	gz := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gz)
+	tr := tar.NewReader(bytes.NewReader(gunzip(t, archive.Bytes())))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		data, _ := io.ReadAll(tr)
+		if hdr.Name == backupPromptsFile {
+			data = bytes.Replace(data, []byte("Hello"), []byte("Hacked"), 1)
+			hdr.Size = int64(len(data))
+		}
+		tw.WriteHeader(hdr)
+		tw.Write(data)
+	}
+	tw.Close()
+	gz.Close()
+
+	if _, err := ReadBackupArchive(&tampered); !errors.Is(err, ErrBackupChecksum) {
+		t.Errorf("Expected checksum error, got %v", err)
+	}
+	if _, err := ReadBackupArchive(bytes.NewReader(archive.Bytes())); err != nil {
+		t.Errorf("Unexpected error for untouched archive: %v", err)
+	}
+}
+
+func TestDecryptRejectsInvalidIterations(t *testing.T) {
+	backup := &AdminBackup{Version: BackupFormatVersion, AIConfig: &types.AIConfig{APIKey: "sk-secret"}}
+	encrypted, err := backup.Encrypt("correct horse")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	for _, iterations := range []int{0, -1, 1 << 40} {
+		tampered := *encrypted
+		enc := *encrypted.Encryption
+		enc.Iterations = iterations
+		tampered.Encryption = &enc
+		if _, err := tampered.Decrypt("correct horse"); err == nil || !strings.Contains(err.Error(), "iterations") {
+			t.Errorf("Iterations %d: expected iterations error, got %v", iterations, err)
+		}
+	}
+	if _, err := encrypted.Decrypt("correct horse"); err != nil {
+		t.Errorf("Unexpected error for untouched backup: %v", err)
+	}
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	out, _ := io.ReadAll(gz)
+	return out
+}
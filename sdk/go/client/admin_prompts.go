@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/prompts"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// ListPromptVersions возвращает версии промпта - промпты с одинаковыми
+// Name и Domain - в порядке возрастания Version
+func (ac *AdminClient) ListPromptVersions(ctx context.Context, name, domain string) ([]*types.PromptConfig, error) {
+	all, err := ac.ListPrompts(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	var versions []*types.PromptConfig
+	for _, p := range all {
+		if p.Name == name && p.Domain == domain {
+			versions = append(versions, p)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// PublishPrompt создает новую версию промпта и делает ее активной.
+// Шаблон предварительно проверяется prompts.Lint; при ошибках возвращается
+// *prompts.LintError. Version назначается следующей за максимальной
+// версией промпта с теми же Name и Domain.
+func (ac *AdminClient) PublishPrompt(ctx context.Context, prompt *types.PromptConfig) (*types.PromptConfig, error) {
+	if issues := prompts.Lint(prompt); prompts.HasErrors(issues) {
+		return nil, &prompts.LintError{Issues: issues}
+	}
+	versions, err := ac.ListPromptVersions(ctx, prompt.Name, prompt.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *prompt
+	next.Version = 1
+	if len(versions) > 0 {
+		next.Version = versions[len(versions)-1].Version + 1
+	}
+	// Новая версия создается неактивной и включается вместе с выключением старой
+	next.Active = false
+	created, err := ac.CreatePrompt(ctx, &next)
+	if err != nil {
+		return nil, err
+	}
+	if created.ID == "" {
+		created.ID = next.ID
+	}
+	if created.ID == "" {
+		return nil, fmt.Errorf("failed to publish prompt %s: server returned no ID", prompt.Name)
+	}
+
+	if err := ac.activatePromptVersion(ctx, versions, created); err != nil {
+		return nil, fmt.Errorf("failed to publish prompt %s version %d: %w", prompt.Name, next.Version, err)
+	}
+	created.Active = true
+	return created, nil
+}
+
+// RollbackPrompt делает активной указанную версию промпта. Если version
+// равна 0, активируется версия, предшествующая текущей активной.
+func (ac *AdminClient) RollbackPrompt(ctx context.Context, name, domain string, version int) (*types.PromptConfig, error) {
+	versions, err := ac.ListPromptVersions(ctx, name, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *types.PromptConfig
+	if version == 0 {
+		active := -1
+		for i, v := range versions {
+			if v.Active {
+				active = i
+			}
+		}
+		if active < 0 {
+			return nil, fmt.Errorf("prompt %s has no active version", name)
+		}
+		if active == 0 {
+			return nil, fmt.Errorf("prompt %s has no version before %d", name, versions[active].Version)
+		}
+		target = versions[active-1]
+	} else {
+		for _, v := range versions {
+			if v.Version == version {
+				target = v
+			}
+		}
+		if target == nil {
+			return nil, fmt.Errorf("prompt %s has no version %d", name, version)
+		}
+	}
+
+	if err := ac.activatePromptVersion(ctx, versions, target); err != nil {
+		return nil, fmt.Errorf("failed to roll back prompt %s to version %d: %w", name, target.Version, err)
+	}
+	activated := *target
+	activated.Active = true
+	return &activated, nil
+}
+
+// activatePromptVersion включает target и выключает остальные активные версии.
+// Сервер не поддерживает транзакции, поэтому сначала включается новая версия
+// (промпт не остается без активной версии), а при ошибке выполненные
+// переключения откатываются.
+func (ac *AdminClient) activatePromptVersion(ctx context.Context, versions []*types.PromptConfig, target *types.PromptConfig) error {
+	setActive := func(p *types.PromptConfig, active bool) error {
+		updated := *p
+		updated.Active = active
+		_, err := ac.UpdatePrompt(ctx, p.ID, &updated)
+		return err
+	}
+
+	wasActive := false
+	for _, v := range versions {
+		if v.ID == target.ID {
+			wasActive = v.Active
+		}
+	}
+	if !wasActive {
+		if err := setActive(target, true); err != nil {
+			return err
+		}
+	}
+
+	var deactivated []*types.PromptConfig
+	for _, v := range versions {
+		if v.ID == target.ID || !v.Active {
+			continue
+		}
+		if err := setActive(v, false); err != nil {
+			errs := []error{err}
+			for _, prev := range deactivated {
+				if rerr := setActive(prev, true); rerr != nil {
+					errs = append(errs, fmt.Errorf("failed to restore version %d: %w", prev.Version, rerr))
+				}
+			}
+			if !wasActive {
+				if rerr := setActive(target, false); rerr != nil {
+					errs = append(errs, fmt.Errorf("failed to restore version %d: %w", target.Version, rerr))
+				}
+			}
+			return errors.Join(errs...)
+		}
+		deactivated = append(deactivated, v)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/prompts"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// activeVersions возвращает версии активных промптов хранилища
+func activeVersions(store *adminStore) []int {
+	var active []int
+	for _, data := range store.resources[PathAPIV1AdminPrompts] {
+		var p types.PromptConfig
+		json.Unmarshal(data, &p)
+		if p.Active {
+			active = append(active, p.Version)
+		}
+	}
+	return active
+}
+
+func TestPublishAndRollbackPrompt(t *testing.T) {
+	store := newAdminStore()
+	store.put(PathAPIV1AdminPrompts, types.PromptConfig{ID: "search-v1", Name: "search", Domain: "commerce", Version: 1, Active: true, Template: "{{query}}", Variables: []string{"query"}})
+	store.put(PathAPIV1AdminPrompts, types.PromptConfig{ID: "other", Name: "other", Domain: "commerce", Version: 7, Active: true})
+	server := httptest.NewServer(store)
+	defer server.Close()
+	admin := NewClient(Config{BaseURL: server.URL}).Admin()
+	ctx := context.Background()
+
+	_, err := admin.PublishPrompt(ctx, &types.PromptConfig{ID: "search-v2", Name: "search", Domain: "commerce", Template: "{{query}} {{city}}", Variables: []string{"query"}})
+	var lintErr *prompts.LintError
+	if !errors.As(err, &lintErr) {
+		t.Fatalf("Expected lint error, got %v", err)
+	}
+
+	published, err := admin.PublishPrompt(ctx, &types.PromptConfig{ID: "search-v2", Name: "search", Domain: "commerce", Template: "{{query}} {{city}}", Variables: []string{"query", "city"}})
+	if err != nil {
+		t.Fatalf("PublishPrompt failed: %v", err)
+	}
+	if published.Version != 2 || !published.Active {
+		t.Errorf("Unexpected published prompt: %+v", published)
+	}
+	versions, _ := admin.ListPromptVersions(ctx, "search", "commerce")
+	if len(versions) != 2 || versions[0].Active || !versions[1].Active {
+		t.Fatalf("Expected only version 2 to be active: %+v", versions)
+	}
+
+	// Откат на предыдущую версию, при ошибке состояние восстанавливается
+	store.fail = map[string]bool{"PUT " + PathAPIV1AdminPrompts + "/search-v2": true}
+	if _, err := admin.RollbackPrompt(ctx, "search", "commerce", 0); err == nil {
+		t.Fatal("Expected rollback error")
+	}
+	versions, _ = admin.ListPromptVersions(ctx, "search", "commerce")
+	if versions[0].Active || !versions[1].Active {
+		t.Errorf("Expected failed rollback to be compensated: %+v", versions)
+	}
+
+	store.fail = nil
+	rolledBack, err := admin.RollbackPrompt(ctx, "search", "commerce", 0)
+	if err != nil || rolledBack.Version != 1 {
+		t.Fatalf("RollbackPrompt failed: %v %+v", err, rolledBack)
+	}
+	if active := activeVersions(store); len(active) != 2 {
+		t.Errorf("Expected version 1 and the unrelated prompt to be active, got %v", active)
+	}
+
+	if _, err := admin.RollbackPrompt(ctx, "search", "commerce", 5); err == nil {
+		t.Error("Expected error for unknown version")
+	}
+}
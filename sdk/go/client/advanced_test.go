@@ -23,19 +23,22 @@ func TestAdvancedScenario(t *testing.T) {
 		case "/api/v1/batch/execute":
 			// Batch operation response
 			response := types.BatchResponse{
-				Results: []types.BatchResult{
-					{OperationID: 0, Success: true, Data: map[string]interface{}{"result": "batch-success"}, ExecutionTimeMS: 100},
-					{OperationID: 1, Success: true, Data: map[string]interface{}{"result": "batch-success"}, ExecutionTimeMS: 150},
+				BatchID: "batch-789",
+				Responses: []*types.ExecuteTemplateResponse{
+					{ExecutionID: "batch-exec-1", Status: "completed", ProcessingTimeMS: 100},
+					{ExecutionID: "batch-exec-2", Status: "completed", ProcessingTimeMS: 150},
 				},
-				Total: 2, Successful: 2, Failed: 0, TotalTimeMS: 250,
-				ResponseMetadata: &types.ResponseMetadata{
+				BatchMetadata: &types.BatchMetadata{TotalRequests: 2, SuccessfulRequests: 2, FailedRequests: 0, TotalProcessingTimeMS: 250},
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": response,
+				"metadata": &types.ResponseMetadata{
 					RequestID: "batch-req-123", ProtocolVersion: "2.0.0", ServerVersion: "2.0.0",
 					Timestamp: time.Now().Unix(), ProcessingTimeMS: 250,
 					RateLimitInfo: &types.RateLimitInfo{Limit: 1000, Remaining: 998, ResetAt: time.Now().Add(time.Hour).Unix()},
 					CacheInfo: &types.CacheInfo{CacheHit: false, CacheKey: "batch:hash", CacheTTL: 300},
 				},
-			}
-			json.NewEncoder(w).Encode(map[string]types.BatchResponse{"data": response})
+			})
 
 		case "/api/v1/webhooks":
 			if r.Method == "POST" {
@@ -217,32 +220,32 @@ func TestAdvancedScenario(t *testing.T) {
 
 	t.Run("Batch Operations", func(t *testing.T) {
 		batch := NewBatchBuilder().
-			AddOperation("execute_template", &types.ExecuteTemplateRequest{
+			AddRequest(&types.ExecuteTemplateRequest{
 				Query: "купить ноутбук",
 				Context: &types.UserContext{TenantID: "enterprise-company-abc"},
 			}).
-			AddOperation("execute_template", &types.ExecuteTemplateRequest{
+			AddRequest(&types.ExecuteTemplateRequest{
 				Query: "забронировать отель",
 				Context: &types.UserContext{TenantID: "enterprise-company-abc"},
 			}).
-			SetOptions(&types.BatchOptions{Parallel: true})
+			SetBatchOptions(&types.ExecuteOptions{ParallelExecution: true})
 
 		result, err := batch.Execute(ctx, client)
 		if err != nil {
 			t.Fatalf("Batch execution failed: %v", err)
 		}
 
-		if result.Total != 2 || result.Successful != 2 {
-			t.Errorf("Expected 2 successful operations, got total=%d successful=%d", result.Total, result.Successful)
+		if result.BatchMetadata == nil || result.BatchMetadata.TotalRequests != 2 || result.BatchMetadata.SuccessfulRequests != 2 {
+			t.Fatalf("Expected 2 successful requests, got %+v", result.BatchMetadata)
 		}
 
 		// Проверяем enterprise метрики в batch ответе
 		if result.ResponseMetadata == nil || result.ResponseMetadata.RateLimitInfo == nil {
-			t.Error("Expected enterprise metrics in batch response")
+			t.Fatal("Expected enterprise metrics in batch response")
 		}
 
-		t.Logf("✅ Batch executed: %d ops in %dms, rate_limit remaining: %d",
-			result.Total, result.TotalTimeMS, result.ResponseMetadata.RateLimitInfo.Remaining)
+		t.Logf("✅ Batch executed: %d requests in %dms, rate_limit remaining: %d",
+			result.BatchMetadata.TotalRequests, result.BatchMetadata.TotalProcessingTimeMS, result.ResponseMetadata.RateLimitInfo.Remaining)
 	})
 
 	t.Run("Webhook Management", func(t *testing.T) {
@@ -76,6 +76,7 @@ func (c *Client) GetEvents(ctx context.Context, req *types.GetEventsRequest) (*t
 // GetStats получает комплексную статистику аналитики.
 // Поддерживает фильтрацию по user_id, tenant_id и периоду (days).
 // По умолчанию период: 7 дней.
+// Если настроен CacheConfig, ответ кэшируется локально согласно x-cache-control.
 func (c *Client) GetStats(ctx context.Context, req *types.GetStatsRequest) (*types.AnalyticsStats, error) {
 	// Строим query параметры
 	params := url.Values{}
@@ -94,7 +95,7 @@ func (c *Client) GetStats(ctx context.Context, req *types.GetStatsRequest) (*typ
 		path += "?" + params.Encode()
 	}
 
-	resp, err := c.doRequest(ctx, "GET", path, nil)
+	resp, err := c.doCachedGet(ctx, path)
 	if err != nil {
 		return nil, err
 	}
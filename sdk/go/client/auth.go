@@ -0,0 +1,236 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Заголовки аутентификации
+const (
+	// DefaultAPIKeyHeader заголовок APIKeyAuth по умолчанию
+	DefaultAPIKeyHeader = "X-API-Key"
+
+	// Заголовки подписи HMACAuth
+	HeaderKeyID     = "X-Nexus-Key-Id"
+	HeaderTimestamp = "X-Nexus-Timestamp"
+	HeaderSignature = "X-Nexus-Signature"
+)
+
+// DefaultSignatureSkew допустимое расхождение часов при проверке подписи
+const DefaultSignatureSkew = 5 * time.Minute
+
+// Authenticator добавляет учетные данные к запросу клиента. Вызывается
+// перед каждой попыткой после interceptors, поэтому подпись покрывает
+// итоговый запрос; body - тело запроса (nil, если тела нет). Bearer-токен
+// из Config.Token и SetToken передается независимо от Authenticator.
+type Authenticator interface {
+	Authenticate(req *http.Request, body []byte) error
+}
+
+// TLSAuthenticator дополнительно реализуется Authenticator, которому
+// нужен TLS клиента (mTLS). ConfigureTLS вызывается один раз в NewClient
+// для настроек встроенного HTTP транспорта; с TransportConfig.Transport
+// и RoundTripper TLS настраивает сам транспорт.
+type TLSAuthenticator interface {
+	ConfigureTLS(config *tls.Config)
+}
+
+// APIKeyAuth передает статический API ключ в заголовке. Для заголовка
+// Authorization ключ передается как Bearer, как его ожидает domain.Server.
+type APIKeyAuth struct {
+	Key    string
+	Header string // Заголовок ("" = DefaultAPIKeyHeader)
+}
+
+// Authenticate устанавливает заголовок с ключом
+func (a *APIKeyAuth) Authenticate(req *http.Request, body []byte) error {
+	if a.Key == "" {
+		return errors.New("api key is empty")
+	}
+	header := a.Header
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	value := a.Key
+	if strings.EqualFold(header, "Authorization") {
+		value = "Bearer " + value
+	}
+	req.Header.Set(header, value)
+	return nil
+}
+
+// HMACAuth подписывает запрос HMAC-SHA256 общего секрета. Подписывается
+// строка "METHOD\nPATH?QUERY\nSHA256(body)\nTIMESTAMP"; timestamp берется
+// из RequestMetadata тела, а для запросов без метаданных - текущее время.
+// Подпись передается в X-Nexus-Signature как "sha256=<hex>", timestamp - в
+// X-Nexus-Timestamp, идентификатор ключа - в X-Nexus-Key-Id.
+type HMACAuth struct {
+	KeyID  string
+	Secret []byte
+}
+
+// Authenticate подписывает запрос
+func (a *HMACAuth) Authenticate(req *http.Request, body []byte) error {
+	if len(a.Secret) == 0 {
+		return errors.New("hmac secret is empty")
+	}
+	timestamp, ok := metadataTimestamp(body)
+	if !ok {
+		timestamp = time.Now().Unix()
+	}
+	if a.KeyID != "" {
+		req.Header.Set(HeaderKeyID, a.KeyID)
+	}
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, "sha256="+signRequest(a.Secret, req.Method, req.URL.RequestURI(), body, timestamp))
+	return nil
+}
+
+// VerifyHMACSignature проверяет подпись HMACAuth на стороне сервера.
+// body - прочитанное тело запроса; maxSkew ограничивает возраст timestamp
+// (0 = DefaultSignatureSkew). Если тело содержит RequestMetadata, ее
+// timestamp должен совпадать с X-Nexus-Timestamp.
+func VerifyHMACSignature(req *http.Request, body, secret []byte, maxSkew time.Duration) error {
+	if maxSkew <= 0 {
+		maxSkew = DefaultSignatureSkew
+	}
+	signature, ok := strings.CutPrefix(req.Header.Get(HeaderSignature), "sha256=")
+	if !ok {
+		return errors.New("missing request signature")
+	}
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid request timestamp")
+	}
+	if metaTimestamp, ok := metadataTimestamp(body); ok && metaTimestamp != timestamp {
+		return errors.New("request timestamp does not match metadata")
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > maxSkew || age < -maxSkew {
+		return errors.New("request timestamp is outside the allowed window")
+	}
+	expected := signRequest(secret, req.Method, req.URL.RequestURI(), body, timestamp)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errors.New("invalid request signature")
+	}
+	return nil
+}
+
+func signRequest(secret []byte, method, uri string, body []byte, timestamp int64) string {
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d", method, uri, hex.EncodeToString(digest[:]), timestamp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// metadataTimestamp извлекает metadata.timestamp из тела запроса
+func metadataTimestamp(body []byte) (int64, bool) {
+	if len(body) == 0 {
+		return 0, false
+	}
+	var envelope struct {
+		Metadata *struct {
+			Timestamp int64 `json:"timestamp"`
+		} `json:"metadata"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Metadata == nil || envelope.Metadata.Timestamp <= 0 {
+		return 0, false
+	}
+	return envelope.Metadata.Timestamp, true
+}
+
+// MTLSAuth предъявляет клиентский сертификат при TLS рукопожатии и
+// перечитывает его с диска, когда файлы сертификата или ключа меняются.
+// Новый сертификат используется для новых соединений; если файлы
+// повреждены, остается предыдущий, а ошибка доступна через Err.
+type MTLSAuth struct {
+	certFile, keyFile string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modified time.Time
+	err      error
+}
+
+// NewMTLSAuth загружает сертификат и ключ в PEM
+func NewMTLSAuth(certFile, keyFile string) (*MTLSAuth, error) {
+	a := &MTLSAuth{certFile: certFile, keyFile: keyFile}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Authenticate ничего не добавляет: аутентификация выполняется на уровне TLS
+func (a *MTLSAuth) Authenticate(req *http.Request, body []byte) error {
+	return nil
+}
+
+// ConfigureTLS подключает сертификат к TLS клиента
+func (a *MTLSAuth) ConfigureTLS(config *tls.Config) {
+	config.GetClientCertificate = a.clientCertificate
+}
+
+// Reload перечитывает сертификат с диска
+func (a *MTLSAuth) Reload() error {
+	modified, err := a.lastModified()
+	if err == nil {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(a.certFile, a.keyFile); err == nil {
+			a.mu.Lock()
+			a.cert, a.modified, a.err = &cert, modified, nil
+			a.mu.Unlock()
+			return nil
+		}
+	}
+	err = fmt.Errorf("failed to load client certificate: %w", err)
+	a.mu.Lock()
+	a.err = err
+	a.mu.Unlock()
+	return err
+}
+
+// Err возвращает ошибку последней перезагрузки сертификата
+func (a *MTLSAuth) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+func (a *MTLSAuth) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	a.mu.Lock()
+	current := a.modified
+	a.mu.Unlock()
+	if modified, err := a.lastModified(); err == nil && !modified.Equal(current) {
+		a.Reload()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cert, nil
+}
+
+// lastModified время последнего изменения файлов сертификата и ключа
+func (a *MTLSAuth) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{a.certFile, a.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Authenticator: &APIKeyAuth{Key: "secret-key"}})
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if header.Get(DefaultAPIKeyHeader) != "secret-key" || header.Get("Authorization") != "" {
+		t.Errorf("Unexpected headers %v", header)
+	}
+
+	client = NewClient(Config{BaseURL: server.URL, Authenticator: &APIKeyAuth{Key: "secret-key", Header: "Authorization"}})
+	client.Health(context.Background())
+	if header.Get("Authorization") != "Bearer secret-key" {
+		t.Errorf("Expected bearer API key, got %q", header.Get("Authorization"))
+	}
+
+	client = NewClient(Config{BaseURL: server.URL, Authenticator: &APIKeyAuth{}})
+	if _, err := client.Health(context.Background()); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+}
+
+func TestHMACAuth(t *testing.T) {
+	secret := []byte("shared-secret")
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderKeyID) != "service-a" {
+			t.Errorf("Unexpected key id %q", r.Header.Get(HeaderKeyID))
+		}
+		verifyErr = VerifyHMACSignature(r, body, secret, 0)
+		w.Write([]byte(`{"metadata":{},"data":{"logged":true,"event_id":"e1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Authenticator: &HMACAuth{KeyID: "service-a", Secret: secret}})
+	if _, err := client.Health(context.Background()); err != nil || verifyErr != nil {
+		t.Fatalf("GET signature rejected: %v, %v", err, verifyErr)
+	}
+	if _, err := client.LogEvent(context.Background(), &types.LogEventRequest{EventType: "click"}); err != nil || verifyErr != nil {
+		t.Fatalf("POST signature rejected: %v, %v", err, verifyErr)
+	}
+
+	body := []byte(`{"metadata":{"timestamp":1}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/events?x=1", nil)
+	(&HMACAuth{Secret: secret}).Authenticate(req, []byte(`{"data":{}}`))
+	if err := VerifyHMACSignature(req, []byte(`{"data":{"tampered":true}}`), secret, 0); err == nil {
+		t.Error("Expected error for tampered body")
+	}
+	if err := VerifyHMACSignature(req, []byte(`{"data":{}}`), []byte("other"), 0); err == nil {
+		t.Error("Expected error for wrong secret")
+	}
+	if err := VerifyHMACSignature(req, body, secret, 0); err == nil {
+		t.Error("Expected error for metadata timestamp mismatch")
+	}
+
+	old := httptest.NewRequest(http.MethodGet, "/health", nil)
+	(&HMACAuth{Secret: secret}).Authenticate(old, []byte(`{"metadata":{"timestamp":1000}}`))
+	if err := VerifyHMACSignature(old, []byte(`{"metadata":{"timestamp":1000}}`), secret, 0); err == nil {
+		t.Error("Expected error for expired timestamp")
+	}
+}
+
+func TestMTLSAuthReload(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "test-ca", nil, nil)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writeClientCertificate(t, certFile, keyFile, "service-a", ca, caKey, time.Now().Add(-time.Hour))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	var peers []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peers = append(peers, r.TLS.PeerCertificates[0].Subject.CommonName)
+		// Новое соединение на каждый запрос, чтобы проверить перезагрузку
+		w.Header().Set("Connection", "close")
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	auth, err := NewMTLSAuth(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewMTLSAuth failed: %v", err)
+	}
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(server.Certificate())
+	client := NewClient(Config{
+		BaseURL:       server.URL,
+		Authenticator: auth,
+		Transport:     &TransportConfig{TLSConfig: &tls.Config{RootCAs: serverCAs}},
+	})
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("Health with client certificate failed: %v", err)
+	}
+
+	writeClientCertificate(t, certFile, keyFile, "service-b", ca, caKey, time.Now())
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("Health after reload failed: %v", err)
+	}
+
+	os.WriteFile(certFile, []byte("broken"), 0o600)
+	os.Chtimes(certFile, time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("Previous certificate must stay in use: %v", err)
+	}
+	if auth.Err() == nil {
+		t.Error("Expected reload error")
+	}
+	if strings.Join(peers, ",") != "service-a,service-b,service-b" {
+		t.Errorf("Unexpected peers %v", peers)
+	}
+
+	if _, err := NewMTLSAuth(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+		t.Error("Expected error for missing certificate")
+	}
+}
+
+// newTestCertificate создает сертификат, подписанный parent (nil - самоподписанный CA)
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// writeClientCertificate записывает клиентский сертификат и ключ в PEM с
+// заданным временем изменения файлов
+func writeClientCertificate(t *testing.T, certFile, keyFile, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey, modified time.Time) {
+	t.Helper()
+	cert, key := newTestCertificate(t, name, ca, caKey)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{certFile, keyFile} {
+		if err := os.Chtimes(name, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// backupFormat идентификатор формата архива резервной копии
+const backupFormat = "nexus-admin-backup"
+
+// Файлы архива резервной копии
+const (
+	backupManifestFile     = "manifest.json"
+	backupAIConfigFile     = "ai_config.json"
+	backupIntegrationsFile = "integrations.json"
+	backupDomainsFile      = "domains.json"
+	backupPromptsFile      = "prompts.json"
+	backupFrontendFile     = "frontend_configs.json"
+)
+
+// Параметры шифрования секретов
+const (
+	backupCipher           = "AES-256-GCM"
+	backupKDF              = "PBKDF2-HMAC-SHA256"
+	backupKDFIterations    = 210000
+	maxBackupKDFIterations = 10 * backupKDFIterations // Больше - заведомо испорченный манифест
+	backupEncryptedValue   = "enc:"
+	backupCheckValue       = "nexus-admin-backup"
+)
+
+var (
+	// ErrBackupChecksum возвращается, если содержимое архива не совпадает с контрольной суммой
+	ErrBackupChecksum = errors.New("backup checksum mismatch")
+	// ErrBackupPassphrase возвращается при отсутствующей или неверной парольной фразе
+	ErrBackupPassphrase = errors.New("invalid or missing backup passphrase")
+)
+
+// BackupEncryption описывает шифрование секретов резервной копии.
+// Шифруются AIConfig.APIKey, DomainConfig.AuthConfig и IntegrationConfig.Credentials.
+type BackupEncryption struct {
+	Algorithm  string `json:"algorithm"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`  // base64
+	Check      string `json:"check"` // зашифрованное контрольное значение для проверки фразы
+}
+
+// backupManifest описание архива с контрольными суммами файлов
+type backupManifest struct {
+	Format        string                `json:"format"`
+	Version       int                   `json:"version"`
+	CreatedAt     time.Time             `json:"created_at"`
+	ServerVersion map[string]string     `json:"server_version,omitempty"`
+	Encryption    *BackupEncryption     `json:"encryption,omitempty"`
+	Files         map[string]backupFile `json:"files"`
+}
+
+// backupFile контрольная сумма и размер файла архива
+type backupFile struct {
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// Encrypt возвращает копию, в которой секреты зашифрованы ключом,
+// полученным из парольной фразы
+func (b *AdminBackup) Encrypt(passphrase string) (*AdminBackup, error) {
+	if b.Encryption != nil {
+		return nil, fmt.Errorf("backup is already encrypted")
+	}
+	if passphrase == "" {
+		return nil, ErrBackupPassphrase
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	enc := &BackupEncryption{
+		Algorithm:  backupCipher,
+		KDF:        backupKDF,
+		Iterations: backupKDFIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+	}
+	aead, err := enc.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if enc.Check, err = sealValue(aead, backupCheckValue); err != nil {
+		return nil, err
+	}
+
+	encrypted, err := b.mapSecrets(func(v string) (string, error) { return sealValue(aead, v) })
+	if err != nil {
+		return nil, err
+	}
+	encrypted.Encryption = enc
+	return encrypted, nil
+}
+
+// Decrypt возвращает копию с расшифрованными секретами
+func (b *AdminBackup) Decrypt(passphrase string) (*AdminBackup, error) {
+	if b.Encryption == nil {
+		return b, nil
+	}
+	if passphrase == "" {
+		return nil, ErrBackupPassphrase
+	}
+	aead, err := b.Encryption.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := openValue(aead, b.Encryption.Check); err != nil || check != backupCheckValue {
+		return nil, ErrBackupPassphrase
+	}
+
+	decrypted, err := b.mapSecrets(func(v string) (string, error) { return openValue(aead, v) })
+	if err != nil {
+		return nil, err
+	}
+	decrypted.Encryption = nil
+	return decrypted, nil
+}
+
+// mapSecrets возвращает копию с преобразованными значениями секретов
+func (b *AdminBackup) mapSecrets(fn func(string) (string, error)) (*AdminBackup, error) {
+	out := *b
+	mapValues := func(m map[string]string) (map[string]string, error) {
+		if m == nil {
+			return nil, nil
+		}
+		mapped := make(map[string]string, len(m))
+		for k, v := range m {
+			var err error
+			if mapped[k], err = fn(v); err != nil {
+				return nil, fmt.Errorf("secret %q: %w", k, err)
+			}
+		}
+		return mapped, nil
+	}
+
+	if b.AIConfig != nil {
+		ai := *b.AIConfig
+		if ai.APIKey != "" {
+			var err error
+			if ai.APIKey, err = fn(ai.APIKey); err != nil {
+				return nil, fmt.Errorf("ai_config api_key: %w", err)
+			}
+		}
+		out.AIConfig = &ai
+	}
+
+	out.Integrations = make([]*types.IntegrationConfig, len(b.Integrations))
+	for i, in := range b.Integrations {
+		copied := *in
+		var err error
+		if copied.Credentials, err = mapValues(in.Credentials); err != nil {
+			return nil, fmt.Errorf("integration %s: %w", in.ID, err)
+		}
+		out.Integrations[i] = &copied
+	}
+
+	out.Domains = make([]*types.DomainConfig, len(b.Domains))
+	for i, d := range b.Domains {
+		copied := *d
+		var err error
+		if copied.AuthConfig, err = mapValues(d.AuthConfig); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", d.ID, err)
+		}
+		out.Domains[i] = &copied
+	}
+	return &out, nil
+}
+
+// aead создает шифр по параметрам и парольной фразе
+func (e *BackupEncryption) aead(passphrase string) (cipher.AEAD, error) {
+	if e.Algorithm != backupCipher || e.KDF != backupKDF {
+		return nil, fmt.Errorf("unsupported backup encryption %s/%s", e.Algorithm, e.KDF)
+	}
+	if e.Iterations <= 0 || e.Iterations > maxBackupKDFIterations {
+		return nil, fmt.Errorf("invalid backup encryption iterations %d", e.Iterations)
+	}
+	salt, err := base64.StdEncoding.DecodeString(e.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup encryption salt: %w", err)
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, e.Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealValue шифрует значение; результат имеет вид "enc:<base64(nonce|ciphertext)>"
+func sealValue(aead cipher.AEAD, value string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return backupEncryptedValue + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openValue расшифровывает значение, созданное sealValue
+func openValue(aead cipher.AEAD, value string) (string, error) {
+	if !strings.HasPrefix(value, backupEncryptedValue) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, backupEncryptedValue))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrBackupPassphrase
+	}
+	return string(plain), nil
+}
+
+// WriteBackupArchive записывает резервную копию в архив tar.gz.
+// Архив содержит manifest.json с версией формата и SHA-256 каждого файла.
+func WriteBackupArchive(w io.Writer, b *AdminBackup) error {
+	sections := []struct {
+		name  string
+		value interface{}
+	}{
+		{backupAIConfigFile, b.AIConfig},
+		{backupIntegrationsFile, b.Integrations},
+		{backupDomainsFile, b.Domains},
+		{backupPromptsFile, b.Prompts},
+		{backupFrontendFile, b.FrontendConfigs},
+	}
+
+	manifest := backupManifest{
+		Format:        backupFormat,
+		Version:       b.Version,
+		CreatedAt:     b.CreatedAt,
+		ServerVersion: b.ServerVersion,
+		Encryption:    b.Encryption,
+		Files:         make(map[string]backupFile, len(sections)),
+	}
+	if manifest.Version == 0 {
+		manifest.Version = BackupFormatVersion
+	}
+
+	files := make(map[string][]byte, len(sections)+1)
+	for _, s := range sections {
+		data, err := json.MarshalIndent(s.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", s.name, err)
+		}
+		sum := sha256.Sum256(data)
+		files[s.name] = data
+		manifest.Files[s.name] = backupFile{SHA256: hex.EncodeToString(sum[:]), Size: len(data)}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := b.CreatedAt
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	// Манифест записывается первым, чтобы формат определялся до чтения данных
+	if err := write(backupManifestFile, data); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	for _, s := range sections {
+		if err := write(s.name, files[s.name]); err != nil {
+			return fmt.Errorf("failed to write backup archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// ReadBackupArchive читает архив, созданный WriteBackupArchive, и проверяет
+// версию формата и контрольные суммы файлов
+func ReadBackupArchive(r io.Reader) (*AdminBackup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return nil, fmt.Errorf("failed to read backup archive: %w", err)
+		}
+		files[hdr.Name] = buf.Bytes()
+	}
+
+	data, ok := files[backupManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("backup archive has no %s", backupManifestFile)
+	}
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", backupManifestFile, err)
+	}
+	if manifest.Format != backupFormat {
+		return nil, fmt.Errorf("not a backup archive: format %q", manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	backup := &AdminBackup{
+		Version:       manifest.Version,
+		CreatedAt:     manifest.CreatedAt,
+		ServerVersion: manifest.ServerVersion,
+		Encryption:    manifest.Encryption,
+	}
+	targets := map[string]interface{}{
+		backupAIConfigFile:     &backup.AIConfig,
+		backupIntegrationsFile: &backup.Integrations,
+		backupDomainsFile:      &backup.Domains,
+		backupPromptsFile:      &backup.Prompts,
+		backupFrontendFile:     &backup.FrontendConfigs,
+	}
+	for name, target := range targets {
+		expected, ok := manifest.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("backup manifest has no entry for %s", name)
+		}
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("backup archive has no %s", name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != expected.SHA256 || len(data) != expected.Size {
+			return nil, fmt.Errorf("%w: %s", ErrBackupChecksum, name)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	return backup, nil
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
 )
@@ -57,10 +59,55 @@ func (c *Client) ExecuteBatch(ctx context.Context, req *types.BatchRequest) (*ty
 	return &result.Data, nil
 }
 
+// GetBatchStatus получает статус выполнения batch операции по ID
+func (c *Client) GetBatchStatus(ctx context.Context, batchID string) (*types.BatchResponse, error) {
+	path := fmt.Sprintf("%s/%s/status", PathAPIV1Batch, batchID)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data types.BatchResponse `json:"data"`
+	}
+
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Data, nil
+}
+
+// CancelBatch отменяет выполняющуюся batch операцию
+func (c *Client) CancelBatch(ctx context.Context, batchID string) (*types.CancelBatchResponse, error) {
+	path := fmt.Sprintf("%s/%s/cancel", PathAPIV1Batch, batchID)
+	resp, err := c.doRequest(ctx, "POST", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data types.CancelBatchResponse `json:"data"`
+	}
+
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Data, nil
+}
+
 // BatchBuilder помогает строить batch запросы согласно протоколу v2.0.0
 type BatchBuilder struct {
 	requests     []*types.ExecuteTemplateRequest
 	batchOptions *types.ExecuteOptions
+	maxBatchSize int // максимальный размер чанка (0 = без разбиения)
+	concurrency  int // количество одновременно отправляемых чанков
+	mode         BatchMode
+
+	serverUnsupported atomic.Bool // сервер не поддерживает batch (режим BatchModeAuto)
 }
 
 // NewBatchBuilder создает новый BatchBuilder
@@ -90,8 +137,38 @@ func (b *BatchBuilder) Build() *types.BatchRequest {
 	}
 }
 
-// Execute выполняет batch через клиент
+// SetMaxBatchSize устанавливает максимальное количество запросов в одном batch.
+// Большие batch разбиваются на чанки и отправляются отдельными запросами.
+func (b *BatchBuilder) SetMaxBatchSize(size int) *BatchBuilder {
+	b.maxBatchSize = size
+	return b
+}
+
+// SetConcurrency устанавливает количество чанков, отправляемых одновременно
+// (по умолчанию DefaultBatchConcurrency)
+func (b *BatchBuilder) SetConcurrency(n int) *BatchBuilder {
+	b.concurrency = n
+	return b
+}
+
+// SetMode устанавливает режим выполнения batch (по умолчанию BatchModeServer)
+func (b *BatchBuilder) SetMode(mode BatchMode) *BatchBuilder {
+	b.mode = mode
+	return b
+}
+
+// Execute выполняет batch через клиент.
+// Если задан SetMaxBatchSize, batch разбивается на чанки, а ответы объединяются
+// в один BatchResponse. В режимах BatchModeLocal и BatchModeAuto запросы могут
+// выполняться по отдельности через ExecuteTemplate (см. BatchMode). Ошибка возвращается, только если не удалось отправить
+// ни один чанк; результаты по отдельным запросам доступны через Run.
 func (b *BatchBuilder) Execute(ctx context.Context, client *Client) (*types.BatchResponse, error) {
-	req := b.Build()
-	return client.ExecuteBatch(ctx, req)
+	result, err := b.Run(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.transportError(); err != nil {
+		return nil, err
+	}
+	return result.Response, nil
 }
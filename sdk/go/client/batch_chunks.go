@@ -0,0 +1,391 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultBatchConcurrency количество одновременно отправляемых чанков по умолчанию
+const DefaultBatchConcurrency = 4
+
+var (
+	// ErrBatchItemMissing возвращается, если в ответе batch нет ответа на запрос
+	ErrBatchItemMissing = errors.New("batch response missing for request")
+	// ErrBatchItemFailed возвращается, если сервер выполнил запрос со статусом failed
+	ErrBatchItemFailed = errors.New("batch request failed")
+)
+
+// BatchItemResult результат выполнения одного запроса из batch
+type BatchItemResult struct {
+	Index    int                            // индекс запроса в BatchBuilder
+	Request  *types.ExecuteTemplateRequest  // исходный запрос
+	Response *types.ExecuteTemplateResponse // ответ сервера (nil, если чанк не выполнен)
+	Err      error                          // ошибка выполнения запроса
+}
+
+// OK возвращает true, если запрос выполнен успешно
+func (r BatchItemResult) OK() bool {
+	return r.Err == nil
+}
+
+// BatchResult результат выполнения batch с разбивкой по запросам
+type BatchResult struct {
+	Response *types.BatchResponse // объединенный ответ (Responses в порядке запросов)
+	Items    []BatchItemResult    // результаты по каждому запросу
+}
+
+// Failed возвращает неуспешные запросы
+func (r *BatchResult) Failed() []BatchItemResult {
+	var failed []BatchItemResult
+	for _, item := range r.Items {
+		if !item.OK() {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// FailedRequests возвращает запросы, которые нужно повторить
+func (r *BatchResult) FailedRequests() []*types.ExecuteTemplateRequest {
+	var reqs []*types.ExecuteTemplateRequest
+	for _, item := range r.Failed() {
+		reqs = append(reqs, item.Request)
+	}
+	return reqs
+}
+
+// Err возвращает *BatchError, если хотя бы один запрос завершился ошибкой
+func (r *BatchResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BatchError{Failed: failed, Total: len(r.Items)}
+}
+
+// transportError возвращает ошибку, если ни один чанк не получил ответа сервера
+func (r *BatchResult) transportError() error {
+	if len(r.Items) == 0 {
+		return nil
+	}
+	for _, item := range r.Items {
+		if item.Response != nil || item.Err == nil {
+			return nil
+		}
+	}
+	return r.Items[0].Err
+}
+
+// BatchError описывает частичный сбой batch
+type BatchError struct {
+	Failed []BatchItemResult
+	Total  int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch: %d of %d requests failed: %v", len(e.Failed), e.Total, e.Failed[0].Err)
+}
+
+// Unwrap возвращает ошибки отдельных запросов для errors.Is/errors.As
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, item := range e.Failed {
+		errs = append(errs, item.Err)
+	}
+	return errs
+}
+
+// Run выполняет batch, разбивая его на чанки по SetMaxBatchSize и отправляя
+// до SetConcurrency чанков одновременно. Ответы сопоставляются с запросами
+// по request_id метаданных (или по позиции, если сервер его не вернул).
+//
+// Пример использования:
+//
+//	result, err := client.NewBatchBuilder().
+//		SetMaxBatchSize(50).
+//		SetConcurrency(2).
+//		AddRequest(req1).
+//		AddRequest(req2).
+//		Run(ctx, nexus)
+//	if err != nil {
+//		return err
+//	}
+//	if result.Err() != nil {
+//		result, err = builder.RetryFailed(ctx, nexus, result)
+//	}
+func (b *BatchBuilder) Run(ctx context.Context, client *Client) (*BatchResult, error) {
+	items := make([]BatchItemResult, len(b.requests))
+	for i, req := range b.requests {
+		if req == nil {
+			return nil, fmt.Errorf("batch request %d is nil", i)
+		}
+		items[i] = BatchItemResult{Index: i, Request: req}
+	}
+	return b.run(ctx, client, items)
+}
+
+// RetryFailed повторно отправляет только неуспешные запросы предыдущего результата
+// и возвращает обновленный результат
+func (b *BatchBuilder) RetryFailed(ctx context.Context, client *Client, prev *BatchResult) (*BatchResult, error) {
+	var retry []BatchItemResult
+	for _, item := range prev.Items {
+		if !item.OK() {
+			retry = append(retry, BatchItemResult{Index: item.Index, Request: item.Request})
+		}
+	}
+	if len(retry) == 0 {
+		return prev, nil
+	}
+
+	retried, err := b.run(ctx, client, retry)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]BatchItemResult, len(prev.Items))
+	copy(items, prev.Items)
+	pos := make(map[int]int, len(items))
+	for i, item := range items {
+		pos[item.Index] = i
+	}
+	for _, item := range retried.Items {
+		items[pos[item.Index]] = item
+	}
+
+	result := &BatchResult{Items: items}
+	result.Response = mergeBatchResponses(prev.Response.BatchID, items, []*types.BatchMetadata{prev.Response.BatchMetadata, retried.Response.BatchMetadata}, 0)
+	result.Response.BatchMetadata.TotalProcessingTimeMS = prev.Response.BatchMetadata.TotalProcessingTimeMS + retried.Response.BatchMetadata.TotalProcessingTimeMS
+	return result, nil
+}
+
+// run отправляет запросы items чанками и заполняет Response/Err
+func (b *BatchBuilder) run(ctx context.Context, client *Client, items []BatchItemResult) (*BatchResult, error) {
+	start := time.Now()
+	chunkSize := b.maxBatchSize
+	if b.mode == BatchModeLocal {
+		// Локально запросы выполняются пулом воркеров, разбиение не нужно
+		chunkSize = 0
+	}
+	chunks := chunkItems(items, chunkSize)
+
+	concurrency := b.concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	responses := make([]*types.BatchResponse, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			failChunk(chunk, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, chunk []BatchItemResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			responses[i] = b.sendChunk(ctx, client, chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	var ids []string
+	var metas []*types.BatchMetadata
+	for _, resp := range responses {
+		if resp != nil {
+			ids = append(ids, resp.BatchID)
+			metas = append(metas, resp.BatchMetadata)
+		}
+	}
+
+	result := &BatchResult{Items: items}
+	result.Response = mergeBatchResponses(strings.Join(ids, ","), items, metas, time.Since(start))
+
+	// Один чанк: сохраняем метаданные сервера без изменений
+	if len(chunks) == 1 && responses[0] != nil {
+		if responses[0].BatchMetadata != nil {
+			result.Response.BatchMetadata = responses[0].BatchMetadata
+		}
+		result.Response.ResponseMetadata = responses[0].ResponseMetadata
+	}
+	return result, nil
+}
+
+// sendChunk выполняет один чанк согласно режиму builder
+func (b *BatchBuilder) sendChunk(ctx context.Context, client *Client, chunk []BatchItemResult) *types.BatchResponse {
+	switch {
+	case b.mode == BatchModeLocal:
+		return b.executeLocal(ctx, client, chunk)
+	case b.mode == BatchModeAuto && b.serverUnsupported.Load():
+		return b.executeLocal(ctx, client, chunk)
+	}
+
+	resp, err := b.sendServerChunk(ctx, client, chunk)
+	if err != nil && b.mode == BatchModeAuto && isBatchUnsupported(err) {
+		b.serverUnsupported.Store(true)
+		client.logger.Warn("Batch endpoint unavailable, falling back to local execution",
+			Field{Key: "error", Value: err.Error()},
+		)
+		for i := range chunk {
+			chunk[i].Err = nil
+		}
+		return b.executeLocal(ctx, client, chunk)
+	}
+	return resp
+}
+
+// sendServerChunk отправляет чанк на сервер и сопоставляет ответы с запросами.
+// Запросы вызывающего не изменяются: метаданные добавляются в копии.
+func (b *BatchBuilder) sendServerChunk(ctx context.Context, client *Client, chunk []BatchItemResult) (*types.BatchResponse, error) {
+	req := &types.BatchRequest{
+		Requests:     make([]*types.ExecuteTemplateRequest, len(chunk)),
+		BatchOptions: b.batchOptions,
+	}
+	for i := range chunk {
+		sent := *chunk[i].Request
+		if sent.Metadata == nil {
+			sent.Metadata = client.createRequestMetadata()
+		}
+		req.Requests[i] = &sent
+	}
+
+	resp, err := client.ExecuteBatch(ctx, req)
+	if err != nil {
+		failChunk(chunk, err)
+		return nil, err
+	}
+
+	matchResponses(chunk, req.Requests, resp.Responses)
+	return resp, nil
+}
+
+// matchResponses сопоставляет ответы с запросами чанка (sent - отправленные
+// копии запросов): сначала по request_id, затем оставшиеся — по позиции
+func matchResponses(chunk []BatchItemResult, sent []*types.ExecuteTemplateRequest, responses []*types.ExecuteTemplateResponse) {
+	byRequestID := make(map[string]int, len(chunk))
+	for i, req := range sent {
+		if req.Metadata != nil && req.Metadata.RequestID != "" {
+			byRequestID[req.Metadata.RequestID] = i
+		}
+	}
+
+	matched := make([]bool, len(chunk))
+	var unmatched []*types.ExecuteTemplateResponse
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if resp.ResponseMetadata != nil {
+			if i, ok := byRequestID[resp.ResponseMetadata.RequestID]; ok && !matched[i] {
+				chunk[i].Response = resp
+				matched[i] = true
+				continue
+			}
+		}
+		unmatched = append(unmatched, resp)
+	}
+
+	for i := range chunk {
+		if matched[i] {
+			continue
+		}
+		if len(unmatched) > 0 {
+			chunk[i].Response = unmatched[0]
+			unmatched = unmatched[1:]
+			matched[i] = true
+			continue
+		}
+		chunk[i].Err = ErrBatchItemMissing
+	}
+
+	for i := range chunk {
+		checkItemStatus(&chunk[i])
+	}
+}
+
+// checkItemStatus устанавливает ошибку для ответа со статусом failed
+func checkItemStatus(item *BatchItemResult) {
+	if item.Response != nil && item.Response.Status == "failed" {
+		item.Err = fmt.Errorf("%w: execution %s", ErrBatchItemFailed, item.Response.ExecutionID)
+	}
+}
+
+// failChunk помечает все запросы чанка ошибкой
+func failChunk(chunk []BatchItemResult, err error) {
+	for i := range chunk {
+		chunk[i].Err = err
+	}
+}
+
+// chunkItems разбивает items на чанки размером не больше size (size <= 0 — один чанк).
+// Чанки ссылаются на тот же массив, поэтому результаты записываются в items.
+func chunkItems(items []BatchItemResult, size int) [][]BatchItemResult {
+	if len(items) == 0 {
+		return nil
+	}
+	if size <= 0 || size >= len(items) {
+		return [][]BatchItemResult{items}
+	}
+	var chunks [][]BatchItemResult
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		chunks = append(chunks, items[start:end:end])
+	}
+	return chunks
+}
+
+// mergeBatchResponses собирает BatchResponse из результатов по запросам и
+// метаданных чанков. Счетчики SuccessfulRequests/FailedRequests считаются по
+// запросам, поэтому запросы чанков без ответа сервера учитываются как неуспешные.
+// Время обработки — фактическое время выполнения всех чанков (elapsed),
+// либо максимум по чанкам, если elapsed не задан.
+func mergeBatchResponses(batchID string, items []BatchItemResult, metas []*types.BatchMetadata, elapsed time.Duration) *types.BatchResponse {
+	merged := &types.BatchMetadata{TotalRequests: int32(len(items))}
+	for _, m := range metas {
+		if m == nil {
+			continue
+		}
+		if merged.StartedAt == 0 || (m.StartedAt != 0 && m.StartedAt < merged.StartedAt) {
+			merged.StartedAt = m.StartedAt
+		}
+		if m.CompletedAt > merged.CompletedAt {
+			merged.CompletedAt = m.CompletedAt
+		}
+		if m.TotalProcessingTimeMS > merged.TotalProcessingTimeMS {
+			merged.TotalProcessingTimeMS = m.TotalProcessingTimeMS
+		}
+	}
+	if elapsed > 0 {
+		merged.TotalProcessingTimeMS = int32(elapsed.Milliseconds())
+	}
+
+	responses := make([]*types.ExecuteTemplateResponse, len(items))
+	for i, item := range items {
+		responses[i] = item.Response
+		if item.OK() {
+			merged.SuccessfulRequests++
+		} else {
+			merged.FailedRequests++
+		}
+	}
+
+	return &types.BatchResponse{
+		BatchID:       batchID,
+		Responses:     responses,
+		BatchMetadata: merged,
+	}
+}
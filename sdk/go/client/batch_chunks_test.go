@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// newBatchServer возвращает сервер, отвечающий на batch в обратном порядке
+// (ответы сопоставляются по request_id). Запросы "fail" выполняются со статусом
+// failed, чанки с запросом "reject" отклоняются, пока установлен reject.
+func newBatchServer(t *testing.T, calls *int32, reject *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		var req types.BatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		for _, item := range req.Requests {
+			if item.Query == "reject" && reject.Load() {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"code": "VALIDATION_FAILED", "type": "VALIDATION_ERROR", "message": "rejected"}}`))
+				return
+			}
+		}
+
+		resp := types.BatchResponse{
+			BatchID:       fmt.Sprintf("batch-%d", atomic.LoadInt32(calls)),
+			BatchMetadata: &types.BatchMetadata{TotalRequests: int32(len(req.Requests)), StartedAt: 100, CompletedAt: 101},
+		}
+		for i := len(req.Requests) - 1; i >= 0; i-- {
+			item := req.Requests[i]
+			status := "completed"
+			if item.Query == "fail" {
+				status = "failed"
+			}
+			resp.Responses = append(resp.Responses, &types.ExecuteTemplateResponse{
+				ExecutionID:      "exec-" + item.Query,
+				Status:           status,
+				ResponseMetadata: &types.ResponseMetadata{RequestID: item.Metadata.RequestID},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": resp})
+	}))
+}
+
+func TestBatchBuilderChunking(t *testing.T) {
+	var calls int32
+	var reject atomic.Bool
+	server := newBatchServer(t, &calls, &reject)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().SetMaxBatchSize(2).SetConcurrency(2)
+	for i := 0; i < 5; i++ {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: fmt.Sprintf("q%d", i)})
+	}
+
+	result, err := builder.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 chunk requests, got %d", calls)
+	}
+	if result.Err() != nil {
+		t.Errorf("Expected no errors, got %v", result.Err())
+	}
+
+	for i, item := range result.Items {
+		expected := fmt.Sprintf("exec-q%d", i)
+		if item.Response == nil || item.Response.ExecutionID != expected {
+			t.Errorf("Item %d: expected %s, got %+v", i, expected, item.Response)
+		}
+		if result.Response.Responses[i] != item.Response {
+			t.Errorf("Item %d: merged response out of order", i)
+		}
+		if item.Request.Metadata != nil {
+			t.Errorf("Item %d: caller's request was modified: %+v", i, item.Request.Metadata)
+		}
+	}
+
+	meta := result.Response.BatchMetadata
+	if meta.TotalRequests != 5 || meta.SuccessfulRequests != 5 || meta.FailedRequests != 0 {
+		t.Errorf("Unexpected merged metadata: %+v", meta)
+	}
+	if meta.StartedAt != 100 || meta.CompletedAt != 101 {
+		t.Errorf("Expected merged timestamps 100-101, got %d-%d", meta.StartedAt, meta.CompletedAt)
+	}
+	if strings.Count(result.Response.BatchID, ",") != 2 {
+		t.Errorf("Expected 3 chunk batch IDs, got %q", result.Response.BatchID)
+	}
+}
+
+func TestBatchBuilderPartialFailureAndRetry(t *testing.T) {
+	var calls int32
+	var reject atomic.Bool
+	reject.Store(true)
+	server := newBatchServer(t, &calls, &reject)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().SetMaxBatchSize(2)
+	for _, q := range []string{"a", "fail", "reject", "b"} {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: q})
+	}
+
+	result, err := builder.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	failed := result.Failed()
+	if len(failed) != 3 {
+		t.Fatalf("Expected 3 failed items, got %d", len(failed))
+	}
+	if !errors.Is(result.Items[1].Err, ErrBatchItemFailed) {
+		t.Errorf("Expected ErrBatchItemFailed for item 1, got %v", result.Items[1].Err)
+	}
+	var errDetail *types.ErrorDetail
+	if !errors.As(result.Items[2].Err, &errDetail) {
+		t.Errorf("Expected ErrorDetail for rejected chunk, got %v", result.Items[2].Err)
+	}
+	var batchErr *BatchError
+	if !errors.As(result.Err(), &batchErr) || batchErr.Total != 4 {
+		t.Errorf("Expected BatchError with total 4, got %v", result.Err())
+	}
+	if result.Response.BatchMetadata.FailedRequests != 3 {
+		t.Errorf("Expected 3 failed requests in metadata, got %d", result.Response.BatchMetadata.FailedRequests)
+	}
+
+	reject.Store(false)
+	before := atomic.LoadInt32(&calls)
+	retried, err := builder.RetryFailed(context.Background(), client, result)
+	if err != nil {
+		t.Fatalf("RetryFailed failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls) - before; got != 2 {
+		t.Errorf("Expected 2 retry chunks for 3 failed items, got %d", got)
+	}
+	if len(retried.Failed()) != 1 || retried.Failed()[0].Index != 1 {
+		t.Errorf("Expected only item 1 to stay failed, got %+v", retried.Failed())
+	}
+	if retried.Items[0].Response != result.Items[0].Response {
+		t.Error("Expected successful item to be kept without retry")
+	}
+	if retried.Items[2].Response == nil || retried.Items[2].Response.ExecutionID != "exec-reject" {
+		t.Errorf("Expected rejected item to succeed on retry, got %+v", retried.Items[2].Response)
+	}
+}
+
+func TestBatchBuilderConcurrencyLimit(t *testing.T) {
+	var mu sync.Mutex
+	var active, maxActive int
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"batch_id": "b", "responses": [{"status": "completed"}]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().SetMaxBatchSize(1).SetConcurrency(2)
+	for i := 0; i < 6; i++ {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: "q"})
+	}
+
+	go func() {
+		for i := 0; i < 6; i++ {
+			release <- struct{}{}
+		}
+	}()
+
+	resp, err := builder.Execute(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if maxActive > 2 {
+		t.Errorf("Expected at most 2 concurrent chunks, got %d", maxActive)
+	}
+	if resp.BatchMetadata.SuccessfulRequests != 6 {
+		t.Errorf("Expected 6 successful requests, got %d", resp.BatchMetadata.SuccessfulRequests)
+	}
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// BatchMode определяет, как BatchBuilder выполняет batch
+type BatchMode int
+
+const (
+	// BatchModeServer отправляет batch на сервер (POST /api/v1/batch/execute)
+	BatchModeServer BatchMode = iota
+	// BatchModeLocal выполняет каждый запрос через ExecuteTemplate пулом воркеров
+	BatchModeLocal
+	// BatchModeAuto отправляет batch на сервер и переключается на локальное
+	// выполнение, если сервер не поддерживает batch endpoint
+	BatchModeAuto
+)
+
+// String возвращает название режима
+func (m BatchMode) String() string {
+	switch m {
+	case BatchModeServer:
+		return "server"
+	case BatchModeLocal:
+		return "local"
+	case BatchModeAuto:
+		return "auto"
+	default:
+		return "unknown"
+	}
+}
+
+// LocalBatchIDPrefix префикс BatchID для batch, выполненных локально
+const LocalBatchIDPrefix = "local-"
+
+// executeLocal выполняет запросы чанка через ExecuteTemplate и синтезирует BatchResponse.
+// BatchOptions.ParallelExecution = false выполняет запросы последовательно,
+// BatchOptions.TimeoutMS ограничивает время каждого запроса.
+func (b *BatchBuilder) executeLocal(ctx context.Context, client *Client, chunk []BatchItemResult) *types.BatchResponse {
+	start := time.Now()
+
+	workers := b.concurrency
+	if workers <= 0 {
+		workers = DefaultBatchConcurrency
+	}
+	var timeout time.Duration
+	if b.batchOptions != nil {
+		if !b.batchOptions.ParallelExecution {
+			workers = 1
+		}
+		if b.batchOptions.TimeoutMS > 0 {
+			timeout = time.Duration(b.batchOptions.TimeoutMS) * time.Millisecond
+		}
+	}
+	if workers > len(chunk) {
+		workers = len(chunk)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				b.executeLocalItem(ctx, client, &chunk[i], timeout)
+			}
+		}()
+	}
+
+	for i := range chunk {
+		if ctx.Err() != nil {
+			chunk[i].Err = ctx.Err()
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	completed := time.Now()
+	metadata := &types.BatchMetadata{
+		TotalRequests:         int32(len(chunk)),
+		StartedAt:             start.Unix(),
+		CompletedAt:           completed.Unix(),
+		TotalProcessingTimeMS: int32(completed.Sub(start).Milliseconds()),
+	}
+	responses := make([]*types.ExecuteTemplateResponse, len(chunk))
+	for i := range chunk {
+		responses[i] = chunk[i].Response
+		if chunk[i].OK() {
+			metadata.SuccessfulRequests++
+		} else {
+			metadata.FailedRequests++
+		}
+	}
+
+	return &types.BatchResponse{
+		BatchID:       LocalBatchIDPrefix + uuid.New().String(),
+		Responses:     responses,
+		BatchMetadata: metadata,
+	}
+}
+
+// executeLocalItem выполняет один запрос batch
+func (b *BatchBuilder) executeLocalItem(ctx context.Context, client *Client, item *BatchItemResult, timeout time.Duration) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Опции batch применяются к копии, запрос вызывающего не изменяется
+	req := item.Request
+	if req.Options == nil && b.batchOptions != nil {
+		withOptions := *req
+		options := *b.batchOptions
+		withOptions.Options = &options
+		req = &withOptions
+	}
+
+	resp, err := client.ExecuteTemplate(ctx, req)
+	if err != nil {
+		item.Err = err
+		return
+	}
+	item.Response = resp
+	checkItemStatus(item)
+}
+
+// isBatchUnsupported проверяет, означает ли ошибка отсутствие batch endpoint на сервере
+func isBatchUnsupported(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return true
+		}
+		return false
+	}
+
+	var detail *types.ErrorDetail
+	if errors.As(err, &detail) {
+		switch detail.Code {
+		case "NOT_FOUND", "ENDPOINT_NOT_FOUND", "METHOD_NOT_ALLOWED", "NOT_IMPLEMENTED", "BATCH_NOT_SUPPORTED":
+			return true
+		}
+		return detail.IsNotFoundError()
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// newTemplateServer возвращает сервер без batch endpoint, выполняющий шаблоны по одному.
+// Запрос "slow" отвечает через 200 мс, "fail" — со статусом failed.
+func newTemplateServer(t *testing.T, batchCalls, active, maxActive *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/batch/execute" {
+			atomic.AddInt32(batchCalls, 1)
+			http.NotFound(w, r)
+			return
+		}
+
+		n := atomic.AddInt32(active, 1)
+		defer atomic.AddInt32(active, -1)
+		for {
+			m := atomic.LoadInt32(maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(maxActive, m, n) {
+				break
+			}
+		}
+
+		var req types.ExecuteTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		delay := 20 * time.Millisecond
+		if req.Query == "slow" {
+			delay = 200 * time.Millisecond
+		}
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		status := "completed"
+		if req.Query == "fail" {
+			status = "failed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": types.ExecuteTemplateResponse{ExecutionID: "exec-" + req.Query, Status: status},
+		})
+	}))
+}
+
+func TestBatchBuilderLocalMode(t *testing.T) {
+	var batchCalls, active, maxActive int32
+	server := newTemplateServer(t, &batchCalls, &active, &maxActive)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().
+		SetMode(BatchModeLocal).
+		SetConcurrency(3).
+		SetBatchOptions(&types.ExecuteOptions{ParallelExecution: true, TimeoutMS: 100})
+	for _, q := range []string{"a", "b", "fail", "slow", "c", "d"} {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: q})
+	}
+
+	result, err := builder.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if batchCalls != 0 {
+		t.Errorf("Expected no batch endpoint calls in local mode, got %d", batchCalls)
+	}
+	if maxActive > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", maxActive)
+	}
+	if maxActive < 2 {
+		t.Errorf("Expected parallel execution, got max %d concurrent requests", maxActive)
+	}
+
+	if !errors.Is(result.Items[2].Err, ErrBatchItemFailed) {
+		t.Errorf("Expected ErrBatchItemFailed for 'fail', got %v", result.Items[2].Err)
+	}
+	if !errors.Is(result.Items[3].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded for 'slow', got %v", result.Items[3].Err)
+	}
+	if result.Items[4].Response == nil || result.Items[4].Response.ExecutionID != "exec-c" {
+		t.Errorf("Expected exec-c for item 4, got %+v", result.Items[4].Response)
+	}
+	for i, item := range result.Items {
+		if item.Request.Options != nil {
+			t.Errorf("Item %d: caller's request was modified: %+v", i, item.Request.Options)
+		}
+	}
+
+	resp := result.Response
+	if !strings.HasPrefix(resp.BatchID, LocalBatchIDPrefix) {
+		t.Errorf("Expected local batch ID, got %s", resp.BatchID)
+	}
+	meta := resp.BatchMetadata
+	if meta.TotalRequests != 6 || meta.SuccessfulRequests != 4 || meta.FailedRequests != 2 {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+	if meta.StartedAt == 0 || meta.CompletedAt < meta.StartedAt || meta.TotalProcessingTimeMS <= 0 {
+		t.Errorf("Unexpected timings: %+v", meta)
+	}
+	if len(resp.Responses) != 6 || resp.Responses[3] != nil {
+		t.Errorf("Expected 6 responses with nil for timed out item, got %+v", resp.Responses)
+	}
+}
+
+func TestBatchBuilderLocalSequential(t *testing.T) {
+	var batchCalls, active, maxActive int32
+	server := newTemplateServer(t, &batchCalls, &active, &maxActive)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().
+		SetMode(BatchModeLocal).
+		SetBatchOptions(&types.ExecuteOptions{ParallelExecution: false})
+	for i := 0; i < 4; i++ {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: "q"})
+	}
+
+	resp, err := builder.Execute(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if maxActive != 1 {
+		t.Errorf("Expected sequential execution, got max %d concurrent requests", maxActive)
+	}
+	if resp.BatchMetadata.SuccessfulRequests != 4 {
+		t.Errorf("Expected 4 successful requests, got %d", resp.BatchMetadata.SuccessfulRequests)
+	}
+}
+
+func TestBatchBuilderAutoFallback(t *testing.T) {
+	var batchCalls, active, maxActive int32
+	server := newTemplateServer(t, &batchCalls, &active, &maxActive)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	builder := NewBatchBuilder().SetMode(BatchModeAuto).SetMaxBatchSize(2).SetConcurrency(1)
+	for _, q := range []string{"a", "b", "c", "d", "e"} {
+		builder.AddRequest(&types.ExecuteTemplateRequest{Query: q})
+	}
+
+	resp, err := builder.Execute(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if batchCalls != 1 {
+		t.Errorf("Expected single batch endpoint probe, got %d", batchCalls)
+	}
+	if resp.BatchMetadata.TotalRequests != 5 || resp.BatchMetadata.SuccessfulRequests != 5 {
+		t.Errorf("Unexpected metadata: %+v", resp.BatchMetadata)
+	}
+	for i, r := range resp.Responses {
+		if r == nil || r.ExecutionID != "exec-"+string(rune('a'+i)) {
+			t.Errorf("Response %d out of order: %+v", i, r)
+		}
+	}
+
+	// Без режима Auto ошибка сервера возвращается как есть
+	_, err = NewBatchBuilder().
+		AddRequest(&types.ExecuteTemplateRequest{Query: "a"}).
+		Execute(context.Background(), client)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected HTTPError 404 in server mode, got %v", err)
+	}
+}
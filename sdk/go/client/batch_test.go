@@ -21,78 +21,74 @@ func TestExecuteBatch(t *testing.T) {
 			t.Fatalf("Failed to decode request: %v", err)
 		}
 
-		// Проверяем, что запрос содержит операции
-		if len(req.Operations) != 2 {
-			t.Errorf("Expected 2 operations, got %d", len(req.Operations))
+		// Проверяем, что запрос содержит запросы и опции
+		if len(req.Requests) != 2 {
+			t.Errorf("Expected 2 requests, got %d", len(req.Requests))
+		}
+		if req.BatchOptions == nil || !req.BatchOptions.ParallelExecution {
+			t.Error("Expected parallel batch options")
 		}
 
 		// Возвращаем успешный ответ
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		response := types.BatchResponse{
-			Results: []types.BatchResult{
-				{
-					OperationID:   0,
-					Success:       true,
-					Data:          map[string]interface{}{"result": "success"},
-					ExecutionTimeMS: 100,
-				},
-				{
-					OperationID:   1,
-					Success:       true,
-					Data:          map[string]interface{}{"result": "success"},
-					ExecutionTimeMS: 150,
-				},
+			BatchID: "batch-123",
+			Responses: []*types.ExecuteTemplateResponse{
+				{ExecutionID: "exec-1", Status: "completed", ProcessingTimeMS: 100},
+				{ExecutionID: "exec-2", Status: "completed", ProcessingTimeMS: 150},
 			},
-			Total:     2,
-			Successful: 2,
-			Failed:    0,
-			TotalTimeMS: 250,
-			ResponseMetadata: &types.ResponseMetadata{
-				RequestID:      "req-123",
-				ProtocolVersion: "2.0.0",
-				ServerVersion:   "2.0.0",
-				Timestamp:       1640995200,
-				ProcessingTimeMS: 250,
+			BatchMetadata: &types.BatchMetadata{
+				TotalRequests:         2,
+				SuccessfulRequests:    2,
+				FailedRequests:        0,
+				TotalProcessingTimeMS: 250,
 			},
 		}
-		json.NewEncoder(w).Encode(map[string]types.BatchResponse{"data": response})
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": response,
+			"metadata": &types.ResponseMetadata{
+				RequestID:        "req-123",
+				ProtocolVersion:  "2.0.0",
+				ServerVersion:    "2.0.0",
+				Timestamp:        1640995200,
+				ProcessingTimeMS: 250,
+			},
+		})
 	}))
 	defer server.Close()
 
 	client := NewClient(Config{BaseURL: server.URL})
 
-	// Создаем batch с двумя операциями
+	// Создаем batch с двумя запросами
 	batch := NewBatchBuilder().
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{
-			Query: "test query 1",
-		}).
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{
-			Query: "test query 2",
-		}).
-		SetOptions(&types.BatchOptions{
-			Parallel: true,
-		})
+		AddRequest(&types.ExecuteTemplateRequest{Query: "test query 1"}).
+		AddRequest(&types.ExecuteTemplateRequest{Query: "test query 2"}).
+		SetBatchOptions(&types.ExecuteOptions{ParallelExecution: true})
 
 	result, err := batch.Execute(context.Background(), client)
 	if err != nil {
 		t.Fatalf("ExecuteBatch failed: %v", err)
 	}
 
-	if result.Total != 2 {
-		t.Errorf("Expected total 2, got %d", result.Total)
+	if result.BatchID != "batch-123" {
+		t.Errorf("Expected batch ID batch-123, got %s", result.BatchID)
+	}
+
+	if len(result.Responses) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(result.Responses))
 	}
 
-	if result.Successful != 2 {
-		t.Errorf("Expected successful 2, got %d", result.Successful)
+	if result.BatchMetadata.SuccessfulRequests != 2 {
+		t.Errorf("Expected successful 2, got %d", result.BatchMetadata.SuccessfulRequests)
 	}
 
-	if result.Failed != 0 {
-		t.Errorf("Expected failed 0, got %d", result.Failed)
+	if result.BatchMetadata.TotalProcessingTimeMS != 250 {
+		t.Errorf("Expected total time 250ms, got %d", result.BatchMetadata.TotalProcessingTimeMS)
 	}
 
-	if result.TotalTimeMS != 250 {
-		t.Errorf("Expected total time 250ms, got %d", result.TotalTimeMS)
+	if result.ResponseMetadata == nil || result.ResponseMetadata.RequestID != "req-123" {
+		t.Errorf("Expected response metadata, got %+v", result.ResponseMetadata)
 	}
 }
 
@@ -101,24 +97,16 @@ func TestExecuteBatch_PartialFailure(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		response := types.BatchResponse{
-			Results: []types.BatchResult{
-				{
-					OperationID:   0,
-					Success:       true,
-					Data:          map[string]interface{}{"result": "success"},
-					ExecutionTimeMS: 100,
-				},
-				{
-					OperationID:   1,
-					Success:       false,
-					Error:         &types.ErrorDetail{Code: "VALIDATION_FAILED", Type: "VALIDATION_ERROR", Message: "Invalid query"},
-					ExecutionTimeMS: 50,
-				},
+			BatchID: "batch-456",
+			Responses: []*types.ExecuteTemplateResponse{
+				{ExecutionID: "exec-1", Status: "completed"},
+				{ExecutionID: "exec-2", Status: "failed"},
+			},
+			BatchMetadata: &types.BatchMetadata{
+				TotalRequests:      2,
+				SuccessfulRequests: 1,
+				FailedRequests:     1,
 			},
-			Total:     2,
-			Successful: 1,
-			Failed:    1,
-			TotalTimeMS: 150,
 		}
 		json.NewEncoder(w).Encode(map[string]types.BatchResponse{"data": response})
 	}))
@@ -127,52 +115,48 @@ func TestExecuteBatch_PartialFailure(t *testing.T) {
 	client := NewClient(Config{BaseURL: server.URL})
 
 	batch := NewBatchBuilder().
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{Query: "valid query"}).
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{Query: "invalid query"})
+		AddRequest(&types.ExecuteTemplateRequest{Query: "valid query"}).
+		AddRequest(&types.ExecuteTemplateRequest{Query: "invalid query"})
 
 	result, err := batch.Execute(context.Background(), client)
 	if err != nil {
 		t.Fatalf("ExecuteBatch failed: %v", err)
 	}
 
-	if result.Successful != 1 {
-		t.Errorf("Expected successful 1, got %d", result.Successful)
-	}
-
-	if result.Failed != 1 {
-		t.Errorf("Expected failed 1, got %d", result.Failed)
+	if result.BatchMetadata.SuccessfulRequests != 1 {
+		t.Errorf("Expected successful 1, got %d", result.BatchMetadata.SuccessfulRequests)
 	}
 
-	if result.Results[1].Success {
-		t.Error("Expected second operation to fail")
+	if result.BatchMetadata.FailedRequests != 1 {
+		t.Errorf("Expected failed 1, got %d", result.BatchMetadata.FailedRequests)
 	}
 
-	if result.Results[1].Error == nil {
-		t.Error("Expected error for failed operation")
+	if result.Responses[1].Status != "failed" {
+		t.Error("Expected second request to fail")
 	}
 }
 
 func TestBatchBuilder(t *testing.T) {
 	builder := NewBatchBuilder()
 
-	// Добавляем операции
-	builder.AddOperation("execute_template", &types.ExecuteTemplateRequest{Query: "test1"})
-	builder.AddOperation("log_event", &types.LogEventRequest{EventType: "test"})
+	// Добавляем запросы
+	builder.AddRequest(&types.ExecuteTemplateRequest{Query: "test1"})
+	builder.AddRequest(&types.ExecuteTemplateRequest{Query: "test2"})
 
 	// Устанавливаем опции
-	builder.SetOptions(&types.BatchOptions{Parallel: true, StopOnError: false})
+	builder.SetBatchOptions(&types.ExecuteOptions{ParallelExecution: true, TimeoutMS: 5000})
 
 	req := builder.Build()
 
-	if len(req.Operations) != 2 {
-		t.Errorf("Expected 2 operations, got %d", len(req.Operations))
+	if len(req.Requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(req.Requests))
 	}
 
-	if req.Options == nil || !req.Options.Parallel {
+	if req.BatchOptions == nil || !req.BatchOptions.ParallelExecution {
 		t.Error("Expected parallel execution option to be set")
 	}
 
-	if req.Options.StopOnError {
-		t.Error("Expected StopOnError to be false")
+	if req.BatchOptions.TimeoutMS != 5000 {
+		t.Errorf("Expected timeout 5000, got %d", req.BatchOptions.TimeoutMS)
 	}
 }
@@ -0,0 +1,307 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Режимы кэширования (значения x-cache-control)
+const (
+	CacheModeNoCache      = "no-cache"      // всегда обращаться к серверу, кэш не читается
+	CacheModeCacheOnly    = "cache-only"    // только кэш, без обращения к серверу
+	CacheModeCacheFirst   = "cache-first"   // кэш, если запись свежая, иначе сервер
+	CacheModeNetworkFirst = "network-first" // сервер, при сетевой ошибке - запись из кэша
+)
+
+const (
+	// DefaultCacheTTL TTL записи кэша по умолчанию
+	DefaultCacheTTL = 5 * time.Minute
+	// DefaultCacheCapacity емкость in-memory кэша по умолчанию
+	DefaultCacheCapacity = 1000
+)
+
+// ErrCacheMiss возвращается в режиме cache-only, если в кэше нет свежей записи
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheEntry представляет закэшированный ответ сервера
+type CacheEntry struct {
+	Key       string    `json:"key"`
+	Body      []byte    `json:"body"`           // тело ответа в формате Application Protocol
+	ETag      string    `json:"etag,omitempty"` // ETag для условных запросов (If-None-Match)
+	StoredAt  time.Time `json:"stored_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired проверяет, истек ли TTL записи
+func (e *CacheEntry) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+// response создает HTTP ответ из закэшированной записи
+func (e *CacheEntry) response() *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if e.ETag != "" {
+		header.Set("ETag", e.ETag)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(e.Body)),
+	}
+}
+
+// Cache интерфейс хранилища закэшированных ответов.
+// Реализации должны быть безопасны для конкурентного использования.
+type Cache interface {
+	// Get возвращает запись по ключу (в том числе устаревшую)
+	Get(key string) (*CacheEntry, bool)
+	// Set сохраняет запись
+	Set(key string, entry *CacheEntry)
+	// Delete удаляет запись
+	Delete(key string)
+	// Clear удаляет все записи
+	Clear()
+}
+
+// CacheConfig содержит конфигурацию локального кэша ответов
+type CacheConfig struct {
+	Store       Cache         // Хранилище (nil = NewMemoryCache(DefaultCacheCapacity))
+	DefaultTTL  time.Duration // TTL, если сервер не вернул cache_ttl и не задан x-cache-ttl (0 = DefaultCacheTTL)
+	DefaultMode string        // Режим, если не задан x-cache-control ("" = cache-first)
+}
+
+// CacheStats содержит метрики локального кэша
+type CacheStats struct {
+	Hits           int64 // ответы из кэша без обращения к серверу
+	Misses         int64 // запросы, не обслуженные кэшем: полный ответ сервера или ошибка без устаревшей записи
+	Revalidations  int64 // ответы 304 Not Modified на условный запрос
+	StaleFallbacks int64 // устаревшие записи, отданные при ошибке сервера (network-first)
+	Stores         int64 // записи, сохраненные в кэш
+}
+
+// HitRate возвращает долю ответов, обслуженных кэшем (0-1)
+func (s CacheStats) HitRate() float64 {
+	served := s.Hits + s.Revalidations + s.StaleFallbacks
+	total := served + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(served) / float64(total)
+}
+
+// responseCache связывает хранилище с настройками и метриками клиента
+type responseCache struct {
+	store       Cache
+	defaultTTL  time.Duration
+	defaultMode string
+
+	hits           atomic.Int64
+	misses         atomic.Int64
+	revalidations  atomic.Int64
+	staleFallbacks atomic.Int64
+	stores         atomic.Int64
+}
+
+// newResponseCache создает кэш клиента (nil, если кэширование не настроено)
+func newResponseCache(config *CacheConfig) *responseCache {
+	if config == nil {
+		return nil
+	}
+	rc := &responseCache{
+		store:       config.Store,
+		defaultTTL:  config.DefaultTTL,
+		defaultMode: config.DefaultMode,
+	}
+	if rc.store == nil {
+		rc.store = NewMemoryCache(DefaultCacheCapacity)
+	}
+	if rc.defaultTTL <= 0 {
+		rc.defaultTTL = DefaultCacheTTL
+	}
+	if !isValidCacheMode(rc.defaultMode) {
+		rc.defaultMode = CacheModeCacheFirst
+	}
+	return rc
+}
+
+// isValidCacheMode проверяет значение x-cache-control
+func isValidCacheMode(mode string) bool {
+	switch mode {
+	case CacheModeNoCache, CacheModeCacheOnly, CacheModeCacheFirst, CacheModeNetworkFirst:
+		return true
+	}
+	return false
+}
+
+// CacheStats возвращает метрики локального кэша.
+// Если кэш не настроен, возвращаются нулевые значения.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Hits:           c.cache.hits.Load(),
+		Misses:         c.cache.misses.Load(),
+		Revalidations:  c.cache.revalidations.Load(),
+		StaleFallbacks: c.cache.staleFallbacks.Load(),
+		Stores:         c.cache.stores.Load(),
+	}
+}
+
+// InvalidateCache удаляет из кэша ответ на GET запрос по указанному пути
+func (c *Client) InvalidateCache(path string) {
+	if c.cache != nil {
+		c.cache.store.Delete(c.cacheKey(http.MethodGet, path))
+	}
+}
+
+// ClearCache удаляет все записи локального кэша
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.store.Clear()
+	}
+}
+
+// cacheMode возвращает режим кэширования для текущего запроса
+func (c *Client) cacheMode() string {
+	if mode := c.customHeaders["x-cache-control"]; isValidCacheMode(mode) {
+		return mode
+	}
+	return c.cache.defaultMode
+}
+
+// cacheKey строит ключ кэша. Ключ учитывает x-cache-key и токен,
+// чтобы ответы разных пользователей не смешивались.
+func (c *Client) cacheKey(method, path string) string {
+	key := method + " " + path
+	if custom := c.customHeaders["x-cache-key"]; custom != "" {
+		key = custom + "|" + key
+	}
+	if c.token != "" {
+		sum := sha256.Sum256([]byte(c.token))
+		key = hex.EncodeToString(sum[:8]) + "|" + key
+	}
+	return key
+}
+
+// cacheTTL определяет TTL ответа: cache_ttl из ResponseMetadata,
+// затем x-cache-ttl клиента, затем TTL по умолчанию
+func (c *Client) cacheTTL(body []byte) time.Duration {
+	var envelope struct {
+		Metadata *types.ResponseMetadata `json:"metadata"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && envelope.Metadata != nil &&
+		envelope.Metadata.CacheInfo != nil && envelope.Metadata.CacheInfo.CacheTTL > 0 {
+		return time.Duration(envelope.Metadata.CacheInfo.CacheTTL) * time.Second
+	}
+	if raw := c.customHeaders["x-cache-ttl"]; raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return c.cache.defaultTTL
+}
+
+// doCachedGet выполняет идемпотентный GET запрос через локальный кэш.
+// Если кэш не настроен, запрос выполняется напрямую через doRequest.
+func (c *Client) doCachedGet(ctx context.Context, path string) (*http.Response, error) {
+	if c.cache == nil {
+		return c.doRequest(ctx, http.MethodGet, path, nil)
+	}
+
+	mode := c.cacheMode()
+	key := c.cacheKey(http.MethodGet, path)
+	now := time.Now()
+
+	var entry *CacheEntry
+	found := false
+	if mode != CacheModeNoCache {
+		entry, found = c.cache.store.Get(key)
+	}
+
+	if found && !entry.Expired(now) && (mode == CacheModeCacheFirst || mode == CacheModeCacheOnly) {
+		c.cache.hits.Add(1)
+		c.logger.Debug("Cache hit", Field{Key: "path", Value: path}, Field{Key: "mode", Value: mode})
+		return entry.response(), nil
+	}
+	if mode == CacheModeCacheOnly {
+		c.cache.misses.Add(1)
+		return nil, fmt.Errorf("%s: %w", path, ErrCacheMiss)
+	}
+
+	var headers http.Header
+	if found && entry.ETag != "" {
+		headers = http.Header{"If-None-Match": []string{entry.ETag}}
+	}
+
+	resp, err := c.doRequestWithHeaders(ctx, http.MethodGet, path, nil, headers)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if mode == CacheModeNetworkFirst && found && ctx.Err() == nil {
+			if err == nil {
+				resp.Body.Close()
+			}
+			c.cache.staleFallbacks.Add(1)
+			c.logger.Warn("Serving cached response after network failure",
+				Field{Key: "path", Value: path},
+				Field{Key: "stored_at", Value: entry.StoredAt},
+			)
+			return entry.response(), nil
+		}
+		c.cache.misses.Add(1)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && found {
+		resp.Body.Close()
+		refreshed := *entry
+		refreshed.StoredAt = now
+		refreshed.ExpiresAt = now.Add(c.cacheTTL(entry.Body))
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			refreshed.ETag = etag
+		}
+		c.cache.store.Set(key, &refreshed)
+		c.cache.revalidations.Add(1)
+		return refreshed.response(), nil
+	}
+
+	// Далее ответ обслужен сервером, а не кэшем
+	c.cache.misses.Add(1)
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.cache.store.Set(key, &CacheEntry{
+		Key:       key,
+		Body:      body,
+		ETag:      resp.Header.Get("ETag"),
+		StoredAt:  now,
+		ExpiresAt: now.Add(c.cacheTTL(body)),
+	})
+	c.cache.stores.Add(1)
+
+	return resp, nil
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// MemoryCache реализует Cache в памяти с вытеснением по LRU
+type MemoryCache struct {
+	mu        sync.Mutex
+	capacity  int
+	order     *list.List
+	items     map[string]*list.Element
+	evictions int64
+}
+
+// memoryItem элемент LRU списка
+type memoryItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache создает in-memory LRU кэш указанной емкости.
+// Если capacity <= 0, используется DefaultCacheCapacity.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get возвращает запись и помечает ее как недавно использованную
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryItem).entry, true
+}
+
+// Set сохраняет запись, вытесняя наименее используемую при превышении емкости
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.items[key]; ok {
+		elem.Value.(*memoryItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.items[key] = m.order.PushFront(&memoryItem{key: key, entry: entry})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryItem).key)
+		m.evictions++
+	}
+}
+
+// Delete удаляет запись
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.items[key]; ok {
+		m.order.Remove(elem)
+		delete(m.items, key)
+	}
+}
+
+// Clear удаляет все записи
+func (m *MemoryCache) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.order.Init()
+	m.items = make(map[string]*list.Element)
+}
+
+// Len возвращает количество записей в кэше
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Evictions возвращает количество записей, вытесненных по LRU
+func (m *MemoryCache) Evictions() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.evictions
+}
+
+// DiskCache реализует Cache в виде JSON файлов в директории.
+// Записи переживают перезапуск приложения. Ошибки ввода-вывода
+// трактуются как промах кэша.
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewDiskCache создает дисковый кэш в указанной директории (создается при необходимости)
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// filePath возвращает путь к файлу записи
+func (d *DiskCache) filePath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get читает запись с диска
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(d.filePath(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Set атомарно записывает запись на диск (через временный файл)
+func (d *DiskCache) Set(key string, entry *CacheEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stored := *entry
+	stored.Key = key
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.filePath(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete удаляет файл записи
+func (d *DiskCache) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	os.Remove(d.filePath(key))
+}
+
+// Clear удаляет все файлы записей в директории кэша
+func (d *DiskCache) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			os.Remove(filepath.Join(d.dir, f.Name()))
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const frontendConfigBody = `{
+	"data": {"id": "cfg-1", "name": "default", "theme": "light", "active": true},
+	"metadata": {
+		"request_id": "550e8400-e29b-41d4-a716-446655440000",
+		"protocol_version": "2.0.0",
+		"server_version": "2.0.0",
+		"timestamp": 1640995200,
+		"processing_time_ms": 5,
+		"cache_info": {"cache_hit": false, "cache_key": "frontend", "cache_ttl": 60}
+	}
+}`
+
+func TestCache_CacheFirst(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(frontendConfigBody))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, CacheConfig: &CacheConfig{}})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		cfg, err := client.GetFrontendConfig(ctx)
+		if err != nil {
+			t.Fatalf("GetFrontendConfig failed: %v", err)
+		}
+		if cfg.ID != "cfg-1" {
+			t.Errorf("Expected config ID cfg-1, got %s", cfg.ID)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 server call, got %d", calls.Load())
+	}
+
+	stats := client.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Stores != 1 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestCache_ETagRevalidation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"data": {"id": "cfg-1", "name": "default"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		BaseURL:     server.URL,
+		CacheConfig: &CacheConfig{DefaultTTL: time.Millisecond},
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetFrontendConfig(ctx); err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	cfg, err := client.GetFrontendConfig(ctx)
+	if err != nil {
+		t.Fatalf("Revalidation request failed: %v", err)
+	}
+	if cfg.ID != "cfg-1" {
+		t.Errorf("Expected cached config after 304, got %+v", cfg)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 server calls, got %d", calls.Load())
+	}
+	stats := client.CacheStats()
+	if stats.Revalidations != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 revalidation and 1 miss, got %+v", stats)
+	}
+	if rate := stats.HitRate(); rate != 0.5 {
+		t.Errorf("Expected hit rate 0.5 (initial fetch + revalidation), got %v", rate)
+	}
+}
+
+func TestCache_NetworkFirstFallback(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": {"execution_id": "exec-1", "status": "completed"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		BaseURL:     server.URL,
+		RetryConfig: &RetryConfig{MaxRetries: 0},
+		CacheConfig: &CacheConfig{},
+	})
+	client.SetCacheControl(CacheModeNetworkFirst)
+	ctx := context.Background()
+
+	if _, err := client.GetExecutionStatus(ctx, "exec-1"); err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+
+	fail.Store(true)
+	result, err := client.GetExecutionStatus(ctx, "exec-1")
+	if err != nil {
+		t.Fatalf("Expected fallback to cache, got error: %v", err)
+	}
+	if result.Status != "completed" {
+		t.Errorf("Expected cached status completed, got %s", result.Status)
+	}
+	if client.CacheStats().StaleFallbacks != 1 {
+		t.Errorf("Expected 1 stale fallback, got %d", client.CacheStats().StaleFallbacks)
+	}
+}
+
+func TestCache_CacheOnlyMiss(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server must not be called in cache-only mode")
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, CacheConfig: &CacheConfig{}})
+	client.SetCacheControl(CacheModeCacheOnly)
+
+	_, err := client.GetFrontendConfig(context.Background())
+	if !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Expected ErrCacheMiss, got %v", err)
+	}
+}
+
+func TestMemoryCache_LRUEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CacheEntry{Key: "a"})
+	cache.Set("b", &CacheEntry{Key: "b"})
+	cache.Get("a")
+	cache.Set("c", &CacheEntry{Key: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected entry a to be kept")
+	}
+	if cache.Len() != 2 || cache.Evictions() != 1 {
+		t.Errorf("Expected len 2 and 1 eviction, got %d and %d", cache.Len(), cache.Evictions())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+
+	expires := time.Now().Add(time.Minute).Truncate(time.Second)
+	cache.Set("GET /health", &CacheEntry{Body: []byte(`{"status":"healthy"}`), ETag: `"x"`, ExpiresAt: expires})
+
+	entry, ok := cache.Get("GET /health")
+	if !ok {
+		t.Fatal("Expected entry to be found on disk")
+	}
+	if string(entry.Body) != `{"status":"healthy"}` || entry.ETag != `"x"` || !entry.ExpiresAt.Equal(expires) {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	cache.Clear()
+	if _, ok := cache.Get("GET /health"); ok {
+		t.Error("Expected cache to be empty after Clear")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
@@ -28,6 +29,9 @@ type Client struct {
 	baseURL         string
 	token           string
 	httpClient      *http.Client
+	transport       Transport
+	authenticator   Authenticator
+	endpoints       *endpointPool
 	protocolVersion string
 	clientVersion   string
 	clientID        string
@@ -37,6 +41,8 @@ type Client struct {
 	logger          Logger
 	interceptors    []Interceptor
 	validator       *Validator
+	cache           *responseCache
+	confirmAction   ConfirmFunc
 }
 
 // Config содержит конфигурацию клиента.
@@ -53,6 +59,11 @@ type Config struct {
 	RetryConfig     *RetryConfig // Конфигурация retry (nil = использовать по умолчанию)
 	Logger          Logger      // Логгер (nil = логирование отключено)
 	Validator       *Validator  // Валидатор для JSON Schema (nil = валидация отключена)
+	CacheConfig     *CacheConfig // Конфигурация локального кэша ответов (nil = кэш отключен)
+	ConfirmAction   ConfirmFunc  // Подтверждение действий с ConfirmText (nil = такие действия отклоняются)
+	Transport       *TransportConfig // Транспорт, HTTP клиент, TLS и прокси (nil = http.Client с Timeout)
+	Authenticator   Authenticator    // mTLS, API ключ или HMAC подпись (nil = только Bearer-токен)
+	Failover        *FailoverConfig  // Несколько endpoints с балансировкой по /ready (nil = только BaseURL)
 }
 
 // NewClient создает новый клиент Nexus Protocol с указанной конфигурацией.
@@ -81,6 +92,25 @@ func NewClient(config Config) *Client {
 		logger = &NoOpLogger{}
 	}
 
+	httpClient := newHTTPClient(config.Transport, config.Timeout, config.Authenticator)
+	var transport Transport = httpClient
+	if config.Transport != nil && config.Transport.Transport != nil {
+		transport = config.Transport.Transport
+	}
+
+	var pool *endpointPool
+	if config.Failover != nil {
+		if config.BaseURL == "" && len(config.Failover.Endpoints) > 0 {
+			config.BaseURL = strings.TrimSuffix(config.Failover.Endpoints[0], "/")
+		}
+		var err error
+		if pool, err = newEndpointPool(transport, config.BaseURL, *config.Failover); err != nil {
+			logger.Warn("Failover disabled", Field{Key: "error", Value: err.Error()})
+		} else {
+			transport = pool
+		}
+	}
+
 	return &Client{
 		baseURL:         config.BaseURL,
 		token:           config.Token,
@@ -93,9 +123,12 @@ func NewClient(config Config) *Client {
 		logger:          logger,
 		interceptors:    make([]Interceptor, 0),
 		validator:       config.Validator,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		cache:           newResponseCache(config.CacheConfig),
+		confirmAction:   config.ConfirmAction,
+		httpClient:      httpClient,
+		transport:       transport,
+		authenticator:   config.Authenticator,
+		endpoints:       pool,
 	}
 }
 
@@ -188,10 +221,22 @@ func (c *Client) createRequestMetadata() *types.RequestMetadata {
 
 // doRequest выполняет HTTP запрос с поддержкой context, retry и rate limiting
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequestWithHeaders(ctx, method, path, body, nil)
+}
+
+// doRequestWithHeaders выполняет HTTP запрос как doRequest, добавляя указанные HTTP заголовки
+// (например, If-None-Match для ревалидации кэша)
+func (c *Client) doRequestWithHeaders(ctx context.Context, method, path string, body interface{}, headers http.Header) (*http.Response, error) {
 	var lastErr error
 	var lastResp *http.Response
 
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+	maxRetries := c.retryConfig.MaxRetries
+	noRetry := retryDisabled(ctx)
+	if noRetry {
+		maxRetries = 0
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Вычисляем задержку для retry
 			backoff := c.calculateBackoff(attempt - 1)
@@ -212,6 +257,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 
 		// Создаем запрос
 		var reqBody io.Reader
+		var payload []byte
 		if body != nil {
 			// Валидация запроса (если валидатор настроен)
 			if c.validator != nil && attempt == 0 {
@@ -228,6 +274,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal request body: %w", err)
 			}
+			payload = jsonData
 			reqBody = bytes.NewBuffer(jsonData)
 		}
 
@@ -241,11 +288,21 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		if c.token != "" {
 			req.Header.Set("Authorization", "Bearer "+c.token)
 		}
+		for key, values := range headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
 
 		// Применяем interceptors перед запросом
 		if err := c.applyInterceptorsBefore(ctx, req); err != nil {
 			return nil, fmt.Errorf("interceptor error: %w", err)
 		}
+		if c.authenticator != nil {
+			if err := c.authenticator.Authenticate(req, payload); err != nil {
+				return nil, fmt.Errorf("authentication failed: %w", err)
+			}
+		}
 
 		// Логируем запрос
 		c.logger.Debug("Sending request",
@@ -255,7 +312,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		)
 
 		startTime := time.Now()
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.transport.Do(req)
 		duration := time.Since(startTime)
 
 		// Логируем ответ
@@ -283,7 +340,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		// Обрабатываем ошибки
 		if err != nil {
 			lastErr = err
-			if !c.shouldRetry(attempt+1, err, 0) {
+			if noRetry || !c.shouldRetry(attempt+1, err, 0) {
 				return nil, fmt.Errorf("request failed: %w", err)
 			}
 			continue
@@ -292,7 +349,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		// Обрабатываем rate limiting (HTTP 429)
 		if resp.StatusCode == http.StatusTooManyRequests {
 			retryAfter := c.handleRateLimit(resp)
-			if c.shouldRetry(attempt+1, nil, resp.StatusCode) {
+			if !noRetry && c.shouldRetry(attempt+1, nil, resp.StatusCode) {
 				c.logger.Warn("Rate limited, waiting",
 					Field{Key: "retry_after_sec", Value: retryAfter.Seconds()},
 					Field{Key: "path", Value: path},
@@ -318,7 +375,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 
 		// Проверяем другие retryable статусы
 		if resp.StatusCode >= 400 {
-			if c.shouldRetry(attempt+1, nil, resp.StatusCode) {
+			if !noRetry && c.shouldRetry(attempt+1, nil, resp.StatusCode) {
 				lastResp = resp
 				lastErr = fmt.Errorf("request failed with status %d", resp.StatusCode)
 				resp.Body.Close()
@@ -399,6 +456,16 @@ func (c *Client) handleRateLimit(resp *http.Response) time.Duration {
 }
 
 
+// HTTPError возвращается для ответов с ошибкой, тело которых не является ErrorResponse
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
 // parseResponse парсит ответ и обрабатывает ошибки
 func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
@@ -414,7 +481,7 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 		if err := json.Unmarshal(body, &errResp); err == nil {
 			return &errResp.Error
 		}
-		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Парсим успешный ответ
@@ -192,6 +192,32 @@ func TestExecuteTemplate_ValidationError(t *testing.T) {
 	}
 }
 
+func TestGetNextWorkflowStep(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://localhost"})
+	result := &types.ExecuteTemplateResponse{Workflow: &types.Workflow{Steps: []types.WorkflowStep{
+		{Step: 1, Action: "order_food", Status: "success", ResultID: "order-1"},
+		{Step: 2, Action: "process_payment", Status: "in_progress", DependsOn: []string{"order-1"}},
+		{Step: 3, Action: "set_delivery_address", Status: "pending", DependsOn: []string{"step-2"}},
+		{Step: 4, Action: "send_receipt", Status: "pending", DependsOn: []string{"1"}},
+	}}}
+
+	next := client.GetNextWorkflowStep(result)
+	if next == nil || next.Step != 4 {
+		t.Fatalf("Expected step 4 to be ready, got %+v", next)
+	}
+
+	result.Workflow.Steps[1].Status = "completed"
+	result.Workflow.Steps[3].Status = "completed"
+	if next := client.GetNextWorkflowStep(result); next == nil || next.Step != 3 {
+		t.Errorf("Expected step 3 to be ready, got %+v", next)
+	}
+
+	result.Workflow.Steps[2].DependsOn = []string{"unknown"}
+	if next := client.GetNextWorkflowStep(result); next != nil {
+		t.Errorf("Expected nil for invalid workflow, got %+v", next)
+	}
+}
+
 func TestContext_Cancellation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(2 * time.Second)
@@ -13,6 +13,7 @@ const (
 
 	// Batch endpoints
 	PathAPIV1BatchExecute = "/api/v1/batch/execute"
+	PathAPIV1Batch        = "/api/v1/batch"
 
 	// Webhooks endpoints
 	PathAPIV1Webhooks = "/api/v1/webhooks"
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
 )
@@ -53,6 +54,45 @@ func (c *Client) GetConversation(ctx context.Context, conversationID string) (*t
 	return &result.Data, nil
 }
 
+// GetConversationHistory получает историю сообщений беседы с пагинацией.
+// Поддерживает фильтрацию по времени (before, after), limit и offset.
+func (c *Client) GetConversationHistory(ctx context.Context, conversationID string, req *types.GetConversationHistoryRequest) (*types.GetConversationHistoryResponse, error) {
+	params := url.Values{}
+	if req.Limit > 0 {
+		params.Add("limit", fmt.Sprintf("%d", req.Limit))
+	}
+	if req.Offset > 0 {
+		params.Add("offset", fmt.Sprintf("%d", req.Offset))
+	}
+	if req.Before != "" {
+		params.Add("before", req.Before)
+	}
+	if req.After != "" {
+		params.Add("after", req.After)
+	}
+
+	path := fmt.Sprintf("%s/%s/history", PathAPIV1Conversations, conversationID)
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data types.GetConversationHistoryResponse `json:"data"`
+	}
+
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Data, nil
+}
+
 // SendMessage отправляет сообщение в беседу и получает ответ от AI.
 // Тип сообщения по умолчанию: "text", если не указан.
 func (c *Client) SendMessage(ctx context.Context, conversationID string, req *types.SendMessageRequest) (*types.MessageResponse, error) {
@@ -0,0 +1,366 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Параметры проверки endpoints по умолчанию
+const (
+	DefaultProbeInterval = 10 * time.Second
+	DefaultProbeTimeout  = 2 * time.Second
+
+	// minEndpointWeight вес готового, но полностью загруженного endpoint
+	minEndpointWeight = 0.01
+)
+
+// FailoverConfig настройки работы с несколькими endpoints (регионами).
+// Запросы распределяются между готовыми endpoints с весами по /ready,
+// а если соединение не удалось установить, тот же запрос (с тем же
+// request_id в метаданных) повторяется на следующем endpoint. Прочие
+// сетевые ошибки (таймаут, обрыв после отправки) переносятся на другой
+// endpoint только для идемпотентных методов: сервер мог уже выполнить
+// запрос. Endpoints должны
+// отличаться только схемой и хостом: путь запроса и подпись HMACAuth
+// сохраняются.
+type FailoverConfig struct {
+	Endpoints     []string      // Базовые URL; Config.BaseURL добавляется первым, если его нет в списке
+	ProbeInterval time.Duration // Период проверки /ready (0 = DefaultProbeInterval)
+	ProbeTimeout  time.Duration // Таймаут проверки (0 = DefaultProbeTimeout)
+}
+
+// EndpointStatus состояние endpoint
+type EndpointStatus struct {
+	URL       string
+	Healthy   bool
+	Weight    float64   // Доля трафика относительно других endpoints
+	LastProbe time.Time // Время последней проверки /ready (нулевое до первой)
+	LastError string    // Причина исключения endpoint
+}
+
+// endpoint состояние одного endpoint пула
+type endpoint struct {
+	url       *url.URL
+	healthy   bool
+	weight    float64
+	lastProbe time.Time
+	lastErr   error
+}
+
+// endpointPool Transport, распределяющий запросы между endpoints. Проверка
+// /ready запускается в фоне при запросах, если с прошлой прошло больше
+// ProbeInterval, поэтому пул не держит фоновых горутин без трафика.
+type endpointPool struct {
+	next     Transport
+	primary  *url.URL // BaseURL клиента: переносятся только запросы к нему
+	basePath string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	probed    *sync.Cond // Сигнал о завершении проверки (probing = false)
+	endpoints []*endpoint
+	probing   bool
+	probedAt  time.Time
+}
+
+func newEndpointPool(next Transport, baseURL string, config FailoverConfig) (*endpointPool, error) {
+	p := &endpointPool{next: next, interval: config.ProbeInterval, timeout: config.ProbeTimeout}
+	p.probed = sync.NewCond(&p.mu)
+	if p.interval <= 0 {
+		p.interval = DefaultProbeInterval
+	}
+	if p.timeout <= 0 {
+		p.timeout = DefaultProbeTimeout
+	}
+	if base, err := url.Parse(baseURL); err == nil {
+		p.basePath = strings.TrimSuffix(base.Path, "/")
+	}
+
+	seen := make(map[string]bool)
+	for _, raw := range append([]string{baseURL}, config.Endpoints...) {
+		raw = strings.TrimSuffix(raw, "/")
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q", raw)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u, healthy: true, weight: 1})
+	}
+	if len(p.endpoints) == 0 {
+		return nil, errors.New("no endpoints configured")
+	}
+	p.primary = p.endpoints[0].url
+	return p, nil
+}
+
+// Do отправляет запрос на endpoint, выбранный по весам, и переходит к
+// следующему при ошибке соединения. Запросы к другим хостам (например,
+// к OAuth2 провайдеру) передаются дальше без изменений.
+func (p *endpointPool) Do(req *http.Request) (*http.Response, error) {
+	if !p.owns(req.URL) {
+		return p.next.Do(req)
+	}
+	p.maybeProbe()
+
+	var lastErr error
+	for i, ep := range p.order() {
+		attempt := req.Clone(req.Context())
+		attempt.URL = p.rewrite(ep, req.URL)
+		attempt.Host = ""
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, lastErr
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := p.next.Do(attempt)
+		if err == nil {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		p.eject(ep, err)
+		if !isDialError(err) && !isIdempotent(req.Method) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// isDialError проверяет, что соединение не было установлено и запрос
+// точно не дошел до сервера
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isIdempotent проверяет, что повтор запроса с методом method безопасен
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// owns проверяет, что запрос адресован BaseURL клиента
+func (p *endpointPool) owns(u *url.URL) bool {
+	return strings.EqualFold(u.Scheme, p.primary.Scheme) && strings.EqualFold(u.Host, p.primary.Host)
+}
+
+// rewrite переносит URL запроса на endpoint
+func (p *endpointPool) rewrite(ep *endpoint, u *url.URL) *url.URL {
+	rewritten := *u
+	rewritten.Scheme = ep.url.Scheme
+	rewritten.Host = ep.url.Host
+	rewritten.Path = ep.url.Path + strings.TrimPrefix(u.Path, p.basePath)
+	rewritten.RawPath = ""
+	return &rewritten
+}
+
+// order возвращает endpoints в порядке попыток: готовые в случайном
+// порядке с учетом весов, затем исключенные как последний шанс
+func (p *endpointPool) order() []*endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var healthy, ejected []*endpoint
+	var total float64
+	for _, ep := range p.endpoints {
+		if ep.healthy {
+			healthy = append(healthy, ep)
+			total += ep.weight
+		} else {
+			ejected = append(ejected, ep)
+		}
+	}
+
+	ordered := make([]*endpoint, 0, len(p.endpoints))
+	for len(healthy) > 0 {
+		pick := rand.Float64() * total
+		i := 0
+		for ; i < len(healthy)-1; i++ {
+			if pick -= healthy[i].weight; pick < 0 {
+				break
+			}
+		}
+		ordered = append(ordered, healthy[i])
+		total -= healthy[i].weight
+		healthy = append(healthy[:i], healthy[i+1:]...)
+	}
+	return append(ordered, ejected...)
+}
+
+// eject исключает endpoint до следующей успешной проверки
+func (p *endpointPool) eject(ep *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.healthy = false
+	ep.lastErr = err
+}
+
+// maybeProbe запускает проверку endpoints в фоне, если пора
+func (p *endpointPool) maybeProbe() {
+	p.mu.Lock()
+	if p.probing || time.Since(p.probedAt) < p.interval {
+		p.mu.Unlock()
+		return
+	}
+	p.probing = true
+	p.mu.Unlock()
+
+	go p.probeAll()
+}
+
+// probeAll проверяет /ready всех endpoints
+func (p *endpointPool) probeAll() {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			weight, err := p.probe(ep)
+			p.mu.Lock()
+			ep.lastProbe = time.Now()
+			ep.healthy, ep.weight, ep.lastErr = err == nil, weight, err
+			p.mu.Unlock()
+		}(ep)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.probing = false
+	p.probedAt = time.Now()
+	p.probed.Broadcast()
+	p.mu.Unlock()
+}
+
+// probe запрашивает /ready endpoint и возвращает его вес
+func (p *endpointPool) probe(ep *endpoint) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.url.String()+PathReady, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.next.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("readiness probe returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	var ready types.ReadinessResponse
+	if err := json.Unmarshal(body, &ready); err != nil {
+		return 0, fmt.Errorf("invalid readiness response: %w", err)
+	}
+	return readinessWeight(&ready)
+}
+
+// readinessWeight вычисляет вес endpoint по ответу /ready. Статусы
+// оцениваются так же, как в HealthWatcher: endpoint не готов, если статус
+// не "ready" или какой-либо компонент либо проверка unhealthy; каждый
+// degraded компонент или проверка вдвое уменьшает вес, а Capacity -
+// пропорционально свободной емкости.
+func readinessWeight(ready *types.ReadinessResponse) (float64, error) {
+	weight := 1.0
+	for _, obs := range healthObservations(ready, nil) {
+		// Внешние сервисы общие для регионов и на выбор endpoint не влияют
+		if obs.Kind == HealthKindExternal {
+			continue
+		}
+		switch obs.Status {
+		case HealthUnhealthy:
+			if obs.Kind == HealthKindServer {
+				return 0, fmt.Errorf("endpoint is %s", ready.Status)
+			}
+			return 0, fmt.Errorf("%s %s is unhealthy", obs.Kind, obs.Name)
+		case HealthDegraded:
+			weight /= 2
+		}
+	}
+
+	if capacity := ready.Capacity; capacity != nil {
+		switch {
+		case capacity.MaxCapacity > 0:
+			weight *= float64(capacity.AvailableCapacity) / float64(capacity.MaxCapacity)
+		case capacity.CurrentLoad > 0:
+			weight *= 1 - float64(capacity.CurrentLoad)
+		}
+	}
+	if weight < minEndpointWeight {
+		weight = minEndpointWeight
+	}
+	return weight, nil
+}
+
+// status возвращает состояние endpoints
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		statuses[i] = EndpointStatus{URL: ep.url.String(), Healthy: ep.healthy, Weight: ep.weight, LastProbe: ep.lastProbe}
+		if ep.lastErr != nil {
+			statuses[i].LastError = ep.lastErr.Error()
+		}
+	}
+	return statuses
+}
+
+// Endpoints возвращает состояние endpoints, если задан Config.Failover
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.endpoints == nil {
+		return nil
+	}
+	return c.endpoints.status()
+}
+
+// ProbeEndpoints синхронно проверяет /ready всех endpoints, не дожидаясь
+// ProbeInterval (например, при старте сервиса). Если проверка уже идет в
+// фоне, сначала дожидается ее завершения.
+func (c *Client) ProbeEndpoints() {
+	if c.endpoints == nil {
+		return
+	}
+	c.endpoints.mu.Lock()
+	for c.endpoints.probing {
+		c.endpoints.probed.Wait()
+	}
+	c.endpoints.probing = true
+	c.endpoints.mu.Unlock()
+	c.endpoints.probeAll()
+}
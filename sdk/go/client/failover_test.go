@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// regionTransport маршрутизирует запросы по хосту на обработчики регионов;
+// к региону без обработчика нельзя подключиться
+type regionTransport struct {
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+	failures map[string]error // ошибка после установки соединения
+	bodies   map[string][]string
+}
+
+func (rt *regionTransport) Do(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	if req.Body != nil && req.URL.Path != PathReady {
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(strings.NewReader(string(body)))
+		rt.bodies[req.URL.Host] = append(rt.bodies[req.URL.Host], string(body))
+	}
+	handler := rt.handlers[req.URL.Host]
+	failure := rt.failures[req.URL.Host]
+	rt.mu.Unlock()
+	if failure != nil {
+		return nil, failure
+	}
+	if handler == nil {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return HandlerTransport(handler).Do(req)
+}
+
+func readyHandler(ready *types.ReadinessResponse) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathReady {
+			json.NewEncoder(w).Encode(ready)
+			return
+		}
+		w.Write([]byte(`{"metadata":{},"data":{"logged":true,"event_id":"e1"}}`))
+	})
+}
+
+func TestFailoverOnConnectionError(t *testing.T) {
+	rt := &regionTransport{
+		handlers: map[string]http.Handler{"eu.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready"})},
+		bodies:   make(map[string][]string),
+	}
+	client := NewClient(Config{
+		BaseURL:     "http://us.nexus.local",
+		RetryConfig: &RetryConfig{},
+		Transport:   &TransportConfig{Transport: rt},
+		Failover:    &FailoverConfig{Endpoints: []string{"http://eu.nexus.local/"}, ProbeInterval: time.Hour},
+	})
+	// Проверка не запускается, а eu исключен: первым пробуется us
+	client.endpoints.probedAt = time.Now()
+	client.endpoints.endpoints[1].healthy = false
+
+	if _, err := client.LogEvent(context.Background(), &types.LogEventRequest{EventType: "click"}); err != nil {
+		t.Fatalf("LogEvent failed: %v", err)
+	}
+	us, eu := rt.bodies["us.nexus.local"], rt.bodies["eu.nexus.local"]
+	if len(us) != 1 || len(eu) != 1 || us[0] != eu[0] {
+		t.Fatalf("Expected the same request on both regions, got %q and %q", us, eu)
+	}
+	if !strings.Contains(eu[0], `"request_id"`) {
+		t.Errorf("Request metadata missing in %s", eu[0])
+	}
+
+	statuses := client.Endpoints()
+	if len(statuses) != 2 || statuses[0].Healthy || !strings.Contains(statuses[0].LastError, "connection refused") {
+		t.Errorf("Expected us to be ejected, got %+v", statuses)
+	}
+
+	// Все endpoints недоступны
+	rt.handlers = nil
+	if _, err := client.LogEvent(context.Background(), &types.LogEventRequest{EventType: "click"}); err == nil {
+		t.Error("Expected error when all endpoints are down")
+	}
+}
+
+func TestFailoverNoReplayAfterSend(t *testing.T) {
+	rt := &regionTransport{
+		handlers: map[string]http.Handler{"eu.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready"})},
+		failures: map[string]error{"us.nexus.local": errors.New("read: connection reset by peer")},
+		bodies:   make(map[string][]string),
+	}
+	client := NewClient(Config{
+		BaseURL:     "http://us.nexus.local",
+		RetryConfig: &RetryConfig{},
+		Transport:   &TransportConfig{Transport: rt},
+		Failover:    &FailoverConfig{Endpoints: []string{"http://eu.nexus.local"}, ProbeInterval: time.Hour},
+	})
+	client.endpoints.probedAt = time.Now()
+	client.endpoints.endpoints[1].healthy = false
+
+	// POST мог быть выполнен в us: повтор в eu недопустим
+	if _, err := client.LogEvent(context.Background(), &types.LogEventRequest{EventType: "click"}); err == nil {
+		t.Fatal("Expected error after connection reset")
+	}
+	if len(rt.bodies["eu.nexus.local"]) != 0 {
+		t.Errorf("Non-idempotent request replayed on another region: %q", rt.bodies["eu.nexus.local"])
+	}
+
+	// GET идемпотентен и переносится на другой регион
+	client.endpoints.endpoints[0].healthy = true
+	if _, err := client.Ready(context.Background()); err != nil {
+		t.Errorf("Expected GET to fail over, got %v", err)
+	}
+}
+
+func TestProbeEndpointsWaitsForBackgroundProbe(t *testing.T) {
+	rt := &regionTransport{
+		handlers: map[string]http.Handler{"a.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready"})},
+		bodies:   make(map[string][]string),
+	}
+	client := NewClient(Config{
+		Transport: &TransportConfig{Transport: rt},
+		Failover:  &FailoverConfig{Endpoints: []string{"http://a.nexus.local"}},
+	})
+	client.endpoints.maybeProbe()
+	client.ProbeEndpoints()
+
+	client.endpoints.mu.Lock()
+	probing := client.endpoints.probing
+	client.endpoints.mu.Unlock()
+	if probing || client.Endpoints()[0].LastProbe.IsZero() {
+		t.Errorf("Expected completed probe, got %+v", client.Endpoints())
+	}
+}
+
+func TestFailoverProbeWeights(t *testing.T) {
+	rt := &regionTransport{
+		handlers: map[string]http.Handler{
+			"a.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready", Components: map[string]*types.ComponentStatus{"ai": {Status: "unhealthy"}}}),
+			"b.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready", Capacity: &types.CapacityInfo{MaxCapacity: 100, AvailableCapacity: 25}}),
+			"c.nexus.local": readyHandler(&types.ReadinessResponse{Status: "ready", Components: map[string]*types.ComponentStatus{"db": {Status: "degraded"}}}),
+		},
+		bodies: make(map[string][]string),
+	}
+	client := NewClient(Config{
+		Transport: &TransportConfig{Transport: rt},
+		Failover:  &FailoverConfig{Endpoints: []string{"http://a.nexus.local", "http://b.nexus.local", "http://c.nexus.local", "http://d.nexus.local"}},
+	})
+	if client.baseURL != "http://a.nexus.local" {
+		t.Errorf("Expected first endpoint as base URL, got %s", client.baseURL)
+	}
+	client.ProbeEndpoints()
+
+	statuses := client.Endpoints()
+	want := []struct {
+		healthy bool
+		weight  float64
+	}{{false, 0}, {true, 0.25}, {true, 0.5}, {false, 0}}
+	for i, w := range want {
+		if statuses[i].Healthy != w.healthy || statuses[i].Weight != w.weight || statuses[i].LastProbe.IsZero() {
+			t.Errorf("Endpoint %s: %+v, want %+v", statuses[i].URL, statuses[i], w)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		if _, err := client.LogEvent(context.Background(), &types.LogEventRequest{EventType: "click"}); err != nil {
+			t.Fatalf("LogEvent failed: %v", err)
+		}
+	}
+	if len(rt.bodies["a.nexus.local"]) != 0 || len(rt.bodies["b.nexus.local"])+len(rt.bodies["c.nexus.local"]) != 20 {
+		t.Errorf("Traffic must go to ready endpoints only: %d/%d/%d", len(rt.bodies["a.nexus.local"]), len(rt.bodies["b.nexus.local"]), len(rt.bodies["c.nexus.local"]))
+	}
+}
+
+func TestReadinessWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		ready  types.ReadinessResponse
+		weight float64
+		err    bool
+	}{
+		{name: "ready", ready: types.ReadinessResponse{Status: "ready"}, weight: 1},
+		{name: "not ready", ready: types.ReadinessResponse{Status: "not_ready"}, err: true},
+		{name: "ok is not ready", ready: types.ReadinessResponse{Status: "ok"}, err: true},
+		{name: "degraded check", ready: types.ReadinessResponse{Status: "ready", Checks: types.ReadinessChecks{Redis: "degraded"}}, weight: 0.5},
+		{name: "external service", ready: types.ReadinessResponse{Status: "ready", ExternalServices: []*types.ExternalServiceStatus{{Name: "llm", Status: "unhealthy"}}}, weight: 1},
+		{name: "failed check", ready: types.ReadinessResponse{Status: "ready", Checks: types.ReadinessChecks{Database: "error"}}, err: true},
+		{name: "load", ready: types.ReadinessResponse{Status: "ready", Capacity: &types.CapacityInfo{CurrentLoad: 0.75}}, weight: 0.25},
+		{name: "full", ready: types.ReadinessResponse{Status: "ready", Capacity: &types.CapacityInfo{MaxCapacity: 10}}, weight: minEndpointWeight},
+	}
+	for _, tt := range tests {
+		weight, err := readinessWeight(&tt.ready)
+		if (err != nil) != tt.err || weight != tt.weight {
+			t.Errorf("%s: weight %v, error %v", tt.name, weight, err)
+		}
+	}
+}
+
+func TestFailoverPassesOtherHosts(t *testing.T) {
+	var idpCalls, nexusCalls int
+	rt := &regionTransport{
+		handlers: map[string]http.Handler{
+			"idp.local": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				idpCalls++
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"access_token": "svc-token", "token_type": "Bearer", "expires_in": 3600}`))
+			}),
+			"eu.nexus.local": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nexusCalls++
+				http.NotFound(w, r)
+			}),
+		},
+		bodies: make(map[string][]string),
+	}
+	client := NewClient(Config{
+		BaseURL:     "http://eu.nexus.local/api",
+		RetryConfig: &RetryConfig{},
+		Transport:   &TransportConfig{Transport: rt},
+		Failover:    &FailoverConfig{Endpoints: []string{"http://us.nexus.local/api"}, ProbeInterval: time.Hour},
+	})
+	client.endpoints.probedAt = time.Now()
+
+	token, err := client.ClientCredentialsToken(context.Background(), &OAuth2Config{TokenURL: "http://idp.local/oauth/token", ClientID: "svc"})
+	if err != nil || token.AccessToken != "svc-token" {
+		t.Fatalf("Expected token from the provider, got %+v: %v", token, err)
+	}
+	if idpCalls != 1 || nexusCalls != 0 {
+		t.Errorf("Expected the provider to be called once and Nexus not at all, got %d and %d", idpCalls, nexusCalls)
+	}
+}
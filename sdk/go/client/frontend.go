@@ -15,8 +15,10 @@ import (
 //	  "metadata": { ... ResponseMetadata ... },
 //	  "data": { ... FrontendConfig ... }
 //	}
+//
+// Если настроен CacheConfig, ответ кэшируется локально согласно x-cache-control.
 func (c *Client) GetFrontendConfig(ctx context.Context) (*types.FrontendConfig, error) {
-	resp, err := c.doRequest(ctx, "GET", PathAPIV1FrontendConfig, nil)
+	resp, err := c.doCachedGet(ctx, PathAPIV1FrontendConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultFrontendInterval период опроса конфигурации фронтенда по умолчанию
+const DefaultFrontendInterval = 30 * time.Second
+
+// FrontendUpdate новая активная конфигурация фронтенда
+type FrontendUpdate struct {
+	Config *types.FrontendConfig
+	Theme  *Theme // ResolveTheme(Config)
+	ETag   string
+	Time   time.Time
+}
+
+// FrontendWatcherConfig настройки FrontendWatcher
+type FrontendWatcherConfig struct {
+	Interval time.Duration        // Период опроса (0 = DefaultFrontendInterval)
+	OnChange func(FrontendUpdate) // Вызывается синхронно для каждого изменения
+}
+
+// FrontendWatcher поддерживает актуальную конфигурацию фронтенда:
+// опрашивает /frontend/config с If-None-Match и публикует подписчикам
+// только изменившиеся конфигурации. Первая полученная конфигурация
+// тоже считается изменением.
+//
+// Пример использования:
+//
+//	watcher := nexus.NewFrontendWatcher(client.FrontendWatcherConfig{})
+//	updates, cancel := watcher.Subscribe()
+//	defer cancel()
+//	go watcher.Run(ctx)
+//	for update := range updates {
+//		render(update.Theme.Colors.Primary, update.Theme.Branding.Logo)
+//	}
+type FrontendWatcher struct {
+	client   *Client
+	interval time.Duration
+	onChange func(FrontendUpdate)
+
+	fetchMu sync.Mutex // Один запрос за раз, чтобы ETag и текущая конфигурация не расходились
+
+	mu          sync.Mutex
+	current     *FrontendUpdate
+	subscribers map[int]chan FrontendUpdate
+	nextID      int
+}
+
+// NewFrontendWatcher создает наблюдатель за конфигурацией фронтенда
+func (c *Client) NewFrontendWatcher(config FrontendWatcherConfig) *FrontendWatcher {
+	w := &FrontendWatcher{
+		client:      c,
+		interval:    config.Interval,
+		onChange:    config.OnChange,
+		subscribers: make(map[int]chan FrontendUpdate),
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultFrontendInterval
+	}
+	return w
+}
+
+// Subscribe подписывает на изменения конфигурации. Если конфигурация уже
+// известна, она сразу доступна в канале. Медленный подписчик получает
+// только последнюю конфигурацию: непрочитанная предыдущая отбрасывается.
+// Вызов cancel отписывает и закрывает канал.
+func (w *FrontendWatcher) Subscribe() (<-chan FrontendUpdate, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	ch := make(chan FrontendUpdate, 1)
+	if w.current != nil {
+		ch <- *w.current
+	}
+	w.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			delete(w.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// Current возвращает последнюю полученную конфигурацию (nil - еще не получена)
+func (w *FrontendWatcher) Current() *FrontendUpdate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.current == nil {
+		return nil
+	}
+	update := *w.current
+	return &update
+}
+
+// Run опрашивает конфигурацию с интервалом Interval, пока ctx не отменен.
+// Ошибки опроса логируются, последняя конфигурация при этом сохраняется.
+func (w *FrontendWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			w.client.logger.Warn("Failed to refresh frontend config", Field{Key: "error", Value: err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh запрашивает конфигурацию немедленно, например по уведомлению
+// об изменении из WebSocket. Ответ 304 Not Modified изменением не считается.
+func (w *FrontendWatcher) Refresh(ctx context.Context) error {
+	w.fetchMu.Lock()
+	defer w.fetchMu.Unlock()
+
+	var headers http.Header
+	if current := w.Current(); current != nil && current.ETag != "" {
+		headers = http.Header{"If-None-Match": []string{current.ETag}}
+	}
+	resp, err := w.client.doRequestWithHeaders(ctx, http.MethodGet, PathAPIV1FrontendConfig, nil, headers)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	var result struct {
+		Data     types.FrontendConfig    `json:"data"`
+		Metadata *types.ResponseMetadata `json:"metadata"`
+	}
+	if err := w.client.parseResponse(resp, &result); err != nil {
+		return err
+	}
+	w.Observe(&result.Data, etag)
+	return nil
+}
+
+// Observe принимает конфигурацию, полученную вне Run (например, вместе с
+// уведомлением по WebSocket), и публикует ее, если она изменилась
+func (w *FrontendWatcher) Observe(config *types.FrontendConfig, etag string) {
+	w.mu.Lock()
+	if w.current != nil && reflect.DeepEqual(w.current.Config, config) {
+		if etag != "" {
+			w.current.ETag = etag
+		}
+		w.mu.Unlock()
+		return
+	}
+	update := FrontendUpdate{Config: config, Theme: ResolveTheme(config), ETag: etag, Time: time.Now()}
+	w.current = &update
+	for _, ch := range w.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- update
+	}
+	w.mu.Unlock()
+
+	if w.onChange != nil {
+		w.onChange(update)
+	}
+}
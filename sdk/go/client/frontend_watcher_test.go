@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// frontendServer отдает конфигурацию с ETag версии и отвечает 304 на If-None-Match
+type frontendServer struct {
+	mu          sync.Mutex
+	version     int
+	primary     string
+	notModified int
+}
+
+func (s *frontendServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	fmt.Fprintf(w, `{"metadata":{},"data":{"id":"cfg","theme":"dark","colors":{"primary":%q},"active":true}}`, s.primary)
+}
+
+func (s *frontendServer) set(version int, primary string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version, s.primary = version, primary
+}
+
+func TestFrontendWatcherRefresh(t *testing.T) {
+	server := &frontendServer{version: 1, primary: "#111111"}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	var changes []FrontendUpdate
+	watcher := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &RetryConfig{}}).NewFrontendWatcher(FrontendWatcherConfig{
+		OnChange: func(u FrontendUpdate) { changes = append(changes, u) },
+	})
+	updates, cancel := watcher.Subscribe()
+	ctx := context.Background()
+
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	first := <-updates
+	if first.ETag != `"v1"` || first.Theme.Base != ThemeDark || first.Theme.Colors.Primary != "#111111" || first.Theme.Colors.Background != "#121212" {
+		t.Fatalf("Unexpected first update %+v %+v", first, first.Theme)
+	}
+
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if server.notModified != 1 || len(changes) != 1 {
+		t.Fatalf("Expected 304 without change, got %d not modified, %d changes", server.notModified, len(changes))
+	}
+
+	// Два изменения без чтения: подписчик получает последнее
+	server.set(2, "#222222")
+	watcher.Refresh(ctx)
+	server.set(3, "#333333")
+	watcher.Refresh(ctx)
+	if latest := <-updates; latest.ETag != `"v3"` || latest.Theme.Colors.Primary != "#333333" {
+		t.Errorf("Expected latest update, got %+v", latest)
+	}
+	if len(changes) != 3 {
+		t.Errorf("OnChange must see every change, got %d", len(changes))
+	}
+
+	// Новый ETag с той же конфигурацией не считается изменением
+	server.set(4, "#333333")
+	watcher.Refresh(ctx)
+	if len(changes) != 3 || watcher.Current().ETag != `"v4"` {
+		t.Errorf("Unexpected state after ETag-only change: %d changes, %+v", len(changes), watcher.Current())
+	}
+
+	cancel()
+	if _, ok := <-updates; ok {
+		t.Error("Expected closed channel after cancel")
+	}
+	cancel()
+
+	late, cancelLate := watcher.Subscribe()
+	defer cancelLate()
+	if update := <-late; update.Config.Colors["primary"] != "#333333" {
+		t.Errorf("Late subscriber must receive current config, got %+v", update.Config)
+	}
+}
+
+func TestFrontendWatcherRun(t *testing.T) {
+	server := &frontendServer{version: 1, primary: "#111111"}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	watcher := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &RetryConfig{}}).NewFrontendWatcher(FrontendWatcherConfig{Interval: 10 * time.Millisecond})
+	updates, cancelSub := watcher.Subscribe()
+	defer cancelSub()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	if update := <-updates; update.Config.Colors["primary"] != "#111111" {
+		t.Fatalf("Unexpected initial config %+v", update.Config)
+	}
+	server.set(2, "#222222")
+	select {
+	case update := <-updates:
+		if update.Config.Colors["primary"] != "#222222" {
+			t.Errorf("Unexpected update %+v", update.Config)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for update")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFrontendWatcherObserve(t *testing.T) {
+	watcher := NewClient(Config{}).NewFrontendWatcher(FrontendWatcherConfig{})
+	if watcher.Current() != nil {
+		t.Fatal("Expected no config before first update")
+	}
+	watcher.Observe(&types.FrontendConfig{Theme: ThemeLight, Branding: map[string]string{"logo": "/logo.svg"}}, "")
+	if current := watcher.Current(); current == nil || current.Theme.Branding.Logo != "/logo.svg" || current.Theme.Colors.Text != "#333333" {
+		t.Errorf("Unexpected current config %+v", current)
+	}
+}
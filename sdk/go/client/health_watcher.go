@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Статусы здоровья компонентов
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Виды объектов, за которыми следит HealthWatcher
+const (
+	HealthKindServer    = "server"           // сам сервер: ошибка /ready или статус не "ready"
+	HealthKindComponent = "component"        // ReadinessResponse.Components
+	HealthKindExternal  = "external_service" // ReadinessResponse.ExternalServices
+	HealthKindCheck     = "check"            // ReadinessResponse.Checks (database, redis, ai_services)
+)
+
+// Параметры HealthWatcher по умолчанию
+const (
+	DefaultHealthInterval = 15 * time.Second
+	DefaultHealthDebounce = 2
+)
+
+// HealthEvent смена статуса компонента. Первое наблюдение каждого
+// компонента публикуется сразу с пустым Previous.
+type HealthEvent struct {
+	Kind      string
+	Name      string
+	Previous  string
+	Status    string
+	LatencyMS int32
+	Message   string
+	Time      time.Time
+}
+
+// HealthWatcherConfig настройки HealthWatcher
+type HealthWatcherConfig struct {
+	Interval time.Duration     // Период опроса /ready (0 = DefaultHealthInterval)
+	Debounce int               // Сколько наблюдений подряд нужно для смены статуса (0 = DefaultHealthDebounce)
+	OnChange func(HealthEvent) // Вызывается синхронно для каждой смены статуса
+}
+
+// HealthWatcher следит за /ready и сообщает о переходах статусов
+// компонентов, внешних сервисов и проверок между healthy, degraded и
+// unhealthy. Чтобы статус не "мигал", новый статус принимается только
+// после Debounce наблюдений подряд.
+//
+// Пример использования:
+//
+//	watcher := nexus.NewHealthWatcher(client.HealthWatcherConfig{
+//		OnChange: func(e client.HealthEvent) {
+//			if e.Kind == client.HealthKindCheck && e.Name == "ai_services" {
+//				shedLoad(e.Status != client.HealthHealthy)
+//			}
+//		},
+//	})
+//	go watcher.Run(ctx)
+type HealthWatcher struct {
+	client   *Client
+	interval time.Duration
+	debounce int
+	onChange func(HealthEvent)
+
+	mu      sync.Mutex
+	targets map[healthKey]*healthTarget
+	events  chan HealthEvent
+}
+
+type healthKey struct {
+	kind, name string
+}
+
+// healthTarget принятый статус и кандидат на смену
+type healthTarget struct {
+	status  string
+	pending string
+	count   int
+}
+
+// NewHealthWatcher создает наблюдатель за здоровьем сервера клиента
+func (c *Client) NewHealthWatcher(config HealthWatcherConfig) *HealthWatcher {
+	w := &HealthWatcher{
+		client:   c,
+		interval: config.Interval,
+		debounce: config.Debounce,
+		onChange: config.OnChange,
+		targets:  make(map[healthKey]*healthTarget),
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultHealthInterval
+	}
+	if w.debounce <= 0 {
+		w.debounce = DefaultHealthDebounce
+	}
+	return w
+}
+
+// Events возвращает канал смен статусов. Канал создается при первом
+// вызове; пока его никто не читает, опрос ждет получателя.
+func (w *HealthWatcher) Events() <-chan HealthEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.events == nil {
+		w.events = make(chan HealthEvent, 16)
+	}
+	return w.events
+}
+
+// Run опрашивает /ready с интервалом Interval, пока ctx не отменен
+func (w *HealthWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		ready, err := w.fetch(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		w.Observe(ctx, ready, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch запрашивает /ready. В отличие от Client.Ready, ответ 503 с
+// телом ReadinessResponse разбирается, чтобы узнать, какой компонент отказал.
+func (w *HealthWatcher) fetch(ctx context.Context) (*types.ReadinessResponse, error) {
+	// Повторы отключены: следующий опрос наступит через Interval, а повторы
+	// RetryConfig задержали бы обнаружение отказа
+	resp, err := w.client.doRequest(WithoutRetry(ctx), http.MethodGet, PathReady, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	var ready types.ReadinessResponse
+	if err := json.Unmarshal(body, &ready); err != nil || ready.Status == "" && resp.StatusCode >= 300 {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return &ready, nil
+}
+
+// Observe учитывает ответ /ready, полученный вне Run, например из
+// уведомления по WebSocket. err - ошибка получения ответа.
+func (w *HealthWatcher) Observe(ctx context.Context, ready *types.ReadinessResponse, err error) {
+	now := time.Now()
+	var events []HealthEvent
+	w.mu.Lock()
+	for _, obs := range healthObservations(ready, err) {
+		if event, changed := w.apply(obs); changed {
+			event.Time = now
+			events = append(events, event)
+		}
+	}
+	ch := w.events
+	w.mu.Unlock()
+
+	for _, event := range events {
+		if w.onChange != nil {
+			w.onChange(event)
+		}
+		if ch != nil {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Status возвращает принятый статус объекта ("" - еще не наблюдался)
+func (w *HealthWatcher) Status(kind, name string) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if target := w.targets[healthKey{kind, name}]; target != nil {
+		return target.status
+	}
+	return ""
+}
+
+// apply учитывает наблюдение с антидребезгом; вызывается под w.mu
+func (w *HealthWatcher) apply(obs HealthEvent) (HealthEvent, bool) {
+	key := healthKey{obs.Kind, obs.Name}
+	target := w.targets[key]
+	if target == nil {
+		w.targets[key] = &healthTarget{status: obs.Status}
+		return obs, true
+	}
+	if obs.Status == target.status {
+		target.pending, target.count = "", 0
+		return obs, false
+	}
+	if obs.Status != target.pending {
+		target.pending, target.count = obs.Status, 0
+	}
+	if target.count++; target.count < w.debounce {
+		return obs, false
+	}
+	obs.Previous = target.status
+	target.status, target.pending, target.count = obs.Status, "", 0
+	return obs, true
+}
+
+// healthObservations раскладывает ответ /ready на статусы объектов в
+// стабильном порядке. Если сервер недоступен, известен только его статус.
+func healthObservations(ready *types.ReadinessResponse, err error) []HealthEvent {
+	if err != nil || ready == nil {
+		message := "no readiness response"
+		if err != nil {
+			message = err.Error()
+		}
+		return []HealthEvent{{Kind: HealthKindServer, Name: HealthKindServer, Status: HealthUnhealthy, Message: message}}
+	}
+
+	server := HealthEvent{Kind: HealthKindServer, Name: HealthKindServer, Status: HealthHealthy}
+	if ready.Status != "" && ready.Status != "ready" {
+		server.Status, server.Message = HealthUnhealthy, ready.Status
+	}
+	observations := []HealthEvent{server}
+
+	for name, check := range map[string]string{"database": ready.Checks.Database, "redis": ready.Checks.Redis, "ai_services": ready.Checks.AIServices} {
+		if check != "" {
+			observations = append(observations, HealthEvent{Kind: HealthKindCheck, Name: name, Status: checkStatus(check), Message: check})
+		}
+	}
+	for name, component := range ready.Components {
+		if component != nil {
+			observations = append(observations, HealthEvent{Kind: HealthKindComponent, Name: name, Status: normalizeHealth(component.Status), LatencyMS: component.LatencyMS, Message: component.Message})
+		}
+	}
+	for _, service := range ready.ExternalServices {
+		if service != nil {
+			observations = append(observations, HealthEvent{Kind: HealthKindExternal, Name: service.Name, Status: normalizeHealth(service.Status), LatencyMS: service.LatencyMS})
+		}
+	}
+
+	sort.SliceStable(observations[1:], func(i, j int) bool {
+		a, b := observations[1+i], observations[1+j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return observations
+}
+
+// checkStatus переводит значение ReadinessChecks в статус здоровья
+func checkStatus(value string) string {
+	switch value {
+	case "ok":
+		return HealthHealthy
+	case HealthDegraded:
+		return HealthDegraded
+	}
+	return HealthUnhealthy
+}
+
+// normalizeHealth приводит неизвестные статусы к unhealthy
+func normalizeHealth(status string) string {
+	switch status {
+	case HealthHealthy, HealthDegraded:
+		return status
+	}
+	return HealthUnhealthy
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func readiness(ai string, components map[string]string) *types.ReadinessResponse {
+	ready := &types.ReadinessResponse{Status: "ready", Checks: types.ReadinessChecks{AIServices: ai}, Components: make(map[string]*types.ComponentStatus)}
+	for name, status := range components {
+		ready.Components[name] = &types.ComponentStatus{Status: status}
+	}
+	return ready
+}
+
+func TestHealthWatcherDebounce(t *testing.T) {
+	var events []HealthEvent
+	watcher := NewClient(Config{}).NewHealthWatcher(HealthWatcherConfig{
+		Debounce: 2,
+		OnChange: func(e HealthEvent) { events = append(events, e) },
+	})
+	ctx := context.Background()
+
+	watcher.Observe(ctx, readiness("ok", map[string]string{"storage": "healthy"}), nil)
+	if len(events) != 3 {
+		t.Fatalf("Expected initial events for server, check and component, got %+v", events)
+	}
+	if events[0].Kind != HealthKindServer || events[1].Name != "ai_services" || events[2].Name != "storage" || events[2].Previous != "" {
+		t.Errorf("Unexpected initial events %+v", events)
+	}
+
+	// Одиночный сбой не меняет статус
+	events = nil
+	watcher.Observe(ctx, readiness("degraded", map[string]string{"storage": "healthy"}), nil)
+	watcher.Observe(ctx, readiness("ok", map[string]string{"storage": "healthy"}), nil)
+	watcher.Observe(ctx, readiness("degraded", map[string]string{"storage": "healthy"}), nil)
+	if len(events) != 0 || watcher.Status(HealthKindCheck, "ai_services") != HealthHealthy {
+		t.Fatalf("Flapping must be debounced, got %+v", events)
+	}
+
+	watcher.Observe(ctx, readiness("degraded", map[string]string{"storage": "healthy"}), nil)
+	if len(events) != 1 || events[0].Previous != HealthHealthy || events[0].Status != HealthDegraded {
+		t.Fatalf("Expected ai_services to become degraded, got %+v", events)
+	}
+
+	events = nil
+	watcher.Observe(ctx, nil, errors.New("connection refused"))
+	watcher.Observe(ctx, nil, errors.New("connection refused"))
+	if len(events) != 1 || events[0].Kind != HealthKindServer || events[0].Status != HealthUnhealthy || events[0].Message != "connection refused" {
+		t.Errorf("Expected server to become unhealthy, got %+v", events)
+	}
+	if watcher.Status(HealthKindComponent, "missing") != "" {
+		t.Error("Expected empty status for unknown component")
+	}
+}
+
+func TestHealthWatcherRun(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ready := readiness("ok", nil)
+		ready.ExternalServices = []*types.ExternalServiceStatus{{Name: "llm", Status: "healthy"}}
+		if atomic.AddInt32(&polls, 1) > 1 {
+			ready.Status = "not_ready"
+			ready.Checks.AIServices = "error"
+			ready.ExternalServices[0].Status = "unhealthy"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(ready)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, RetryConfig: &RetryConfig{}})
+	watcher := client.NewHealthWatcher(HealthWatcherConfig{Interval: 10 * time.Millisecond, Debounce: 1})
+	events := watcher.Events()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	changed := make(map[string]string)
+	for len(changed) < 3 {
+		select {
+		case e := <-events:
+			if e.Previous != "" {
+				changed[e.Kind+"/"+e.Name] = e.Status
+			}
+		case <-ctx.Done():
+			t.Fatalf("Timed out, changes so far: %v", changed)
+		}
+	}
+	want := map[string]string{"server/server": HealthUnhealthy, "check/ai_services": HealthUnhealthy, "external_service/llm": HealthUnhealthy}
+	for key, status := range want {
+		if changed[key] != status {
+			t.Errorf("%s: got %q, want %q", key, changed[key], status)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHealthWatcherFetchWithoutRetry(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	retry := DefaultRetryConfig()
+	retry.InitialDelay = time.Millisecond
+	watcher := NewClient(Config{BaseURL: server.URL, RetryConfig: &retry}).NewHealthWatcher(HealthWatcherConfig{})
+
+	if _, err := watcher.fetch(context.Background()); err == nil {
+		t.Fatal("Expected error for 503 without readiness body")
+	}
+	if polls != 1 {
+		t.Errorf("Expected exactly one request per poll, got %d", polls)
+	}
+}
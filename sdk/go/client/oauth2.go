@@ -0,0 +1,245 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Типы грантов OAuth2
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// Параметры опроса device flow (RFC 8628)
+const (
+	DefaultDevicePollInterval = 5 * time.Second
+	deviceSlowDownStep        = 5 * time.Second
+)
+
+// OAuth2Config параметры OAuth2 провайдера. Запросы к провайдеру идут
+// через транспорт клиента (TLS, прокси), но без Bearer-токена и
+// Authenticator клиента.
+type OAuth2Config struct {
+	TokenURL               string   // Token endpoint
+	DeviceAuthorizationURL string   // Device authorization endpoint (для device flow)
+	ClientID               string   // Идентификатор клиента
+	ClientSecret           string   // Секрет (client_secret_basic); пусто для публичных клиентов, например CLI
+	Scopes                 []string // Запрашиваемые scopes
+	Audience               string   // Параметр audience, если его требует провайдер
+}
+
+// OAuth2Token токен провайдера
+type OAuth2Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresIn    int32     `json:"expires_in,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	IDToken      string    `json:"id_token,omitempty"`
+	Expiry       time.Time `json:"-"` // Время истечения по ExpiresIn (нулевое, если не указано)
+}
+
+// Expired сообщает, истек ли токен с учетом запаса skew
+func (t *OAuth2Token) Expired(now time.Time, skew time.Duration) bool {
+	return !t.Expiry.IsZero() && !now.Add(skew).Before(t.Expiry)
+}
+
+// DeviceAuthorization ответ device authorization endpoint. Пользователь
+// открывает VerificationURI и вводит UserCode.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int32  `json:"expires_in"`
+	Interval                int32  `json:"interval,omitempty"`
+}
+
+// OAuth2Error ошибка провайдера (RFC 6749, раздел 5.2)
+type OAuth2Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuth2Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth2: %s: %s", e.Code, e.Description)
+	}
+	return "oauth2: " + e.Code
+}
+
+// ClientCredentialsToken получает токен сервиса по client credentials
+// (RFC 6749, раздел 4.4) и устанавливает его в клиент.
+func (c *Client) ClientCredentialsToken(ctx context.Context, config *OAuth2Config) (*OAuth2Token, error) {
+	form := url.Values{"grant_type": {GrantClientCredentials}}
+	return c.requestOAuth2Token(ctx, config, form)
+}
+
+// RefreshOAuth2Token обновляет токен провайдера по refresh token и
+// устанавливает новый access token в клиент
+func (c *Client) RefreshOAuth2Token(ctx context.Context, config *OAuth2Config, refreshToken string) (*OAuth2Token, error) {
+	form := url.Values{"grant_type": {GrantRefreshToken}, "refresh_token": {refreshToken}}
+	token, err := c.requestOAuth2Token(ctx, config, form)
+	if err != nil {
+		return nil, err
+	}
+	// Провайдер может не выдавать новый refresh token
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// StartDeviceAuthorization начинает device flow (RFC 8628): возвращает код,
+// который пользователь подтверждает в браузере. Токен затем получается
+// через PollDeviceToken.
+//
+// Пример использования:
+//
+//	auth, err := client.StartDeviceAuthorization(ctx, oauthConfig)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Откройте %s и введите код %s\n", auth.VerificationURI, auth.UserCode)
+//	token, err := client.PollDeviceToken(ctx, oauthConfig, auth)
+func (c *Client) StartDeviceAuthorization(ctx context.Context, config *OAuth2Config) (*DeviceAuthorization, error) {
+	if config.DeviceAuthorizationURL == "" {
+		return nil, fmt.Errorf("oauth2: device authorization URL is not set")
+	}
+	var auth DeviceAuthorization
+	if err := c.postOAuth2Form(ctx, config, config.DeviceAuthorizationURL, config.scopeForm(url.Values{}), &auth); err != nil {
+		return nil, err
+	}
+	if auth.DeviceCode == "" {
+		return nil, fmt.Errorf("oauth2: device authorization response has no device_code")
+	}
+	return &auth, nil
+}
+
+// PollDeviceToken опрашивает token endpoint, пока пользователь не
+// подтвердит вход, и устанавливает полученный токен в клиент. Интервал
+// опроса берется из auth (по умолчанию 5 секунд) и увеличивается при
+// slow_down. Отказ пользователя и истечение кода возвращаются как
+// *OAuth2Error с кодами access_denied и expired_token.
+func (c *Client) PollDeviceToken(ctx context.Context, config *OAuth2Config, auth *DeviceAuthorization) (*OAuth2Token, error) {
+	interval := DefaultDevicePollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	var deadline <-chan time.Time
+	if auth.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(auth.ExpiresIn) * time.Second)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	form := url.Values{"grant_type": {GrantDeviceCode}, "device_code": {auth.DeviceCode}}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, &OAuth2Error{Code: "expired_token", Description: "device code expired"}
+		case <-time.After(interval):
+		}
+
+		token, err := c.requestOAuth2Token(ctx, config, form)
+		if err == nil {
+			return token, nil
+		}
+		oauthErr, ok := err.(*OAuth2Error)
+		if !ok {
+			return nil, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += deviceSlowDownStep
+		default:
+			return nil, err
+		}
+	}
+}
+
+// requestOAuth2Token выполняет запрос к token endpoint и устанавливает
+// полученный access token в клиент
+func (c *Client) requestOAuth2Token(ctx context.Context, config *OAuth2Config, form url.Values) (*OAuth2Token, error) {
+	if config.TokenURL == "" {
+		return nil, fmt.Errorf("oauth2: token URL is not set")
+	}
+	if form.Get("grant_type") != GrantDeviceCode {
+		form = config.scopeForm(form)
+	}
+	requested := time.Now()
+	var token OAuth2Token
+	if err := c.postOAuth2Form(ctx, config, config.TokenURL, form, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: token response has no access_token")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = requested.Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	c.SetToken(token.AccessToken)
+	return &token, nil
+}
+
+// scopeForm добавляет scope и audience к параметрам запроса
+func (config *OAuth2Config) scopeForm(form url.Values) url.Values {
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	if config.Audience != "" {
+		form.Set("audience", config.Audience)
+	}
+	return form
+}
+
+// postOAuth2Form отправляет form-запрос провайдеру. Конфиденциальный
+// клиент аутентифицируется через Basic, публичный передает client_id в форме.
+func (c *Client) postOAuth2Form(ctx context.Context, config *OAuth2Config, endpoint string, form url.Values, result interface{}) error {
+	if config.ClientSecret == "" {
+		form.Set("client_id", config.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	}
+
+	resp, err := c.transport.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		oauthErr := &OAuth2Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, oauthErr) == nil && oauthErr.Code != "" {
+			return oauthErr
+		}
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
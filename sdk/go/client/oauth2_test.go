@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+)
+
+// oauthProvider тестовый OAuth2 провайдер; deviceResult задает ответ
+// token endpoint после первого authorization_pending
+func oauthProvider(t *testing.T, deviceResult string) (*httptest.Server, *int32) {
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case GrantClientCredentials:
+			id, secret, ok := r.BasicAuth()
+			if !ok || id != "svc" || secret != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"invalid_client"}`))
+				return
+			}
+			if r.PostForm.Get("scope") != "nexus.read nexus.write" || r.PostForm.Get("audience") != "nexus" {
+				t.Errorf("Unexpected form %v", r.PostForm)
+			}
+			w.Write([]byte(`{"access_token":"svc-token","token_type":"Bearer","expires_in":3600}`))
+		case GrantDeviceCode:
+			if r.PostForm.Get("client_id") != "cli" || r.PostForm.Get("device_code") != "dev-123" {
+				t.Errorf("Unexpected form %v", r.PostForm)
+			}
+			if atomic.AddInt32(&polls, 1) == 1 || deviceResult == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			if deviceResult != "ok" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": deviceResult})
+				return
+			}
+			w.Write([]byte(`{"access_token":"user-token","token_type":"Bearer","refresh_token":"r1"}`))
+		case GrantRefreshToken:
+			w.Write([]byte(`{"access_token":"refreshed","token_type":"Bearer"}`))
+		}
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("client_id") != "cli" {
+			t.Errorf("Expected client_id in form, got %v", r.PostForm)
+		}
+		w.Write([]byte(`{"device_code":"dev-123","user_code":"ABCD-EFGH","verification_uri":"https://id.example.com/device","expires_in":60,"interval":1}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestClientCredentialsToken(t *testing.T) {
+	provider, _ := oauthProvider(t, "")
+	client := NewClient(Config{BaseURL: "http://nexus.local", Token: "old"})
+	config := &OAuth2Config{
+		TokenURL:     provider.URL + "/token",
+		ClientID:     "svc",
+		ClientSecret: "s3cret",
+		Scopes:       []string{"nexus.read", "nexus.write"},
+		Audience:     "nexus",
+	}
+
+	token, err := client.ClientCredentialsToken(context.Background(), config)
+	if err != nil {
+		t.Fatalf("ClientCredentialsToken failed: %v", err)
+	}
+	if token.AccessToken != "svc-token" || client.token != "svc-token" {
+		t.Errorf("Token not applied: %+v, client token %q", token, client.token)
+	}
+	if token.Expired(time.Now(), time.Minute) || !token.Expired(time.Now().Add(time.Hour), 0) {
+		t.Errorf("Unexpected expiry %v", token.Expiry)
+	}
+
+	refreshed, err := client.RefreshOAuth2Token(context.Background(), config, "r1")
+	if err != nil || refreshed.AccessToken != "refreshed" || refreshed.RefreshToken != "r1" {
+		t.Errorf("Unexpected refresh %+v: %v", refreshed, err)
+	}
+
+	config.ClientSecret = "wrong"
+	_, err = client.ClientCredentialsToken(context.Background(), config)
+	var oauthErr *OAuth2Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_client" || oauthErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected invalid_client error, got %v", err)
+	}
+}
+
+func TestDeviceFlow(t *testing.T) {
+	for _, tt := range []struct {
+		result string
+		code   string
+	}{
+		{result: "ok"},
+		{result: "access_denied", code: "access_denied"},
+	} {
+		tt := tt
+		t.Run(tt.result, func(t *testing.T) {
+			t.Parallel()
+			provider, polls := oauthProvider(t, tt.result)
+			client := NewClient(Config{BaseURL: "http://nexus.local"})
+			config := &OAuth2Config{TokenURL: provider.URL + "/token", DeviceAuthorizationURL: provider.URL + "/device", ClientID: "cli"}
+
+			auth, err := client.StartDeviceAuthorization(context.Background(), config)
+			if err != nil {
+				t.Fatalf("StartDeviceAuthorization failed: %v", err)
+			}
+			if auth.UserCode != "ABCD-EFGH" || auth.Interval != 1 {
+				t.Errorf("Unexpected authorization %+v", auth)
+			}
+
+			token, err := client.PollDeviceToken(context.Background(), config, auth)
+			if tt.code != "" {
+				var oauthErr *OAuth2Error
+				if !errors.As(err, &oauthErr) || oauthErr.Code != tt.code {
+					t.Fatalf("Expected %s, got %v", tt.code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PollDeviceToken failed: %v", err)
+			}
+			if token.AccessToken != "user-token" || client.token != "user-token" || atomic.LoadInt32(polls) != 2 {
+				t.Errorf("Unexpected token %+v after %d polls", token, atomic.LoadInt32(polls))
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := NewClient(Config{})
+	if _, err := client.PollDeviceToken(ctx, &OAuth2Config{TokenURL: "http://idp"}, &DeviceAuthorization{DeviceCode: "x"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenClaims(t *testing.T) {
+	token, err := jwt.Sign(jwt.Claims{
+		"sub":                "user-1",
+		"email":              "ops@example.com",
+		"preferred_username": "ops",
+		"given_name":         "Ivan",
+		"tid":                "tenant-a",
+		"realm_access":       map[string]interface{}{"roles": []string{"admin", "user"}},
+		"scope":              "nexus.read nexus.write",
+		"aud":                "nexus",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}, "HS256", []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(Config{Token: token})
+	claims, err := client.TokenClaims()
+	if err != nil {
+		t.Fatalf("TokenClaims failed: %v", err)
+	}
+	if !claims.HasRole("admin") || claims.TenantID != "tenant-a" || len(claims.Scopes) != 2 || claims.Audience[0] != "nexus" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+	if claims.Expired(time.Now(), 0) || !claims.Expired(time.Now(), 2*time.Hour) {
+		t.Errorf("Unexpected expiry %v", claims.ExpiresAt)
+	}
+
+	profile := claims.UserProfile()
+	if profile.ID != "user-1" || profile.Email != "ops@example.com" || profile.Username != "ops" || profile.FirstName != "Ivan" || profile.TenantID != "tenant-a" || len(profile.Roles) != 2 {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+
+	if _, err := NewClient(Config{}).TokenClaims(); err == nil {
+		t.Error("Expected error without token")
+	}
+	if _, err := ParseTokenClaims("not-a-jwt"); err == nil {
+		t.Error("Expected error for malformed token")
+	}
+}
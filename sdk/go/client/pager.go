@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultPageSize размер страницы по умолчанию для постраничных итераторов
+const DefaultPageSize = 50
+
+var (
+	// ErrNoMorePages возвращается при запросе страницы за пределами выборки
+	ErrNoMorePages = errors.New("no more pages")
+
+	// ErrCollectLimit возвращается Collect, если результатов больше заданного лимита.
+	// Вместе с ошибкой возвращаются первые max элементов.
+	ErrCollectLimit = errors.New("collect limit reached")
+)
+
+// PageRequest описывает запрашиваемую страницу.
+// При курсорной пагинации заполнен Cursor, иначе используется Offset.
+type PageRequest struct {
+	Offset int
+	Limit  int
+	Cursor string
+}
+
+// Page представляет страницу результатов
+type Page[T any] struct {
+	Items []T
+
+	// Total общее количество элементов (0, если сервер его не сообщает)
+	Total int
+
+	// NextCursor и PrevCursor курсоры соседних страниц (для курсорной пагинации)
+	NextCursor string
+	PrevCursor string
+
+	// Request запрос, которым была получена страница
+	Request PageRequest
+}
+
+// PageFunc загружает одну страницу
+type PageFunc[T any] func(ctx context.Context, req PageRequest) (*Page[T], error)
+
+// Seq2 итератор пар значений. Совпадает по форме с iter.Seq2 из Go 1.23,
+// поэтому в новых версиях Go его можно использовать в range.
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// pageResult результат фоновой загрузки страницы
+type pageResult[T any] struct {
+	page *Page[T]
+	err  error
+}
+
+// Pager последовательно обходит постраничный endpoint.
+// Поддерживает пагинацию по смещению и по курсорам: если страница содержит
+// NextCursor, следующая запрашивается по курсору, иначе по смещению.
+// Пока вызывающий обрабатывает текущую страницу, следующая загружается в фоне.
+// Pager не предназначен для одновременного использования из нескольких горутин.
+type Pager[T any] struct {
+	fetch    PageFunc[T]
+	pageSize int
+	prefetch bool
+
+	current *Page[T]
+	next    PageRequest
+	done    bool
+
+	pending       chan pageResult[T]
+	pendingReq    PageRequest
+	cancelPending context.CancelFunc
+}
+
+// NewPager создает итератор поверх функции загрузки страницы.
+// pageSize <= 0 означает DefaultPageSize. Фоновая загрузка включена по умолчанию.
+func NewPager[T any](pageSize int, fetch PageFunc[T]) *Pager[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Pager[T]{
+		fetch:    fetch,
+		pageSize: pageSize,
+		prefetch: true,
+		next:     PageRequest{Limit: pageSize},
+	}
+}
+
+// SetPrefetch включает или отключает фоновую загрузку следующей страницы
+func (p *Pager[T]) SetPrefetch(enabled bool) *Pager[T] {
+	p.prefetch = enabled
+	if !enabled {
+		p.stopPrefetch()
+	}
+	return p
+}
+
+// StartAt задает начальную позицию обхода: смещение или курсор
+func (p *Pager[T]) StartAt(offset int, cursor string) *Pager[T] {
+	p.stopPrefetch()
+	p.current = nil
+	p.done = false
+	p.next = PageRequest{Offset: offset, Limit: p.pageSize, Cursor: cursor}
+	return p
+}
+
+// HasNext сообщает, есть ли еще страницы
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Current возвращает последнюю загруженную страницу
+func (p *Pager[T]) Current() *Page[T] {
+	return p.current
+}
+
+// NextPage загружает следующую страницу.
+// При ошибке позиция не сдвигается, и вызов можно повторить.
+func (p *Pager[T]) NextPage(ctx context.Context) (*Page[T], error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+
+	req := p.next
+	var page *Page[T]
+	var err error
+
+	if p.pending != nil && p.pendingReq == req {
+		select {
+		case res := <-p.pending:
+			page, err = res.page, res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		p.pending, p.cancelPending = nil, nil
+
+		// Фоновая загрузка могла прерваться вместе с контекстом
+		// предыдущего вызова - повторяем ее с текущим
+		if err != nil {
+			page, err = p.load(ctx, req)
+		}
+	} else {
+		p.stopPrefetch()
+		page, err = p.load(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.advance(page)
+	if !p.done && p.prefetch {
+		p.startPrefetch(ctx)
+	}
+	return page, nil
+}
+
+// PrevPage загружает страницу, предшествующую текущей:
+// по PrevCursor, если он есть, иначе по смещению
+func (p *Pager[T]) PrevPage(ctx context.Context) (*Page[T], error) {
+	if p.current == nil {
+		return nil, ErrNoMorePages
+	}
+
+	var req PageRequest
+	switch {
+	case p.current.PrevCursor != "":
+		req = PageRequest{Limit: p.pageSize, Cursor: p.current.PrevCursor}
+	case p.current.Request.Cursor == "" && p.current.Request.Offset > 0:
+		offset := p.current.Request.Offset - p.pageSize
+		if offset < 0 {
+			offset = 0
+		}
+		req = PageRequest{Offset: offset, Limit: p.pageSize}
+	default:
+		return nil, ErrNoMorePages
+	}
+
+	p.stopPrefetch()
+	page, err := p.load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.done = false
+	p.advance(page)
+	return page, nil
+}
+
+// All возвращает итератор по всем элементам начиная с текущей позиции.
+// Ошибка загрузки передается последней парой (нулевое значение, err).
+// Обход прекращается при отмене ctx или когда yield возвращает false.
+func (p *Pager[T]) All(ctx context.Context) Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer p.stopPrefetch()
+		for p.HasNext() {
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect собирает все оставшиеся элементы.
+// max > 0 ограничивает количество: если элементов больше, возвращаются
+// первые max вместе с ErrCollectLimit.
+func (p *Pager[T]) Collect(ctx context.Context, max int) ([]T, error) {
+	var items []T
+	var err error
+	p.All(ctx)(func(item T, iterErr error) bool {
+		switch {
+		case iterErr != nil:
+			err = iterErr
+			return false
+		case max > 0 && len(items) == max:
+			err = ErrCollectLimit
+			return false
+		}
+		items = append(items, item)
+		return true
+	})
+	return items, err
+}
+
+// Close останавливает фоновую загрузку
+func (p *Pager[T]) Close() {
+	p.stopPrefetch()
+}
+
+// load вызывает fetch и проставляет запрос в странице
+func (p *Pager[T]) load(ctx context.Context, req PageRequest) (*Page[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	page, err := p.fetch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		page = &Page[T]{}
+	}
+	page.Request = req
+	return page, nil
+}
+
+// advance делает страницу текущей и вычисляет запрос следующей
+func (p *Pager[T]) advance(page *Page[T]) {
+	p.current = page
+	req := page.Request
+	n := len(page.Items)
+
+	switch {
+	case page.NextCursor != "":
+		p.next = PageRequest{Limit: p.pageSize, Cursor: page.NextCursor}
+		p.done = false
+	case req.Cursor != "":
+		// Курсорная выборка без следующего курсора закончилась
+		p.done = true
+	case n == 0:
+		p.done = true
+	case page.Total > 0:
+		p.next = PageRequest{Offset: req.Offset + n, Limit: p.pageSize}
+		p.done = req.Offset+n >= page.Total
+	default:
+		// Total неизвестен: неполная страница означает конец выборки
+		p.next = PageRequest{Offset: req.Offset + n, Limit: p.pageSize}
+		p.done = n < req.Limit
+	}
+}
+
+// startPrefetch запускает загрузку следующей страницы в фоне
+func (p *Pager[T]) startPrefetch(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan pageResult[T], 1)
+	req := p.next
+
+	p.pending, p.pendingReq, p.cancelPending = ch, req, cancel
+	go func() {
+		page, err := p.load(ctx, req)
+		ch <- pageResult[T]{page: page, err: err}
+	}()
+}
+
+// stopPrefetch отменяет незавершенную фоновую загрузку
+func (p *Pager[T]) stopPrefetch() {
+	if p.cancelPending != nil {
+		p.cancelPending()
+	}
+	p.pending, p.cancelPending = nil, nil
+}
@@ -0,0 +1,301 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// offsetSource отдает числа 0..total-1 страницами по смещению
+func offsetSource(total int, calls *int32) PageFunc[int] {
+	return func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		atomic.AddInt32(calls, 1)
+		page := &Page[int]{Total: total}
+		for i := req.Offset; i < req.Offset+req.Limit && i < total; i++ {
+			page.Items = append(page.Items, i)
+		}
+		return page, nil
+	}
+}
+
+// cursorSource отдает числа 0..total-1 страницами по курсорам вида "c<offset>"
+func cursorSource(total, size int) PageFunc[int] {
+	return func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		offset := 0
+		if req.Cursor != "" {
+			offset, _ = strconv.Atoi(req.Cursor[1:])
+		}
+		page := &Page[int]{}
+		for i := offset; i < offset+size && i < total; i++ {
+			page.Items = append(page.Items, i)
+		}
+		if offset+size < total {
+			page.NextCursor = fmt.Sprintf("c%d", offset+size)
+		}
+		if offset > 0 {
+			page.PrevCursor = fmt.Sprintf("c%d", offset-size)
+		}
+		return page, nil
+	}
+}
+
+func TestPagerOffset(t *testing.T) {
+	var calls int32
+	pager := NewPager(3, offsetSource(10, &calls))
+
+	var got []int
+	pager.All(context.Background())(func(item int, err error) bool {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got = append(got, item)
+		return true
+	})
+
+	if len(got) != 10 || got[0] != 0 || got[9] != 9 {
+		t.Errorf("Expected 0..9, got %v", got)
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 page requests, got %d", calls)
+	}
+	if pager.HasNext() {
+		t.Error("Expected pager to be exhausted")
+	}
+}
+
+func TestPagerUnknownTotal(t *testing.T) {
+	pager := NewPager(4, func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		page := &Page[int]{}
+		for i := req.Offset; i < req.Offset+req.Limit && i < 6; i++ {
+			page.Items = append(page.Items, i)
+		}
+		return page, nil
+	})
+
+	items, err := pager.Collect(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(items) != 6 {
+		t.Errorf("Expected 6 items, got %v", items)
+	}
+}
+
+func TestPagerCursor(t *testing.T) {
+	pager := NewPager(4, cursorSource(10, 4)).SetPrefetch(false)
+	ctx := context.Background()
+
+	first, _ := pager.NextPage(ctx)
+	second, _ := pager.NextPage(ctx)
+	if first.Items[0] != 0 || second.Items[0] != 4 || second.Request.Cursor != "c4" {
+		t.Fatalf("Unexpected pages: %v, %v", first.Items, second)
+	}
+
+	prev, err := pager.PrevPage(ctx)
+	if err != nil {
+		t.Fatalf("PrevPage failed: %v", err)
+	}
+	if prev.Items[0] != 0 {
+		t.Errorf("Expected previous page to start at 0, got %v", prev.Items)
+	}
+	if _, err := pager.PrevPage(ctx); !errors.Is(err, ErrNoMorePages) {
+		t.Errorf("Expected ErrNoMorePages before first page, got %v", err)
+	}
+
+	rest, err := pager.Collect(ctx, 0)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(rest) != 6 || rest[0] != 4 {
+		t.Errorf("Expected items 4..9 after previous page, got %v", rest)
+	}
+}
+
+func TestPagerCollectLimit(t *testing.T) {
+	var calls int32
+	items, err := NewPager(3, offsetSource(100, &calls)).Collect(context.Background(), 5)
+	if !errors.Is(err, ErrCollectLimit) {
+		t.Errorf("Expected ErrCollectLimit, got %v", err)
+	}
+	if len(items) != 5 {
+		t.Errorf("Expected 5 items, got %d", len(items))
+	}
+
+	// Ровно max элементов - не ошибка
+	items, err = NewPager(3, offsetSource(5, &calls)).Collect(context.Background(), 5)
+	if err != nil || len(items) != 5 {
+		t.Errorf("Expected 5 items without error, got %d, %v", len(items), err)
+	}
+}
+
+func TestPagerPrefetch(t *testing.T) {
+	requested := make(chan int, 10)
+	pager := NewPager(2, func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		requested <- req.Offset
+		return &Page[int]{Items: []int{req.Offset, req.Offset + 1}, Total: 6}, nil
+	})
+	defer pager.Close()
+
+	if _, err := pager.NextPage(context.Background()); err != nil {
+		t.Fatalf("NextPage failed: %v", err)
+	}
+	<-requested
+
+	// Следующая страница запрашивается до вызова NextPage
+	select {
+	case offset := <-requested:
+		if offset != 2 {
+			t.Errorf("Expected prefetch of offset 2, got %d", offset)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Next page was not prefetched")
+	}
+
+	page, err := pager.NextPage(context.Background())
+	if err != nil || page.Items[0] != 2 {
+		t.Fatalf("Expected prefetched page, got %v, %v", page, err)
+	}
+	<-requested
+	select {
+	case offset := <-requested:
+		t.Errorf("Unexpected extra request for offset %d", offset)
+	default:
+	}
+}
+
+func TestPagerContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pager := NewPager(2, func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		if req.Offset > 0 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &Page[int]{Items: []int{0, 1}, Total: 10}, nil
+	})
+
+	var lastErr error
+	count := 0
+	pager.All(ctx)(func(item int, err error) bool {
+		if err != nil {
+			lastErr = err
+			return false
+		}
+		count++
+		if count == 2 {
+			cancel()
+		}
+		return true
+	})
+
+	if count != 2 || !errors.Is(lastErr, context.Canceled) {
+		t.Errorf("Expected 2 items and context.Canceled, got %d, %v", count, lastErr)
+	}
+}
+
+func TestPagerRetryAfterError(t *testing.T) {
+	fail := true
+	pager := NewPager(2, func(ctx context.Context, req PageRequest) (*Page[int], error) {
+		if fail {
+			return nil, errors.New("temporary failure")
+		}
+		return &Page[int]{Items: []int{1}}, nil
+	})
+
+	if _, err := pager.NextPage(context.Background()); err == nil {
+		t.Fatal("Expected error")
+	}
+	fail = false
+	page, err := pager.NextPage(context.Background())
+	if err != nil || len(page.Items) != 1 {
+		t.Errorf("Expected retry to succeed, got %v, %v", page, err)
+	}
+}
+
+func TestWebhooksPager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("active_only") != "true" {
+			t.Errorf("Expected active_only filter to be kept on every page")
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		resp := types.ListWebhooksResponse{Total: 5, Limit: int32(limit), Offset: int32(offset)}
+		for i := offset; i < offset+limit && i < 5; i++ {
+			resp.Webhooks = append(resp.Webhooks, types.WebhookInfo{ID: fmt.Sprintf("wh-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": resp})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	webhooks, err := client.WebhooksPager(&types.ListWebhooksRequest{ActiveOnly: true, Limit: 2}).Collect(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(webhooks) != 5 || webhooks[4].ID != "wh-4" {
+		t.Errorf("Unexpected webhooks: %+v", webhooks)
+	}
+}
+
+func TestConversationHistoryPager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := PathAPIV1Conversations + "/conv-1/history"
+		if r.URL.Path != expected {
+			t.Errorf("Expected path %s, got %s", expected, r.URL.Path)
+		}
+		if r.URL.Query().Get("before") != "2025-01-18T10:00:00Z" {
+			t.Errorf("Expected before filter, got %q", r.URL.Query().Get("before"))
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		resp := types.GetConversationHistoryResponse{Total: 3}
+		for i := offset; i < offset+2 && i < 3; i++ {
+			resp.Messages = append(resp.Messages, types.Message{ID: fmt.Sprintf("msg-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": resp})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	req := &types.GetConversationHistoryRequest{Limit: 2, Before: "2025-01-18T10:00:00Z"}
+	messages, err := client.ConversationHistoryPager("conv-1", req).Collect(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(messages) != 3 || messages[2].ID != "msg-2" {
+		t.Errorf("Unexpected messages: %+v", messages)
+	}
+}
+
+func TestDomainsPager(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "a"}, {"id": "b"}, {"id": "c"}]`))
+	}))
+	defer server.Close()
+
+	admin := NewClient(Config{BaseURL: server.URL}).Admin()
+	domains, err := admin.DomainsPager(2).Collect(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(domains) != 3 || domains[2].ID != "c" {
+		t.Errorf("Unexpected domains: %+v", domains)
+	}
+	if calls != 1 {
+		t.Errorf("Expected list to be loaded once, got %d requests", calls)
+	}
+}
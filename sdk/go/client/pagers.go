@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// WebhooksPager возвращает итератор по зарегистрированным webhooks.
+// req задает фильтры и размер страницы (Limit); Offset задает начальную позицию.
+func (c *Client) WebhooksPager(req *types.ListWebhooksRequest) *Pager[types.WebhookInfo] {
+	base := types.ListWebhooksRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	pager := NewPager(int(base.Limit), func(ctx context.Context, page PageRequest) (*Page[types.WebhookInfo], error) {
+		pageReq := base
+		pageReq.Limit = int32(page.Limit)
+		pageReq.Offset = int32(page.Offset)
+		pageReq.Metadata = nil
+
+		resp, err := c.ListWebhooks(ctx, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[types.WebhookInfo]{Items: resp.Webhooks, Total: int(resp.Total)}, nil
+	})
+	return pager.StartAt(int(base.Offset), "")
+}
+
+// EventsPager возвращает итератор по событиям аналитики.
+// req задает фильтры и размер страницы (Limit); Offset задает начальную позицию.
+func (c *Client) EventsPager(req *types.GetEventsRequest) *Pager[types.AnalyticsEvent] {
+	base := types.GetEventsRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	pager := NewPager(int(base.Limit), func(ctx context.Context, page PageRequest) (*Page[types.AnalyticsEvent], error) {
+		pageReq := base
+		pageReq.Limit = int32(page.Limit)
+		pageReq.Offset = int32(page.Offset)
+
+		resp, err := c.GetEvents(ctx, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[types.AnalyticsEvent]{Items: resp.Events, Total: int(resp.Total)}, nil
+	})
+	return pager.StartAt(int(base.Offset), "")
+}
+
+// ConversationHistoryPager возвращает итератор по истории сообщений беседы.
+// req задает фильтры по времени и размер страницы (Limit, не более 100).
+func (c *Client) ConversationHistoryPager(conversationID string, req *types.GetConversationHistoryRequest) *Pager[types.Message] {
+	base := types.GetConversationHistoryRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	pager := NewPager(int(base.Limit), func(ctx context.Context, page PageRequest) (*Page[types.Message], error) {
+		pageReq := base
+		pageReq.Limit = int32(page.Limit)
+		pageReq.Offset = int32(page.Offset)
+
+		resp, err := c.GetConversationHistory(ctx, conversationID, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[types.Message]{Items: resp.Messages, Total: int(resp.Total)}, nil
+	})
+	return pager.StartAt(int(base.Offset), "")
+}
+
+// PromptsPager возвращает итератор по промптам домена (пустой domain - все промпты)
+func (ac *AdminClient) PromptsPager(domain string, pageSize int) *Pager[*types.PromptConfig] {
+	return NewPager(pageSize, sliceFetcher(func(ctx context.Context) ([]*types.PromptConfig, error) {
+		return ac.ListPrompts(ctx, domain)
+	}))
+}
+
+// DomainsPager возвращает итератор по конфигурациям доменов
+func (ac *AdminClient) DomainsPager(pageSize int) *Pager[*types.DomainConfig] {
+	return NewPager(pageSize, sliceFetcher(ac.ListDomains))
+}
+
+// IntegrationsPager возвращает итератор по интеграциям указанного типа (пустой тип - все)
+func (ac *AdminClient) IntegrationsPager(integrationType string, pageSize int) *Pager[*types.IntegrationConfig] {
+	return NewPager(pageSize, sliceFetcher(func(ctx context.Context) ([]*types.IntegrationConfig, error) {
+		return ac.ListIntegrations(ctx, integrationType)
+	}))
+}
+
+// sliceFetcher адаптирует endpoint, возвращающий весь список целиком:
+// список загружается один раз при запросе первой страницы и далее
+// нарезается на страницы локально
+func sliceFetcher[T any](list func(ctx context.Context) ([]T, error)) PageFunc[T] {
+	var mu sync.Mutex
+	var items []T
+	loaded := false
+
+	return func(ctx context.Context, req PageRequest) (*Page[T], error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !loaded || req.Offset == 0 {
+			all, err := list(ctx)
+			if err != nil {
+				return nil, err
+			}
+			items, loaded = all, true
+		}
+
+		start := req.Offset
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + req.Limit
+		if end > len(items) {
+			end = len(items)
+		}
+		return &Page[T]{Items: items[start:end], Total: len(items)}, nil
+	}
+}
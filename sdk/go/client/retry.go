@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"math"
 	"net/http"
 	"time"
@@ -33,6 +34,23 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
+// noRetryKey ключ контекста, отключающий retry
+type noRetryKey struct{}
+
+// WithoutRetry возвращает контекст, в котором запросы клиента отправляются
+// ровно один раз, без повторов по RetryConfig. Нужен для неидемпотентных
+// операций (оплата, отправка сообщения), которые нельзя повторять, не зная,
+// выполнил ли их сервер.
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryDisabled проверяет, отключен ли retry в контексте
+func retryDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRetryKey{}).(bool)
+	return disabled
+}
+
 // isRetryableError проверяет, можно ли повторить запрос при данной ошибке
 func (c *Client) isRetryableError(err error, statusCode int) bool {
 	// Проверяем статус код
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Параметры сессии по умолчанию
+const (
+	DefaultSessionMessages = 50
+
+	// sessionHistoryPage размер страницы истории при синхронизации (максимум сервера)
+	sessionHistoryPage = 100
+)
+
+// ErrSessionNotFound возвращается SessionStore.Load и ResumeSession, если
+// сессия не сохранялась
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionState сохраняемое состояние сессии
+type SessionState struct {
+	ID             string                          `json:"id"`
+	ConversationID string                          `json:"conversation_id,omitempty"` // Пусто, пока беседа не создана
+	Conversation   types.CreateConversationRequest `json:"conversation"`              // Параметры создания беседы
+	Messages       []types.Message                 `json:"messages,omitempty"`        // Последние сообщения в хронологическом порядке
+	Total          int32                           `json:"total"`                     // Сообщений в беседе на сервере
+	UpdatedAt      time.Time                       `json:"updated_at"`
+}
+
+// SessionStore хранилище состояния сессий. Реализации должны быть
+// безопасны для конкурентного использования.
+type SessionStore interface {
+	// Load возвращает состояние или ErrSessionNotFound
+	Load(ctx context.Context, id string) (*SessionState, error)
+	// Save сохраняет состояние под state.ID
+	Save(ctx context.Context, state *SessionState) error
+	// Delete удаляет состояние
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionConfig настройки сессии
+type SessionConfig struct {
+	ID           string                          // Ключ сессии в Store (пусто = новый UUID)
+	Conversation types.CreateConversationRequest // Параметры беседы, создаваемой при первом сообщении
+	MaxMessages  int                             // Размер локальной истории (0 = DefaultSessionMessages)
+	Store        SessionStore                    // Хранилище состояния (nil = без сохранения)
+}
+
+// Session беседа с локальной историей. Беседа создается на сервере при
+// первом сообщении; локально хранятся последние MaxMessages сообщений.
+// Если на сервере появились сообщения, отправленные не через эту сессию,
+// история синхронизируется с сервером. После каждого изменения состояние
+// сохраняется в Store, поэтому после перезапуска сессию можно продолжить
+// через ResumeSession.
+//
+// Пример использования:
+//
+//	session, err := nexus.ResumeSession(ctx, chatID, client.SessionConfig{Store: store})
+//	if errors.Is(err, client.ErrSessionNotFound) {
+//		session = nexus.NewSession(client.SessionConfig{ID: chatID, Store: store})
+//	}
+//	resp, err := session.Send(ctx, "Привет!")
+type Session struct {
+	client      *Client
+	store       SessionStore
+	maxMessages int
+
+	opMu sync.Mutex // Сообщения и синхронизации выполняются по одной
+
+	mu    sync.Mutex
+	state SessionState
+}
+
+// NewSession создает новую сессию. Запросов к серверу не выполняется.
+func (c *Client) NewSession(config SessionConfig) *Session {
+	id := config.ID
+	if id == "" {
+		id = uuid.New().String()
+	}
+	conversation := config.Conversation
+	conversation.Metadata = nil
+	return c.newSession(config, SessionState{ID: id, Conversation: conversation})
+}
+
+// ResumeSession загружает сессию id из config.Store и синхронизирует ее
+// историю с сервером. config.ID игнорируется.
+func (c *Client) ResumeSession(ctx context.Context, id string, config SessionConfig) (*Session, error) {
+	if config.Store == nil {
+		return nil, fmt.Errorf("resume session %s: %w", id, ErrSessionNotFound)
+	}
+	state, err := config.Store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s := c.newSession(config, *state)
+	if err := s.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (c *Client) newSession(config SessionConfig, state SessionState) *Session {
+	s := &Session{client: c, store: config.Store, maxMessages: config.MaxMessages, state: state}
+	if s.maxMessages <= 0 {
+		s.maxMessages = DefaultSessionMessages
+	}
+	s.state.Messages = s.trim(s.state.Messages)
+	return s
+}
+
+// ID возвращает ключ сессии
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.ID
+}
+
+// ConversationID возвращает ID беседы ("" - беседа еще не создана)
+func (s *Session) ConversationID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.ConversationID
+}
+
+// Messages возвращает копию локальной истории в хронологическом порядке
+func (s *Session) Messages() []types.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Message(nil), s.state.Messages...)
+}
+
+// State возвращает копию текущего состояния
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.state
+	state.Messages = append([]types.Message(nil), s.state.Messages...)
+	return state
+}
+
+// Send отправляет текстовое сообщение
+func (s *Session) Send(ctx context.Context, content string) (*types.MessageResponse, error) {
+	return s.SendMessage(ctx, &types.SendMessageRequest{Content: content})
+}
+
+// SendMessage отправляет сообщение, при необходимости создав беседу, и
+// добавляет сообщение пользователя и ответ AI в локальную историю. Если
+// сообщение отправлено, но состояние не сохранилось, возвращаются и ответ,
+// и ошибка. Создание беседы и отправка не повторяются по RetryConfig, чтобы
+// не создать дубликаты, если сервер обработал запрос, но ответ потерялся.
+func (s *Session) SendMessage(ctx context.Context, req *types.SendMessageRequest) (*types.MessageResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	conversationID, err := s.ensureConversation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.SendMessage(WithoutRetry(ctx), conversationID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	expected := s.state.Total
+	for _, msg := range []*types.Message{resp.UserMessage, resp.AIResponse} {
+		if msg != nil {
+			s.state.Messages = append(s.state.Messages, *msg)
+			expected++
+		}
+	}
+	s.state.Messages = s.trim(s.state.Messages)
+	s.state.Total = expected
+	s.mu.Unlock()
+
+	// Сообщения, отправленные в беседу в обход сессии
+	if resp.TotalMessages > expected {
+		err := s.sync(ctx)
+		if err == nil {
+			return resp, nil
+		}
+		s.client.logger.Warn("Failed to sync session history",
+			Field{Key: "session_id", Value: s.ID()},
+			Field{Key: "error", Value: err.Error()},
+		)
+	}
+	return resp, s.save(ctx)
+}
+
+// Sync заменяет локальную историю последними сообщениями беседы на сервере
+func (s *Session) Sync(ctx context.Context) error {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+	return s.sync(ctx)
+}
+
+// Delete удаляет состояние сессии из Store. Беседа на сервере сохраняется.
+func (s *Session) Delete(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Delete(ctx, s.ID())
+}
+
+// ensureConversation создает беседу, если ее еще нет
+func (s *Session) ensureConversation(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	conversationID, req := s.state.ConversationID, s.state.Conversation
+	s.mu.Unlock()
+	if conversationID != "" {
+		return conversationID, nil
+	}
+
+	conv, err := s.client.CreateConversation(WithoutRetry(ctx), &req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create conversation: %w", err)
+	}
+	s.mu.Lock()
+	// Total остается 0: сообщения, созданные сервером вместе с беседой
+	// (например, системный промпт), подтянутся синхронизацией после ответа
+	s.state.ConversationID = conv.ID
+	s.mu.Unlock()
+	// Сохраняем сразу, чтобы беседа не потерялась, если отправка не удастся
+	if err := s.save(ctx); err != nil {
+		return "", err
+	}
+	return conv.ID, nil
+}
+
+// sync загружает последние maxMessages сообщений; вызывается под opMu
+func (s *Session) sync(ctx context.Context) error {
+	conversationID := s.ConversationID()
+	if conversationID == "" {
+		return nil
+	}
+
+	head, err := s.client.GetConversationHistory(ctx, conversationID, &types.GetConversationHistoryRequest{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to sync session history: %w", err)
+	}
+	offset := head.Total - int32(s.maxMessages)
+	if offset < 0 {
+		offset = 0
+	}
+
+	messages := make([]types.Message, 0, head.Total-offset)
+	for offset < head.Total {
+		page, err := s.client.GetConversationHistory(ctx, conversationID, &types.GetConversationHistoryRequest{Limit: sessionHistoryPage, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("failed to sync session history: %w", err)
+		}
+		if len(page.Messages) == 0 {
+			break
+		}
+		messages = append(messages, page.Messages...)
+		offset += int32(len(page.Messages))
+	}
+
+	s.mu.Lock()
+	s.state.Messages = s.trim(messages)
+	s.state.Total = head.Total
+	s.mu.Unlock()
+	return s.save(ctx)
+}
+
+// trim оставляет последние maxMessages сообщений
+func (s *Session) trim(messages []types.Message) []types.Message {
+	if len(messages) <= s.maxMessages {
+		return messages
+	}
+	return append([]types.Message(nil), messages[len(messages)-s.maxMessages:]...)
+}
+
+// save сохраняет состояние в Store
+func (s *Session) save(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	state := s.State()
+	state.UpdatedAt = time.Now()
+	if err := s.store.Save(ctx, &state); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// MemorySessionStore реализует SessionStore в памяти (для тестов и
+// однопроцессных приложений: состояние теряется при перезапуске)
+type MemorySessionStore struct {
+	mu     sync.Mutex
+	states map[string]*SessionState
+}
+
+// NewMemorySessionStore создает хранилище сессий в памяти
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{states: make(map[string]*SessionState)}
+}
+
+// Load возвращает копию сохраненного состояния
+func (m *MemorySessionStore) Load(ctx context.Context, id string) (*SessionState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[id]
+	if !ok {
+		return nil, fmt.Errorf("load session %s: %w", id, ErrSessionNotFound)
+	}
+	loaded := *state
+	loaded.Messages = append([]types.Message(nil), state.Messages...)
+	return &loaded, nil
+}
+
+// Save сохраняет копию состояния
+func (m *MemorySessionStore) Save(ctx context.Context, state *SessionState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *state
+	stored.Messages = append([]types.Message(nil), state.Messages...)
+	m.states[state.ID] = &stored
+	return nil
+}
+
+// Delete удаляет состояние
+func (m *MemorySessionStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, id)
+	return nil
+}
+
+// FileSessionStore реализует SessionStore в виде JSON файлов в директории
+type FileSessionStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileSessionStore создает файловое хранилище сессий в указанной
+// директории (создается при необходимости)
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// filePath возвращает путь к файлу сессии
+func (f *FileSessionStore) filePath(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load читает состояние с диска
+func (f *FileSessionStore) Load(ctx context.Context, id string) (*SessionState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.filePath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("load session %s: %w", id, ErrSessionNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	if state.ID != id {
+		return nil, fmt.Errorf("load session %s: %w", id, ErrSessionNotFound)
+	}
+	return &state, nil
+}
+
+// Save атомарно записывает состояние на диск (через временный файл)
+func (f *FileSessionStore) Save(ctx context.Context, state *SessionState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	tmp, err := os.CreateTemp(f.dir, "session-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.filePath(state.ID)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет файл сессии
+func (f *FileSessionStore) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(f.filePath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
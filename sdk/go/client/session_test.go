@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// conversationServer хранит одну беседу и отвечает как сервер Nexus
+type conversationServer struct {
+	mu          sync.Mutex
+	created     int
+	posts       int // запросов на отправку сообщения
+	unavailable int // столько отправок завершаются 503
+	messages    []types.Message
+}
+
+func (s *conversationServer) add(sender, content string) types.Message {
+	msg := types.Message{ID: fmt.Sprintf("m%d", len(s.messages)+1), ConversationID: "conv-1", SenderType: sender, Type: "text", Content: content}
+	s.messages = append(s.messages, msg)
+	return msg
+}
+
+func (s *conversationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data interface{}
+	switch {
+	case r.URL.Path == PathAPIV1Conversations:
+		s.created++
+		data = types.Conversation{ID: "conv-1", Status: "active"}
+	case strings.HasSuffix(r.URL.Path, "/messages"):
+		s.posts++
+		if s.unavailable > 0 {
+			s.unavailable--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req types.SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		user := s.add("user", req.Content)
+		ai := s.add("assistant", "re: "+req.Content)
+		data = types.MessageResponse{ConversationID: "conv-1", UserMessage: &user, AIResponse: &ai, TotalMessages: int32(len(s.messages))}
+	case strings.HasSuffix(r.URL.Path, "/history"):
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := offset + limit
+		if end > len(s.messages) {
+			end = len(s.messages)
+		}
+		data = types.GetConversationHistoryResponse{Messages: s.messages[offset:end], Total: int32(len(s.messages)), Limit: int32(limit), Offset: int32(offset)}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"metadata": map[string]string{}, "data": data})
+}
+
+func contents(messages []types.Message) string {
+	parts := make([]string, len(messages))
+	for i, msg := range messages {
+		parts[i] = msg.Content
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestSessionLazyCreateAndWindow(t *testing.T) {
+	server := &conversationServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	store := NewMemorySessionStore()
+	client := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &RetryConfig{}})
+	session := client.NewSession(SessionConfig{ID: "chat-1", MaxMessages: 3, Store: store, Conversation: types.CreateConversationRequest{Title: "Support"}})
+	ctx := context.Background()
+
+	if session.ConversationID() != "" || server.created != 0 {
+		t.Fatal("Conversation must not be created before the first message")
+	}
+	if _, err := store.Load(ctx, "chat-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	for _, text := range []string{"a", "b"} {
+		if _, err := session.Send(ctx, text); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if server.created != 1 || session.ConversationID() != "conv-1" {
+		t.Errorf("Expected one conversation, created %d", server.created)
+	}
+	if got := contents(session.Messages()); got != "re: a,b,re: b" {
+		t.Errorf("Expected last 3 messages, got %q", got)
+	}
+
+	saved, err := store.Load(ctx, "chat-1")
+	if err != nil || saved.ConversationID != "conv-1" || saved.Total != 4 || contents(saved.Messages) != "re: a,b,re: b" || saved.Conversation.Title != "Support" {
+		t.Errorf("Unexpected saved state %+v, %v", saved, err)
+	}
+}
+
+func TestSessionSendWithoutRetry(t *testing.T) {
+	server := &conversationServer{unavailable: 1}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	retry := DefaultRetryConfig()
+	retry.InitialDelay = time.Millisecond
+	client := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &retry})
+	session := client.NewSession(SessionConfig{})
+	ctx := context.Background()
+
+	if _, err := session.Send(ctx, "a"); err == nil {
+		t.Fatal("Expected error for 503")
+	}
+	if server.posts != 1 || len(session.Messages()) != 0 {
+		t.Errorf("Expected exactly one POST and no local messages, got %d posts and %d messages", server.posts, len(session.Messages()))
+	}
+
+	if _, err := session.Send(ctx, "a"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if server.created != 1 || contents(session.Messages()) != "a,re: a" {
+		t.Errorf("Unexpected state after resend: created %d, messages %q", server.created, contents(session.Messages()))
+	}
+}
+
+func TestSessionSyncsExternalMessages(t *testing.T) {
+	server := &conversationServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &RetryConfig{}})
+	session := client.NewSession(SessionConfig{MaxMessages: 10})
+	ctx := context.Background()
+	if _, err := session.Send(ctx, "a"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	// Сообщение, отправленное другим клиентом
+	server.mu.Lock()
+	server.add("system", "operator joined")
+	server.mu.Unlock()
+
+	if _, err := session.Send(ctx, "b"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := contents(session.Messages()); got != "a,re: a,operator joined,b,re: b" {
+		t.Errorf("Expected history synced with server, got %q", got)
+	}
+	if state := session.State(); state.Total != 5 || state.ID == "" {
+		t.Errorf("Unexpected state %+v", state)
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	server := &conversationServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	client := NewClient(Config{BaseURL: httpServer.URL, RetryConfig: &RetryConfig{}})
+	ctx := context.Background()
+
+	if _, err := client.ResumeSession(ctx, "chat-1", SessionConfig{Store: store}); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	session := client.NewSession(SessionConfig{ID: "chat-1", Store: store})
+	if _, err := session.Send(ctx, "a"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	// Пока сервис был остановлен, в беседе появились сообщения
+	server.mu.Lock()
+	for i := 0; i < 150; i++ {
+		server.add("user", strconv.Itoa(i))
+	}
+	server.mu.Unlock()
+
+	resumed, err := client.ResumeSession(ctx, "chat-1", SessionConfig{Store: store, MaxMessages: 120})
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	messages := resumed.Messages()
+	if resumed.ConversationID() != "conv-1" || len(messages) != 120 || messages[0].Content != "30" || messages[119].Content != "149" {
+		t.Fatalf("Unexpected resumed history: %d messages, first %q", len(messages), messages[0].Content)
+	}
+	if _, err := resumed.Send(ctx, "b"); err != nil || server.created != 1 {
+		t.Errorf("Resumed session must reuse the conversation: %v, created %d", err, server.created)
+	}
+
+	if err := resumed.Delete(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Load(ctx, "chat-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound after delete, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/workflow"
 )
 
 // ExecuteTemplate выполняет контекстно-зависимый шаблон.
@@ -75,9 +76,10 @@ func (c *Client) ExecuteTemplate(ctx context.Context, req *types.ExecuteTemplate
 
 // GetExecutionStatus получает статус выполнения шаблона по execution ID.
 // executionID должен быть валидным UUID.
+// Если настроен CacheConfig, ответ кэшируется локально согласно x-cache-control.
 func (c *Client) GetExecutionStatus(ctx context.Context, executionID string) (*types.ExecuteTemplateResponse, error) {
 	path := fmt.Sprintf("%s/%s", PathAPIV1TemplatesStatus, executionID)
-	resp, err := c.doRequest(ctx, "GET", path, nil)
+	resp, err := c.doCachedGet(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -113,60 +115,23 @@ func (c *Client) StreamTemplateResults(ctx context.Context, executionID string)
 	return resp, nil
 }
 
-// GetWorkflowSteps возвращает шаги workflow из ответа, отсортированные по номеру шага
+// GetWorkflowSteps возвращает шаги workflow из ответа, отсортированные по номеру шага.
+// Для выполнения шагов с учетом зависимостей используйте workflow.Executor.
 func (c *Client) GetWorkflowSteps(result *types.ExecuteTemplateResponse) []types.WorkflowStep {
-	if result.Workflow == nil || len(result.Workflow.Steps) == 0 {
-		return nil
-	}
-	
-	// Сортируем шаги по номеру
-	steps := make([]types.WorkflowStep, len(result.Workflow.Steps))
-	copy(steps, result.Workflow.Steps)
-	
-	// Простая сортировка по номеру шага
-	for i := 0; i < len(steps)-1; i++ {
-		for j := i + 1; j < len(steps); j++ {
-			if steps[i].Step > steps[j].Step {
-				steps[i], steps[j] = steps[j], steps[i]
-			}
-		}
-	}
-	
-	return steps
+	return workflow.SortedSteps(result.Workflow)
 }
 
-// GetNextWorkflowStep возвращает следующий шаг workflow, который готов к выполнению
-// (все зависимости выполнены)
+// GetNextWorkflowStep возвращает первый шаг workflow, готовый к выполнению
+// (см. workflow.ReadySteps), или nil, если готовых шагов нет или граф некорректен
 func (c *Client) GetNextWorkflowStep(result *types.ExecuteTemplateResponse) *types.WorkflowStep {
 	if result.Workflow == nil {
 		return nil
 	}
-	
-	steps := c.GetWorkflowSteps(result)
-	completedResults := make(map[string]bool)
-	
-	for _, step := range steps {
-		// Проверяем, выполнены ли все зависимости
-		allDepsCompleted := true
-		for _, depID := range step.DependsOn {
-			if !completedResults[depID] {
-				allDepsCompleted = false
-				break
-			}
-		}
-		
-		// Если зависимости выполнены и шаг еще не выполнен
-		if allDepsCompleted && step.Status == "pending" {
-			return &step
-		}
-		
-		// Если шаг выполнен, добавляем его результат в список выполненных
-		if step.Status == "completed" && step.ResultID != "" {
-			completedResults[step.ResultID] = true
-		}
+	ready, err := workflow.ReadySteps(result.Workflow)
+	if err != nil || len(ready) == 0 {
+		return nil
 	}
-	
-	return nil
+	return &ready[0]
 }
 
 // GetWorkflowStepByDomain возвращает шаги workflow для указанного домена
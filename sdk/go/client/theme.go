@@ -0,0 +1,288 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Базовые темы FrontendConfig.Theme
+const (
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeCustom = "custom"
+)
+
+// Theme типизированная тема фронтенда. Известные ключи FrontendConfig
+// разобраны в поля, остальные сохраняются в Other.
+type Theme struct {
+	Base       string                       // Базовая тема: light или dark
+	Colors     ThemeColors                  // FrontendConfig.Colors
+	Layout     ThemeLayout                  // FrontendConfig.Layout
+	Components map[string]map[string]string // Стили компонентов: button -> border_radius -> 8px
+	Branding   ThemeBranding                // FrontendConfig.Branding
+}
+
+// ThemeColors цветовая схема
+type ThemeColors struct {
+	Primary    string
+	Secondary  string
+	Accent     string
+	Background string
+	Text       string
+	Other      map[string]string
+}
+
+// ThemeLayout настройки layout
+type ThemeLayout struct {
+	HeaderHeight       string
+	HeaderSticky       *bool
+	SidebarWidth       string
+	SidebarCollapsible *bool
+	Other              map[string]interface{}
+}
+
+// ThemeBranding логотип и название
+type ThemeBranding struct {
+	Name    string
+	Logo    string
+	Favicon string
+	Other   map[string]string
+}
+
+// BaseTheme возвращает встроенную тему light или dark; для остальных
+// имен - light
+func BaseTheme(name string) *Theme {
+	sticky, collapsible := true, true
+	theme := &Theme{
+		Base: ThemeLight,
+		Colors: ThemeColors{
+			Primary:    "#0066CC",
+			Secondary:  "#00CC66",
+			Accent:     "#FF6600",
+			Background: "#FFFFFF",
+			Text:       "#333333",
+		},
+		Layout: ThemeLayout{HeaderHeight: "64px", HeaderSticky: &sticky, SidebarWidth: "240px", SidebarCollapsible: &collapsible},
+		Components: map[string]map[string]string{
+			"button": {"border_radius": "8px", "padding": "12px 24px"},
+			"card":   {"border_radius": "12px", "shadow": "0 2px 8px rgba(0,0,0,0.1)"},
+		},
+	}
+	if name == ThemeDark {
+		theme.Base = ThemeDark
+		theme.Colors.Primary = "#4D9FFF"
+		theme.Colors.Background = "#121212"
+		theme.Colors.Text = "#E6E6E6"
+		theme.Components["card"]["shadow"] = "0 2px 8px rgba(0,0,0,0.6)"
+	}
+	return theme
+}
+
+// ResolveTheme строит тему конфигурации: встроенная тема config.Theme
+// (custom наследует light) с переопределениями из Colors, Layout,
+// Components и Branding
+func ResolveTheme(config *types.FrontendConfig) *Theme {
+	return BaseTheme(config.Theme).Merge(ParseTheme(config))
+}
+
+// ParseTheme разбирает только значения, заданные в конфигурации, без
+// наследования базовой темы
+func ParseTheme(config *types.FrontendConfig) *Theme {
+	theme := &Theme{Base: config.Theme}
+
+	for key, value := range config.Colors {
+		switch key {
+		case "primary":
+			theme.Colors.Primary = value
+		case "secondary":
+			theme.Colors.Secondary = value
+		case "accent":
+			theme.Colors.Accent = value
+		case "background":
+			theme.Colors.Background = value
+		case "text":
+			theme.Colors.Text = value
+		default:
+			theme.Colors.Other = setString(theme.Colors.Other, key, value)
+		}
+	}
+
+	for key, value := range config.Layout {
+		section, _ := value.(map[string]interface{})
+		switch {
+		case key == "header" && section != nil:
+			theme.Layout.HeaderHeight = stringValue(section["height"])
+			theme.Layout.HeaderSticky = boolValue(section["sticky"])
+		case key == "sidebar" && section != nil:
+			theme.Layout.SidebarWidth = stringValue(section["width"])
+			theme.Layout.SidebarCollapsible = boolValue(section["collapsible"])
+		default:
+			if theme.Layout.Other == nil {
+				theme.Layout.Other = make(map[string]interface{})
+			}
+			theme.Layout.Other[key] = value
+		}
+	}
+
+	for name, value := range config.Components {
+		props, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if theme.Components == nil {
+			theme.Components = make(map[string]map[string]string)
+		}
+		style := make(map[string]string, len(props))
+		for prop, v := range props {
+			style[prop] = stringValue(v)
+		}
+		theme.Components[name] = style
+	}
+
+	for key, value := range config.Branding {
+		switch key {
+		case "name":
+			theme.Branding.Name = value
+		case "logo":
+			theme.Branding.Logo = value
+		case "favicon":
+			theme.Branding.Favicon = value
+		default:
+			theme.Branding.Other = setString(theme.Branding.Other, key, value)
+		}
+	}
+	return theme
+}
+
+// Merge возвращает новую тему: значения override, заданные явно (непустые
+// строки, не-nil флаги, ключи карт), заменяют значения t. Стили
+// компонентов объединяются по свойствам. t и override не изменяются.
+func (t *Theme) Merge(override *Theme) *Theme {
+	merged := t.clone()
+	if override == nil {
+		return merged
+	}
+	if override.Base == ThemeLight || override.Base == ThemeDark {
+		merged.Base = override.Base
+	}
+
+	mergeString(&merged.Colors.Primary, override.Colors.Primary)
+	mergeString(&merged.Colors.Secondary, override.Colors.Secondary)
+	mergeString(&merged.Colors.Accent, override.Colors.Accent)
+	mergeString(&merged.Colors.Background, override.Colors.Background)
+	mergeString(&merged.Colors.Text, override.Colors.Text)
+	for k, v := range override.Colors.Other {
+		merged.Colors.Other = setString(merged.Colors.Other, k, v)
+	}
+
+	mergeString(&merged.Layout.HeaderHeight, override.Layout.HeaderHeight)
+	mergeString(&merged.Layout.SidebarWidth, override.Layout.SidebarWidth)
+	if override.Layout.HeaderSticky != nil {
+		v := *override.Layout.HeaderSticky
+		merged.Layout.HeaderSticky = &v
+	}
+	if override.Layout.SidebarCollapsible != nil {
+		v := *override.Layout.SidebarCollapsible
+		merged.Layout.SidebarCollapsible = &v
+	}
+	for k, v := range override.Layout.Other {
+		if merged.Layout.Other == nil {
+			merged.Layout.Other = make(map[string]interface{})
+		}
+		merged.Layout.Other[k] = v
+	}
+
+	for name, style := range override.Components {
+		if merged.Components == nil {
+			merged.Components = make(map[string]map[string]string)
+		}
+		for prop, v := range style {
+			merged.Components[name] = setString(merged.Components[name], prop, v)
+		}
+	}
+
+	mergeString(&merged.Branding.Name, override.Branding.Name)
+	mergeString(&merged.Branding.Logo, override.Branding.Logo)
+	mergeString(&merged.Branding.Favicon, override.Branding.Favicon)
+	for k, v := range override.Branding.Other {
+		merged.Branding.Other = setString(merged.Branding.Other, k, v)
+	}
+	return merged
+}
+
+// Component возвращает свойство стиля компонента ("" - не задано)
+func (t *Theme) Component(name, prop string) string {
+	return t.Components[name][prop]
+}
+
+// clone глубокая копия темы
+func (t *Theme) clone() *Theme {
+	c := *t
+	c.Colors.Other = copyStrings(t.Colors.Other)
+	c.Branding.Other = copyStrings(t.Branding.Other)
+	if t.Layout.HeaderSticky != nil {
+		v := *t.Layout.HeaderSticky
+		c.Layout.HeaderSticky = &v
+	}
+	if t.Layout.SidebarCollapsible != nil {
+		v := *t.Layout.SidebarCollapsible
+		c.Layout.SidebarCollapsible = &v
+	}
+	if t.Layout.Other != nil {
+		c.Layout.Other = make(map[string]interface{}, len(t.Layout.Other))
+		for k, v := range t.Layout.Other {
+			c.Layout.Other[k] = v
+		}
+	}
+	if t.Components != nil {
+		c.Components = make(map[string]map[string]string, len(t.Components))
+		for name, style := range t.Components {
+			c.Components[name] = copyStrings(style)
+		}
+	}
+	return &c
+}
+
+func mergeString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func setString(m map[string]string, key, value string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	m[key] = value
+	return m
+}
+
+func copyStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+func boolValue(v interface{}) *bool {
+	b, ok := v.(bool)
+	if !ok {
+		return nil
+	}
+	return &b
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestResolveTheme(t *testing.T) {
+	config := &types.FrontendConfig{
+		Theme:  ThemeCustom,
+		Colors: map[string]string{"primary": "#FF0000", "warning": "#FFCC00"},
+		Layout: map[string]interface{}{
+			"header": map[string]interface{}{"sticky": false},
+			"footer": map[string]interface{}{"visible": true},
+		},
+		Components: map[string]interface{}{
+			"button": map[string]interface{}{"border_radius": "0"},
+			"input":  map[string]interface{}{"height": 40},
+		},
+		Branding: map[string]string{"name": "Acme", "support_email": "help@acme.io"},
+	}
+
+	theme := ResolveTheme(config)
+	if theme.Base != ThemeLight {
+		t.Errorf("Custom theme must inherit light, got %s", theme.Base)
+	}
+	if theme.Colors.Primary != "#FF0000" || theme.Colors.Background != "#FFFFFF" || theme.Colors.Other["warning"] != "#FFCC00" {
+		t.Errorf("Unexpected colors %+v", theme.Colors)
+	}
+	if theme.Layout.HeaderSticky == nil || *theme.Layout.HeaderSticky || theme.Layout.HeaderHeight != "64px" {
+		t.Errorf("Expected sticky=false override with inherited height, got %+v", theme.Layout)
+	}
+	if theme.Layout.Other["footer"] == nil {
+		t.Error("Unknown layout sections must be kept")
+	}
+	if theme.Component("button", "border_radius") != "0" || theme.Component("button", "padding") != "12px 24px" || theme.Component("input", "height") != "40" {
+		t.Errorf("Unexpected components %+v", theme.Components)
+	}
+	if theme.Branding.Name != "Acme" || theme.Branding.Other["support_email"] != "help@acme.io" {
+		t.Errorf("Unexpected branding %+v", theme.Branding)
+	}
+
+	dark := ResolveTheme(&types.FrontendConfig{Theme: ThemeDark})
+	if dark.Base != ThemeDark || dark.Colors.Background != "#121212" || dark.Colors.Secondary != "#00CC66" {
+		t.Errorf("Unexpected dark theme %+v", dark.Colors)
+	}
+}
+
+func TestThemeMergeDoesNotMutate(t *testing.T) {
+	base := BaseTheme(ThemeLight)
+	sticky := false
+	override := &Theme{
+		Base:       ThemeDark,
+		Layout:     ThemeLayout{HeaderSticky: &sticky},
+		Components: map[string]map[string]string{"card": {"shadow": "none"}},
+	}
+
+	merged := base.Merge(override)
+	if merged.Base != ThemeDark || *merged.Layout.HeaderSticky || merged.Component("card", "shadow") != "none" || merged.Component("card", "border_radius") != "12px" {
+		t.Errorf("Unexpected merge result %+v", merged)
+	}
+	if !*base.Layout.HeaderSticky || base.Component("card", "shadow") == "none" {
+		t.Error("Merge must not modify the base theme")
+	}
+	sticky = true
+	if *merged.Layout.HeaderSticky {
+		t.Error("Merge must copy override flags")
+	}
+
+	// Custom не меняет базу, пустые значения не переопределяют
+	merged = merged.Merge(&Theme{Base: ThemeCustom})
+	if merged.Base != ThemeDark || merged.Colors.Primary != "#0066CC" {
+		t.Errorf("Empty override changed theme: %+v", merged)
+	}
+}
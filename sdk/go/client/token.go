@@ -0,0 +1,121 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// TokenClaims claims JWT access token, разобранные локально без проверки
+// подписи. Подходят для решений на стороне клиента (пора ли обновлять
+// токен, показывать ли админские команды), но не для авторизации:
+// подпись проверяет сервер.
+type TokenClaims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	Email     string
+	Username  string
+	FirstName string
+	LastName  string
+	TenantID  string
+	Roles     []string
+	Scopes    []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time // Нулевое, если exp не указан
+
+	// Raw все claims токена
+	Raw map[string]interface{}
+}
+
+// ParseTokenClaims разбирает claims JWT без проверки подписи. Роли
+// берутся из "roles", "role" или "realm_access.roles", tenant - из
+// "tenant_id" или "tid", scopes - из "scope" или "scp".
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parsed, err := jwt.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	c := parsed.Claims
+	claims := &TokenClaims{
+		Subject:   c.String("sub"),
+		Issuer:    c.String("iss"),
+		Audience:  c.Strings("aud"),
+		Email:     c.String("email"),
+		Username:  firstClaim(c, "preferred_username", "username"),
+		FirstName: firstClaim(c, "given_name", "first_name"),
+		LastName:  firstClaim(c, "family_name", "last_name"),
+		TenantID:  firstClaim(c, "tenant_id", "tid"),
+		Roles:     c.Strings("roles"),
+		Raw:       c,
+	}
+	if len(claims.Roles) == 0 {
+		claims.Roles = c.Strings("role")
+	}
+	if realm, ok := c["realm_access"].(map[string]interface{}); len(claims.Roles) == 0 && ok {
+		claims.Roles = jwt.Claims(realm).Strings("roles")
+	}
+	if scope := c.String("scope"); scope != "" {
+		claims.Scopes = strings.Fields(scope)
+	} else {
+		claims.Scopes = c.Strings("scp")
+	}
+	claims.IssuedAt, _ = c.Time("iat")
+	claims.ExpiresAt, _ = c.Time("exp")
+	return claims, nil
+}
+
+func firstClaim(c jwt.Claims, names ...string) string {
+	for _, name := range names {
+		if v := c.String(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Expired сообщает, истек ли токен с учетом запаса skew. Токен без exp
+// не истекает.
+func (c *TokenClaims) Expired(now time.Time, skew time.Duration) bool {
+	return !c.ExpiresAt.IsZero() && !now.Add(skew).Before(c.ExpiresAt)
+}
+
+// HasRole сообщает, есть ли у токена роль
+func (c *TokenClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserProfile возвращает профиль пользователя по claims. Поля, которых
+// нет в токене (статус, даты), остаются пустыми.
+func (c *TokenClaims) UserProfile() *types.UserProfile {
+	roles := c.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return &types.UserProfile{
+		ID:        c.Subject,
+		Email:     c.Email,
+		Username:  c.Username,
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+		Roles:     roles,
+		TenantID:  c.TenantID,
+	}
+}
+
+// TokenClaims разбирает текущий токен клиента без обращения к серверу
+func (c *Client) TokenClaims() (*TokenClaims, error) {
+	if c.token == "" {
+		return nil, errors.New("client has no token")
+	}
+	return ParseTokenClaims(c.token)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Transport отправляет подготовленный запрос клиента и возвращает ответ.
+// Retry, interceptors, кэш и разбор ответа остаются на стороне Client,
+// поэтому транспорт отвечает только за доставку. *http.Client реализует
+// Transport; транспорты поверх WebSocket или gRPC отображают метод и путь
+// запроса на свои вызовы и возвращают ответ с кодом статуса и телом
+// в формате протокола.
+type Transport interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TransportFunc адаптер функции к Transport
+type TransportFunc func(req *http.Request) (*http.Response, error)
+
+// Do вызывает f(req)
+func (f TransportFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TransportConfig настройки HTTP транспорта клиента. Поля применяются
+// по приоритету: Transport, затем HTTPClient, затем RoundTripper, затем
+// TLSConfig и Proxy.
+type TransportConfig struct {
+	Transport    Transport                             // Готовый транспорт (HTTPClient и остальные поля игнорируются)
+	HTTPClient   *http.Client                          // HTTP клиент (nil = новый клиент с Config.Timeout)
+	RoundTripper http.RoundTripper                     // Транспорт HTTP клиента (TLSConfig и Proxy игнорируются)
+	TLSConfig    *tls.Config                           // Настройки TLS, например клиентский сертификат для mTLS
+	Proxy        func(*http.Request) (*url.URL, error) // Выбор прокси (nil = http.ProxyFromEnvironment), см. http.ProxyURL
+}
+
+// newHTTPClient строит HTTP клиент по настройкам транспорта. Переданный
+// HTTPClient не изменяется: при необходимости используется его копия.
+// TLSAuthenticator настраивает TLS, если не задан RoundTripper.
+func newHTTPClient(config *TransportConfig, timeout time.Duration, auth Authenticator) *http.Client {
+	if config == nil {
+		config = &TransportConfig{}
+	}
+	var hc http.Client
+	if config.HTTPClient != nil {
+		hc = *config.HTTPClient
+	} else {
+		hc.Timeout = timeout
+	}
+	tlsAuth, _ := auth.(TLSAuthenticator)
+
+	switch {
+	case config.RoundTripper != nil:
+		hc.Transport = config.RoundTripper
+	case config.TLSConfig != nil || config.Proxy != nil || tlsAuth != nil:
+		base, ok := hc.Transport.(*http.Transport)
+		if !ok || base == nil {
+			base = http.DefaultTransport.(*http.Transport)
+		}
+		transport := base.Clone()
+		if config.TLSConfig != nil {
+			transport.TLSClientConfig = config.TLSConfig.Clone()
+		}
+		if tlsAuth != nil {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+			tlsAuth.ConfigureTLS(transport.TLSClientConfig)
+		}
+		if config.Proxy != nil {
+			transport.Proxy = config.Proxy
+		}
+		hc.Transport = transport
+	}
+	return &hc
+}
+
+// HandlerTransport возвращает транспорт, вызывающий http.Handler в памяти
+// без сети. Удобен для тестов с эталонным сервером или domain.Server.
+func HandlerTransport(handler http.Handler) Transport {
+	return TransportFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		if req.Body == nil {
+			req.Body = http.NoBody
+		}
+		if req.RequestURI == "" {
+			req.RequestURI = req.URL.RequestURI()
+		}
+		w := &memoryResponse{header: make(http.Header)}
+		handler.ServeHTTP(w, req)
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+			StatusCode:    w.status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        w.header,
+			Body:          io.NopCloser(&w.body),
+			ContentLength: int64(w.body.Len()),
+			Request:       req,
+		}, nil
+	})
+}
+
+// memoryResponse http.ResponseWriter для HandlerTransport
+type memoryResponse struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (w *memoryResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *memoryResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *memoryResponse) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
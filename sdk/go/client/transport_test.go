@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func healthHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != PathHealth {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(types.HealthResponse{Status: "healthy", Version: "2.0.0"})
+	})
+}
+
+func TestHandlerTransport(t *testing.T) {
+	calls := 0
+	handler := healthHandler(t)
+	client := NewClient(Config{
+		BaseURL: "http://nexus.local",
+		Transport: &TransportConfig{Transport: TransportFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("Expected Authorization header, got %q", req.Header.Get("Authorization"))
+			}
+			return HandlerTransport(handler).Do(req)
+		})},
+		Token: "token",
+	})
+
+	health, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if health.Status != "healthy" || calls != 1 {
+		t.Errorf("Unexpected health %+v after %d calls", health, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Health(ctx); err == nil {
+		t.Error("Expected error for canceled context")
+	}
+}
+
+func TestTransportTLS(t *testing.T) {
+	server := httptest.NewTLSServer(healthHandler(t))
+	defer server.Close()
+
+	if _, err := NewClient(Config{BaseURL: server.URL, RetryConfig: &RetryConfig{}}).Health(context.Background()); err == nil {
+		t.Fatal("Expected certificate error without TLS config")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewClient(Config{
+		BaseURL:   server.URL,
+		Transport: &TransportConfig{TLSConfig: &tls.Config{RootCAs: pool}},
+	})
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("Health over TLS failed: %v", err)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	if hc := newHTTPClient(nil, time.Second, nil); hc.Timeout != time.Second || hc.Transport != nil {
+		t.Errorf("Unexpected default client %+v", hc)
+	}
+
+	custom := &http.Client{Timeout: time.Minute}
+	proxy := http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy:3128"})
+	hc := newHTTPClient(&TransportConfig{HTTPClient: custom, Proxy: proxy, TLSConfig: &tls.Config{ServerName: "nexus"}}, time.Second, nil)
+	if hc == custom || custom.Transport != nil {
+		t.Error("HTTPClient must not be modified")
+	}
+	if hc.Timeout != time.Minute {
+		t.Errorf("Expected timeout of custom client, got %v", hc.Timeout)
+	}
+	transport, ok := hc.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", hc.Transport)
+	}
+	if transport.TLSClientConfig.ServerName != "nexus" {
+		t.Errorf("TLS config not applied: %+v", transport.TLSClientConfig)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://nexus.local", nil)
+	if u, _ := transport.Proxy(req); u == nil || u.Host != "proxy:3128" {
+		t.Errorf("Proxy not applied: %v", u)
+	}
+
+	rt := TransportFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	hc = newHTTPClient(&TransportConfig{RoundTripper: roundTripper(rt), TLSConfig: &tls.Config{}}, time.Second, nil)
+	if _, ok := hc.Transport.(roundTripper); !ok {
+		t.Errorf("RoundTripper must take precedence over TLSConfig, got %T", hc.Transport)
+	}
+}
+
+// roundTripper адаптер Transport к http.RoundTripper для тестов
+type roundTripper TransportFunc
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
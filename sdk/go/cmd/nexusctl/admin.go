@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func adminCommands() *command {
+	return &command{
+		name:    "admin",
+		summary: "Администрирование конфигурации",
+		sub: []*command{
+			{name: "plan", summary: "Сравнить манифесты с конфигурацией сервера", run: (*app).runAdminPlan},
+			{name: "apply", summary: "Применить манифесты к серверу", run: (*app).runAdminApply},
+			{name: "backup", summary: "Резервная копия конфигурации", run: (*app).runAdminBackup},
+			{name: "restore", args: "<archive>", summary: "Восстановить конфигурацию из копии", run: (*app).runAdminRestore},
+			{
+				name:    "domains",
+				summary: "Конфигурации доменов",
+				sub: []*command{
+					{name: "list", summary: "Список доменов", run: (*app).runDomainsList},
+					{name: "get", args: "<id>", summary: "Конфигурация домена", run: (*app).runDomainsGet},
+					{name: "delete", args: "<id>", summary: "Удалить домен", run: (*app).runDomainsDelete},
+					{name: "classify", args: "<query>", summary: "Проверить выбор доменов для запроса", run: (*app).runDomainsClassify},
+				},
+			},
+			{
+				name:    "prompts",
+				summary: "Промпты",
+				sub: []*command{
+					{name: "list", summary: "Список промптов", run: (*app).runPromptsList},
+					{name: "get", args: "<id>", summary: "Промпт", run: (*app).runPromptsGet},
+					{name: "delete", args: "<id>", summary: "Удалить промпт", run: (*app).runPromptsDelete},
+					{name: "render", args: "<id>", summary: "Подставить переменные в шаблон", run: (*app).runPromptsRender},
+					{name: "lint", args: "<id>", summary: "Проверить шаблон и переменные", run: (*app).runPromptsLint},
+					{name: "diff", args: "<old-id> <new-id>", summary: "Сравнить версии промпта", run: (*app).runPromptsDiff},
+					{name: "versions", args: "<name>", summary: "Версии промпта", run: (*app).runPromptsVersions},
+					{name: "publish", summary: "Опубликовать новую версию", run: (*app).runPromptsPublish},
+					{name: "rollback", args: "<name>", summary: "Вернуть предыдущую версию", run: (*app).runPromptsRollback},
+				},
+			},
+			{
+				name:    "integrations",
+				summary: "Интеграции",
+				sub: []*command{
+					{name: "list", summary: "Список интеграций", run: (*app).runIntegrationsList},
+					{name: "get", args: "<id>", summary: "Интеграция", run: (*app).runIntegrationsGet},
+					{name: "delete", args: "<id>", summary: "Удалить интеграцию", run: (*app).runIntegrationsDelete},
+				},
+			},
+		},
+	}
+}
+
+func (a *app) runDomainsList(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin domains list")
+	showSecrets := fs.Bool("show-secrets", false, "Show auth_config values")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	domains, err := c.Admin().ListDomains(ctx)
+	if err != nil {
+		return err
+	}
+	if !*showSecrets {
+		for i, d := range domains {
+			domains[i] = maskDomain(d)
+		}
+	}
+	return a.print(domains, func(t *table) {
+		t.header("ID", "Name", "Type", "Enabled", "Priority", "Endpoint")
+		for _, d := range domains {
+			t.row(d.ID, d.Name, d.Type, d.Enabled, d.Priority, d.Endpoint)
+		}
+	})
+}
+
+func (a *app) runDomainsGet(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin domains get")
+	showSecrets := fs.Bool("show-secrets", false, "Show auth_config values")
+	positional, err := parseArgs(fs, args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	domain, err := c.Admin().GetDomain(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	if !*showSecrets {
+		domain = maskDomain(domain)
+	}
+	return a.print(domain, nil)
+}
+
+func (a *app) runDomainsDelete(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("admin domains delete"), args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Admin().DeleteDomain(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Domain %s deleted\n", positional[0])
+	return nil
+}
+
+func (a *app) runPromptsList(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts list")
+	domain := fs.String("domain", "", "Filter by domain")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	prompts, err := c.Admin().ListPrompts(ctx, *domain)
+	if err != nil {
+		return err
+	}
+	return a.print(prompts, func(t *table) {
+		t.header("ID", "Name", "Domain", "Type", "Version", "Active")
+		for _, p := range prompts {
+			t.row(p.ID, p.Name, p.Domain, p.Type, p.Version, p.Active)
+		}
+	})
+}
+
+func (a *app) runPromptsGet(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("admin prompts get"), args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	prompt, err := c.Admin().GetPrompt(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return a.print(prompt, nil)
+}
+
+func (a *app) runPromptsDelete(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("admin prompts delete"), args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Admin().DeletePrompt(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Prompt %s deleted\n", positional[0])
+	return nil
+}
+
+func (a *app) runIntegrationsList(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin integrations list")
+	integrationType := fs.String("type", "", "Filter by integration type")
+	showSecrets := fs.Bool("show-secrets", false, "Show credential values")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	integrations, err := c.Admin().ListIntegrations(ctx, *integrationType)
+	if err != nil {
+		return err
+	}
+	if !*showSecrets {
+		for i, in := range integrations {
+			integrations[i] = maskIntegration(in)
+		}
+	}
+	return a.print(integrations, func(t *table) {
+		t.header("ID", "Name", "Type", "Provider", "Enabled", "Webhook URL")
+		for _, in := range integrations {
+			t.row(in.ID, in.Name, in.Type, in.Provider, in.Enabled, in.WebhookURL)
+		}
+	})
+}
+
+func (a *app) runIntegrationsGet(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin integrations get")
+	showSecrets := fs.Bool("show-secrets", false, "Show credential values")
+	positional, err := parseArgs(fs, args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	integration, err := c.Admin().GetIntegration(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	if !*showSecrets {
+		integration = maskIntegration(integration)
+	}
+	return a.print(integration, nil)
+}
+
+func (a *app) runIntegrationsDelete(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("admin integrations delete"), args, "id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Admin().DeleteIntegration(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Integration %s deleted\n", positional[0])
+	return nil
+}
+
+// maskDomain возвращает копию домена со скрытыми значениями AuthConfig
+func maskDomain(d *types.DomainConfig) *types.DomainConfig {
+	if d == nil {
+		return nil
+	}
+	masked := *d
+	masked.AuthConfig = maskValues(d.AuthConfig)
+	return &masked
+}
+
+// maskIntegration возвращает копию интеграции со скрытыми Credentials
+func maskIntegration(in *types.IntegrationConfig) *types.IntegrationConfig {
+	if in == nil {
+		return nil
+	}
+	masked := *in
+	masked.Credentials = maskValues(in.Credentials)
+	return &masked
+}
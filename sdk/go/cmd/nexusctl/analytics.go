@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/analytics"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func analyticsCommands() *command {
+	return &command{
+		name:    "analytics",
+		summary: "Статистика и выгрузка событий",
+		sub: []*command{
+			{name: "stats", summary: "Статистика за период", run: (*app).runAnalyticsStats},
+			{name: "export", summary: "Выгрузить события (JSON Lines, CSV, JSON)", run: (*app).runAnalyticsExport},
+		},
+	}
+}
+
+func (a *app) runAnalyticsStats(ctx context.Context, args []string) error {
+	fs := a.flagSet("analytics stats")
+	days := fs.Int("days", 7, "Period in days")
+	userID := fs.String("user-id", "", "Filter by user ID")
+	tenantID := fs.String("tenant-id", "", "Filter by tenant ID")
+	from := fs.String("from", "", "Compute stats locally from an exported JSON/JSON Lines file ('-' for stdin)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	var stats *types.AnalyticsStats
+	if *from != "" {
+		var err error
+		if stats, err = a.localStats(*from, int32(*days)); err != nil {
+			return err
+		}
+	} else {
+		c, err := a.newClient(ctx)
+		if err != nil {
+			return err
+		}
+		stats, err = c.GetStats(ctx, &types.GetStatsRequest{UserID: *userID, TenantID: *tenantID, Days: int32(*days)})
+		if err != nil {
+			return err
+		}
+	}
+	return a.print(stats, func(t *table) { renderStats(t, stats) })
+}
+
+// localStats вычисляет статистику по выгруженным событиям
+func (a *app) localStats(path string, days int32) (*types.AnalyticsStats, error) {
+	var r io.Reader = a.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	agg := analytics.NewAggregator(analytics.AggregatorConfig{Days: days})
+	if _, err := agg.AddFrom(r); err != nil {
+		return nil, fmt.Errorf("failed to read events from %s: %w", path, err)
+	}
+	return agg.Stats(), nil
+}
+
+func renderStats(t *table, stats *types.AnalyticsStats) {
+	t.field("Period days", stats.PeriodDays)
+	t.field("Total events", stats.TotalEvents)
+	t.field("Total users", stats.TotalUsers)
+	t.field("Active users", stats.ActiveUsers)
+	t.field("Events today", stats.EventsToday)
+	if p := stats.PerformanceMetrics; p != nil {
+		t.field("Avg response ms", p.AvgResponseTimeMS)
+		t.field("P95 response ms", p.P95ResponseTimeMS)
+		t.field("P99 response ms", p.P99ResponseTimeMS)
+		t.field("Error rate", p.ErrorRate)
+	}
+
+	if len(stats.TopEvents) > 0 {
+		t.blank()
+		t.header("Event", "Count", "Percent")
+		for _, e := range stats.TopEvents {
+			t.row(e.Event, e.Count, e.Percentage)
+		}
+	}
+
+	if len(stats.DomainBreakdown) > 0 {
+		domains := make([]string, 0, len(stats.DomainBreakdown))
+		for d := range stats.DomainBreakdown {
+			domains = append(domains, d)
+		}
+		sort.Strings(domains)
+
+		t.blank()
+		t.header("Domain", "Requests", "Success rate", "Avg ms", "Errors")
+		for _, d := range domains {
+			m := stats.DomainBreakdown[d]
+			t.row(d, m.RequestsCount, m.SuccessRate, m.AvgResponseTimeMS, m.ErrorCount)
+		}
+	}
+}
+
+// Форматы выгрузки событий
+const (
+	exportJSONL = "jsonl"
+	exportCSV   = "csv"
+	exportJSON  = "json"
+)
+
+func (a *app) runAnalyticsExport(ctx context.Context, args []string) error {
+	fs := a.flagSet("analytics export")
+	eventType := fs.String("event-type", "", "Filter by event type")
+	userID := fs.String("user-id", "", "Filter by user ID")
+	format := fs.String("format", exportJSONL, "Export format: jsonl, csv or json")
+	out := fs.String("out", "", "Output file (default stdout)")
+	max := fs.Int("max", 0, "Maximum number of events (0 = all)")
+	pageSize := fs.Int("page-size", 100, "Events per request")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	var write func(types.AnalyticsEvent) error
+	var finish func() error
+
+	w := a.stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch *format {
+	case exportJSONL:
+		enc := json.NewEncoder(w)
+		write = func(e types.AnalyticsEvent) error { return enc.Encode(e) }
+		finish = func() error { return nil }
+	case exportJSON:
+		var events []types.AnalyticsEvent
+		write = func(e types.AnalyticsEvent) error { events = append(events, e); return nil }
+		finish = func() error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if events == nil {
+				events = []types.AnalyticsEvent{}
+			}
+			return enc.Encode(events)
+		}
+	case exportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "event_type", "user_id", "tenant_id", "timestamp", "data"}); err != nil {
+			return err
+		}
+		write = func(e types.AnalyticsEvent) error {
+			data := ""
+			if len(e.Data) > 0 {
+				raw, err := json.Marshal(e.Data)
+				if err != nil {
+					return err
+				}
+				data = string(raw)
+			}
+			return cw.Write([]string{e.ID, e.EventType, e.UserID, e.TenantID, e.Timestamp, data})
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return usagef("unknown export format %q (expected jsonl, csv or json)", *format)
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	pager := c.EventsPager(&types.GetEventsRequest{EventType: *eventType, UserID: *userID, Limit: int32(*pageSize)})
+
+	count := 0
+	var iterErr error
+	pager.All(ctx)(func(e types.AnalyticsEvent, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		if *max > 0 && count >= *max {
+			return false
+		}
+		if iterErr = write(e); iterErr != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if iterErr != nil {
+		return iterErr
+	}
+	if err := finish(); err != nil {
+		return err
+	}
+
+	if *out != "" {
+		fmt.Fprintf(a.stderr, "Exported %d events to %s\n", count, *out)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// envPassword переменная окружения с паролем для auth login
+const envPassword = "NEXUS_PASSWORD"
+
+func authCommands() *command {
+	return &command{
+		name:    "auth",
+		summary: "Аутентификация и профили",
+		sub: []*command{
+			{name: "login", summary: "Войти и сохранить токены в профиль", run: (*app).runAuthLogin},
+			{name: "logout", summary: "Удалить токены из профиля", run: (*app).runAuthLogout},
+			{name: "whoami", summary: "Профиль текущего пользователя", run: (*app).runAuthWhoami},
+		},
+	}
+}
+
+func (a *app) runAuthLogin(ctx context.Context, args []string) error {
+	fs := a.flagSet("auth login")
+	email := fs.String("email", "", "Account email")
+	password := fs.String("password", "", "Account password (prefer --password-stdin or "+envPassword+")")
+	passwordStdin := fs.Bool("password-stdin", false, "Read the password from stdin")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usagef("auth login: --email is required")
+	}
+
+	pass := firstNonEmpty(*password, os.Getenv(envPassword))
+	if *passwordStdin || pass == "" {
+		if !*passwordStdin {
+			fmt.Fprint(a.stderr, "Password: ")
+		}
+		line, err := bufio.NewReader(a.stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		pass = strings.TrimRight(line, "\r\n")
+	}
+
+	store, name, prof, err := a.currentProfile()
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Login(ctx, &types.LoginRequest{Email: *email, Password: pass})
+	if err != nil {
+		return err
+	}
+
+	prof.Server = firstNonEmpty(a.server, os.Getenv(envServer), prof.Server)
+	prof.Email = *email
+	prof.setTokens(resp.AccessToken, resp.RefreshToken, resp.TokenType, resp.ExpiresIn, time.Now())
+	store.Current = name
+	if err := store.save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stderr, "Logged in as %s (profile %q)\n", *email, name)
+	if resp.User == nil {
+		return nil
+	}
+	return a.print(resp.User, func(t *table) { renderUser(t, resp.User) })
+}
+
+func (a *app) runAuthLogout(ctx context.Context, args []string) error {
+	if _, err := parseArgs(a.flagSet("auth logout"), args); err != nil {
+		return err
+	}
+
+	store, name, prof, err := a.currentProfile()
+	if err != nil {
+		return err
+	}
+	prof.AccessToken, prof.RefreshToken, prof.TokenType, prof.ExpiresAt = "", "", "", 0
+	if err := store.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Logged out (profile %q)\n", name)
+	return nil
+}
+
+func (a *app) runAuthWhoami(ctx context.Context, args []string) error {
+	if _, err := parseArgs(a.flagSet("auth whoami"), args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.GetUserProfile(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(user, func(t *table) { renderUser(t, user) })
+}
+
+func renderUser(t *table, user *types.UserProfile) {
+	t.field("ID", user.ID)
+	t.field("Email", user.Email)
+	t.field("Name", strings.TrimSpace(user.FirstName+" "+user.LastName))
+	t.field("Status", user.Status)
+	t.field("Roles", user.Roles)
+	t.field("Last login", formatUnix(user.LastLoginAt))
+}
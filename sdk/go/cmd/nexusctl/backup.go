@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+)
+
+// envBackupPassphrase переменная окружения с парольной фразой резервной копии
+const envBackupPassphrase = "NEXUS_BACKUP_PASSPHRASE"
+
+func (a *app) runAdminBackup(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin backup")
+	out := fs.String("out", "", "Archive file to write (- for stdout)")
+	encrypt := fs.Bool("encrypt", false, "Encrypt credentials with a passphrase (env "+envBackupPassphrase+")")
+	passphraseStdin := fs.Bool("passphrase-stdin", false, "Read the passphrase from stdin")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return usagef("admin backup: --out is required")
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	backup, err := c.Admin().Backup(ctx)
+	if err != nil {
+		return err
+	}
+	if *encrypt {
+		passphrase, err := a.readPassphrase(*passphraseStdin)
+		if err != nil {
+			return err
+		}
+		if backup, err = backup.Encrypt(passphrase); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(a.stderr, "Warning: credentials are stored in plaintext; use --encrypt to protect them")
+	}
+
+	var buf bytes.Buffer
+	if err := client.WriteBackupArchive(&buf, backup); err != nil {
+		return err
+	}
+	if *out == "-" {
+		_, err = a.stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Backup written to %s: %d domains, %d prompts, %d integrations, %d frontend configs\n",
+		*out, len(backup.Domains), len(backup.Prompts), len(backup.Integrations), len(backup.FrontendConfigs))
+	return nil
+}
+
+func (a *app) runAdminRestore(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin restore")
+	conflict := fs.String("conflict", string(client.ConflictSkip), "Policy for existing resources: skip, overwrite or rename")
+	suffix := fs.String("rename-suffix", client.DefaultRenameSuffix, "ID suffix used by --conflict rename")
+	dryRun := fs.Bool("dry-run", false, "Report actions without changing the server")
+	passphraseStdin := fs.Bool("passphrase-stdin", false, "Read the passphrase from stdin")
+	var mappings listFlag
+	fs.Var(&mappings, "map", "ID remapping as old=new or kind/old=new (repeatable)")
+	positional, err := parseArgs(fs, args, "archive")
+	if err != nil {
+		return err
+	}
+
+	opts := client.RestoreOptions{
+		Conflict:     client.ConflictPolicy(*conflict),
+		RenameSuffix: *suffix,
+		DryRun:       *dryRun,
+		IDMap:        make(map[string]string),
+	}
+	switch opts.Conflict {
+	case client.ConflictSkip, client.ConflictOverwrite, client.ConflictRename:
+	default:
+		return usagef("admin restore: unknown conflict policy %q", *conflict)
+	}
+	for _, m := range mappings {
+		from, to, ok := strings.Cut(m, "=")
+		if !ok || from == "" || to == "" {
+			return usagef("admin restore: invalid --map %q (expected old=new)", m)
+		}
+		opts.IDMap[from] = to
+	}
+
+	data, err := a.readInput(positional[0])
+	if err != nil {
+		return err
+	}
+	backup, err := client.ReadBackupArchive(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if backup.Encryption != nil {
+		if opts.Passphrase, err = a.readPassphrase(*passphraseStdin); err != nil {
+			return err
+		}
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, restoreErr := c.Admin().Restore(ctx, backup, opts)
+	if report == nil {
+		return restoreErr
+	}
+	if err := a.print(report, func(t *table) {
+		t.header("Kind", "Source ID", "Target ID", "Action", "Error")
+		for _, item := range report.Items {
+			t.row(item.Kind, item.SourceID, item.TargetID, item.Action, item.Error)
+		}
+	}); err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Fprintln(a.stderr, "Dry run: no changes applied")
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("%d of %d resources failed to restore", report.Count(client.RestoreFailed), len(report.Items))
+	}
+	return nil
+}
+
+// readPassphrase берет парольную фразу из окружения или stdin
+func (a *app) readPassphrase(fromStdin bool) (string, error) {
+	if !fromStdin {
+		if passphrase := os.Getenv(envBackupPassphrase); passphrase != "" {
+			return passphrase, nil
+		}
+		fmt.Fprint(a.stderr, "Passphrase: ")
+	}
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	passphrase := strings.TrimRight(line, "\r\n")
+	if passphrase == "" {
+		return "", usagef("passphrase is empty")
+	}
+	return passphrase, nil
+}
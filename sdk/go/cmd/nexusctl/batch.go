@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func batchCommands() *command {
+	return &command{
+		name:    "batch",
+		summary: "Пакетное выполнение запросов",
+		sub: []*command{
+			{name: "submit", summary: "Выполнить пакет запросов из файла", run: (*app).runBatchSubmit},
+			{name: "status", args: "<batch-id>", summary: "Статус пакета", run: (*app).runBatchStatus},
+			{name: "cancel", args: "<batch-id>", summary: "Отменить пакет", run: (*app).runBatchCancel},
+		},
+	}
+}
+
+// batchModes соответствие имен режимов константам SDK
+var batchModes = map[string]client.BatchMode{
+	"server": client.BatchModeServer,
+	"local":  client.BatchModeLocal,
+	"auto":   client.BatchModeAuto,
+}
+
+func (a *app) runBatchSubmit(ctx context.Context, args []string) error {
+	fs := a.flagSet("batch submit")
+	file := fs.String("f", "", "JSON file with a BatchRequest or an array of requests ('-' for stdin)")
+	chunkSize := fs.Int("chunk-size", 0, "Maximum requests per server batch (0 = no chunking)")
+	concurrency := fs.Int("concurrency", client.DefaultBatchConcurrency, "Concurrent chunks or local requests")
+	mode := fs.String("mode", "server", "Execution mode: server, local or auto")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("batch submit: -f is required")
+	}
+	batchMode, ok := batchModes[*mode]
+	if !ok {
+		return usagef("unknown batch mode %q", *mode)
+	}
+
+	data, err := a.readInput(*file)
+	if err != nil {
+		return err
+	}
+	req := &types.BatchRequest{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &req.Requests)
+	} else {
+		err = json.Unmarshal(data, req)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *file, err)
+	}
+	if len(req.Requests) == 0 {
+		return fmt.Errorf("no requests in %s", *file)
+	}
+
+	builder := client.NewBatchBuilder().
+		SetBatchOptions(req.BatchOptions).
+		SetMaxBatchSize(*chunkSize).
+		SetConcurrency(*concurrency).
+		SetMode(batchMode)
+	for _, r := range req.Requests {
+		builder.AddRequest(r)
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := builder.Run(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.print(result.Response, func(t *table) { renderBatchItems(t, result) }); err != nil {
+		return err
+	}
+	return result.Err()
+}
+
+// renderBatchItems выводит результаты элементов пакета
+func renderBatchItems(t *table, result *client.BatchResult) {
+	t.field("Batch ID", result.Response.BatchID)
+	if meta := result.Response.BatchMetadata; meta != nil {
+		t.field("Successful", fmt.Sprintf("%d/%d", meta.SuccessfulRequests, meta.TotalRequests))
+		t.field("Processing ms", meta.TotalProcessingTimeMS)
+	}
+
+	t.blank()
+	t.header("#", "Query", "Status", "Execution ID", "Error")
+	for _, item := range result.Items {
+		var status, executionID string
+		if item.Response != nil {
+			status, executionID = item.Response.Status, item.Response.ExecutionID
+		}
+		var errText string
+		if item.Err != nil {
+			errText = item.Err.Error()
+		}
+		t.row(item.Index, item.Request.Query, status, executionID, errText)
+	}
+}
+
+func (a *app) runBatchStatus(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("batch status"), args, "batch-id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.GetBatchStatus(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) {
+		t.field("Batch ID", resp.BatchID)
+		if meta := resp.BatchMetadata; meta != nil {
+			t.field("Total", meta.TotalRequests)
+			t.field("Successful", meta.SuccessfulRequests)
+			t.field("Failed", meta.FailedRequests)
+			t.field("Processing ms", meta.TotalProcessingTimeMS)
+		}
+		if len(resp.Responses) > 0 {
+			t.blank()
+			t.header("#", "Execution ID", "Status", "Time ms")
+			for i, r := range resp.Responses {
+				if r == nil {
+					t.row(i, nil, nil, nil)
+					continue
+				}
+				t.row(i, r.ExecutionID, r.Status, r.ProcessingTimeMS)
+			}
+		}
+	})
+}
+
+func (a *app) runBatchCancel(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("batch cancel"), args, "batch-id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.CancelBatch(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) {
+		t.field("Batch ID", resp.BatchID)
+		t.field("Status", resp.Status)
+		t.field("Message", resp.Message)
+	})
+}
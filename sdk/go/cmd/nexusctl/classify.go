@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/classifier"
+)
+
+func (a *app) runDomainsClassify(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin domains classify")
+	dir := fs.String("dir", "", "Classify with domain manifests from a directory instead of the server")
+	fs.StringVar(dir, "d", "", "Shorthand for --dir")
+	threshold := fs.Float64("threshold", classifier.DefaultThreshold, "Minimum confidence to select a domain")
+	maxDomains := fs.Int("max", classifier.DefaultMaxDomains, "Maximum number of selected domains")
+	includeDisabled := fs.Bool("include-disabled", false, "Consider disabled domains")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usagef("usage: %s <query>", fs.Name())
+	}
+	query := strings.Join(positional, " ")
+
+	config := classifier.Config{
+		Threshold:       float32(*threshold),
+		MaxDomains:      *maxDomains,
+		IncludeDisabled: *includeDisabled,
+	}
+	var c *classifier.Classifier
+	if *dir != "" {
+		c, err = classifier.LoadDir(*dir, config)
+	} else {
+		client, cerr := a.newClient(ctx)
+		if cerr != nil {
+			return cerr
+		}
+		c, err = classifier.FromServer(ctx, client.Admin(), config)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := c.Classify(query)
+	return a.print(result, func(t *table) {
+		t.header("Selected", "Domain", "Confidence", "Relevance", "Priority", "Reason")
+		for _, d := range result.SelectedDomains {
+			t.row("yes", d.DomainID, fmt.Sprintf("%.2f", d.Confidence), fmt.Sprintf("%.2f", d.Relevance), d.Priority, d.Reason)
+		}
+		for _, d := range result.RejectedDomains {
+			t.row("no", d.DomainID, fmt.Sprintf("%.2f", d.Confidence), fmt.Sprintf("%.2f", d.Relevance), d.Priority, d.Reason)
+		}
+	})
+}
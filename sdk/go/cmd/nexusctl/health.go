@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func (a *app) runHealth(ctx context.Context, args []string) error {
+	if _, err := parseArgs(a.flagSet("health"), args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	health, err := c.Health(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(health, func(t *table) {
+		t.field("Status", health.Status)
+		t.field("Version", health.Version)
+		t.field("Timestamp", health.Timestamp)
+	})
+}
+
+func (a *app) runReady(ctx context.Context, args []string) error {
+	if _, err := parseArgs(a.flagSet("ready"), args); err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	ready, err := c.Ready(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(ready, func(t *table) { renderReadiness(t, ready) })
+}
+
+func renderReadiness(t *table, ready *types.ReadinessResponse) {
+	t.field("Status", ready.Status)
+	t.field("Timestamp", ready.Timestamp)
+	t.field("Database", ready.Checks.Database)
+	t.field("Redis", ready.Checks.Redis)
+	t.field("AI services", ready.Checks.AIServices)
+	if ready.Capacity != nil {
+		t.field("Load", ready.Capacity.CurrentLoad)
+		t.field("Queue size", ready.Capacity.QueueSize)
+		t.field("Active connections", ready.Capacity.ActiveConnections)
+	}
+
+	if len(ready.Components) == 0 {
+		return
+	}
+	names := make([]string, 0, len(ready.Components))
+	for name := range ready.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t.blank()
+	t.header("Component", "Status", "Latency ms", "Message")
+	for _, name := range names {
+		comp := ready.Components[name]
+		t.row(name, comp.Status, comp.LatencyMS, comp.Message)
+	}
+}
@@ -0,0 +1,293 @@
+// Команда nexusctl - консольный клиент Nexus Protocol на основе Go SDK.
+//
+// Использование:
+//
+//	nexusctl [глобальные флаги] <команда> [подкоманда] [флаги] [аргументы]
+//
+// Адрес сервера и токен берутся из флагов --server и --token, переменных
+// окружения NEXUS_API_URL и NEXUS_API_TOKEN или из профиля, сохраненного
+// командой "auth login".
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+)
+
+// Переменные окружения
+const (
+	envServer  = "NEXUS_API_URL"
+	envToken   = "NEXUS_API_TOKEN"
+	envProfile = "NEXUSCTL_PROFILE"
+	envConfig  = "NEXUSCTL_CONFIG"
+)
+
+// command описывает команду или группу команд
+type command struct {
+	name    string
+	args    string // описание позиционных аргументов для справки
+	summary string
+	run     func(a *app, ctx context.Context, args []string) error
+	sub     []*command
+}
+
+// usageError ошибка в аргументах командной строки
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// app хранит глобальные параметры и потоки ввода-вывода
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	server     string
+	token      string
+	profile    string
+	configPath string
+	output     string
+	timeout    time.Duration
+
+	commands []*command
+}
+
+func newApp(stdin io.Reader, stdout, stderr io.Writer) *app {
+	a := &app{stdin: stdin, stdout: stdout, stderr: stderr}
+	a.commands = []*command{
+		{name: "health", summary: "Проверка здоровья сервера", run: (*app).runHealth},
+		{name: "ready", summary: "Проверка готовности сервера", run: (*app).runReady},
+		templateCommands(),
+		batchCommands(),
+		webhookCommands(),
+		authCommands(),
+		analyticsCommands(),
+		adminCommands(),
+	}
+	return a
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(newApp(os.Stdin, os.Stdout, os.Stderr).run(ctx, os.Args[1:]))
+}
+
+// run выполняет команду и возвращает код завершения процесса
+func (a *app) run(ctx context.Context, args []string) int {
+	err := a.dispatch(ctx, args)
+
+	var uerr *usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &uerr):
+		fmt.Fprintf(a.stderr, "Error: %v\n", err)
+		return 2
+	default:
+		fmt.Fprintf(a.stderr, "Error: %v\n", err)
+		return 1
+	}
+}
+
+// dispatch разбирает глобальные флаги и находит команду
+func (a *app) dispatch(ctx context.Context, args []string) error {
+	fs := a.flagSet("nexusctl")
+	fs.Usage = func() { a.printUsage(nil, nil) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+
+	var path []string
+	cmds := a.commands
+	for {
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			a.printUsage(path, cmds)
+			if len(args) == 0 && len(path) > 0 {
+				return usagef("missing subcommand for %q", strings.Join(path, " "))
+			}
+			return nil
+		}
+
+		cmd := findCommand(cmds, args[0])
+		if cmd == nil {
+			return usagef("unknown command %q", strings.Join(append(path, args[0]), " "))
+		}
+		path, args = append(path, cmd.name), args[1:]
+
+		if cmd.run != nil {
+			if a.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, a.timeout)
+				defer cancel()
+			}
+			return cmd.run(a, ctx, args)
+		}
+		cmds = cmd.sub
+	}
+}
+
+// findCommand ищет команду по имени
+func findCommand(cmds []*command, name string) *command {
+	for _, cmd := range cmds {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// printUsage выводит справку по списку команд
+func (a *app) printUsage(path []string, cmds []*command) {
+	if cmds == nil {
+		cmds = a.commands
+	}
+	prefix := strings.TrimSpace("nexusctl " + strings.Join(path, " "))
+	fmt.Fprintf(a.stderr, "Usage: %s <command> [flags] [args]\n\nCommands:\n", prefix)
+
+	sorted := append([]*command(nil), cmds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(a.stderr, "  %-28s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintf(a.stderr, "\nGlobal flags:\n")
+	fs := a.flagSet(prefix)
+	fs.SetOutput(a.stderr)
+	fs.PrintDefaults()
+}
+
+// flagSet создает набор флагов с глобальными флагами, чтобы их можно было
+// указывать как до, так и после имени команды
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.server, "server", a.server, "Base URL of the Nexus API (env "+envServer+")")
+	fs.StringVar(&a.token, "token", a.token, "Access token (env "+envToken+")")
+	fs.StringVar(&a.profile, "profile", a.profile, "Profile name (env "+envProfile+")")
+	fs.StringVar(&a.configPath, "config", a.configPath, "Path to the profile file (env "+envConfig+")")
+	fs.StringVar(&a.output, "output", a.output, "Output format: table, json or yaml")
+	fs.StringVar(&a.output, "o", a.output, "Shorthand for --output")
+	fs.DurationVar(&a.timeout, "timeout", a.timeout, "Overall command timeout (0 = none)")
+	return fs
+}
+
+// parseFlags разбирает флаги, допуская их вперемешку с позиционными аргументами
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseArgs разбирает флаги и проверяет количество позиционных аргументов
+func parseArgs(fs *flag.FlagSet, args []string, want ...string) ([]string, error) {
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != len(want) {
+		if len(want) == 0 {
+			return nil, usagef("%s: unexpected arguments %v", fs.Name(), positional)
+		}
+		return nil, usagef("usage: %s <%s>", fs.Name(), strings.Join(want, "> <"))
+	}
+	return positional, nil
+}
+
+// newClient создает клиент SDK по флагам, окружению и профилю
+func (a *app) newClient(ctx context.Context) (*client.Client, error) {
+	store, name, prof, err := a.currentProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(a.server, os.Getenv(envServer), prof.Server)
+	if server == "" {
+		return nil, usagef("server URL is not set: use --server, %s or auth login", envServer)
+	}
+
+	c := client.NewClient(client.Config{
+		BaseURL:    strings.TrimRight(server, "/"),
+		ClientID:   "nexusctl",
+		ClientType: "cli",
+	})
+
+	// Токен профиля не отправляется на другой сервер
+	token := firstNonEmpty(a.token, os.Getenv(envToken))
+	if token == "" && prof.AccessToken != "" && (prof.Server == "" || prof.Server == server) {
+		// Токен профиля обновляется, если истек и есть refresh token
+		if prof.expired(time.Now()) && prof.RefreshToken != "" {
+			if err := refreshProfile(ctx, c, prof); err != nil {
+				return nil, fmt.Errorf("failed to refresh token of profile %q: %w", name, err)
+			}
+			if err := store.save(); err != nil {
+				return nil, err
+			}
+		}
+		token = prof.AccessToken
+	}
+	if token != "" {
+		c.SetToken(token)
+	}
+	return c, nil
+}
+
+// readInput читает файл или stdin, если path равен "-"
+func (a *app) readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(a.stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// readJSON читает JSON из файла или stdin в v
+func (a *app) readJSON(path string, v interface{}) error {
+	data, err := a.readInput(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// runCLI выполняет команду с изолированным файлом профилей
+func runCLI(t *testing.T, config, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	t.Setenv(envServer, "")
+	t.Setenv(envToken, "")
+	t.Setenv(envProfile, "")
+
+	var stdout, stderr bytes.Buffer
+	a := newApp(strings.NewReader(stdin), &stdout, &stderr)
+	code := a.run(context.Background(), append([]string{"--config", config}, args...))
+	return code, stdout.String(), stderr.String()
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func TestHealthOutputFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "healthy", "version": "2.0.0", "timestamp": "2025-01-18T10:00:00Z"}`))
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	code, out, _ := runCLI(t, config, "", "--server", server.URL, "health")
+	if code != 0 || !strings.Contains(out, "Status:") || !strings.Contains(out, "healthy") {
+		t.Errorf("Unexpected table output (code %d): %s", code, out)
+	}
+
+	code, out, _ = runCLI(t, config, "", "health", "--server", server.URL, "-o", "json")
+	var health types.HealthResponse
+	if err := json.Unmarshal([]byte(out), &health); code != 0 || err != nil || health.Version != "2.0.0" {
+		t.Errorf("Unexpected JSON output (code %d, err %v): %s", code, err, out)
+	}
+
+	code, out, _ = runCLI(t, config, "", "health", "--server", server.URL, "-o", "yaml")
+	if code != 0 || !strings.Contains(out, "status: healthy\n") {
+		t.Errorf("Unexpected YAML output (code %d): %s", code, out)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	if code, _, stderr := runCLI(t, config, "", "unknown"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("Expected usage error for unknown command, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCLI(t, config, "", "webhooks", "delete"); code != 2 || !strings.Contains(stderr, "<webhook-id>") {
+		t.Errorf("Expected usage error for missing argument, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCLI(t, config, "", "health"); code != 2 || !strings.Contains(stderr, "server URL is not set") {
+		t.Errorf("Expected missing server error, got %d: %s", code, stderr)
+	}
+}
+
+func TestAuthLoginStoresProfile(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case client.PathAPIV1AuthLogin:
+			var req types.LoginRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeData(w, types.LoginResponse{AccessToken: "access-1", RefreshToken: "refresh-1", TokenType: "Bearer", ExpiresIn: 3600})
+		case client.PathAPIV1UsersProfile:
+			authHeader = r.Header.Get("Authorization")
+			writeData(w, types.UserProfile{ID: "user-1", Email: "ops@example.com"})
+		}
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "nexusctl", "config.json")
+
+	code, _, stderr := runCLI(t, config, "secret\n", "--server", server.URL, "auth", "login", "--email", "ops@example.com", "--password-stdin")
+	if code != 0 {
+		t.Fatalf("Login failed (code %d): %s", code, stderr)
+	}
+
+	info, err := os.Stat(config)
+	if err != nil {
+		t.Fatalf("Profile file not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected profile file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// Сервер и токен берутся из профиля
+	code, out, stderr := runCLI(t, config, "", "auth", "whoami")
+	if code != 0 || !strings.Contains(out, "ops@example.com") {
+		t.Fatalf("whoami failed (code %d): %s %s", code, out, stderr)
+	}
+	if authHeader != "Bearer access-1" {
+		t.Errorf("Expected profile token to be sent, got %q", authHeader)
+	}
+
+	runCLI(t, config, "", "auth", "logout")
+	store, _ := loadProfiles(config)
+	if p := store.Profiles[defaultProfile]; p.AccessToken != "" || p.Server != server.URL {
+		t.Errorf("Expected tokens to be cleared and server kept, got %+v", p)
+	}
+}
+
+func TestExpiredProfileTokenIsRefreshed(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case client.PathAPIV1AuthRefresh:
+			writeData(w, types.RefreshTokenResponse{AccessToken: "access-2", TokenType: "Bearer", ExpiresIn: 3600})
+		default:
+			authHeader = r.Header.Get("Authorization")
+			writeData(w, types.UserProfile{ID: "user-1"})
+		}
+	}))
+	defer server.Close()
+
+	config := filepath.Join(t.TempDir(), "config.json")
+	store, _ := loadProfiles(config)
+	store.Profiles[defaultProfile] = &profile{Server: server.URL, AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: 1}
+	if err := store.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if code, _, stderr := runCLI(t, config, "", "auth", "whoami"); code != 0 {
+		t.Fatalf("whoami failed (code %d): %s", code, stderr)
+	}
+	if authHeader != "Bearer access-2" {
+		t.Errorf("Expected refreshed token, got %q", authHeader)
+	}
+	store, _ = loadProfiles(config)
+	if p := store.Profiles[defaultProfile]; p.AccessToken != "access-2" || p.RefreshToken != "refresh-1" {
+		t.Errorf("Expected refreshed token to be saved, got %+v", p)
+	}
+}
+
+func TestWebhooksListMasksSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, types.ListWebhooksResponse{
+			Webhooks: []types.WebhookInfo{{ID: "wh-1", Config: &types.WebhookConfig{URL: "https://example.com/hook", Events: []string{"a", "b"}, Secret: "s3cr3t", Active: true}}},
+			Total:    1,
+		})
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	code, out, _ := runCLI(t, config, "", "--server", server.URL, "webhooks", "list")
+	if code != 0 || !strings.Contains(out, "wh-1") || !strings.Contains(out, "a,b") {
+		t.Errorf("Unexpected table output (code %d): %s", code, out)
+	}
+
+	_, out, _ = runCLI(t, config, "", "--server", server.URL, "-o", "json", "webhooks", "list")
+	if strings.Contains(out, "s3cr3t") || !strings.Contains(out, secretMask) {
+		t.Errorf("Expected secret to be masked: %s", out)
+	}
+}
+
+func TestAnalyticsExportCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := types.GetEventsResponse{Total: 3}
+		if r.URL.Query().Get("offset") == "" {
+			resp.Events = []types.AnalyticsEvent{{ID: "1", EventType: "search"}, {ID: "2", EventType: "click"}}
+		} else {
+			resp.Events = []types.AnalyticsEvent{{ID: "3", EventType: "purchase", Data: map[string]interface{}{"amount": 10}}}
+		}
+		writeData(w, resp)
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	code, out, stderr := runCLI(t, config, "", "--server", server.URL, "analytics", "export", "--format", "csv", "--page-size", "2")
+	if code != 0 {
+		t.Fatalf("export failed (code %d): %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || lines[0] != "id,event_type,user_id,tenant_id,timestamp,data" {
+		t.Fatalf("Unexpected CSV: %s", out)
+	}
+	if lines[3] != `3,purchase,,,,"{""amount"":10}"` {
+		t.Errorf("Unexpected CSV row: %s", lines[3])
+	}
+}
+
+func TestBatchSubmitReportsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.BatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := types.BatchResponse{BatchID: "batch-1"}
+		for _, item := range req.Requests {
+			status := "completed"
+			if item.Query == "bad" {
+				status = "failed"
+			}
+			resp.Responses = append(resp.Responses, &types.ExecuteTemplateResponse{
+				ExecutionID:      "exec-" + item.Query,
+				Status:           status,
+				ResponseMetadata: &types.ResponseMetadata{RequestID: item.Metadata.RequestID},
+			})
+		}
+		writeData(w, resp)
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	stdin := `[{"query": "good"}, {"query": "bad"}]`
+	code, out, stderr := runCLI(t, config, stdin, "--server", server.URL, "batch", "submit", "-f", "-")
+	if code != 1 || !strings.Contains(stderr, "1 of 2 requests failed") {
+		t.Errorf("Expected failure exit code, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "exec-good") || !strings.Contains(out, "exec-bad") {
+		t.Errorf("Expected both items in output: %s", out)
+	}
+}
+
+func TestAdminIntegrationsMasksCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "int-1", "name": "Stripe", "credentials": {"api_key": "sk_live"}}`))
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	_, out, _ := runCLI(t, config, "", "--server", server.URL, "admin", "integrations", "get", "int-1")
+	if strings.Contains(out, "sk_live") || !strings.Contains(out, "api_key: '"+secretMask+"'") {
+		t.Errorf("Expected credentials to be masked: %s", out)
+	}
+
+	_, out, _ = runCLI(t, config, "", "--server", server.URL, "admin", "integrations", "get", "int-1", "--show-secrets")
+	if !strings.Contains(out, "sk_live") {
+		t.Errorf("Expected credentials with --show-secrets: %s", out)
+	}
+}
+
+func TestAdminApplyFromManifests(t *testing.T) {
+	var created types.DomainConfig
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == client.PathAPIV1AdminDomains:
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == client.PathAPIV1AdminDomains:
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(created)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	manifest := "kind: Domain\nspec:\n  id: commerce\n  auth_config:\n    token: ${COMMERCE_TOKEN}\n"
+	os.WriteFile(filepath.Join(dir, "commerce.yaml"), []byte(manifest), 0o644)
+	t.Setenv("COMMERCE_TOKEN", "t0ken")
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	code, out, stderr := runCLI(t, config, "n\n", "--server", server.URL, "admin", "apply", "-d", dir)
+	if code != 1 || !strings.Contains(stderr, "apply cancelled") || created.ID != "" {
+		t.Fatalf("Expected apply to be cancelled (code %d): %s", code, stderr)
+	}
+	if !strings.Contains(out, "+ Domain commerce") || strings.Contains(out, "t0ken") {
+		t.Errorf("Unexpected plan output: %s", out)
+	}
+
+	code, _, stderr = runCLI(t, config, "", "--server", server.URL, "admin", "apply", "-d", dir, "--yes")
+	if code != 0 || created.ID != "commerce" || created.AuthConfig["token"] != "t0ken" {
+		t.Errorf("Apply failed (code %d): %s %+v", code, stderr, created)
+	}
+}
+
+func TestAdminBackupAndRestore(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			w.Write([]byte(`{}`))
+			return
+		}
+		switch r.URL.Path {
+		case client.PathAPIV1AdminAIConfig:
+			w.Write([]byte(`{"model": "gpt-4", "api_key": "sk-secret"}`))
+		case client.PathAPIV1AdminDomains:
+			w.Write([]byte(`[{"id": "commerce", "auth_config": {"token": "t0ken"}}]`))
+		case client.PathAPIV1AdminVersion:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+	config := filepath.Join(t.TempDir(), "config.json")
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	code, _, stderr := runCLI(t, config, "s3cret\n", "--server", server.URL, "admin", "backup", "--out", archive, "--encrypt", "--passphrase-stdin")
+	if code != 0 || !strings.Contains(stderr, "1 domains") {
+		t.Fatalf("Backup failed (code %d): %s", code, stderr)
+	}
+	data, _ := os.ReadFile(archive)
+	if bytes.Contains(data, []byte("t0ken")) {
+		t.Error("Archive must not contain plaintext credentials")
+	}
+
+	code, out, stderr := runCLI(t, config, "s3cret\n", "--server", server.URL, "admin", "restore", archive,
+		"--passphrase-stdin", "--conflict", "rename", "--map", "domain/commerce=shop", "--dry-run")
+	if code != 0 || !strings.Contains(out, "shop") || !strings.Contains(out, "created") || len(writes) != 0 {
+		t.Errorf("Unexpected dry-run restore (code %d): %s %s %v", code, out, stderr, writes)
+	}
+
+	if code, _, stderr := runCLI(t, config, "wrong\n", "--server", server.URL, "admin", "restore", archive, "--passphrase-stdin"); code != 1 || !strings.Contains(stderr, "passphrase") {
+		t.Errorf("Expected passphrase error (code %d): %s", code, stderr)
+	}
+}
+
+func TestPromptsLintAndRenderLocalFile(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+	file := filepath.Join(t.TempDir(), "prompt.yaml")
+	os.WriteFile(file, []byte("name: search\ntemplate: |\n  Запрос: {{query}}\n  Город: {{city}}\nvariables: [query]\n"), 0o644)
+
+	code, out, stderr := runCLI(t, config, "", "admin", "prompts", "lint", "--file", file)
+	if code != 1 || !strings.Contains(out, "undeclared-variable") || !strings.Contains(out, "2:8") {
+		t.Errorf("Expected lint failure (code %d): %s %s", code, out, stderr)
+	}
+
+	code, out, stderr = runCLI(t, config, "", "admin", "prompts", "render", "--file", file, "--var", "query=борщ", "--var", "city=Москва")
+	if code != 0 || out != "Запрос: борщ\nГород: Москва\n" {
+		t.Errorf("Unexpected render output (code %d): %q %s", code, out, stderr)
+	}
+}
+
+func TestDomainsClassifyLocalManifests(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "travel.yaml"), []byte("kind: Domain\nspec:\n  id: travel\n  enabled: true\n  priority: 70\n  keywords: [отель, бронировать]\n"), 0o644)
+
+	code, out, stderr := runCLI(t, config, "", "admin", "domains", "classify", "-d", dir, "бронировать", "отель")
+	if code != 0 || !strings.Contains(out, "yes") || !strings.Contains(out, "0.91") {
+		t.Errorf("Unexpected classify output (code %d): %s %s", code, out, stderr)
+	}
+}
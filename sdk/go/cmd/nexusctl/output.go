@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/internal/yaml"
+)
+
+// Форматы вывода
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// secretMask заменяет значения секретов в выводе
+const secretMask = "******"
+
+// outputFormat возвращает выбранный формат вывода
+func (a *app) outputFormat() (string, error) {
+	switch strings.ToLower(a.output) {
+	case "", formatTable:
+		return formatTable, nil
+	case formatJSON:
+		return formatJSON, nil
+	case formatYAML, "yml":
+		return formatYAML, nil
+	}
+	return "", usagef("unknown output format %q (expected table, json or yaml)", a.output)
+}
+
+// print выводит значение в выбранном формате. render рисует табличное
+// представление; если он не задан, таблица заменяется YAML.
+func (a *app) print(v interface{}, render func(t *table)) error {
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format == formatJSON:
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case format == formatYAML || render == nil:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = a.stdout.Write(data)
+		return err
+	}
+
+	t := newTable(a.stdout)
+	render(t)
+	return t.flush()
+}
+
+// table выводит выровненные колонки
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(w io.Writer) *table {
+	return &table{w: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)}
+}
+
+// header выводит заголовок таблицы
+func (t *table) header(columns ...string) {
+	fmt.Fprintln(t.w, strings.ToUpper(strings.Join(columns, "\t")))
+}
+
+// row выводит строку таблицы
+func (t *table) row(values ...interface{}) {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = cell(v)
+	}
+	fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+}
+
+// field выводит пару "название: значение"
+func (t *table) field(name string, value interface{}) {
+	fmt.Fprintf(t.w, "%s:\t%s\n", name, cell(value))
+}
+
+// blank выводит пустую строку между блоками
+func (t *table) blank() {
+	t.flush()
+	fmt.Fprintln(t.w)
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+// cell форматирует значение ячейки
+func cell(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		s = v
+	case []string:
+		s = strings.Join(v, ",")
+	case float32, float64:
+		s = fmt.Sprintf("%.2f", v)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "-"
+	}
+	if len([]rune(s)) > 60 {
+		s = string([]rune(s)[:57]) + "..."
+	}
+	return s
+}
+
+// maskValues возвращает копию map с замаскированными значениями
+func maskValues(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return values
+	}
+	masked := make(map[string]string, len(values))
+	for k, v := range values {
+		if v != "" {
+			v = secretMask
+		}
+		masked[k] = v
+	}
+	return masked
+}
+
+// formatUnix форматирует Unix timestamp (0 - пусто)
+func formatUnix(ts int64) string {
+	if ts <= 0 {
+		return ""
+	}
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
+
+// warnTruncated предупреждает в stderr, что выборка обрезана по лимиту
+func (a *app) warnTruncated(err error, max int) {
+	if isCollectLimit(err) {
+		fmt.Fprintf(a.stderr, "Warning: output truncated to %d items, use --max to change the limit\n", max)
+	}
+}
+
+// isCollectLimit сообщает, что Pager.Collect остановился на лимите
+func isCollectLimit(err error) bool {
+	return errors.Is(err, client.ErrCollectLimit)
+}
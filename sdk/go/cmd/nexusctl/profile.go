@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// defaultProfile имя профиля по умолчанию
+const defaultProfile = "default"
+
+// tokenExpirySkew запас времени, за который токен считается истекшим
+const tokenExpirySkew = 30 * time.Second
+
+// profile хранит параметры подключения и токены
+type profile struct {
+	Server       string `json:"server,omitempty"`
+	Email        string `json:"email,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresAt    int64  `json:"expires_at,omitempty"` // Unix timestamp
+}
+
+// expired сообщает, истек ли access token
+func (p *profile) expired(now time.Time) bool {
+	return p.ExpiresAt > 0 && now.Add(tokenExpirySkew).Unix() >= p.ExpiresAt
+}
+
+// setTokens сохраняет выданные токены
+func (p *profile) setTokens(accessToken, refreshToken, tokenType string, expiresIn int32, now time.Time) {
+	p.AccessToken = accessToken
+	if refreshToken != "" {
+		p.RefreshToken = refreshToken
+	}
+	p.TokenType = tokenType
+	p.ExpiresAt = 0
+	if expiresIn > 0 {
+		p.ExpiresAt = now.Add(time.Duration(expiresIn) * time.Second).Unix()
+	}
+}
+
+// profileStore файл профилей
+type profileStore struct {
+	path     string
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// defaultConfigPath возвращает путь к файлу профилей по умолчанию
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "nexusctl", "config.json"), nil
+}
+
+// loadProfiles читает файл профилей (отсутствующий файл - пустой набор)
+func loadProfiles(path string) (*profileStore, error) {
+	store := &profileStore{path: path, Profiles: make(map[string]*profile)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles %s: %w", path, err)
+	}
+	if store.Profiles == nil {
+		store.Profiles = make(map[string]*profile)
+	}
+	return store, nil
+}
+
+// save атомарно записывает файл профилей с правами 0600
+func (s *profileStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".config-*")
+	if err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	return nil
+}
+
+// currentProfile загружает файл профилей и возвращает выбранный профиль.
+// Несуществующий профиль создается в памяти (сохраняется только явно).
+func (a *app) currentProfile() (*profileStore, string, *profile, error) {
+	path := firstNonEmpty(a.configPath, os.Getenv(envConfig))
+	if path == "" {
+		var err error
+		if path, err = defaultConfigPath(); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	store, err := loadProfiles(path)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	name := firstNonEmpty(a.profile, os.Getenv(envProfile), store.Current, defaultProfile)
+	prof, ok := store.Profiles[name]
+	if !ok {
+		prof = &profile{}
+		store.Profiles[name] = prof
+	}
+	return store, name, prof, nil
+}
+
+// refreshProfile обновляет access token профиля по refresh token
+func refreshProfile(ctx context.Context, c *client.Client, prof *profile) error {
+	resp, err := c.RefreshToken(ctx, &types.RefreshTokenRequest{RefreshToken: prof.RefreshToken})
+	if err != nil {
+		return err
+	}
+	prof.setTokens(resp.AccessToken, "", resp.TokenType, resp.ExpiresIn, time.Now())
+	return nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/internal/yaml"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/prompts"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// loadPrompt читает промпт из файла (YAML или JSON) или с сервера по ID
+func (a *app) loadPrompt(ctx context.Context, file, id string) (*types.PromptConfig, error) {
+	if file != "" {
+		data, err := a.readInput(file)
+		if err != nil {
+			return nil, err
+		}
+		var prompt types.PromptConfig
+		if strings.EqualFold(filepath.Ext(file), ".json") {
+			err = json.Unmarshal(data, &prompt)
+		} else {
+			err = yaml.Unmarshal(data, &prompt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		return &prompt, nil
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Admin().GetPrompt(ctx, id)
+}
+
+// promptSource разбирает аргументы команды, принимающей <id> или --file
+func promptSource(name string, file string, positional []string) (string, error) {
+	switch {
+	case file != "" && len(positional) == 0:
+		return "", nil
+	case file == "" && len(positional) == 1:
+		return positional[0], nil
+	}
+	return "", usagef("usage: %s <id> | --file <prompt.yaml>", name)
+}
+
+func (a *app) runPromptsRender(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts render")
+	file := fs.String("file", "", "Render a local prompt file instead of a server prompt")
+	var vars listFlag
+	fs.Var(&vars, "var", "Variable value as name=value (repeatable)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := promptSource(fs.Name(), *file, positional)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{}, len(vars))
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return usagef("admin prompts render: invalid --var %q (expected name=value)", v)
+		}
+		values[name] = value
+	}
+
+	prompt, err := a.loadPrompt(ctx, *file, id)
+	if err != nil {
+		return err
+	}
+	text, err := prompts.Render(prompt, values)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	_, err = fmt.Fprint(a.stdout, text)
+	return err
+}
+
+func (a *app) runPromptsLint(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts lint")
+	file := fs.String("file", "", "Lint a local prompt file instead of a server prompt")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := promptSource(fs.Name(), *file, positional)
+	if err != nil {
+		return err
+	}
+	prompt, err := a.loadPrompt(ctx, *file, id)
+	if err != nil {
+		return err
+	}
+
+	issues := prompts.Lint(prompt)
+	if err := a.print(issues, func(t *table) {
+		t.header("Severity", "Line", "Code", "Message")
+		for _, issue := range issues {
+			line := ""
+			if issue.Line > 0 {
+				line = fmt.Sprintf("%d:%d", issue.Line, issue.Column)
+			}
+			t.row(issue.Severity, line, issue.Code, issue.Message)
+		}
+	}); err != nil {
+		return err
+	}
+	if prompts.HasErrors(issues) {
+		return fmt.Errorf("prompt template has errors")
+	}
+	return nil
+}
+
+func (a *app) runPromptsDiff(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("admin prompts diff"), args, "old-id", "new-id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	old, err := c.Admin().GetPrompt(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	new, err := c.Admin().GetPrompt(ctx, positional[1])
+	if err != nil {
+		return err
+	}
+	diff := prompts.Diff(old, new)
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+	if format != formatTable {
+		return a.print(diff, nil)
+	}
+	if diff.Empty() {
+		fmt.Fprintln(a.stderr, "No differences")
+		return nil
+	}
+	fmt.Fprint(a.stdout, diff.String())
+	return nil
+}
+
+func (a *app) runPromptsVersions(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts versions")
+	domain := fs.String("domain", "", "Prompt domain")
+	positional, err := parseArgs(fs, args, "name")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	versions, err := c.Admin().ListPromptVersions(ctx, positional[0], *domain)
+	if err != nil {
+		return err
+	}
+	return a.print(versions, func(t *table) {
+		t.header("Version", "ID", "Active", "Description")
+		for _, p := range versions {
+			t.row(p.Version, p.ID, p.Active, p.Description)
+		}
+	})
+}
+
+func (a *app) runPromptsPublish(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts publish")
+	file := fs.String("file", "", "Prompt file (YAML or JSON, - for stdin)")
+	fs.StringVar(file, "f", "", "Shorthand for --file")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("admin prompts publish: --file is required")
+	}
+	prompt, err := a.loadPrompt(ctx, *file, "")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	published, err := c.Admin().PublishPrompt(ctx, prompt)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Prompt %s version %d published as %s\n", published.Name, published.Version, published.ID)
+	return nil
+}
+
+func (a *app) runPromptsRollback(ctx context.Context, args []string) error {
+	fs := a.flagSet("admin prompts rollback")
+	domain := fs.String("domain", "", "Prompt domain")
+	version := fs.Int("version", 0, "Version to activate (default: the one before the active version)")
+	positional, err := parseArgs(fs, args, "name")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	active, err := c.Admin().RollbackPrompt(ctx, positional[0], *domain, *version)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Prompt %s rolled back to version %d (%s)\n", active.Name, active.Version, active.ID)
+	return nil
+}
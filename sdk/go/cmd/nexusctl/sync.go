@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/configsync"
+)
+
+// syncFlags флаги, общие для admin plan и admin apply
+type syncFlags struct {
+	dir    *string
+	prune  *bool
+	ignore listFlag
+}
+
+func (a *app) syncFlagSet(name string) (*flag.FlagSet, *syncFlags) {
+	fs := a.flagSet(name)
+	f := &syncFlags{
+		dir:   fs.String("dir", "", "Directory with YAML/JSON manifests"),
+		prune: fs.Bool("prune", false, "Delete server resources missing from the manifests"),
+	}
+	fs.StringVar(f.dir, "d", "", "Shorthand for --dir")
+	fs.Var(&f.ignore, "ignore", "Field path to ignore when comparing (repeatable)")
+	return fs, f
+}
+
+// syncPlan загружает манифесты и вычисляет план изменений
+func (a *app) syncPlan(ctx context.Context, f *syncFlags, dryRun bool) (*configsync.Syncer, *configsync.Plan, error) {
+	if *f.dir == "" {
+		return nil, nil, usagef("--dir is required")
+	}
+	resources, err := configsync.LoadDir(*f.dir, configsync.LoadOptions{LookupEnv: os.LookupEnv})
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncer := configsync.New(c.Admin(), configsync.Config{Prune: *f.prune, DryRun: dryRun, IgnoreFields: f.ignore})
+	plan, err := syncer.Plan(ctx, resources)
+	if err != nil {
+		return nil, nil, err
+	}
+	return syncer, plan, nil
+}
+
+// printPlan выводит план текстом или в формате --output
+func (a *app) printPlan(plan *configsync.Plan) error {
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+	if format == formatTable {
+		return plan.Write(a.stdout)
+	}
+	return a.print(plan, nil)
+}
+
+func (a *app) runAdminPlan(ctx context.Context, args []string) error {
+	fs, f := a.syncFlagSet("admin plan")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	_, plan, err := a.syncPlan(ctx, f, true)
+	if err != nil {
+		return err
+	}
+	return a.printPlan(plan)
+}
+
+func (a *app) runAdminApply(ctx context.Context, args []string) error {
+	fs, f := a.syncFlagSet("admin apply")
+	dryRun := fs.Bool("dry-run", false, "Show the plan without applying it")
+	yes := fs.Bool("yes", false, "Apply without confirmation")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	syncer, plan, err := a.syncPlan(ctx, f, *dryRun)
+	if err != nil {
+		return err
+	}
+	if err := a.printPlan(plan); err != nil {
+		return err
+	}
+	if plan.Empty() {
+		return nil
+	}
+	if *dryRun {
+		fmt.Fprintln(a.stderr, "Dry run: no changes applied")
+		return nil
+	}
+	if !*yes && !a.confirm("Apply these changes?") {
+		return fmt.Errorf("apply cancelled")
+	}
+
+	result, err := syncer.Apply(ctx, plan)
+	fmt.Fprintf(a.stderr, "Applied %d of %d changes\n", len(result.Applied), len(plan.Changes))
+	return err
+}
+
+// confirm запрашивает подтверждение; ответ читается из stdin
+func (a *app) confirm(question string) bool {
+	fmt.Fprintf(a.stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(a.stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/internal/yaml"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/results"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func templateCommands() *command {
+	return &command{
+		name:    "template",
+		summary: "Выполнение шаблонов",
+		sub: []*command{
+			{name: "execute", args: "[query]", summary: "Выполнить запрос", run: (*app).runTemplateExecute},
+			{name: "status", args: "<execution-id>", summary: "Статус выполнения", run: (*app).runTemplateStatus},
+			{name: "stream", args: "<execution-id>", summary: "Поток результатов (SSE)", run: (*app).runTemplateStream},
+		},
+	}
+}
+
+func (a *app) runTemplateExecute(ctx context.Context, args []string) error {
+	fs := a.flagSet("template execute")
+	file := fs.String("f", "", "Read the request from a JSON file ('-' for stdin)")
+	query := fs.String("query", "", "Query text (can also be passed as arguments)")
+	language := fs.String("language", "ru", "Query language")
+	userID := fs.String("user-id", "", "User ID for the request context")
+	locale := fs.String("locale", "", "User locale, e.g. ru-RU")
+	currency := fs.String("currency", "", "User currency, e.g. RUB")
+	timeoutMS := fs.Int("timeout-ms", 0, "Server-side execution timeout in milliseconds")
+	maxResults := fs.Int("max-results", 0, "Maximum results per domain")
+	webSearch := fs.Bool("web-search", false, "Include web search results")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	req := &types.ExecuteTemplateRequest{}
+	if *file != "" {
+		if err := a.readJSON(*file, req); err != nil {
+			return err
+		}
+	}
+	if q := firstNonEmpty(*query, strings.Join(positional, " ")); q != "" {
+		req.Query = q
+	}
+	if req.Query == "" {
+		return usagef("query is required: use --query, arguments or -f")
+	}
+	if req.Language == "" {
+		req.Language = *language
+	}
+	if *userID != "" || *locale != "" || *currency != "" {
+		if req.Context == nil {
+			req.Context = &types.UserContext{}
+		}
+		req.Context.UserID = firstNonEmpty(*userID, req.Context.UserID)
+		req.Context.Locale = firstNonEmpty(*locale, req.Context.Locale)
+		req.Context.Currency = firstNonEmpty(*currency, req.Context.Currency)
+	}
+	if *timeoutMS > 0 || *maxResults > 0 || *webSearch {
+		if req.Options == nil {
+			req.Options = &types.ExecuteOptions{}
+		}
+		if *timeoutMS > 0 {
+			req.Options.TimeoutMS = int32(*timeoutMS)
+		}
+		if *maxResults > 0 {
+			req.Options.MaxResultsPerDomain = int32(*maxResults)
+		}
+		req.Options.IncludeWebSearch = req.Options.IncludeWebSearch || *webSearch
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := c.ExecuteTemplate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) { renderExecution(t, resp) })
+}
+
+func (a *app) runTemplateStatus(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("template status"), args, "execution-id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.GetExecutionStatus(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) { renderExecution(t, resp) })
+}
+
+// renderExecution выводит статус выполнения и объединенный список результатов
+func renderExecution(t *table, resp *types.ExecuteTemplateResponse) {
+	t.field("Execution ID", resp.ExecutionID)
+	t.field("Status", resp.Status)
+	t.field("Query type", resp.QueryType)
+	t.field("Processing ms", resp.ProcessingTimeMS)
+
+	if len(resp.Sections) > 0 {
+		t.blank()
+		t.header("Domain", "Status", "Results", "Time ms", "Error")
+		for _, s := range resp.Sections {
+			t.row(s.DomainID, s.Status, len(s.Results), s.ResponseTimeMS, s.Error)
+		}
+	}
+
+	merged := results.Merge(resp, results.MergeOptions{IncludeWebSearch: true})
+	if len(merged.Items) > 0 {
+		t.blank()
+		t.header("Rank", "Domain", "Type", "Title", "Relevance")
+		for _, item := range merged.Items {
+			t.row(item.Rank, item.DomainID, item.Type, item.Title, item.Relevance)
+		}
+	}
+}
+
+// sseEvent событие потока Server-Sent Events
+type sseEvent struct {
+	Event string          `json:"event,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func (a *app) runTemplateStream(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("template stream"), args, "execution-id")
+	if err != nil {
+		return err
+	}
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.StreamTemplateResults(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var event sseEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				event.Data = sseData(strings.Join(data, "\n"))
+				if err := a.printEvent(format, &event); err != nil {
+					return err
+				}
+			}
+			event, data = sseEvent{}, nil
+		case strings.HasPrefix(line, ":"):
+			// комментарий (keep-alive)
+		case strings.HasPrefix(line, "event:"):
+			event.Event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "id:"):
+			event.ID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if len(data) > 0 {
+		event.Data = sseData(strings.Join(data, "\n"))
+		if err := a.printEvent(format, &event); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// sseData возвращает данные события как JSON (не-JSON данные - как строку)
+func sseData(s string) json.RawMessage {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	quoted, _ := json.Marshal(s)
+	return quoted
+}
+
+// printEvent выводит событие потока: JSON Lines, YAML-документы или строки текста
+func (a *app) printEvent(format string, event *sseEvent) error {
+	switch format {
+	case formatJSON:
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "%s\n", data)
+		return err
+	case formatYAML:
+		data, err := yaml.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "---\n%s", data)
+		return err
+	}
+	name := firstNonEmpty(event.Event, "message")
+	_, err := fmt.Fprintf(a.stdout, "%s\t%s\n", name, event.Data)
+	return err
+}
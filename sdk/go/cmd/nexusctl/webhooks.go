@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func webhookCommands() *command {
+	return &command{
+		name:    "webhooks",
+		summary: "Управление webhooks",
+		sub: []*command{
+			{name: "register", summary: "Зарегистрировать webhook", run: (*app).runWebhookRegister},
+			{name: "list", summary: "Список webhooks", run: (*app).runWebhookList},
+			{name: "test", args: "<webhook-id>", summary: "Отправить тестовое событие", run: (*app).runWebhookTest},
+			{name: "delete", args: "<webhook-id>", summary: "Удалить webhook", run: (*app).runWebhookDelete},
+		},
+	}
+}
+
+// listFlag флаг, который можно указать несколько раз или через запятую
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+func (a *app) runWebhookRegister(ctx context.Context, args []string) error {
+	fs := a.flagSet("webhooks register")
+	url := fs.String("url", "", "Webhook URL")
+	secret := fs.String("secret", "", "Secret used to sign deliveries")
+	description := fs.String("description", "", "Webhook description")
+	maxRetries := fs.Int("max-retries", 0, "Maximum delivery retries")
+	var events, headers listFlag
+	fs.Var(&events, "event", "Event to subscribe to (repeatable or comma-separated)")
+	fs.Var(&headers, "header", "Extra header as Key=Value (repeatable)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *url == "" || len(events) == 0 {
+		return usagef("webhooks register: --url and --event are required")
+	}
+
+	config := &types.WebhookConfig{
+		URL:         *url,
+		Events:      events,
+		Secret:      *secret,
+		Description: *description,
+		Active:      true,
+	}
+	for _, h := range headers {
+		key, value, ok := strings.Cut(h, "=")
+		if !ok {
+			return usagef("invalid header %q: expected Key=Value", h)
+		}
+		if config.Headers == nil {
+			config.Headers = make(map[string]string)
+		}
+		config.Headers[key] = value
+	}
+	if *maxRetries > 0 {
+		config.RetryPolicy = &types.WebhookRetryPolicy{MaxRetries: int32(*maxRetries)}
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := c.RegisterWebhook(ctx, &types.RegisterWebhookRequest{Config: config})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) {
+		t.field("Webhook ID", resp.WebhookID)
+		t.field("Status", resp.Status)
+		t.field("Message", resp.Message)
+	})
+}
+
+func (a *app) runWebhookList(ctx context.Context, args []string) error {
+	fs := a.flagSet("webhooks list")
+	activeOnly := fs.Bool("active-only", false, "Show only active webhooks")
+	max := fs.Int("max", 1000, "Maximum number of webhooks to fetch (0 = all)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	webhooks, err := c.WebhooksPager(&types.ListWebhooksRequest{ActiveOnly: *activeOnly}).Collect(ctx, *max)
+	if err != nil && !isCollectLimit(err) {
+		return err
+	}
+
+	for i := range webhooks {
+		if webhooks[i].Config != nil && webhooks[i].Config.Secret != "" {
+			masked := *webhooks[i].Config
+			masked.Secret = secretMask
+			webhooks[i].Config = &masked
+		}
+	}
+
+	if printErr := a.print(webhooks, func(t *table) {
+		t.header("ID", "URL", "Events", "Active", "Success", "Errors", "Last used")
+		for _, wh := range webhooks {
+			cfg := wh.Config
+			if cfg == nil {
+				cfg = &types.WebhookConfig{}
+			}
+			t.row(wh.ID, cfg.URL, cfg.Events, cfg.Active, wh.SuccessCount, wh.ErrorCount, formatUnix(wh.LastUsedAt))
+		}
+	}); printErr != nil {
+		return printErr
+	}
+	a.warnTruncated(err, *max)
+	return nil
+}
+
+func (a *app) runWebhookTest(ctx context.Context, args []string) error {
+	fs := a.flagSet("webhooks test")
+	event := fs.String("event", "", "Test event type")
+	data := fs.String("data", "", "Test payload as a JSON object")
+	positional, err := parseArgs(fs, args, "webhook-id")
+	if err != nil {
+		return err
+	}
+
+	req := &types.TestWebhookRequest{WebhookID: positional[0], Event: *event}
+	if *data != "" {
+		if err := json.Unmarshal([]byte(*data), &req.Data); err != nil {
+			return usagef("invalid --data: %v", err)
+		}
+	}
+
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := c.TestWebhook(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) {
+		t.field("Webhook ID", resp.WebhookID)
+		t.field("Status", resp.Status)
+		t.field("Response code", resp.ResponseCode)
+		t.field("Response ms", resp.ResponseTimeMS)
+	})
+}
+
+func (a *app) runWebhookDelete(ctx context.Context, args []string) error {
+	positional, err := parseArgs(a.flagSet("webhooks delete"), args, "webhook-id")
+	if err != nil {
+		return err
+	}
+	c, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.DeleteWebhook(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(t *table) {
+		t.field("Webhook ID", resp.WebhookID)
+		t.field("Status", resp.Status)
+		t.field("Message", resp.Message)
+	})
+}
@@ -0,0 +1,98 @@
+package configsync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// ApplyResult результат применения плана
+type ApplyResult struct {
+	Applied []*Change `json:"applied"`
+	DryRun  bool      `json:"dry_run,omitempty"`
+}
+
+// Apply выполняет изменения плана по порядку. При ошибке применение
+// останавливается, так как следующие ресурсы могут зависеть от неудавшегося;
+// уже выполненные изменения возвращаются в ApplyResult.Applied.
+// При Config.DryRun сервер не изменяется.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) (*ApplyResult, error) {
+	result := &ApplyResult{DryRun: s.config.DryRun}
+	if s.config.DryRun {
+		return result, nil
+	}
+
+	for _, ch := range plan.Changes {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := s.applyChange(ctx, ch); err != nil {
+			return result, fmt.Errorf("failed to %s %s %q: %w", ch.Action, ch.Kind, ch.ID, err)
+		}
+		result.Applied = append(result.Applied, ch)
+	}
+	return result, nil
+}
+
+// applyChange выполняет одно изменение
+func (s *Syncer) applyChange(ctx context.Context, ch *Change) error {
+	if ch.Action == ActionDelete {
+		return s.deleteResource(ctx, ch.Kind, ch.ID)
+	}
+	if ch.object == nil {
+		return fmt.Errorf("change has no desired state")
+	}
+
+	create := ch.Action == ActionCreate
+	var err error
+	switch obj := ch.object.(type) {
+	case *types.AIConfig:
+		err = s.admin.UpdateAIConfig(ctx, obj)
+	case *types.IntegrationConfig:
+		if create {
+			_, err = s.admin.CreateIntegration(ctx, obj)
+		} else {
+			_, err = s.admin.UpdateIntegration(ctx, ch.ID, obj)
+		}
+	case *types.DomainConfig:
+		if create {
+			_, err = s.admin.CreateDomain(ctx, obj)
+		} else {
+			_, err = s.admin.UpdateDomain(ctx, ch.ID, obj)
+		}
+	case *types.PromptConfig:
+		if create {
+			_, err = s.admin.CreatePrompt(ctx, obj)
+		} else {
+			_, err = s.admin.UpdatePrompt(ctx, ch.ID, obj)
+		}
+	case *types.FrontendConfig:
+		if create {
+			_, err = s.admin.CreateFrontendConfig(ctx, obj)
+		} else if len(ch.Fields) > 0 {
+			_, err = s.admin.UpdateFrontendConfig(ctx, ch.ID, obj)
+		}
+		if err == nil && ch.activate {
+			err = s.admin.SetActiveFrontendConfig(ctx, ch.ID)
+		}
+	default:
+		err = fmt.Errorf("unsupported object %T", obj)
+	}
+	return err
+}
+
+// deleteResource удаляет ресурс сервера
+func (s *Syncer) deleteResource(ctx context.Context, kind Kind, id string) error {
+	switch kind {
+	case KindIntegration:
+		return s.admin.DeleteIntegration(ctx, id)
+	case KindDomain:
+		return s.admin.DeleteDomain(ctx, id)
+	case KindPrompt:
+		return s.admin.DeletePrompt(ctx, id)
+	case KindFrontendConfig:
+		return s.admin.DeleteFrontendConfig(ctx, id)
+	}
+	return fmt.Errorf("%s resources cannot be deleted", kind)
+}
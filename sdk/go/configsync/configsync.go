@@ -0,0 +1,122 @@
+// Package configsync реализует декларативное управление административной
+// конфигурацией: манифесты доменов, промптов, интеграций, AIConfig и
+// FrontendConfig загружаются из каталога, сравниваются с текущим состоянием
+// сервера (Plan) и применяются в порядке зависимостей (Apply).
+package configsync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Kind тип ресурса манифеста
+type Kind string
+
+const (
+	KindAIConfig       Kind = "AIConfig"
+	KindIntegration    Kind = "Integration"
+	KindDomain         Kind = "Domain"
+	KindPrompt         Kind = "Prompt"
+	KindFrontendConfig Kind = "FrontendConfig"
+)
+
+// kindOrder задает порядок применения: промпты ссылаются на домены,
+// домены могут использовать интеграции. Удаление выполняется в обратном порядке.
+var kindOrder = map[Kind]int{
+	KindAIConfig:       0,
+	KindIntegration:    1,
+	KindDomain:         2,
+	KindPrompt:         3,
+	KindFrontendConfig: 4,
+}
+
+// Kinds возвращает поддерживаемые типы ресурсов в порядке применения
+func Kinds() []Kind {
+	kinds := make([]Kind, 0, len(kindOrder))
+	for k := range kindOrder {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kindOrder[kinds[i]] < kindOrder[kinds[j]] })
+	return kinds
+}
+
+// newObject создает типизированный объект ресурса
+func newObject(kind Kind) (interface{}, error) {
+	switch kind {
+	case KindAIConfig:
+		return &types.AIConfig{}, nil
+	case KindIntegration:
+		return &types.IntegrationConfig{}, nil
+	case KindDomain:
+		return &types.DomainConfig{}, nil
+	case KindPrompt:
+		return &types.PromptConfig{}, nil
+	case KindFrontendConfig:
+		return &types.FrontendConfig{}, nil
+	}
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
+
+// Admin - административные методы, используемые при синхронизации.
+// *client.AdminClient реализует этот интерфейс.
+type Admin interface {
+	GetAIConfig(ctx context.Context) (*types.AIConfig, error)
+	UpdateAIConfig(ctx context.Context, config *types.AIConfig) error
+
+	ListIntegrations(ctx context.Context, integrationType string) ([]*types.IntegrationConfig, error)
+	CreateIntegration(ctx context.Context, config *types.IntegrationConfig) (*types.IntegrationConfig, error)
+	UpdateIntegration(ctx context.Context, id string, config *types.IntegrationConfig) (*types.IntegrationConfig, error)
+	DeleteIntegration(ctx context.Context, id string) error
+
+	ListDomains(ctx context.Context) ([]*types.DomainConfig, error)
+	CreateDomain(ctx context.Context, domain *types.DomainConfig) (*types.DomainConfig, error)
+	UpdateDomain(ctx context.Context, id string, domain *types.DomainConfig) (*types.DomainConfig, error)
+	DeleteDomain(ctx context.Context, id string) error
+
+	ListPrompts(ctx context.Context, domain string) ([]*types.PromptConfig, error)
+	CreatePrompt(ctx context.Context, prompt *types.PromptConfig) (*types.PromptConfig, error)
+	UpdatePrompt(ctx context.Context, id string, prompt *types.PromptConfig) (*types.PromptConfig, error)
+	DeletePrompt(ctx context.Context, id string) error
+
+	ListFrontendConfigs(ctx context.Context) ([]*types.FrontendConfig, error)
+	CreateFrontendConfig(ctx context.Context, config *types.FrontendConfig) (*types.FrontendConfig, error)
+	UpdateFrontendConfig(ctx context.Context, id string, config *types.FrontendConfig) (*types.FrontendConfig, error)
+	DeleteFrontendConfig(ctx context.Context, id string) error
+	SetActiveFrontendConfig(ctx context.Context, id string) error
+}
+
+var _ Admin = (*client.AdminClient)(nil)
+
+// Config параметры синхронизации
+type Config struct {
+	// Prune удаляет ресурсы, отсутствующие в манифестах. Затрагиваются
+	// только типы ресурсов, присутствующие в манифестах.
+	Prune bool
+
+	// DryRun запрещает Apply изменять состояние сервера
+	DryRun bool
+
+	// IgnoreFields - пути полей (например "metadata.revision"), которые
+	// не сравниваются. Поля created_at и updated_at не сравниваются всегда.
+	IgnoreFields []string
+}
+
+// Syncer вычисляет и применяет изменения административной конфигурации
+type Syncer struct {
+	admin  Admin
+	config Config
+	ignore map[string]bool
+}
+
+// New создает Syncer
+func New(admin Admin, config Config) *Syncer {
+	ignore := map[string]bool{"created_at": true, "updated_at": true}
+	for _, f := range config.IgnoreFields {
+		ignore[f] = true
+	}
+	return &Syncer{admin: admin, config: config, ignore: ignore}
+}
@@ -0,0 +1,350 @@
+package configsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// fakeAdmin хранит состояние в памяти и записывает вызовы
+type fakeAdmin struct {
+	ai           *types.AIConfig
+	integrations map[string]*types.IntegrationConfig
+	domains      map[string]*types.DomainConfig
+	prompts      map[string]*types.PromptConfig
+	frontend     map[string]*types.FrontendConfig
+	calls        []string
+	fail         string
+}
+
+func newFakeAdmin() *fakeAdmin {
+	return &fakeAdmin{
+		ai:           &types.AIConfig{Provider: "openai", Model: "gpt-4", APIKey: "sk-old", MaxTokens: 1000},
+		integrations: map[string]*types.IntegrationConfig{},
+		domains:      map[string]*types.DomainConfig{},
+		prompts:      map[string]*types.PromptConfig{},
+		frontend:     map[string]*types.FrontendConfig{},
+	}
+}
+
+func (f *fakeAdmin) record(call string) error {
+	f.calls = append(f.calls, call)
+	if call == f.fail {
+		return errors.New("server error")
+	}
+	return nil
+}
+
+func (f *fakeAdmin) GetAIConfig(ctx context.Context) (*types.AIConfig, error) {
+	c := *f.ai
+	return &c, nil
+}
+
+func (f *fakeAdmin) UpdateAIConfig(ctx context.Context, config *types.AIConfig) error {
+	f.ai = config
+	return f.record("update AIConfig " + aiConfigID)
+}
+
+func (f *fakeAdmin) ListIntegrations(ctx context.Context, integrationType string) ([]*types.IntegrationConfig, error) {
+	var list []*types.IntegrationConfig
+	for _, v := range f.integrations {
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (f *fakeAdmin) CreateIntegration(ctx context.Context, config *types.IntegrationConfig) (*types.IntegrationConfig, error) {
+	f.integrations[config.ID] = config
+	return config, f.record("create Integration " + config.ID)
+}
+
+func (f *fakeAdmin) UpdateIntegration(ctx context.Context, id string, config *types.IntegrationConfig) (*types.IntegrationConfig, error) {
+	f.integrations[id] = config
+	return config, f.record("update Integration " + id)
+}
+
+func (f *fakeAdmin) DeleteIntegration(ctx context.Context, id string) error {
+	delete(f.integrations, id)
+	return f.record("delete Integration " + id)
+}
+
+func (f *fakeAdmin) ListDomains(ctx context.Context) ([]*types.DomainConfig, error) {
+	var list []*types.DomainConfig
+	for _, v := range f.domains {
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (f *fakeAdmin) CreateDomain(ctx context.Context, domain *types.DomainConfig) (*types.DomainConfig, error) {
+	f.domains[domain.ID] = domain
+	return domain, f.record("create Domain " + domain.ID)
+}
+
+func (f *fakeAdmin) UpdateDomain(ctx context.Context, id string, domain *types.DomainConfig) (*types.DomainConfig, error) {
+	f.domains[id] = domain
+	return domain, f.record("update Domain " + id)
+}
+
+func (f *fakeAdmin) DeleteDomain(ctx context.Context, id string) error {
+	delete(f.domains, id)
+	return f.record("delete Domain " + id)
+}
+
+func (f *fakeAdmin) ListPrompts(ctx context.Context, domain string) ([]*types.PromptConfig, error) {
+	var list []*types.PromptConfig
+	for _, v := range f.prompts {
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (f *fakeAdmin) CreatePrompt(ctx context.Context, prompt *types.PromptConfig) (*types.PromptConfig, error) {
+	f.prompts[prompt.ID] = prompt
+	return prompt, f.record("create Prompt " + prompt.ID)
+}
+
+func (f *fakeAdmin) UpdatePrompt(ctx context.Context, id string, prompt *types.PromptConfig) (*types.PromptConfig, error) {
+	f.prompts[id] = prompt
+	return prompt, f.record("update Prompt " + id)
+}
+
+func (f *fakeAdmin) DeletePrompt(ctx context.Context, id string) error {
+	delete(f.prompts, id)
+	return f.record("delete Prompt " + id)
+}
+
+func (f *fakeAdmin) ListFrontendConfigs(ctx context.Context) ([]*types.FrontendConfig, error) {
+	var list []*types.FrontendConfig
+	for _, v := range f.frontend {
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (f *fakeAdmin) CreateFrontendConfig(ctx context.Context, config *types.FrontendConfig) (*types.FrontendConfig, error) {
+	f.frontend[config.ID] = config
+	return config, f.record("create FrontendConfig " + config.ID)
+}
+
+func (f *fakeAdmin) UpdateFrontendConfig(ctx context.Context, id string, config *types.FrontendConfig) (*types.FrontendConfig, error) {
+	f.frontend[id] = config
+	return config, f.record("update FrontendConfig " + id)
+}
+
+func (f *fakeAdmin) DeleteFrontendConfig(ctx context.Context, id string) error {
+	delete(f.frontend, id)
+	return f.record("delete FrontendConfig " + id)
+}
+
+func (f *fakeAdmin) SetActiveFrontendConfig(ctx context.Context, id string) error {
+	return f.record("activate FrontendConfig " + id)
+}
+
+const manifests = `kind: AIConfig
+spec:
+  api_key: ${AI_KEY}
+  max_tokens: 2000
+---
+kind: Domain
+spec:
+  id: commerce
+  name: Commerce
+  endpoint: http://commerce:8080
+  auth_config:
+    token: ${DOMAIN_TOKEN}
+  keywords: [buy, order]
+---
+kind: Prompt
+spec:
+  id: greeting
+  domain: commerce
+  template: |
+    Hello, {{name}}!
+---
+kind: Integration
+spec:
+  id: stripe
+  name: Stripe
+  credentials:
+    api_key: sk_live
+`
+
+func loadTestManifests(t *testing.T) []*Resource {
+	t.Helper()
+	env := map[string]string{"AI_KEY": "sk-new", "DOMAIN_TOKEN": "t0ken"}
+	resources, err := Load([]byte(manifests), "config.yaml", LoadOptions{
+		LookupEnv: func(name string) (string, bool) {
+			v, ok := env[name]
+			return v, ok
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return resources
+}
+
+func TestPlanAndApply(t *testing.T) {
+	admin := newFakeAdmin()
+	admin.domains["commerce"] = &types.DomainConfig{ID: "commerce", Name: "Commerce", Endpoint: "http://old:8080", Priority: 10, CreatedAt: "2025-01-01"}
+	admin.prompts["legacy"] = &types.PromptConfig{ID: "legacy", Name: "Legacy"}
+	admin.frontend["default"] = &types.FrontendConfig{ID: "default"}
+
+	syncer := New(admin, Config{Prune: true})
+	plan, err := syncer.Plan(context.Background(), loadTestManifests(t))
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	var got []string
+	for _, ch := range plan.Changes {
+		got = append(got, string(ch.Action)+" "+string(ch.Kind)+" "+ch.ID)
+	}
+	want := []string{
+		"update AIConfig ai-config",
+		"create Integration stripe",
+		"update Domain commerce",
+		"create Prompt greeting",
+		"delete Prompt legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Unexpected plan:\n%s", strings.Join(got, "\n"))
+	}
+
+	var out bytes.Buffer
+	plan.Write(&out)
+	text := out.String()
+	for _, secret := range []string{"sk-new", "sk-old", "t0ken", "sk_live"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("Plan leaks secret %q:\n%s", secret, text)
+		}
+	}
+	for _, line := range []string{
+		`api_key: (sensitive) -> (sensitive)`,
+		`max_tokens: 1000 -> 2000`,
+		`endpoint: "http://old:8080" -> "http://commerce:8080"`,
+		`auth_config.token: null -> (sensitive)`,
+		`Plan: 2 to create, 2 to update, 1 to delete, 0 unchanged.`,
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Plan output missing %q:\n%s", line, text)
+		}
+	}
+
+	result, err := syncer.Apply(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Applied) != len(plan.Changes) {
+		t.Errorf("Expected %d applied changes, got %d", len(plan.Changes), len(result.Applied))
+	}
+	if strings.Join(admin.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected call order:\n%s", strings.Join(admin.calls, "\n"))
+	}
+
+	// Поля, не указанные в манифесте, сохраняются
+	if d := admin.domains["commerce"]; d.Priority != 10 || d.AuthConfig["token"] != "t0ken" {
+		t.Errorf("Unexpected domain after apply: %+v", d)
+	}
+	if admin.ai.Model != "gpt-4" || admin.ai.APIKey != "sk-new" {
+		t.Errorf("Unexpected AI config after apply: %+v", admin.ai)
+	}
+	if _, ok := admin.frontend["default"]; !ok {
+		t.Error("Frontend configs must not be pruned when no FrontendConfig manifests are given")
+	}
+
+	// Повторный план пуст
+	plan, err = syncer.Plan(context.Background(), loadTestManifests(t))
+	if err != nil || !plan.Empty() || plan.Unchanged != 4 {
+		t.Errorf("Expected empty plan after apply, got %+v (err %v)", plan, err)
+	}
+}
+
+func TestApplyDryRunAndFailure(t *testing.T) {
+	admin := newFakeAdmin()
+	resources := loadTestManifests(t)
+
+	plan, _ := New(admin, Config{}).Plan(context.Background(), resources)
+	result, err := New(admin, Config{DryRun: true}).Apply(context.Background(), plan)
+	if err != nil || !result.DryRun || len(admin.calls) != 0 {
+		t.Fatalf("Dry run must not call the server: %v %v", err, admin.calls)
+	}
+
+	admin.fail = "create Domain commerce"
+	result, err = New(admin, Config{}).Apply(context.Background(), plan)
+	if err == nil || !strings.Contains(err.Error(), `failed to create Domain "commerce"`) {
+		t.Fatalf("Expected apply error, got %v", err)
+	}
+	// Промпт зависит от домена и не применяется после ошибки
+	if len(result.Applied) != 2 || admin.prompts["greeting"] != nil {
+		t.Errorf("Expected to stop after failure, applied %d: %v", len(result.Applied), admin.calls)
+	}
+}
+
+func TestFrontendConfigActivation(t *testing.T) {
+	admin := newFakeAdmin()
+	admin.frontend["dark"] = &types.FrontendConfig{ID: "dark", Theme: "dark"}
+
+	resources, err := Load([]byte(`{"kind": "FrontendConfig", "spec": {"id": "dark", "active": true}}`), "frontend.json", LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	syncer := New(admin, Config{})
+	plan, err := syncer.Plan(context.Background(), resources)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if _, err := syncer.Apply(context.Background(), plan); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Join(admin.calls, ",") != "update FrontendConfig dark,activate FrontendConfig dark" {
+		t.Errorf("Unexpected calls: %v", admin.calls)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]string{
+		"kind: Widget\nspec: {}\n":                          `unknown kind "Widget"`,
+		"kind: Domain\nspec:\n  name: x\n":                  "spec.id is required",
+		"kind: Domain\nspec:\n  id: x\n  endpoints: y\n":    `unknown field "endpoints"`,
+		"kind: AIConfig\nspec:\n  api_key: ${MISSING}\n":    "MISSING is not set",
+		"kind: Domain\nmetadata: {}\nspec:\n  id: x\n":      `unknown manifest field "metadata"`,
+		"kind: Prompt\nspec:\n  id: p\n  version: latest\n": "version",
+	}
+	for input, want := range tests {
+		_, err := Load([]byte(input), "bad.yaml", LoadOptions{LookupEnv: func(string) (string, bool) { return "", false }})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "domains"), 0o755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.WriteFile(filepath.Join(dir, "domains", "commerce.yaml"), []byte("kind: Domain\nspec:\n  id: commerce\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "prompts.json"), []byte(`[{"kind": "Prompt", "spec": {"id": "a"}}, {"kind": "Prompt", "spec": {"id": "b"}}]`), 0o644)
+	os.WriteFile(filepath.Join(dir, ".git", "config.yaml"), []byte("not a manifest"), 0o644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# docs"), 0o644)
+
+	resources, err := LoadDir(dir, LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(resources) != 3 || resources[0].ID != "commerce" || resources[0].Source != filepath.Join(dir, "domains", "commerce.yaml") {
+		t.Errorf("Unexpected resources: %+v", resources)
+	}
+
+	os.WriteFile(filepath.Join(dir, "dup.yaml"), []byte("kind: Prompt\nspec:\n  id: a\n"), 0o644)
+	if _, err := LoadDir(dir, LoadOptions{}); err == nil || !strings.Contains(err.Error(), "defined twice") {
+		t.Errorf("Expected duplicate error, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package configsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/internal/yaml"
+)
+
+// aiConfigID идентификатор единственного ресурса AIConfig
+const aiConfigID = "ai-config"
+
+// Resource ресурс, описанный в манифесте.
+//
+// Манифест - YAML или JSON документ вида:
+//
+//	kind: Domain
+//	spec:
+//	  id: commerce
+//	  name: Commerce
+//	  endpoint: http://commerce:8080
+//
+// Поля spec соответствуют json-тегам типов types.DomainConfig,
+// types.PromptConfig, types.IntegrationConfig, types.AIConfig и
+// types.FrontendConfig. Поле id обязательно для всех типов, кроме AIConfig.
+type Resource struct {
+	Kind   Kind                   `json:"kind"`
+	ID     string                 `json:"id"`
+	Source string                 `json:"source,omitempty"` // файл манифеста
+	Spec   map[string]interface{} `json:"spec"`
+}
+
+// LoadOptions параметры загрузки манифестов
+type LoadOptions struct {
+	// LookupEnv подставляет переменные вида ${NAME} в строковые значения
+	// spec, чтобы секреты не хранились в манифестах. Если nil, подстановка
+	// не выполняется. Отсутствующая переменная считается ошибкой.
+	LookupEnv func(name string) (string, bool)
+}
+
+// LoadDir загружает манифесты *.yaml, *.yml и *.json из каталога
+// и его подкаталогов. Скрытые файлы и каталоги пропускаются.
+func LoadDir(dir string, opts LoadOptions) ([]*Resource, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	sort.Strings(files)
+
+	var resources []*Resource
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests: %w", err)
+		}
+		loaded, err := Load(data, path, opts)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, loaded...)
+	}
+	if err := checkDuplicates(resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// Load разбирает ресурсы одного манифеста. Файлы с расширением .json
+// читаются как JSON (объект, массив объектов или поток объектов),
+// остальные - как YAML с документами, разделенными "---".
+func Load(data []byte, source string, opts LoadOptions) ([]*Resource, error) {
+	docs, err := parseDocuments(data, strings.EqualFold(filepath.Ext(source), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	var resources []*Resource
+	for i, doc := range docs {
+		res, err := newResource(doc, source, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", source, i+1, err)
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+// parseDocuments разбирает документы манифеста в обобщенные значения
+func parseDocuments(data []byte, isJSON bool) ([]interface{}, error) {
+	if !isJSON {
+		return yaml.ParseDocuments(data)
+	}
+
+	var docs []interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	for {
+		var doc interface{}
+		if err := dec.Decode(&doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if list, ok := doc.([]interface{}); ok {
+			docs = append(docs, list...)
+		} else {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+// newResource проверяет документ манифеста и создает ресурс
+func newResource(doc interface{}, source string, opts LoadOptions) (*Resource, error) {
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("manifest must be a mapping with kind and spec")
+	}
+	for key := range m {
+		if key != "kind" && key != "spec" {
+			return nil, fmt.Errorf("unknown manifest field %q", key)
+		}
+	}
+
+	kind, _ := m["kind"].(string)
+	if _, ok := kindOrder[Kind(kind)]; !ok {
+		return nil, fmt.Errorf("unknown kind %q", m["kind"])
+	}
+	spec, ok := m["spec"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: spec must be a mapping", kind)
+	}
+
+	if opts.LookupEnv != nil {
+		expanded, err := expandEnv(spec, opts.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+		spec = expanded.(map[string]interface{})
+	}
+
+	res := &Resource{Kind: Kind(kind), Source: source, Spec: spec}
+	if res.Kind == KindAIConfig {
+		res.ID = aiConfigID
+	} else {
+		id, _ := spec["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("%s: spec.id is required", kind)
+		}
+		res.ID = id
+	}
+
+	// Строгий разбор выявляет опечатки в именах полей
+	if _, err := decodeObject(res.Kind, spec); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", kind, res.ID, err)
+	}
+	return res, nil
+}
+
+// decodeObject переносит spec в типизированный объект, запрещая неизвестные поля
+func decodeObject(kind Kind, spec map[string]interface{}) (interface{}, error) {
+	obj, err := newObject(kind)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// checkDuplicates проверяет уникальность ресурсов
+func checkDuplicates(resources []*Resource) error {
+	seen := make(map[string]*Resource)
+	for _, res := range resources {
+		key := string(res.Kind) + "/" + res.ID
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("%s %q is defined twice: %s and %s", res.Kind, res.ID, prev.Source, res.Source)
+		}
+		seen[key] = res
+	}
+	return nil
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv подставляет переменные окружения в строковые значения
+func expandEnv(v interface{}, lookup func(string) (string, bool)) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		var missing string
+		out := envPattern.ReplaceAllStringFunc(v, func(ref string) string {
+			name := envPattern.FindStringSubmatch(ref)[1]
+			value, ok := lookup(name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("environment variable %s is not set", missing)
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded, err := expandEnv(item, lookup)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded, err := expandEnv(item, lookup)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return v, nil
+}
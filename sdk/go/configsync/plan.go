@@ -0,0 +1,406 @@
+package configsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Action действие над ресурсом
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// SensitiveValue подставляется в план вместо значений секретных полей
+const SensitiveValue = "(sensitive)"
+
+// FieldDiff изменение одного поля. Path - путь через точку, например
+// "auth_config.token". Значения секретных полей заменяются на SensitiveValue.
+type FieldDiff struct {
+	Path      string      `json:"path"`
+	Old       interface{} `json:"old,omitempty"`
+	New       interface{} `json:"new,omitempty"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// Change изменение одного ресурса
+type Change struct {
+	Action Action      `json:"action"`
+	Kind   Kind        `json:"kind"`
+	ID     string      `json:"id"`
+	Name   string      `json:"name,omitempty"`
+	Source string      `json:"source,omitempty"`
+	Fields []FieldDiff `json:"fields,omitempty"`
+
+	object   interface{} // желаемое состояние для create и update
+	activate bool        // FrontendConfig нужно сделать активной
+}
+
+// Plan изменения, необходимые для приведения сервера к манифестам.
+// Изменения упорядочены так, как их выполнит Apply.
+type Plan struct {
+	Changes   []*Change `json:"changes"`
+	Unchanged int       `json:"unchanged"`
+}
+
+// Empty сообщает, что сервер уже соответствует манифестам
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count возвращает количество изменений с указанным действием
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, ch := range p.Changes {
+		if ch.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Write выводит план в текстовом виде
+func (p *Plan) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, ch := range p.Changes {
+		sign := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[ch.Action]
+		fmt.Fprintf(&buf, "%s %s %s", sign, ch.Kind, ch.ID)
+		if ch.Name != "" && ch.Name != ch.ID {
+			fmt.Fprintf(&buf, " (%s)", ch.Name)
+		}
+		if ch.Source != "" {
+			fmt.Fprintf(&buf, "  [%s]", ch.Source)
+		}
+		buf.WriteByte('\n')
+
+		for _, f := range ch.Fields {
+			if ch.Action == ActionCreate {
+				fmt.Fprintf(&buf, "    %s: %s\n", f.Path, formatValue(f.New))
+			} else {
+				fmt.Fprintf(&buf, "    %s: %s -> %s\n", f.Path, formatValue(f.Old), formatValue(f.New))
+			}
+		}
+		if ch.activate {
+			fmt.Fprintf(&buf, "    (will be activated)\n")
+		}
+	}
+	if p.Empty() {
+		fmt.Fprintf(&buf, "No changes. %d resources are up to date.\n", p.Unchanged)
+	} else {
+		fmt.Fprintf(&buf, "\nPlan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+			p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete), p.Unchanged)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// formatValue форматирует значение поля для текстового плана
+func formatValue(v interface{}) string {
+	if v == SensitiveValue {
+		return SensitiveValue
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Plan сравнивает ресурсы манифестов с текущим состоянием сервера.
+//
+// Поля, отсутствующие в манифесте, сохраняют текущие значения на сервере,
+// поэтому манифест может описывать только управляемые поля. Ресурсы сервера,
+// отсутствующие в манифестах, удаляются только при Config.Prune.
+func (s *Syncer) Plan(ctx context.Context, resources []*Resource) (*Plan, error) {
+	if err := checkDuplicates(resources); err != nil {
+		return nil, err
+	}
+	byKind := make(map[Kind][]*Resource)
+	for _, res := range resources {
+		byKind[res.Kind] = append(byKind[res.Kind], res)
+	}
+
+	plan := &Plan{}
+	var deletes []*Change
+	for _, kind := range Kinds() {
+		wanted, ok := byKind[kind]
+		if !ok {
+			continue
+		}
+		live, err := s.fetchLive(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(wanted, func(i, j int) bool { return wanted[i].ID < wanted[j].ID })
+		declared := make(map[string]bool, len(wanted))
+		for _, res := range wanted {
+			declared[res.ID] = true
+			ch, err := s.planResource(res, live[res.ID])
+			if err != nil {
+				return nil, err
+			}
+			if ch == nil {
+				plan.Unchanged++
+				continue
+			}
+			plan.Changes = append(plan.Changes, ch)
+		}
+
+		if !s.config.Prune || kind == KindAIConfig {
+			continue
+		}
+		var pruned []*Change
+		for id, current := range live {
+			if declared[id] {
+				continue
+			}
+			name, _ := current["name"].(string)
+			pruned = append(pruned, &Change{Action: ActionDelete, Kind: kind, ID: id, Name: name})
+		}
+		sort.Slice(pruned, func(i, j int) bool { return pruned[i].ID < pruned[j].ID })
+		// Удаления выполняются после всех изменений в обратном порядке типов
+		deletes = append(pruned, deletes...)
+	}
+	plan.Changes = append(plan.Changes, deletes...)
+	return plan, nil
+}
+
+// planResource вычисляет изменение ресурса или nil, если изменений нет
+func (s *Syncer) planResource(res *Resource, current map[string]interface{}) (*Change, error) {
+	spec := res.Spec
+	if current != nil {
+		spec = mergeSpec(current, res.Spec)
+	}
+	object, err := decodeObject(res.Kind, spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", res.Kind, res.ID, err)
+	}
+	desired, err := toMap(object)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := &Change{Action: ActionUpdate, Kind: res.Kind, ID: res.ID, Source: res.Source, object: object}
+	ch.Name, _ = desired["name"].(string)
+	if current == nil {
+		ch.Action = ActionCreate
+	}
+	ch.Fields = s.diff(res.Kind, nil, current, desired)
+
+	if fc, ok := object.(*types.FrontendConfig); ok && fc.Active {
+		wasActive, _ := current["active"].(bool)
+		ch.activate = !wasActive
+	}
+	if ch.Action == ActionUpdate && len(ch.Fields) == 0 && !ch.activate {
+		return nil, nil
+	}
+	return ch, nil
+}
+
+// fetchLive получает текущие ресурсы сервера указанного типа
+func (s *Syncer) fetchLive(ctx context.Context, kind Kind) (map[string]map[string]interface{}, error) {
+	var (
+		items []interface{}
+		err   error
+	)
+	switch kind {
+	case KindAIConfig:
+		var config *types.AIConfig
+		if config, err = s.admin.GetAIConfig(ctx); err == nil {
+			m, err := toMap(config)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]map[string]interface{}{aiConfigID: m}, nil
+		}
+	case KindIntegration:
+		var list []*types.IntegrationConfig
+		list, err = s.admin.ListIntegrations(ctx, "")
+		items = toInterfaces(list)
+	case KindDomain:
+		var list []*types.DomainConfig
+		list, err = s.admin.ListDomains(ctx)
+		items = toInterfaces(list)
+	case KindPrompt:
+		var list []*types.PromptConfig
+		list, err = s.admin.ListPrompts(ctx, "")
+		items = toInterfaces(list)
+	case KindFrontendConfig:
+		var list []*types.FrontendConfig
+		list, err = s.admin.ListFrontendConfigs(ctx)
+		items = toInterfaces(list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s resources: %w", kind, err)
+	}
+
+	live := make(map[string]map[string]interface{}, len(items))
+	for _, item := range items {
+		m, err := toMap(item)
+		if err != nil {
+			return nil, err
+		}
+		if id, _ := m["id"].(string); id != "" {
+			live[id] = m
+		}
+	}
+	return live, nil
+}
+
+func toInterfaces[T any](list []*T) []interface{} {
+	items := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// toMap переводит объект в обобщенное JSON-представление
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// mergeSpec накладывает spec на текущее состояние: вложенные отображения
+// объединяются, остальные значения заменяются
+func mergeSpec(current, spec map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(current)+len(spec))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range spec {
+		cm, ok1 := merged[k].(map[string]interface{})
+		sm, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			merged[k] = mergeSpec(cm, sm)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// diff сравнивает значения и возвращает изменения полей
+func (s *Syncer) diff(kind Kind, path []string, old, new interface{}) []FieldDiff {
+	joined := strings.Join(path, ".")
+	if s.ignore[joined] || joined == "id" {
+		return nil
+	}
+
+	om, oldIsMap := old.(map[string]interface{})
+	nm, newIsMap := new.(map[string]interface{})
+	if (oldIsMap || old == nil) && (newIsMap || new == nil) && (oldIsMap || newIsMap) {
+		keys := make(map[string]bool)
+		for k := range om {
+			keys[k] = true
+		}
+		for k := range nm {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		var fields []FieldDiff
+		for _, k := range sorted {
+			child := append(append([]string(nil), path...), k)
+			fields = append(fields, s.diff(kind, child, om[k], nm[k])...)
+		}
+		return fields
+	}
+
+	if isEmpty(old) && isEmpty(new) || reflect.DeepEqual(old, new) {
+		return nil
+	}
+	field := FieldDiff{Path: joined, Old: old, New: new}
+	if isSensitive(kind, path) {
+		field.Sensitive = true
+		field.Old, field.New = maskValue(old), maskValue(new)
+	}
+	return []FieldDiff{field}
+}
+
+// isEmpty сообщает, что значение не задано
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func maskValue(v interface{}) interface{} {
+	if isEmpty(v) {
+		return nil
+	}
+	return SensitiveValue
+}
+
+// sensitiveFields поля, значения которых всегда скрываются
+var sensitiveFields = map[Kind]string{
+	KindAIConfig:    "api_key",
+	KindDomain:      "auth_config",
+	KindIntegration: "credentials",
+}
+
+// isSensitive сообщает, содержит ли поле секрет
+func isSensitive(kind Kind, path []string) bool {
+	if len(path) > 0 && sensitiveFields[kind] == path[0] {
+		return true
+	}
+	for _, segment := range path {
+		if isSecretKey(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretKey распознает имена ключей, похожие на секреты
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range []string{"secret", "password", "passwd", "api_key", "apikey", "private_key", "credential"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return key == "token" || strings.HasSuffix(key, "_token")
+}
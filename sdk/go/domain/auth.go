@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultAPIKeyHeader заголовок с API ключом по умолчанию
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// claimsKey ключ контекста для claims JWT
+type claimsKey struct{}
+
+// Claims возвращает claims JWT запроса, если домен использует AuthType "jwt"
+func Claims(ctx context.Context) map[string]interface{} {
+	claims, _ := ctx.Value(claimsKey{}).(jwt.Claims)
+	return claims
+}
+
+// authenticator проверяет учетные данные запроса
+type authenticator interface {
+	authenticate(r *http.Request) (jwt.Claims, *types.ErrorDetail)
+}
+
+func newAuthenticator(authType string, config map[string]string) (authenticator, error) {
+	switch authType {
+	case "", "none":
+		return nil, nil
+	case "api_key":
+		if config["api_key"] == "" {
+			return nil, fmt.Errorf("auth_config.api_key is required for api_key auth")
+		}
+		header := config["header"]
+		if header == "" {
+			header = DefaultAPIKeyHeader
+		}
+		return &apiKeyAuth{header: header, key: config["api_key"]}, nil
+	case "jwt":
+		auth := &jwtAuth{expect: jwt.Expectations{Issuer: config["issuer"], Audience: config["audience"]}}
+		switch {
+		case config["secret"] != "":
+			auth.key = []byte(config["secret"])
+		case config["public_key"] != "":
+			key, err := jwt.ParsePublicKey([]byte(config["public_key"]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth_config.public_key: %w", err)
+			}
+			auth.key = key
+		default:
+			return nil, fmt.Errorf("auth_config.secret or auth_config.public_key is required for jwt auth")
+		}
+		return auth, nil
+	}
+	return nil, fmt.Errorf("unsupported auth type %q", authType)
+}
+
+// apiKeyAuth проверяет статический API ключ
+type apiKeyAuth struct {
+	header string
+	key    string
+}
+
+func (a *apiKeyAuth) authenticate(r *http.Request) (jwt.Claims, *types.ErrorDetail) {
+	value := r.Header.Get(a.header)
+	if strings.EqualFold(a.header, "Authorization") {
+		value = bearerToken(r)
+	}
+	if value == "" || subtle.ConstantTimeCompare([]byte(value), []byte(a.key)) != 1 {
+		return nil, &types.ErrorDetail{Code: "AUTHENTICATION_FAILED", Type: "AUTHENTICATION_ERROR", Message: "Invalid or missing API key"}
+	}
+	return nil, nil
+}
+
+// jwtAuth проверяет подпись и claims Bearer-токена
+type jwtAuth struct {
+	key    interface{}
+	expect jwt.Expectations
+}
+
+func (a *jwtAuth) authenticate(r *http.Request) (jwt.Claims, *types.ErrorDetail) {
+	raw := bearerToken(r)
+	if raw == "" {
+		return nil, &types.ErrorDetail{Code: "AUTHENTICATION_FAILED", Type: "AUTHENTICATION_ERROR", Message: "Bearer token is required"}
+	}
+	token, err := jwt.Parse(raw)
+	if err != nil {
+		return nil, &types.ErrorDetail{Code: "TOKEN_MALFORMED", Type: "AUTHENTICATION_ERROR", Message: "Malformed token"}
+	}
+	if err := token.Verify(a.key); err != nil {
+		return nil, &types.ErrorDetail{Code: "INVALID_TOKEN", Type: "AUTHENTICATION_ERROR", Message: "Invalid token"}
+	}
+	if err := token.Claims.Validate(a.expect); err != nil {
+		if errors.Is(err, jwt.ErrExpired) {
+			return nil, &types.ErrorDetail{Code: "TOKEN_EXPIRED", Type: "AUTHENTICATION_ERROR", Message: "Token expired"}
+		}
+		return nil, &types.ErrorDetail{Code: "INVALID_TOKEN", Type: "AUTHENTICATION_ERROR", Message: "Invalid token", Details: err.Error()}
+	}
+	return token.Claims, nil
+}
+
+// bearerToken извлекает токен из заголовка Authorization
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
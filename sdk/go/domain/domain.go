@@ -0,0 +1,84 @@
+// Package domain помогает реализовать доменный микросервис (commerce,
+// recipes, travel, ...), которому AI Service маршрутизирует запросы.
+//
+// Сервис принимает запросы в формате Application Protocol
+// ({"metadata": ..., "data": ...}) и отвечает секцией результатов:
+//
+//	POST /execute  data: domain.Request        -> data: types.DomainSection
+//	POST /actions  data: domain.ActionRequest  -> data: types.ExecuteActionResponse
+//	GET  /health                               -> types.HealthResponse
+//
+// Server проверяет метаданные и версию протокола, применяет
+// DomainConfig.Timeout и DomainConfig.AuthType и заполняет ResponseMetadata;
+// реализации остается вернуть результаты:
+//
+//	srv, err := domain.NewServer(domain.HandlerFunc(func(ctx context.Context, req *domain.Request) (*types.DomainSection, error) {
+//		return &types.DomainSection{Results: []types.ResultItem{{ID: "1", Type: "product", Title: req.Query}}}, nil
+//	}), domain.Config{Domain: cfg})
+//	http.ListenAndServe(":8080", srv)
+package domain
+
+import (
+	"context"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Пути доменного сервиса относительно DomainConfig.Endpoint
+const (
+	PathExecute = "/execute"
+	PathActions = "/actions"
+	PathHealth  = "/health"
+)
+
+// Статусы DomainSection
+const (
+	StatusSuccess = "success"
+	StatusPartial = "partial"
+	StatusError   = "error"
+)
+
+// Request запрос, маршрутизированный домену
+type Request struct {
+	ExecutionID string                 `json:"execution_id,omitempty"`
+	Query       string                 `json:"query"`
+	Language    string                 `json:"language,omitempty"`
+	Context     *types.UserContext     `json:"context,omitempty"`
+	Options     *types.ExecuteOptions  `json:"options,omitempty"`
+	Filters     *types.AdvancedFilters `json:"filters,omitempty"`
+
+	// Metadata метаданные конверта запроса
+	Metadata *types.RequestMetadata `json:"-"`
+}
+
+// ActionRequest запрос выполнения действия над результатом домена
+type ActionRequest struct {
+	ResultID   string                 `json:"result_id"`
+	ActionType string                 `json:"action_type"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Context    *types.UserContext     `json:"context,omitempty"`
+
+	// Metadata метаданные конверта запроса
+	Metadata *types.RequestMetadata `json:"-"`
+}
+
+// Handler обрабатывает запросы домена. Ошибка *types.ErrorDetail
+// возвращается клиенту как есть, остальные ошибки - как INTERNAL_ERROR
+// без подробностей.
+type Handler interface {
+	Execute(ctx context.Context, req *Request) (*types.DomainSection, error)
+}
+
+// HandlerFunc адаптер функции к Handler
+type HandlerFunc func(ctx context.Context, req *Request) (*types.DomainSection, error)
+
+// Execute вызывает f(ctx, req)
+func (f HandlerFunc) Execute(ctx context.Context, req *Request) (*types.DomainSection, error) {
+	return f(ctx, req)
+}
+
+// ActionHandler дополнительно реализуется Handler, если домен поддерживает
+// действия над результатами (Action). Без него /actions отвечает NOT_FOUND.
+type ActionHandler interface {
+	ExecuteAction(ctx context.Context, req *ActionRequest) (*types.ExecuteActionResponse, error)
+}
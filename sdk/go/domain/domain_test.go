@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/protocol"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+type commerce struct{}
+
+func (commerce) Execute(ctx context.Context, req *Request) (*types.DomainSection, error) {
+	switch req.Query {
+	case "slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case "missing":
+		return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "No products"}
+	case "broken":
+		return nil, errors.New("database password is wrong")
+	}
+	return &types.DomainSection{
+		Title: "Товары",
+		Results: []types.ResultItem{{
+			ID:      "p1",
+			Type:    "product",
+			Title:   req.Query + " для " + req.Context.UserID,
+			Actions: []types.Action{{Type: "purchase", Label: "Купить", Method: "POST"}},
+		}},
+	}, nil
+}
+
+func (commerce) ExecuteAction(ctx context.Context, req *ActionRequest) (*types.ExecuteActionResponse, error) {
+	return &types.ExecuteActionResponse{Status: "success", ResultID: "order-" + req.ResultID}, nil
+}
+
+func post(t *testing.T, h http.Handler, path string, metadata *types.RequestMetadata, data interface{}, header http.Header) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+	t.Helper()
+	body, _ := json.Marshal(protocol.NewRequestMessage(metadata, data))
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var out map[string]json.RawMessage
+	json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+func TestServerExecute(t *testing.T) {
+	srv, err := NewServer(commerce{}, Config{Domain: &types.DomainConfig{ID: "commerce", Timeout: 1}, ServerVersion: "1.4.0"})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	metadata := types.NewRequestMetadata("2.0.0", "1.0.0")
+
+	rec, out := post(t, srv, PathExecute, metadata, &Request{Query: "ноутбук", Context: &types.UserContext{UserID: "u1"}}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	var meta types.ResponseMetadata
+	var section types.DomainSection
+	json.Unmarshal(out["metadata"], &meta)
+	json.Unmarshal(out["data"], &section)
+	if err := types.ValidateResponseMetadata(&meta); err != nil || meta.RequestID != metadata.RequestID || meta.ServerVersion != "1.4.0" {
+		t.Errorf("Unexpected response metadata %+v: %v", meta, err)
+	}
+	if section.DomainID != "commerce" || section.Status != StatusSuccess || section.Results[0].Title != "ноутбук для u1" || section.Results[0].Actions[0].Type != "purchase" {
+		t.Errorf("Unexpected section: %+v", section)
+	}
+
+	rec, out = post(t, srv, PathActions, metadata, &ActionRequest{ResultID: "p1", ActionType: "purchase"}, nil)
+	var action types.ExecuteActionResponse
+	json.Unmarshal(out["data"], &action)
+	if rec.Code != http.StatusOK || action.ResultID != "order-p1" || action.ActionType != "purchase" {
+		t.Errorf("Unexpected action response %d: %s", rec.Code, rec.Body)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		metadata *types.RequestMetadata
+		query    string
+		status   int
+		code     string
+	}{
+		{"timeout", metadata, "slow", http.StatusGatewayTimeout, "TIMEOUT"},
+		{"domain error", metadata, "missing", http.StatusNotFound, "RESOURCE_NOT_FOUND"},
+		{"internal error", metadata, "broken", http.StatusInternalServerError, "INTERNAL_ERROR"},
+		{"empty query", metadata, "", http.StatusBadRequest, "MISSING_REQUIRED_FIELD"},
+		{"bad metadata", &types.RequestMetadata{RequestID: "x"}, "q", http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"incompatible version", types.NewRequestMetadata("3.0.0", "1.0.0"), "q", http.StatusBadRequest, "PROTOCOL_VERSION_MISMATCH"},
+	} {
+		rec, _ := post(t, srv, PathExecute, tt.metadata, &Request{Query: tt.query}, nil)
+		var resp types.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != tt.status || resp.Error.Code != tt.code {
+			t.Errorf("%s: got %d %s", tt.name, rec.Code, rec.Body)
+		}
+		if tt.code == "INTERNAL_ERROR" && bytes.Contains(rec.Body.Bytes(), []byte("password")) {
+			t.Errorf("%s: internal details leaked: %s", tt.name, rec.Body)
+		}
+	}
+}
+
+func TestServerTimeoutFromOptions(t *testing.T) {
+	srv, _ := NewServer(commerce{}, Config{Domain: &types.DomainConfig{ID: "commerce", Timeout: 30}})
+	start := time.Now()
+	rec, _ := post(t, srv, PathExecute, types.NewRequestMetadata("2.0.0", "1.0.0"), &Request{Query: "slow", Options: &types.ExecuteOptions{TimeoutMS: 50}}, nil)
+	if rec.Code != http.StatusGatewayTimeout || time.Since(start) > 5*time.Second {
+		t.Errorf("Expected request timeout to apply, got %d after %s", rec.Code, time.Since(start))
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	if _, err := NewServer(commerce{}, Config{Domain: &types.DomainConfig{ID: "c", AuthType: "api_key"}}); err == nil {
+		t.Error("Expected error for api_key auth without key")
+	}
+	if _, err := NewServer(commerce{}, Config{Domain: &types.DomainConfig{ID: "c", AuthType: "kerberos"}}); err == nil {
+		t.Error("Expected error for unsupported auth type")
+	}
+
+	metadata := types.NewRequestMetadata("2.0.0", "1.0.0")
+	req := &Request{Query: "q", Context: &types.UserContext{}}
+
+	apiKey, _ := NewServer(commerce{}, Config{Domain: &types.DomainConfig{ID: "c", AuthType: "api_key", AuthConfig: map[string]string{"api_key": "k1"}}})
+	if rec, _ := post(t, apiKey, PathExecute, metadata, req, http.Header{"X-Api-Key": {"wrong"}}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong key, got %d", rec.Code)
+	}
+	if rec, _ := post(t, apiKey, PathExecute, metadata, req, http.Header{"X-Api-Key": {"k1"}}); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for valid key, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var gotClaims map[string]interface{}
+	handler := HandlerFunc(func(ctx context.Context, req *Request) (*types.DomainSection, error) {
+		gotClaims = Claims(ctx)
+		return nil, nil
+	})
+	jwtServer, _ := NewServer(handler, Config{Domain: &types.DomainConfig{ID: "c", AuthType: "jwt", AuthConfig: map[string]string{"secret": "s3cret", "audience": "c"}}})
+	valid, _ := jwt.Sign(jwt.Claims{"sub": "ai-service", "aud": "c", "exp": time.Now().Add(time.Minute).Unix()}, "HS256", []byte("s3cret"))
+	expired, _ := jwt.Sign(jwt.Claims{"aud": "c", "exp": time.Now().Add(-time.Minute).Unix()}, "HS256", []byte("s3cret"))
+
+	for token, code := range map[string]string{expired: "TOKEN_EXPIRED", "garbage": "TOKEN_MALFORMED"} {
+		rec, _ := post(t, jwtServer, PathExecute, metadata, req, http.Header{"Authorization": {"Bearer " + token}})
+		var resp types.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusUnauthorized || resp.Error.Code != code {
+			t.Errorf("Expected %s, got %d %s", code, rec.Code, rec.Body)
+		}
+	}
+	rec, _ := post(t, jwtServer, PathExecute, metadata, req, http.Header{"Authorization": {"Bearer " + valid}})
+	if rec.Code != http.StatusOK || gotClaims["sub"] != "ai-service" {
+		t.Errorf("Expected valid token to pass, got %d %s (claims %v)", rec.Code, rec.Body, gotClaims)
+	}
+
+	// Сервис без ActionHandler не поддерживает действия
+	rec, _ = post(t, jwtServer, PathActions, metadata, &ActionRequest{ActionType: "buy"}, http.Header{"Authorization": {"Bearer " + valid}})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for actions, got %d", rec.Code)
+	}
+}
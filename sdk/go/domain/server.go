@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultMaxBodyBytes максимальный размер тела запроса по умолчанию
+const DefaultMaxBodyBytes = 1 << 20
+
+// Config параметры доменного сервиса
+type Config struct {
+	// Domain конфигурация домена: ID, Timeout (в секундах, 0 - без
+	// ограничения), AuthType и AuthConfig (см. NewServer)
+	Domain *types.DomainConfig
+
+	// ProtocolVersion версия протокола сервиса (по умолчанию client.DefaultProtocolVersion)
+	ProtocolVersion string
+
+	// ServerVersion версия сервиса в ResponseMetadata (по умолчанию ProtocolVersion)
+	ServerVersion string
+
+	// MaxBodyBytes максимальный размер тела запроса (по умолчанию DefaultMaxBodyBytes)
+	MaxBodyBytes int64
+}
+
+// Server http.Handler доменного сервиса
+type Server struct {
+	handler  Handler
+	config   Config
+	auth     authenticator
+	protocol http.Handler
+}
+
+// NewServer создает сервис для handler.
+//
+// Поддерживаемые DomainConfig.AuthType и ключи AuthConfig:
+//   - "" или "none" - без проверки;
+//   - "api_key" - api_key (обязателен) и header (по умолчанию X-API-Key;
+//     для Authorization ожидается "Bearer <key>");
+//   - "jwt" - secret (HS256/384/512) или public_key (PEM, RS*/ES*),
+//     необязательные issuer и audience.
+func NewServer(handler Handler, config Config) (*Server, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("domain handler is required")
+	}
+	if config.Domain == nil || config.Domain.ID == "" {
+		return nil, fmt.Errorf("domain config with ID is required")
+	}
+	if config.ProtocolVersion == "" {
+		config.ProtocolVersion = client.DefaultProtocolVersion
+	}
+	if config.ServerVersion == "" {
+		config.ServerVersion = config.ProtocolVersion
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	auth, err := newAuthenticator(config.Domain.AuthType, config.Domain.AuthConfig)
+	if err != nil {
+		return nil, fmt.Errorf("domain %s: %w", config.Domain.ID, err)
+	}
+	s := &Server{handler: handler, config: config, auth: auth}
+	s.protocol = middleware.Protocol(middleware.Config{
+		ProtocolVersion: config.ProtocolVersion,
+		ServerVersion:   config.ServerVersion,
+		MaxBodyBytes:    config.MaxBodyBytes,
+		RequireMetadata: true,
+	})(middleware.HandlerFunc(s.serve))
+	return s, nil
+}
+
+// ServeHTTP реализует http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case PathHealth:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&types.HealthResponse{
+			Status:    "healthy",
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Version:   s.config.ServerVersion,
+		})
+		return
+	case PathExecute, PathActions:
+	default:
+		middleware.WriteError(w, r, &types.ErrorDetail{Code: "ENDPOINT_NOT_FOUND", Type: "NOT_FOUND", Message: "Endpoint not found"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		middleware.WriteError(w, r, &types.ErrorDetail{Code: "METHOD_NOT_ALLOWED", Type: "VALIDATION_ERROR", Message: "Method not allowed"})
+		return
+	}
+
+	// Учетные данные проверяются до разбора тела запроса
+	if s.auth != nil {
+		claims, detail := s.auth.authenticate(r)
+		if detail != nil {
+			middleware.WriteError(w, r, detail)
+			return
+		}
+		if claims != nil {
+			r = r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims))
+		}
+	}
+	s.protocol.ServeHTTP(w, r)
+}
+
+// serve обрабатывает запрос после проверки метаданных
+func (s *Server) serve(r *http.Request) (interface{}, error) {
+	if r.URL.Path == PathExecute {
+		return s.execute(r)
+	}
+	return s.executeAction(r)
+}
+
+func (s *Server) execute(r *http.Request) (interface{}, error) {
+	start := time.Now()
+	var req Request
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Query == "" {
+		return nil, &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Query cannot be empty", Field: "data.query"}
+	}
+	req.Metadata = middleware.RequestMetadata(r.Context())
+
+	timeout := time.Duration(s.config.Domain.Timeout) * time.Second
+	if req.Options != nil && req.Options.TimeoutMS > 0 {
+		if t := time.Duration(req.Options.TimeoutMS) * time.Millisecond; timeout == 0 || t < timeout {
+			timeout = t
+		}
+	}
+	return s.run(r.Context(), timeout, func(ctx context.Context) (interface{}, error) {
+		section, err := s.handler.Execute(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		if section == nil {
+			section = &types.DomainSection{}
+		}
+		if section.DomainID == "" {
+			section.DomainID = s.config.Domain.ID
+		}
+		if section.Status == "" {
+			section.Status = StatusSuccess
+		}
+		section.ResponseTimeMS = int32(time.Since(start).Milliseconds())
+		return section, nil
+	})
+}
+
+func (s *Server) executeAction(r *http.Request) (interface{}, error) {
+	actions, ok := s.handler.(ActionHandler)
+	if !ok {
+		return nil, &types.ErrorDetail{Code: "ENDPOINT_NOT_FOUND", Type: "NOT_FOUND", Message: fmt.Sprintf("Domain %s does not support actions", s.config.Domain.ID)}
+	}
+	var req ActionRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.ActionType == "" {
+		return nil, &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Action type is required", Field: "data.action_type"}
+	}
+	req.Metadata = middleware.RequestMetadata(r.Context())
+
+	return s.run(r.Context(), time.Duration(s.config.Domain.Timeout)*time.Second, func(ctx context.Context) (interface{}, error) {
+		resp, err := actions.ExecuteAction(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			resp = &types.ExecuteActionResponse{}
+		}
+		if resp.ActionType == "" {
+			resp.ActionType = req.ActionType
+		}
+		return resp, nil
+	})
+}
+
+// run выполняет fn с таймаутом. Если обработчик не реагирует на отмену
+// контекста, ответ с ошибкой возвращается по истечении таймаута,
+// а fn завершается в фоне.
+func (s *Server) run(ctx context.Context, timeout time.Duration, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{err: fmt.Errorf("domain handler panic: %v", p)}
+			}
+		}()
+		result, err := fn(ctx)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil && errors.Is(o.err, context.DeadlineExceeded) {
+			return nil, timeoutError(s.config.Domain.ID, timeout)
+		}
+		return o.result, o.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, timeoutError(s.config.Domain.ID, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func timeoutError(domainID string, timeout time.Duration) *types.ErrorDetail {
+	return &types.ErrorDetail{
+		Code:    "TIMEOUT",
+		Type:    "EXTERNAL_ERROR",
+		Message: fmt.Sprintf("Domain %s did not respond within %s", domainID, timeout),
+	}
+}
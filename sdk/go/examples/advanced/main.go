@@ -157,9 +157,9 @@ func demonstrateAdvancedSearch(ctx context.Context, client *nexus.Client) {
 func demonstrateBatchOperations(ctx context.Context, client *nexus.Client) {
 	fmt.Println("\n📦 3. Batch операции для высокой производительности")
 
-	// Создаем batch с несколькими операциями
+	// Создаем batch с несколькими запросами
 	batch := nexus.NewBatchBuilder().
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{
+		AddRequest(&types.ExecuteTemplateRequest{
 			Query: "купить iPhone 15",
 			Language: "ru",
 			Context: &types.UserContext{
@@ -167,7 +167,7 @@ func demonstrateBatchOperations(ctx context.Context, client *nexus.Client) {
 				TenantID: "enterprise-company-abc",
 			},
 		}).
-		AddOperation("execute_template", &types.ExecuteTemplateRequest{
+		AddRequest(&types.ExecuteTemplateRequest{
 			Query: "забронировать отель в Париже",
 			Language: "ru",
 			Context: &types.UserContext{
@@ -175,18 +175,8 @@ func demonstrateBatchOperations(ctx context.Context, client *nexus.Client) {
 				TenantID: "enterprise-company-abc",
 			},
 		}).
-		AddOperation("log_event", &types.LogEventRequest{
-			EventType: "batch_operation_demo",
-			UserID:    "batch-user-1",
-			TenantID:  "enterprise-company-abc",
-			Data: map[string]interface{}{
-				"operation": "batch_demo",
-				"timestamp": time.Now().Unix(),
-			},
-		}).
-		SetOptions(&types.BatchOptions{
-			Parallel:    true,
-			StopOnError: false,
+		SetBatchOptions(&types.ExecuteOptions{
+			ParallelExecution: true,
 		})
 
 	// Выполняем batch
@@ -197,19 +187,38 @@ func demonstrateBatchOperations(ctx context.Context, client *nexus.Client) {
 	}
 
 	fmt.Printf("✅ Batch выполнен:\n")
-	fmt.Printf("   - Всего операций: %d\n", batchResult.Total)
-	fmt.Printf("   - Успешных: %d\n", batchResult.Successful)
-	fmt.Printf("   - Неудачных: %d\n", batchResult.Failed)
-	fmt.Printf("   - Общее время: %d ms\n", batchResult.TotalTimeMS)
+	if meta := batchResult.BatchMetadata; meta != nil {
+		fmt.Printf("   - Всего запросов: %d\n", meta.TotalRequests)
+		fmt.Printf("   - Успешных: %d\n", meta.SuccessfulRequests)
+		fmt.Printf("   - Неудачных: %d\n", meta.FailedRequests)
+		fmt.Printf("   - Общее время: %d ms\n", meta.TotalProcessingTimeMS)
+	}
 
-	// Показываем результаты по операциям
-	for i, res := range batchResult.Results {
+	// Показываем результаты по запросам
+	for i, res := range batchResult.Responses {
 		status := "✅"
-		if !res.Success {
+		if res == nil || res.Status != "completed" {
 			status = "❌"
 		}
-		fmt.Printf("   %d. %s Операция #%d - %d ms\n",
-			i+1, status, res.OperationID, res.ExecutionTimeMS)
+		if res == nil {
+			fmt.Printf("   %d. %s нет ответа\n", i+1, status)
+			continue
+		}
+		fmt.Printf("   %d. %s Выполнение %s - %d ms\n",
+			i+1, status, res.ExecutionID, res.ProcessingTimeMS)
+	}
+
+	// События аналитики отправляются отдельно от batch
+	if _, err := client.LogEvent(ctx, &types.LogEventRequest{
+		EventType: "batch_operation_demo",
+		UserID:    "batch-user-1",
+		TenantID:  "enterprise-company-abc",
+		Data: map[string]interface{}{
+			"operation": "batch_demo",
+			"timestamp": time.Now().Unix(),
+		},
+	}); err != nil {
+		log.Printf("Ошибка логирования события: %v", err)
 	}
 }
 
@@ -0,0 +1,119 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Unmarshal разбирает YAML-документ и записывает результат в v.
+// Значение переносится через encoding/json, поэтому v заполняется
+// по json-тегам. Документ должен быть единственным.
+func Unmarshal(data []byte, v interface{}) error {
+	docs, err := ParseDocuments(data)
+	if err != nil {
+		return err
+	}
+	if len(docs) > 1 {
+		return fmt.Errorf("yaml: expected a single document, got %d", len(docs))
+	}
+	var doc interface{}
+	if len(docs) == 1 {
+		doc = docs[0]
+	}
+	return Convert(doc, v)
+}
+
+// Convert переносит разобранное значение в v через encoding/json
+func Convert(doc interface{}, v interface{}) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// ParseDocuments разбирает поток документов, разделенных "---".
+// Отображения возвращаются как map[string]interface{}, последовательности -
+// как []interface{}, числа - как json.Number. Пустые документы пропускаются.
+func ParseDocuments(data []byte) ([]interface{}, error) {
+	var docs []interface{}
+	dec := yamlv3.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		normalized, err := normalize(doc)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, normalized)
+	}
+}
+
+// normalize приводит значения yaml.v3 к типам encoding/json с UseNumber
+func normalize(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			normalized, err := normalize(value)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			name := fmt.Sprint(key)
+			if _, ok := m[name]; ok {
+				return nil, fmt.Errorf("yaml: duplicate key %q", name)
+			}
+			normalized, err := normalize(value)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = normalized
+		}
+		return m, nil
+	case []interface{}:
+		for i, value := range v {
+			normalized, err := normalize(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+		return v, nil
+	case int:
+		return json.Number(strconv.Itoa(v)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(v, 10)), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return v, nil
+		}
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return v, nil
+}
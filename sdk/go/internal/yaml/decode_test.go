@@ -0,0 +1,125 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	v := sample{
+		Name:     "commerce",
+		Count:    3,
+		Enabled:  true,
+		Tags:     []string{"shop", "true", "42"},
+		Labels:   map[string]string{"owner": "team: core"},
+		Template: "Line one\n\n  indented\nLine two\n",
+		Items:    []item{{ID: "a", Score: 0.5}, {ID: "-b", Score: 1}},
+		Empty:    []string{},
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got sample
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", got, v)
+	}
+}
+
+func TestParseDocuments(t *testing.T) {
+	input := `# комментарий
+kind: Domain
+spec:
+  id: commerce   # идентификатор
+  keywords: [buy, "sell, trade", 'it''s']
+  limits: {timeout: 30, retry: 3}
+  endpoint: http://commerce:8080/api
+  steps:
+  - name: first
+    weight: 1.5
+  -
+    name: second
+  template: >-
+    Folded
+    text
+
+    next
+---
+kind: Prompt
+spec:
+  description: "Quoted \"value\"\n"
+  active: false
+  variables:
+    - ~
+`
+
+	docs, err := ParseDocuments([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseDocuments failed: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents, got %d", len(docs))
+	}
+
+	got, _ := json.Marshal(docs)
+	want := `[{"kind":"Domain","spec":{"endpoint":"http://commerce:8080/api","id":"commerce",` +
+		`"keywords":["buy","sell, trade","it's"],"limits":{"retry":3,"timeout":30},` +
+		`"steps":[{"name":"first","weight":1.5},{"name":"second"}],"template":"Folded text\nnext"}},` +
+		`{"kind":"Prompt","spec":{"active":false,"description":"Quoted \"value\"\n","variables":[null]}}]`
+	if string(got) != want {
+		t.Errorf("Unexpected documents:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestParseAnchors(t *testing.T) {
+	input := `defaults: &defaults
+  timeout: 30
+  retry: 3
+commerce:
+  <<: *defaults
+  timeout: 10
+tags: &tags [shop, food]
+travel:
+  tags: *tags
+`
+	docs, err := ParseDocuments([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseDocuments failed: %v", err)
+	}
+	got, _ := json.Marshal(docs[0].(map[string]interface{})["commerce"])
+	if string(got) != `{"retry":3,"timeout":10}` {
+		t.Errorf("Unexpected merged mapping %s", got)
+	}
+	got, _ = json.Marshal(docs[0].(map[string]interface{})["travel"])
+	if string(got) != `{"tags":["shop","food"]}` {
+		t.Errorf("Unexpected alias %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"a: 1\na: 2\n":          "already defined",
+		"a:\n  b: 1\n   c: 2\n": "mapping values are not allowed",
+		"a: *ref\n":             "unknown anchor",
+		"a: [1, 2\n":            "did not find expected",
+		"a: \"open\n":           "unexpected end of stream",
+		"a:\n\t- b\n":           "cannot start any token",
+	}
+	for input, want := range tests {
+		_, err := ParseDocuments([]byte(input))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseDocuments(%q) error = %v, want %q", input, err, want)
+		}
+	}
+
+	var v sample
+	if err := Unmarshal([]byte("name: a\n---\nname: b\n"), &v); err == nil {
+		t.Error("Expected error for multiple documents")
+	}
+}
@@ -0,0 +1,59 @@
+// Package yaml связывает gopkg.in/yaml.v3 с encoding/json: значения
+// сериализуются и заполняются по json-тегам, как в остальном SDK, а
+// разобранные документы представлены теми же типами, что и после
+// encoding/json с UseNumber.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Marshal сериализует значение в YAML. Значение сначала переводится в JSON,
+// поэтому учитываются json-теги и порядок полей структур.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON является корректным YAML: разбор сохраняет порядок ключей
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	toBlockStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yaml11Bools строки, которые парсеры YAML 1.1 читают как bool
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true, "off": true, "Off": true, "OFF": true,
+}
+
+// toBlockStyle сбрасывает стиль JSON (flow-коллекции и строки в кавычках),
+// чтобы кодировщик выбрал блочный стиль и кавычки только там, где они нужны.
+// Строки, которые YAML 1.1 считает bool, остаются в кавычках.
+func toBlockStyle(n *yamlv3.Node) {
+	n.Style = 0
+	if n.Kind == yamlv3.ScalarNode && n.Tag == "!!str" && yaml11Bools[n.Value] {
+		n.Style = yamlv3.DoubleQuotedStyle
+	}
+	for _, child := range n.Content {
+		toBlockStyle(child)
+	}
+}
@@ -0,0 +1,93 @@
+package yaml
+
+import (
+	"testing"
+)
+
+type sample struct {
+	Name     string            `json:"name"`
+	Count    int               `json:"count"`
+	Enabled  bool              `json:"enabled"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Template string            `json:"template"`
+	Items    []item            `json:"items"`
+	Empty    []string          `json:"empty"`
+	Missing  *item             `json:"missing"`
+}
+
+type item struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+func TestMarshal(t *testing.T) {
+	v := sample{
+		Name:     "commerce",
+		Count:    3,
+		Enabled:  true,
+		Tags:     []string{"shop", "true", "42"},
+		Labels:   map[string]string{"owner": "team: core"},
+		Template: "Line one\nLine two\n",
+		Items:    []item{{ID: "a", Score: 0.5}, {ID: "-b", Score: 1}},
+		Empty:    []string{},
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `name: commerce
+count: 3
+enabled: true
+tags:
+  - shop
+  - "true"
+  - "42"
+labels:
+  owner: 'team: core'
+template: |
+  Line one
+  Line two
+items:
+  - id: a
+    score: 0.5
+  - id: -b
+    score: 1
+empty: []
+missing: null
+`
+	if string(data) != expected {
+		t.Errorf("Unexpected YAML:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestMarshalScalars(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{"plain text", "plain text\n"},
+		{"", "\"\"\n"},
+		{"yes", "\"yes\"\n"},
+		{"off", "\"off\"\n"},
+		{"null", "\"null\"\n"},
+		{" padded", "' padded'\n"},
+		{"a # comment", "'a # comment'\n"},
+		{"no\nfinal newline", "|-\n  no\n  final newline\n"},
+		{" indented\nblock", "|2-\n   indented\n  block\n"},
+		{12.5, "12.5\n"},
+		{map[string]int{}, "{}\n"},
+	}
+
+	for _, tt := range tests {
+		data, err := Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("Marshal(%q) failed: %v", tt.in, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("Marshal(%q) = %q, want %q", tt.in, data, tt.want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Package jwt реализует разбор, подпись и проверку JSON Web Token (RFC 7519)
+// в объеме, нужном SDK и эталонному серверу: алгоритмы HS256/384/512, RS256/384/512 и ES256/384/512
+// и проверка стандартных claims exp, nbf, iss и aud. Токены без exp
+// не принимаются, а ключ ES* должен лежать на кривой своего алгоритма.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed токен не является корректным JWT
+	ErrMalformed = errors.New("jwt: malformed token")
+	// ErrSignature подпись токена не совпадает
+	ErrSignature = errors.New("jwt: invalid signature")
+	// ErrExpired срок действия токена истек
+	ErrExpired = errors.New("jwt: token expired")
+	// ErrMissingExpiration в токене нет claim exp
+	ErrMissingExpiration = errors.New("jwt: token has no expiration")
+	// ErrNotYetValid токен еще не действует (nbf)
+	ErrNotYetValid = errors.New("jwt: token not valid yet")
+)
+
+// Claims полезная нагрузка токена
+type Claims map[string]interface{}
+
+// String возвращает строковый claim
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings возвращает claim-список строк; строка считается списком из одного элемента
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// Time возвращает claim-время в секундах Unix (exp, nbf, iat)
+func (c Claims) Time(name string) (time.Time, bool) {
+	switch v := c[name].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case json.Number:
+		n, err := v.Int64()
+		return time.Unix(n, 0), err == nil
+	case int64:
+		return time.Unix(v, 0), true
+	case int:
+		return time.Unix(int64(v), 0), true
+	}
+	return time.Time{}, false
+}
+
+// Token разобранный токен
+type Token struct {
+	Header    map[string]interface{}
+	Claims    Claims
+	Algorithm string
+
+	signingInput string
+	signature    []byte
+}
+
+// Parse разбирает токен без проверки подписи
+func Parse(token string) (*Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+	t := &Token{signingInput: parts[0] + "." + parts[1]}
+	if err := decodeSegment(parts[0], &t.Header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformed, err)
+	}
+	if err := decodeSegment(parts[1], &t.Claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrMalformed, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrMalformed, err)
+	}
+	t.signature = sig
+	t.Algorithm, _ = t.Header["alg"].(string)
+	return t, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Verify проверяет подпись токена. key - []byte для HS*, *rsa.PublicKey
+// для RS* и *ecdsa.PublicKey для ES*. Алгоритм "none" не принимается.
+func (t *Token) Verify(key interface{}) error {
+	hash, err := algorithmHash(t.Algorithm)
+	if err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write([]byte(t.signingInput))
+	digest := h.Sum(nil)
+
+	switch t.Algorithm[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("jwt: %s requires a []byte key", t.Algorithm)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(t.signingInput))
+		if !hmac.Equal(mac.Sum(nil), t.signature) {
+			return ErrSignature
+		}
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("jwt: %s requires an RSA public key", t.Algorithm)
+		}
+		if rsa.VerifyPKCS1v15(pub, hash, digest, t.signature) != nil {
+			return ErrSignature
+		}
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("jwt: %s requires an ECDSA public key", t.Algorithm)
+		}
+		if err := checkCurve(t.Algorithm, pub.Curve); err != nil {
+			return err
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != 2*size {
+			return ErrSignature
+		}
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrSignature
+		}
+	}
+	return nil
+}
+
+// Expectations ожидаемые значения стандартных claims. Пустые поля не проверяются.
+type Expectations struct {
+	Issuer   string
+	Audience string
+	Now      time.Time     // по умолчанию time.Now()
+	Leeway   time.Duration // допустимое расхождение часов
+}
+
+// Validate проверяет exp, nbf, iss и aud. Claim exp обязателен.
+func (c Claims) Validate(exp Expectations) error {
+	now := exp.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	t, ok := c.Time("exp")
+	if !ok {
+		return ErrMissingExpiration
+	}
+	if !now.Before(t.Add(exp.Leeway)) {
+		return ErrExpired
+	}
+	if t, ok := c.Time("nbf"); ok && now.Add(exp.Leeway).Before(t) {
+		return ErrNotYetValid
+	}
+	if exp.Issuer != "" && c.String("iss") != exp.Issuer {
+		return fmt.Errorf("jwt: unexpected issuer %q", c.String("iss"))
+	}
+	if exp.Audience != "" {
+		found := false
+		for _, aud := range c.Strings("aud") {
+			if aud == exp.Audience {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("jwt: token is not intended for audience %q", exp.Audience)
+		}
+	}
+	return nil
+}
+
+// Sign создает подписанный токен. key - []byte для HS*, *rsa.PrivateKey
+// для RS* и *ecdsa.PrivateKey для ES*.
+func Sign(claims Claims, algorithm string, key interface{}) (string, error) {
+	hash, err := algorithmHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	h := hash.New()
+	h.Write([]byte(input))
+	digest := h.Sum(nil)
+
+	var sig []byte
+	switch algorithm[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return "", fmt.Errorf("jwt: %s requires a []byte key", algorithm)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case "RS":
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("jwt: %s requires an RSA private key", algorithm)
+		}
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, priv, hash, digest); err != nil {
+			return "", err
+		}
+	case "ES":
+		priv, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("jwt: %s requires an ECDSA private key", algorithm)
+		}
+		if err := checkCurve(algorithm, priv.Curve); err != nil {
+			return "", err
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest)
+		if err != nil {
+			return "", err
+		}
+		size := (priv.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// algorithmHash возвращает хеш-функцию алгоритма подписи
+func algorithmHash(algorithm string) (crypto.Hash, error) {
+	if len(algorithm) == 5 {
+		switch algorithm[:2] {
+		case "HS", "RS", "ES":
+			switch algorithm[2:] {
+			case "256":
+				return crypto.SHA256, nil
+			case "384":
+				return crypto.SHA384, nil
+			case "512":
+				return crypto.SHA512, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("jwt: unsupported algorithm %q", algorithm)
+}
+
+// checkCurve проверяет, что кривая ключа соответствует алгоритму ES*
+// (ES256 - P-256, ES384 - P-384, ES512 - P-521)
+func checkCurve(algorithm string, curve elliptic.Curve) error {
+	var expected elliptic.Curve
+	switch algorithm {
+	case "ES256":
+		expected = elliptic.P256()
+	case "ES384":
+		expected = elliptic.P384()
+	case "ES512":
+		expected = elliptic.P521()
+	}
+	if expected == nil {
+		return fmt.Errorf("jwt: unsupported algorithm %q", algorithm)
+	}
+	if curve == nil || curve.Params().Name != expected.Params().Name {
+		return fmt.Errorf("jwt: %s requires a %s key", algorithm, expected.Params().Name)
+	}
+	return nil
+}
+
+// ParsePublicKey разбирает PEM с открытым ключом RSA или ECDSA
+// (PKIX "PUBLIC KEY", PKCS#1 "RSA PUBLIC KEY" или сертификат X.509)
+func ParsePublicKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: no PEM data found")
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("jwt: unsupported public key type %T", key)
+}
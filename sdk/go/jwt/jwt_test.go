@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	ecPub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePublicKey failed: %v", err)
+	}
+
+	claims := Claims{"sub": "user-1", "aud": []string{"commerce", "travel"}, "exp": time.Now().Add(time.Minute).Unix()}
+	for _, tt := range []struct {
+		alg         string
+		sign, check interface{}
+	}{
+		{"HS256", []byte("secret"), []byte("secret")},
+		{"RS256", rsaKey, &rsaKey.PublicKey},
+		{"ES256", ecKey, ecPub},
+	} {
+		token, err := Sign(claims, tt.alg, tt.sign)
+		if err != nil {
+			t.Fatalf("%s: Sign failed: %v", tt.alg, err)
+		}
+		parsed, err := Parse(token)
+		if err != nil || parsed.Algorithm != tt.alg || parsed.Claims.String("sub") != "user-1" {
+			t.Fatalf("%s: Parse = %+v, %v", tt.alg, parsed, err)
+		}
+		if err := parsed.Verify(tt.check); err != nil {
+			t.Errorf("%s: Verify failed: %v", tt.alg, err)
+		}
+		tampered, _ := Parse(token[:len(token)-4] + "AAAA")
+		if err := tampered.Verify(tt.check); !errors.Is(err, ErrSignature) {
+			t.Errorf("%s: expected signature error, got %v", tt.alg, err)
+		}
+	}
+
+	token, _ := Sign(claims, "HS256", []byte("secret"))
+	parsed, _ := Parse(token)
+	if err := parsed.Verify(&rsaKey.PublicKey); err == nil {
+		t.Error("Expected key type mismatch to fail")
+	}
+	if _, err := Parse("a.b"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected malformed error, got %v", err)
+	}
+	none, _ := Parse("eyJhbGciOiJub25lIn0.e30.")
+	if err := none.Verify([]byte("secret")); err == nil {
+		t.Error("Expected alg none to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	claims := Claims{"exp": float64(now.Unix() + 60), "nbf": float64(now.Unix() - 60), "iss": "nexus", "aud": "commerce"}
+
+	if err := claims.Validate(Expectations{Issuer: "nexus", Audience: "commerce", Now: now}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := claims.Validate(Expectations{Now: now.Add(2 * time.Minute)}); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected expired error, got %v", err)
+	}
+	if err := claims.Validate(Expectations{Now: now.Add(2 * time.Minute), Leeway: 2 * time.Minute}); err != nil {
+		t.Errorf("Expected leeway to apply, got %v", err)
+	}
+	if err := claims.Validate(Expectations{Now: now.Add(-2 * time.Minute)}); !errors.Is(err, ErrNotYetValid) {
+		t.Errorf("Expected nbf error, got %v", err)
+	}
+	if err := claims.Validate(Expectations{Audience: "travel", Now: now}); err == nil {
+		t.Error("Expected audience error")
+	}
+	if err := (Claims{"sub": "user-1"}).Validate(Expectations{Now: now}); !errors.Is(err, ErrMissingExpiration) {
+		t.Errorf("Expected missing expiration error, got %v", err)
+	}
+}
+
+func TestCurveMustMatchAlgorithm(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	claims := Claims{"exp": time.Now().Add(time.Minute).Unix()}
+
+	if _, err := Sign(claims, "ES384", p256); err == nil {
+		t.Error("Expected Sign to reject a P-256 key for ES384")
+	}
+	token, err := Sign(claims, "ES384", p384)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	parsed, _ := Parse(token)
+	if err := parsed.Verify(&p384.PublicKey); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// Заголовок ES256 с ключом P-384 отклоняется до проверки подписи
+	parsed.Algorithm = "ES256"
+	if err := parsed.Verify(&p384.PublicKey); err == nil || errors.Is(err, ErrSignature) {
+		t.Errorf("Expected curve mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+// Package middleware реализует серверную сторону Application Protocol
+// для net/http: разбор и проверку RequestMetadata, проверку совместимости
+// версии протокола, конверт ResponseMessage и ответы ErrorResponse.
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/api/v1/templates/execute", middleware.HandlerFunc(func(r *http.Request) (interface{}, error) {
+//		var req types.ExecuteTemplateRequest
+//		if err := middleware.Decode(r, &req); err != nil {
+//			return nil, err
+//		}
+//		md := middleware.RequestMetadata(r.Context())
+//		return execute(r.Context(), md, &req)
+//	}))
+//	http.ListenAndServe(":8080", middleware.Protocol(middleware.Config{ServerVersion: "1.2.0"})(mux))
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/protocol"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// HTTP заголовки и параметры протокола
+const (
+	HeaderProtocolVersion = "X-Protocol-Version"
+	HeaderRequestID       = "X-Request-ID"
+	QueryProtocolVersion  = "protocol_version"
+)
+
+// DefaultMaxBodyBytes максимальный размер тела запроса по умолчанию
+const DefaultMaxBodyBytes = 10 << 20
+
+// Config параметры middleware
+type Config struct {
+	// ProtocolVersion версия протокола сервера (по умолчанию client.DefaultProtocolVersion)
+	ProtocolVersion string
+
+	// ServerVersion версия сервера в ResponseMetadata (по умолчанию ProtocolVersion)
+	ServerVersion string
+
+	// MaxBodyBytes максимальный размер тела запроса (по умолчанию DefaultMaxBodyBytes)
+	MaxBodyBytes int64
+
+	// RequireMetadata требует metadata в JSON-теле запроса. Без него
+	// запросы без metadata получают метаданные с request_id из заголовка
+	// X-Request-ID (или новым UUID) и версией из X-Protocol-Version или
+	// параметра protocol_version.
+	RequireMetadata bool
+}
+
+func (c Config) withDefaults() Config {
+	if c.ProtocolVersion == "" {
+		c.ProtocolVersion = client.DefaultProtocolVersion
+	}
+	if c.ServerVersion == "" {
+		c.ServerVersion = c.ProtocolVersion
+	}
+	if c.MaxBodyBytes <= 0 {
+		c.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	return c
+}
+
+// state состояние запроса, сохраняемое в контексте
+type state struct {
+	config   Config
+	metadata *types.RequestMetadata
+	start    time.Time
+}
+
+type stateKey struct{}
+
+// RequestMetadata возвращает метаданные запроса, разобранные Protocol
+func RequestMetadata(ctx context.Context) *types.RequestMetadata {
+	if st, ok := ctx.Value(stateKey{}).(*state); ok {
+		return st.metadata
+	}
+	return nil
+}
+
+// Protocol возвращает middleware, которое:
+//   - принимает тело в конверте {"metadata": ..., "data": ...} (тело
+//     заменяется на data) или с полем metadata рядом с полями запроса;
+//   - проверяет metadata через types.ValidateRequestMetadata;
+//   - отклоняет несовместимую protocol_version (types.IsCompatible)
+//     ошибкой PROTOCOL_VERSION_ERROR;
+//   - сохраняет метаданные в контексте (см. RequestMetadata) и
+//     возвращает request_id в заголовке X-Request-ID.
+//
+// Конвертом считается JSON-объект ровно с двумя полями metadata и data.
+func Protocol(config Config) func(http.Handler) http.Handler {
+	config = config.withDefaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			st := &state{config: config, start: time.Now()}
+			r = r.WithContext(context.WithValue(r.Context(), stateKey{}, st))
+
+			metadata, detail := readMetadata(w, r, config)
+			if detail != nil {
+				WriteError(w, r, detail)
+				return
+			}
+			version := r.URL.Query().Get(QueryProtocolVersion)
+			if version == "" {
+				version = r.Header.Get(HeaderProtocolVersion)
+			}
+			if metadata == nil {
+				metadata = &types.RequestMetadata{
+					RequestID:       r.Header.Get(HeaderRequestID),
+					ProtocolVersion: version,
+					Timestamp:       st.start.Unix(),
+				}
+				if metadata.RequestID == "" {
+					metadata.RequestID = uuid.New().String()
+				}
+			}
+			st.metadata = metadata
+			w.Header().Set(HeaderRequestID, metadata.RequestID)
+
+			if metadata.ProtocolVersion != "" {
+				if ok, err := types.IsCompatible(metadata.ProtocolVersion, config.ProtocolVersion); !ok {
+					WriteError(w, r, &types.ErrorDetail{
+						Code:    "PROTOCOL_VERSION_MISMATCH",
+						Type:    "PROTOCOL_VERSION_ERROR",
+						Message: "Protocol version not supported",
+						Field:   "metadata.protocol_version",
+						Details: err.Error(),
+						Metadata: map[string]string{
+							"client_version": metadata.ProtocolVersion,
+							"server_version": config.ProtocolVersion,
+						},
+					})
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// readMetadata читает тело запроса и извлекает metadata. Для конверта
+// тело запроса заменяется на data.
+func readMetadata(w http.ResponseWriter, r *http.Request, config Config) (*types.RequestMetadata, *types.ErrorDetail) {
+	if r.Body == nil || r.Body == http.NoBody || !isJSON(r) {
+		if config.RequireMetadata && r.ContentLength != 0 && r.Body != nil && r.Body != http.NoBody {
+			return nil, &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Request body must be JSON"}
+		}
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.MaxBodyBytes))
+	r.Body.Close()
+	if err != nil {
+		return nil, &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Failed to read request body", Details: err.Error()}
+	}
+	setBody(r, body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		if config.RequireMetadata {
+			return nil, &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Request metadata is required", Field: "metadata"}
+		}
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		if config.RequireMetadata {
+			return nil, &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Request body must be a JSON object", Details: err.Error()}
+		}
+		return nil, nil // не объект (например, массив) - передается обработчику как есть
+	}
+	raw, ok := fields["metadata"]
+	if !ok || string(raw) == "null" {
+		if config.RequireMetadata {
+			return nil, &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Request metadata is required", Field: "metadata"}
+		}
+		return nil, nil
+	}
+	var metadata types.RequestMetadata
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Invalid request metadata", Field: "metadata", Details: err.Error()}
+	}
+	if err := types.ValidateRequestMetadata(&metadata); err != nil {
+		return nil, &types.ErrorDetail{Code: "VALIDATION_FAILED", Type: "VALIDATION_ERROR", Message: "Invalid request metadata", Field: "metadata", Details: err.Error()}
+	}
+	if data, ok := fields["data"]; ok && len(fields) == 2 {
+		setBody(r, data)
+	}
+	return &metadata, nil
+}
+
+func setBody(r *http.Request, body []byte) {
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+}
+
+// isJSON сообщает, что тело запроса - JSON (Content-Type не указан или application/json)
+func isJSON(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	return err == nil && mediaType == "application/json"
+}
+
+// Decode разбирает тело запроса (data конверта) в v. Ошибка разбора
+// возвращается как *types.ErrorDetail с типом VALIDATION_ERROR.
+func Decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Request data is required", Field: "data"}
+		}
+		return &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Invalid request data", Field: "data", Details: err.Error()}
+	}
+	return nil
+}
+
+// HandlerFunc обработчик, результат которого оборачивается в ResponseMessage,
+// а ошибка отправляется как ErrorResponse (см. WriteError)
+type HandlerFunc func(r *http.Request) (interface{}, error)
+
+// ServeHTTP реализует http.Handler
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := f(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	WriteResponse(w, r, http.StatusOK, data)
+}
+
+// WriteResponse отправляет data в конверте ResponseMessage. Метаданные
+// ответа содержат request_id запроса и время обработки с момента входа
+// в Protocol.
+func WriteResponse(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	writeJSON(w, status, protocol.NewResponseMessage(ResponseMetadata(r.Context()), data))
+}
+
+// ResponseMetadata формирует метаданные ответа для запроса
+func ResponseMetadata(ctx context.Context) *types.ResponseMetadata {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	if !ok {
+		config := Config{}.withDefaults()
+		return &types.ResponseMetadata{
+			RequestID:       uuid.New().String(),
+			ProtocolVersion: config.ProtocolVersion,
+			ServerVersion:   config.ServerVersion,
+			Timestamp:       time.Now().Unix(),
+		}
+	}
+	metadata := &types.ResponseMetadata{
+		ProtocolVersion:  st.config.ProtocolVersion,
+		ServerVersion:    st.config.ServerVersion,
+		Timestamp:        time.Now().Unix(),
+		ProcessingTimeMS: int32(time.Since(st.start).Milliseconds()),
+	}
+	if st.metadata != nil {
+		metadata.RequestID = st.metadata.RequestID
+	}
+	return metadata
+}
+
+// WriteError отправляет ошибку как ErrorResponse с HTTP статусом по ее типу
+// (см. StatusCode). *types.ErrorDetail передается как есть, превышение
+// срока контекста - как TIMEOUT, остальные ошибки - как INTERNAL_ERROR
+// без подробностей. В metadata ошибки добавляется request_id.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	detail := ToErrorDetail(err)
+	if md := RequestMetadata(r.Context()); md != nil && md.RequestID != "" {
+		if detail.Metadata == nil {
+			detail.Metadata = make(map[string]string)
+		}
+		detail.Metadata["request_id"] = md.RequestID
+	}
+	writeJSON(w, StatusCode(detail), &types.ErrorResponse{Error: *detail})
+}
+
+// ToErrorDetail приводит ошибку к копии *types.ErrorDetail
+func ToErrorDetail(err error) *types.ErrorDetail {
+	var detail *types.ErrorDetail
+	switch {
+	case errors.As(err, &detail):
+		copied := *detail
+		if detail.Metadata != nil {
+			copied.Metadata = make(map[string]string, len(detail.Metadata))
+			for k, v := range detail.Metadata {
+				copied.Metadata[k] = v
+			}
+		}
+		return &copied
+	case errors.Is(err, context.DeadlineExceeded):
+		return &types.ErrorDetail{Code: "TIMEOUT", Type: "EXTERNAL_ERROR", Message: "Request timed out"}
+	}
+	return &types.ErrorDetail{Code: "INTERNAL_ERROR", Type: "INTERNAL_ERROR", Message: "Internal server error"}
+}
+
+// StatusCode возвращает HTTP статус для ошибки по ее типу, как описано
+// в protocol/ERROR_HANDLING.md. Коды TIMEOUT, SERVICE_UNAVAILABLE и
+// METHOD_NOT_ALLOWED имеют собственные статусы.
+func StatusCode(detail *types.ErrorDetail) int {
+	switch detail.Code {
+	case "TIMEOUT":
+		return http.StatusGatewayTimeout
+	case "SERVICE_UNAVAILABLE":
+		return http.StatusServiceUnavailable
+	case "METHOD_NOT_ALLOWED":
+		return http.StatusMethodNotAllowed
+	}
+	switch detail.Type {
+	case "VALIDATION_ERROR", "PROTOCOL_VERSION_ERROR":
+		return http.StatusBadRequest
+	case "AUTHENTICATION_ERROR":
+		return http.StatusUnauthorized
+	case "AUTHORIZATION_ERROR":
+		return http.StatusForbidden
+	case "NOT_FOUND":
+		return http.StatusNotFound
+	case "CONFLICT":
+		return http.StatusConflict
+	case "RATE_LIMIT_ERROR":
+		return http.StatusTooManyRequests
+	case "EXTERNAL_ERROR":
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/protocol"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+type echoRequest struct {
+	Query    string                 `json:"query"`
+	Metadata *types.RequestMetadata `json:"metadata,omitempty"`
+}
+
+func newHandler(config Config) http.Handler {
+	return Protocol(config)(HandlerFunc(func(r *http.Request) (interface{}, error) {
+		var req echoRequest
+		if r.Method == http.MethodPost {
+			if err := Decode(r, &req); err != nil {
+				return nil, err
+			}
+		}
+		switch req.Query {
+		case "missing":
+			return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "Not found"}
+		case "slow":
+			return nil, context.DeadlineExceeded
+		case "broken":
+			return nil, errors.New("secret connection string")
+		}
+		return map[string]string{"query": req.Query, "request_id": RequestMetadata(r.Context()).RequestID}, nil
+	}))
+}
+
+func serve(h http.Handler, method, target string, body interface{}, header http.Header) (*httptest.ResponseRecorder, protocol.ResponseMessage, types.ErrorResponse) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var msg protocol.ResponseMessage
+	var errResp types.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &msg)
+	json.Unmarshal(rec.Body.Bytes(), &errResp)
+	return rec, msg, errResp
+}
+
+func TestProtocolEnvelopeAndInlineMetadata(t *testing.T) {
+	h := newHandler(Config{ServerVersion: "1.5.0"})
+	metadata := types.NewRequestMetadata("2.0.0", "1.0.0")
+
+	for name, body := range map[string]interface{}{
+		"envelope": protocol.NewRequestMessage(metadata, map[string]string{"query": "борщ"}),
+		"inline":   echoRequest{Query: "борщ", Metadata: metadata},
+	} {
+		rec, msg, _ := serve(h, http.MethodPost, "/execute", body, nil)
+		data, _ := msg.Data.(map[string]interface{})
+		if rec.Code != http.StatusOK || data["query"] != "борщ" || data["request_id"] != metadata.RequestID {
+			t.Errorf("%s: unexpected response %d: %s", name, rec.Code, rec.Body)
+			continue
+		}
+		if err := types.ValidateResponseMetadata(msg.Metadata); err != nil || msg.Metadata.RequestID != metadata.RequestID || msg.Metadata.ServerVersion != "1.5.0" {
+			t.Errorf("%s: unexpected metadata %+v: %v", name, msg.Metadata, err)
+		}
+		if rec.Header().Get(HeaderRequestID) != metadata.RequestID {
+			t.Errorf("%s: missing %s header", name, HeaderRequestID)
+		}
+	}
+
+	// Запрос без тела получает request_id из заголовка
+	rec, msg, _ := serve(h, http.MethodGet, "/status", nil, http.Header{"X-Request-Id": {"req-1"}})
+	if rec.Code != http.StatusOK || msg.Metadata.RequestID != "req-1" {
+		t.Errorf("Unexpected GET response %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	h := newHandler(Config{})
+	strict := newHandler(Config{RequireMetadata: true})
+	metadata := types.NewRequestMetadata("2.0.0", "1.0.0")
+	envelope := func(query string) interface{} {
+		return protocol.NewRequestMessage(metadata, map[string]string{"query": query})
+	}
+
+	for _, tt := range []struct {
+		name    string
+		handler http.Handler
+		method  string
+		target  string
+		body    interface{}
+		header  http.Header
+		status  int
+		code    string
+	}{
+		{"newer minor version", h, http.MethodPost, "/", protocol.NewRequestMessage(types.NewRequestMetadata("2.1.0", "1.0.0"), nil), nil, http.StatusBadRequest, "PROTOCOL_VERSION_MISMATCH"},
+		{"version in query", h, http.MethodGet, "/?protocol_version=1.0.0", nil, nil, http.StatusBadRequest, "PROTOCOL_VERSION_MISMATCH"},
+		{"version in header", h, http.MethodGet, "/", nil, http.Header{"X-Protocol-Version": {"3.0.0"}}, http.StatusBadRequest, "PROTOCOL_VERSION_MISMATCH"},
+		{"invalid metadata", h, http.MethodPost, "/", echoRequest{Query: "q", Metadata: &types.RequestMetadata{RequestID: "1"}}, nil, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"metadata required", strict, http.MethodPost, "/", echoRequest{Query: "q"}, nil, http.StatusBadRequest, "MISSING_REQUIRED_FIELD"},
+		{"handler error", h, http.MethodPost, "/", envelope("missing"), nil, http.StatusNotFound, "RESOURCE_NOT_FOUND"},
+		{"deadline", h, http.MethodPost, "/", envelope("slow"), nil, http.StatusGatewayTimeout, "TIMEOUT"},
+		{"internal", h, http.MethodPost, "/", envelope("broken"), nil, http.StatusInternalServerError, "INTERNAL_ERROR"},
+	} {
+		rec, _, resp := serve(tt.handler, tt.method, tt.target, tt.body, tt.header)
+		if rec.Code != tt.status || resp.Error.Code != tt.code {
+			t.Errorf("%s: got %d %s", tt.name, rec.Code, rec.Body)
+		}
+		if bytes.Contains(rec.Body.Bytes(), []byte("secret")) {
+			t.Errorf("%s: internal error details leaked", tt.name)
+		}
+		if tt.code == "RESOURCE_NOT_FOUND" && resp.Error.Metadata["request_id"] != metadata.RequestID {
+			t.Errorf("%s: expected request_id in error metadata, got %v", tt.name, resp.Error.Metadata)
+		}
+	}
+}
+
+func TestWriteResponseOutsideMiddleware(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteResponse(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusCreated, map[string]int{"n": 1})
+	var msg protocol.ResponseMessage
+	json.Unmarshal(rec.Body.Bytes(), &msg)
+	if rec.Code != http.StatusCreated || msg.Metadata == nil || msg.Metadata.Timestamp < time.Now().Add(-time.Minute).Unix() {
+		t.Errorf("Unexpected response %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,232 @@
+package prompts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// DefaultDiffContext количество строк контекста вокруг изменений
+const DefaultDiffContext = 3
+
+// LineOp операция над строкой шаблона
+type LineOp byte
+
+const (
+	LineEqual   LineOp = ' '
+	LineDeleted LineOp = '-'
+	LineAdded   LineOp = '+'
+)
+
+// DiffLine строка построчного сравнения шаблонов
+type DiffLine struct {
+	Op   LineOp
+	Text string
+}
+
+// FieldChange изменение поля промпта
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// PromptDiff различия между двумя версиями промпта
+type PromptDiff struct {
+	OldVersion       int           `json:"old_version"`
+	NewVersion       int           `json:"new_version"`
+	Fields           []FieldChange `json:"fields,omitempty"`
+	VariablesAdded   []string      `json:"variables_added,omitempty"`
+	VariablesRemoved []string      `json:"variables_removed,omitempty"`
+	Template         []DiffLine    `json:"-"`
+}
+
+// Diff сравнивает две версии промпта: описательные поля, список
+// переменных и построчно шаблон. ID, Version и Active не сравниваются.
+func Diff(old, new *types.PromptConfig) *PromptDiff {
+	d := &PromptDiff{OldVersion: old.Version, NewVersion: new.Version}
+
+	field := func(name, a, b string) {
+		if a != b {
+			d.Fields = append(d.Fields, FieldChange{Field: name, Old: a, New: b})
+		}
+	}
+	field("name", old.Name, new.Name)
+	field("description", old.Description, new.Description)
+	field("domain", old.Domain, new.Domain)
+	field("type", old.Type, new.Type)
+
+	keys := make(map[string]bool)
+	for k := range old.Metadata {
+		keys[k] = true
+	}
+	for k := range new.Metadata {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		field("metadata."+k, old.Metadata[k], new.Metadata[k])
+	}
+
+	d.VariablesAdded = subtract(new.Variables, old.Variables)
+	d.VariablesRemoved = subtract(old.Variables, new.Variables)
+	d.Template = diffLines(splitLines(old.Template), splitLines(new.Template))
+	return d
+}
+
+// Empty сообщает, что версии не различаются
+func (d *PromptDiff) Empty() bool {
+	if len(d.Fields) > 0 || len(d.VariablesAdded) > 0 || len(d.VariablesRemoved) > 0 {
+		return false
+	}
+	for _, l := range d.Template {
+		if l.Op != LineEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// String форматирует изменения полей и unified diff шаблона
+func (d *PromptDiff) String() string {
+	var b strings.Builder
+	for _, f := range d.Fields {
+		fmt.Fprintf(&b, "%s: %q -> %q\n", f.Field, f.Old, f.New)
+	}
+	if len(d.VariablesAdded) > 0 {
+		fmt.Fprintf(&b, "variables added: %s\n", strings.Join(d.VariablesAdded, ", "))
+	}
+	if len(d.VariablesRemoved) > 0 {
+		fmt.Fprintf(&b, "variables removed: %s\n", strings.Join(d.VariablesRemoved, ", "))
+	}
+	b.WriteString(d.Unified(DefaultDiffContext))
+	return b.String()
+}
+
+// Unified возвращает diff шаблона в формате unified с context строками контекста
+func (d *PromptDiff) Unified(context int) string {
+	var changes []int
+	for i, l := range d.Template {
+		if l.Op != LineEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// Номера строк старой и новой версии перед каждой операцией
+	oldBefore := make([]int, len(d.Template)+1)
+	newBefore := make([]int, len(d.Template)+1)
+	for i, l := range d.Template {
+		oldBefore[i+1], newBefore[i+1] = oldBefore[i], newBefore[i]
+		if l.Op != LineAdded {
+			oldBefore[i+1]++
+		}
+		if l.Op != LineDeleted {
+			newBefore[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- template (version %d)\n+++ template (version %d)\n", d.OldVersion, d.NewVersion)
+	for i := 0; i < len(changes); {
+		start := max(changes[i]-context, 0)
+		last := changes[i]
+		for i++; i < len(changes) && changes[i]-last <= 2*context; i++ {
+			last = changes[i]
+		}
+		end := min(last+context+1, len(d.Template))
+
+		oldCount := oldBefore[end] - oldBefore[start]
+		newCount := newBefore[end] - newBefore[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldBefore[start], oldCount), hunkRange(newBefore[start], newCount))
+		for _, l := range d.Template[start:end] {
+			fmt.Fprintf(&b, "%c%s\n", l.Op, l.Text)
+		}
+	}
+	return b.String()
+}
+
+// hunkRange форматирует диапазон строк заголовка hunk
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines разбивает текст на строки без завершающего перевода строки
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines строит построчное сравнение по наибольшей общей подпоследовательности
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] - длина НОП для a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: LineEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: LineDeleted, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: LineAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: LineDeleted, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: LineAdded, Text: b[j]})
+	}
+	return lines
+}
+
+// subtract возвращает элементы a, отсутствующие в b
+func subtract(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, v := range b {
+		in[v] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !in[v] {
+			out = append(out, v)
+			in[v] = true
+		}
+	}
+	return out
+}
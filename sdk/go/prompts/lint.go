@@ -0,0 +1,118 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Severity важность замечания
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Коды замечаний Lint
+const (
+	IssueSyntax      = "syntax"
+	IssueEmpty       = "empty-template"
+	IssueUndeclared  = "undeclared-variable"
+	IssueUnused      = "unused-variable"
+	IssueDuplicate   = "duplicate-variable"
+	IssueInvalidName = "invalid-variable-name"
+)
+
+// Issue замечание к шаблону промпта
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Variable string   `json:"variable,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+}
+
+// LintError возвращается, если шаблон содержит ошибки
+type LintError struct {
+	Issues []Issue
+}
+
+func (e *LintError) Error() string {
+	var msgs []string
+	for _, issue := range e.Issues {
+		if issue.Severity == SeverityError {
+			msgs = append(msgs, issue.String())
+		}
+	}
+	return "prompt template has errors: " + strings.Join(msgs, "; ")
+}
+
+// Lint проверяет шаблон промпта: синтаксис подстановок, переменные,
+// используемые в шаблоне, но не объявленные в Variables (ошибка), и
+// объявленные, но не используемые (предупреждение).
+func Lint(prompt *types.PromptConfig) []Issue {
+	var issues []Issue
+	if strings.TrimSpace(prompt.Template) == "" {
+		issues = append(issues, Issue{Severity: SeverityError, Code: IssueEmpty, Message: "template is empty"})
+	}
+
+	declared := make(map[string]bool, len(prompt.Variables))
+	for _, name := range prompt.Variables {
+		switch {
+		case checkName(name) != nil:
+			issues = append(issues, Issue{Severity: SeverityError, Code: IssueInvalidName, Variable: name,
+				Message: fmt.Sprintf("variable name %q is not a valid placeholder name", name)})
+		case declared[name]:
+			issues = append(issues, Issue{Severity: SeverityWarning, Code: IssueDuplicate, Variable: name,
+				Message: fmt.Sprintf("variable %q is declared more than once", name)})
+		}
+		declared[name] = true
+	}
+
+	t, err := Parse(prompt.Template)
+	if err != nil {
+		var serr *SyntaxError
+		if errors.As(err, &serr) {
+			issues = append(issues, Issue{Severity: SeverityError, Code: IssueSyntax, Message: serr.Msg, Line: serr.Line, Column: serr.Column})
+		}
+		return issues
+	}
+
+	used := make(map[string]bool)
+	for _, p := range t.placeholders {
+		if !declared[p.Name] && !used[p.Name] {
+			issues = append(issues, Issue{Severity: SeverityError, Code: IssueUndeclared, Variable: p.Name, Line: p.Line, Column: p.Column,
+				Message: fmt.Sprintf("variable %q is used but not declared in variables", p.Name)})
+		}
+		used[p.Name] = true
+	}
+	for _, name := range prompt.Variables {
+		if !used[name] && checkName(name) == nil {
+			issues = append(issues, Issue{Severity: SeverityWarning, Code: IssueUnused, Variable: name,
+				Message: fmt.Sprintf("variable %q is declared but not used", name)})
+			used[name] = true // одно предупреждение на имя
+		}
+	}
+	return issues
+}
+
+// HasErrors сообщает, есть ли среди замечаний ошибки
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
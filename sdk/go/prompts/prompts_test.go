@@ -0,0 +1,117 @@
+package prompts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("Запрос: {{query}}\nГород: {{ location }}, снова {{query}}")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := strings.Join(tmpl.Variables(), ","); got != "query,location" {
+		t.Errorf("Unexpected variables: %s", got)
+	}
+
+	out, err := tmpl.Render(map[string]interface{}{"query": "борщ", "location": "Москва", "extra": 1})
+	if err != nil || out != "Запрос: борщ\nГород: Москва, снова борщ" {
+		t.Errorf("Unexpected render result %q (err %v)", out, err)
+	}
+
+	_, err = tmpl.Render(map[string]interface{}{})
+	var missing *MissingVariablesError
+	if !errors.As(err, &missing) || strings.Join(missing.Names, ",") != "location,query" {
+		t.Errorf("Expected missing variables error, got %v", err)
+	}
+
+	for text, want := range map[string]string{
+		"Привет {{name":     "template:1:8: unclosed placeholder",
+		"a\nb {{}}":         "template:2:3: empty placeholder",
+		"{{first name}} x ": `invalid placeholder name "first name"`,
+	} {
+		if _, err := Parse(text); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want %q", text, err, want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	issues := Lint(&types.PromptConfig{
+		Template:  "Query: {{query}}\nUser: {{user.name}}",
+		Variables: []string{"query", "location", "query"},
+	})
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, string(issue.Severity)+" "+issue.Code+" "+issue.Variable)
+	}
+	want := []string{
+		"warning duplicate-variable query",
+		"error undeclared-variable user.name",
+		"warning unused-variable location",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected issues:\n%s", strings.Join(got, "\n"))
+	}
+	if !HasErrors(issues) || issues[1].Line != 2 || issues[1].Column != 7 {
+		t.Errorf("Unexpected undeclared issue: %+v", issues[1])
+	}
+
+	if issues := Lint(&types.PromptConfig{Template: "Hi {{name}}", Variables: []string{"name"}}); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+	if issues := Lint(&types.PromptConfig{Template: "Hi {{name"}); !HasErrors(issues) || issues[0].Code != IssueSyntax {
+		t.Errorf("Expected syntax error, got %+v", issues)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &types.PromptConfig{
+		Version:   1,
+		Name:      "search",
+		Template:  "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nQuery: {{query}}\n",
+		Variables: []string{"query"},
+		Metadata:  map[string]string{"author": "alice"},
+	}
+	new := &types.PromptConfig{
+		Version:   2,
+		Name:      "search",
+		Template:  "line 1 changed\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nQuery: {{query}}\nCity: {{city}}\n",
+		Variables: []string{"query", "city"},
+		Metadata:  map[string]string{"author": "bob"},
+	}
+
+	d := Diff(old, new)
+	if d.Empty() || len(d.Fields) != 1 || d.Fields[0].Field != "metadata.author" {
+		t.Fatalf("Unexpected field changes: %+v", d.Fields)
+	}
+	if strings.Join(d.VariablesAdded, ",") != "city" || len(d.VariablesRemoved) != 0 {
+		t.Errorf("Unexpected variable changes: +%v -%v", d.VariablesAdded, d.VariablesRemoved)
+	}
+
+	want := `--- template (version 1)
++++ template (version 2)
+@@ -1,4 +1,4 @@
+-line 1
++line 1 changed
+ line 2
+ line 3
+ line 4
+@@ -8,3 +8,4 @@
+ line 8
+ line 9
+ Query: {{query}}
++City: {{city}}
+`
+	if got := d.Unified(3); got != want {
+		t.Errorf("Unexpected unified diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if !Diff(old, old).Empty() {
+		t.Error("Expected empty diff for identical prompts")
+	}
+}
@@ -0,0 +1,158 @@
+// Package prompts содержит локальные инструменты для шаблонов промптов
+// PromptConfig: разбор и подстановку переменных в синтаксисе сервера
+// ({{name}}), проверку объявленных переменных (Lint) и сравнение версий (Diff).
+package prompts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Разделители подстановки
+const (
+	openDelim  = "{{"
+	closeDelim = "}}"
+)
+
+// Placeholder подстановка в шаблоне
+type Placeholder struct {
+	Name   string
+	Offset int // смещение "{{" в байтах
+	Line   int // номер строки, с 1
+	Column int // номер символа в строке, с 1
+}
+
+// SyntaxError ошибка разбора шаблона
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("template:%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// MissingVariablesError возвращается Render, если значения переменных не переданы
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("missing template variables: %s", strings.Join(e.Names, ", "))
+}
+
+// Template разобранный шаблон промпта
+type Template struct {
+	text         string
+	placeholders []Placeholder
+}
+
+// Parse разбирает шаблон. Имя переменной состоит из букв, цифр и символов
+// "_", "-", "."; пробелы вокруг имени допускаются: {{ query }}.
+func Parse(text string) (*Template, error) {
+	t := &Template{text: text}
+	for pos := 0; ; {
+		start := strings.Index(text[pos:], openDelim)
+		if start < 0 {
+			break
+		}
+		start += pos
+		line, col := position(text, start)
+
+		end := strings.Index(text[start+len(openDelim):], closeDelim)
+		if end < 0 {
+			return nil, &SyntaxError{Line: line, Column: col, Msg: "unclosed placeholder"}
+		}
+		end += start + len(openDelim)
+
+		raw := text[start+len(openDelim) : end]
+		name := strings.TrimSpace(raw)
+		if err := checkName(name); err != nil {
+			return nil, &SyntaxError{Line: line, Column: col, Msg: err.Error()}
+		}
+		t.placeholders = append(t.placeholders, Placeholder{Name: name, Offset: start, Line: line, Column: col})
+		pos = end + len(closeDelim)
+	}
+	return t, nil
+}
+
+// checkName проверяет имя переменной
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty placeholder")
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '_' || r == '-' || r == '.':
+		default:
+			return fmt.Errorf("invalid placeholder name %q", name)
+		}
+	}
+	return nil
+}
+
+// position возвращает строку и символ (в рунах) для смещения
+func position(text string, offset int) (int, int) {
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return line, len([]rune(before[lineStart:])) + 1
+}
+
+// Placeholders возвращает подстановки в порядке появления
+func (t *Template) Placeholders() []Placeholder {
+	return append([]Placeholder(nil), t.placeholders...)
+}
+
+// Variables возвращает имена используемых переменных без повторов
+func (t *Template) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range t.placeholders {
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Render подставляет значения переменных. Значения форматируются через
+// fmt.Sprint. Если значений не хватает, возвращается *MissingVariablesError.
+func (t *Template) Render(vars map[string]interface{}) (string, error) {
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", &MissingVariablesError{Names: missing}
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, p := range t.placeholders {
+		b.WriteString(t.text[pos:p.Offset])
+		b.WriteString(fmt.Sprint(vars[p.Name]))
+		end := strings.Index(t.text[p.Offset:], closeDelim)
+		pos = p.Offset + end + len(closeDelim)
+	}
+	b.WriteString(t.text[pos:])
+	return b.String(), nil
+}
+
+// Render разбирает шаблон промпта и подставляет значения переменных
+func Render(prompt *types.PromptConfig, vars map[string]interface{}) (string, error) {
+	t, err := Parse(prompt.Template)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(vars)
+}
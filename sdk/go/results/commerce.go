@@ -0,0 +1,99 @@
+package results
+
+// Типы результатов домена commerce (и связанных payment, delivery)
+const (
+	TypeProductPurchase   = "product_purchase"
+	TypeProductComparison = "product_comparison"
+	TypeFoodOrder         = "food_order"
+	TypePaymentProcessing = "payment_processing"
+	TypeDeliveryAddress   = "delivery_address"
+)
+
+// Store описывает магазин или точку продаж в результатах commerce
+type Store struct {
+	Name              string `json:"name"`
+	Address           string `json:"address,omitempty"`
+	Distance          string `json:"distance,omitempty"` // "200м", "1.2 км"
+	Phone             string `json:"phone,omitempty"`
+	WorkHours         string `json:"work_hours,omitempty"`
+	Price             Price  `json:"price,omitempty"`
+	Rating            Number `json:"rating,omitempty"`
+	InStock           bool   `json:"in_stock,omitempty"`
+	PickupAvailable   bool   `json:"pickup_available,omitempty"`
+	DeliveryAvailable bool   `json:"delivery_available,omitempty"`
+}
+
+// Restaurant описывает ресторан в результатах заказа еды
+type Restaurant struct {
+	Name              string `json:"name"`
+	Address           string `json:"address,omitempty"`
+	Distance          string `json:"distance,omitempty"`
+	Rating            Number `json:"rating,omitempty"`
+	WorkHours         string `json:"work_hours,omitempty"`
+	DeliveryAvailable bool   `json:"delivery_available,omitempty"`
+}
+
+// ProductPurchase данные результата product_purchase
+type ProductPurchase struct {
+	Price        Price        `json:"price"`
+	Availability string       `json:"availability,omitempty"` // "в наличии"
+	Rating       Number       `json:"rating,omitempty"`
+	Stores       []Store      `json:"stores,omitempty"`
+	Restaurants  []Restaurant `json:"restaurants,omitempty"`
+	DeliveryTime string       `json:"delivery_time,omitempty"` // "30-45 минут"
+	DeliveryFee  Price        `json:"delivery_fee,omitempty"`
+	Total        Price        `json:"total,omitempty"`
+}
+
+// ProductComparison данные результата product_comparison (сравнение цен)
+type ProductComparison struct {
+	BestPrice    Price   `json:"best_price"`
+	PriceRange   string  `json:"price_range,omitempty"` // "89990 ₽ - 95990 ₽"
+	AveragePrice Price   `json:"average_price,omitempty"`
+	Stores       []Store `json:"stores,omitempty"`
+}
+
+// CheapestInStock возвращает магазин с минимальной ценой среди товаров в наличии
+func (p *ProductComparison) CheapestInStock() (*Store, bool) {
+	var best *Store
+	for i := range p.Stores {
+		s := &p.Stores[i]
+		if !s.InStock {
+			continue
+		}
+		if best == nil || s.Price.Amount < best.Price.Amount {
+			best = s
+		}
+	}
+	return best, best != nil
+}
+
+// FoodOrder данные результата food_order
+type FoodOrder struct {
+	Restaurant        string `json:"restaurant"`
+	Item              string `json:"item"`
+	Size              string `json:"size,omitempty"`
+	Price             Price  `json:"price"`
+	DeliveryAvailable bool   `json:"delivery_available,omitempty"`
+	EstimatedTime     string `json:"estimated_time,omitempty"`
+	RestaurantRating  Number `json:"restaurant_rating,omitempty"`
+}
+
+// PaymentProcessing данные результата payment_processing
+type PaymentProcessing struct {
+	Amount                  Price    `json:"amount"`
+	OrderID                 string   `json:"order_id,omitempty"`
+	Currency                string   `json:"currency,omitempty"`
+	PaymentMethods          []string `json:"payment_methods,omitempty"` // card, apple_pay, google_pay, cash
+	EstimatedProcessingTime string   `json:"estimated_processing_time,omitempty"`
+}
+
+// DeliveryAddress данные результата delivery_address
+type DeliveryAddress struct {
+	OrderID               string `json:"order_id,omitempty"`
+	DeliveryType          string `json:"delivery_type,omitempty"` // courier, pickup
+	EstimatedDeliveryTime string `json:"estimated_delivery_time,omitempty"`
+	DeliveryFee           Price  `json:"delivery_fee,omitempty"`
+	FreeDeliveryThreshold Price  `json:"free_delivery_threshold,omitempty"`
+	CurrentAddress        string `json:"current_address,omitempty"`
+}
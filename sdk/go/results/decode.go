@@ -0,0 +1,146 @@
+// Package results содержит утилиты для работы с результатами ExecuteTemplateResponse:
+// типизированное декодирование ResultItem.Data и объединение результатов секций.
+package results
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// ErrUnknownType возвращается, если для ResultItem.Type не зарегистрирована структура
+var ErrUnknownType = errors.New("unknown result type")
+
+// Registry сопоставляет ResultItem.Type со структурами Go для декодирования Data
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]func() interface{}
+}
+
+// NewRegistry создает пустой реестр
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]func() interface{})}
+}
+
+// Register регистрирует фабрику структуры для типа результата.
+// Фабрика должна возвращать указатель на новую структуру.
+func (r *Registry) Register(resultType string, factory func() interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[resultType] = factory
+}
+
+// Types возвращает отсортированный список зарегистрированных типов
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]string, 0, len(r.factories))
+	for t := range r.factories {
+		list = append(list, t)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Decode декодирует Data результата в зарегистрированную для его типа структуру.
+// Возвращает указатель на структуру (например, *ProductPurchase).
+func (r *Registry) Decode(item *types.ResultItem) (interface{}, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[item.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, item.Type)
+	}
+
+	target := factory()
+	if err := decodeInto(item, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// RegisterType регистрирует структуру T для типа результата в реестре
+func RegisterType[T any](r *Registry, resultType string) {
+	r.Register(resultType, func() interface{} { return new(T) })
+}
+
+// DefaultRegistry реестр со встроенными структурами доменов commerce, recipes,
+// travel, notifications и health
+var DefaultRegistry = newDefaultRegistry()
+
+// Register регистрирует фабрику структуры в DefaultRegistry
+func Register(resultType string, factory func() interface{}) {
+	DefaultRegistry.Register(resultType, factory)
+}
+
+// Decode декодирует Data результата через DefaultRegistry
+func Decode(item *types.ResultItem) (interface{}, error) {
+	return DefaultRegistry.Decode(item)
+}
+
+// DecodeData декодирует Data результата в структуру T независимо от реестра.
+//
+// Пример использования:
+//
+//	for _, item := range section.Results {
+//		if item.Type != results.TypeProductPurchase {
+//			continue
+//		}
+//		product, err := results.DecodeData[results.ProductPurchase](&item)
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(product.Price.Amount, product.Stores[0].Name)
+//	}
+func DecodeData[T any](item *types.ResultItem) (*T, error) {
+	target := new(T)
+	if err := decodeInto(item, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// decodeInto перекодирует map Data через JSON в target
+func decodeInto(item *types.ResultItem, target interface{}) error {
+	if item == nil {
+		return errors.New("result item is nil")
+	}
+	if len(item.Data) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(item.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data of result %s: %w", item.ID, err)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("failed to decode data of result %s (%s): %w", item.ID, item.Type, err)
+	}
+	return nil
+}
+
+// newDefaultRegistry создает реестр со встроенными типами
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	RegisterType[ProductPurchase](r, TypeProductPurchase)
+	RegisterType[ProductComparison](r, TypeProductComparison)
+	RegisterType[FoodOrder](r, TypeFoodOrder)
+	RegisterType[PaymentProcessing](r, TypePaymentProcessing)
+	RegisterType[DeliveryAddress](r, TypeDeliveryAddress)
+
+	RegisterType[Recipe](r, TypeRecipe)
+
+	RegisterType[HotelOffer](r, TypeHotel)
+	RegisterType[FlightOffer](r, TypeFlight)
+	RegisterType[TourOffer](r, TypeTour)
+
+	RegisterType[Reminder](r, TypeReminder)
+	RegisterType[MedicationReminder](r, TypeMedicationReminder)
+	RegisterType[MedicationTracking](r, TypeMedicationTracking)
+
+	return r
+}
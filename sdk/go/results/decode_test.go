@@ -0,0 +1,204 @@
+package results
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// itemFromJSON создает ResultItem из JSON, как его возвращает сервер
+func itemFromJSON(t *testing.T, raw string) *types.ResultItem {
+	t.Helper()
+	var item types.ResultItem
+	if err := json.Unmarshal([]byte(raw), &item); err != nil {
+		t.Fatalf("Failed to unmarshal item: %v", err)
+	}
+	return &item
+}
+
+func TestDecodeProductPurchase(t *testing.T) {
+	item := itemFromJSON(t, `{
+		"id": "milk-1",
+		"type": "product_purchase",
+		"title": "Молоко Простоквашино 3.2%",
+		"data": {
+			"price": "89 ₽",
+			"availability": "в наличии",
+			"rating": "4.8",
+			"stores": [
+				{"name": "Пятерочка", "distance": "200м", "pickup_available": true, "work_hours": "08:00-23:00"},
+				{"name": "Магнит", "distance": "350м", "price": "92,50 ₽", "rating": 4.5}
+			]
+		}
+	}`)
+
+	decoded, err := Decode(item)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	product, ok := decoded.(*ProductPurchase)
+	if !ok {
+		t.Fatalf("Expected *ProductPurchase, got %T", decoded)
+	}
+
+	if product.Price.Amount != 89 || product.Price.Currency != "RUB" {
+		t.Errorf("Expected price 89 RUB, got %v %s", product.Price.Amount, product.Price.Currency)
+	}
+	if product.Price.String() != "89 ₽" {
+		t.Errorf("Expected raw price '89 ₽', got %q", product.Price.String())
+	}
+	if product.Rating != 4.8 {
+		t.Errorf("Expected rating 4.8 from string, got %v", product.Rating)
+	}
+	if len(product.Stores) != 2 {
+		t.Fatalf("Expected 2 stores, got %d", len(product.Stores))
+	}
+	if !product.Stores[0].PickupAvailable {
+		t.Error("Expected pickup available for first store")
+	}
+	if product.Stores[1].Price.Amount != 92.5 {
+		t.Errorf("Expected store price 92.5, got %v", product.Stores[1].Price.Amount)
+	}
+	if product.Stores[1].Rating != 4.5 {
+		t.Errorf("Expected store rating 4.5 from number, got %v", product.Stores[1].Rating)
+	}
+}
+
+func TestDecodeProductComparison(t *testing.T) {
+	item := itemFromJSON(t, `{
+		"id": "iphone-compare",
+		"type": "product_comparison",
+		"data": {
+			"best_price": "89 990 ₽",
+			"price_range": "89990 ₽ - 95990 ₽",
+			"stores": [
+				{"name": "М.Видео", "price": "95990 ₽", "in_stock": true},
+				{"name": "DNS", "price": "89990 ₽", "in_stock": true},
+				{"name": "Ситилинк", "price": "87990 ₽", "in_stock": false}
+			]
+		}
+	}`)
+
+	comparison, err := DecodeData[ProductComparison](item)
+	if err != nil {
+		t.Fatalf("DecodeData failed: %v", err)
+	}
+	if comparison.BestPrice.Amount != 89990 {
+		t.Errorf("Expected best price 89990, got %v", comparison.BestPrice.Amount)
+	}
+
+	store, ok := comparison.CheapestInStock()
+	if !ok {
+		t.Fatal("Expected cheapest store in stock")
+	}
+	if store.Name != "DNS" {
+		t.Errorf("Expected DNS, got %s", store.Name)
+	}
+}
+
+func TestDecodeMedicationReminder(t *testing.T) {
+	item := itemFromJSON(t, `{
+		"id": "med-1",
+		"type": "medication_reminder",
+		"data": {
+			"reminder_type": "medication",
+			"medication": "Ибупрофен",
+			"dosage": "400мг",
+			"delay_hours": 8,
+			"repeat": true,
+			"notification_methods": ["push", "sms"]
+		}
+	}`)
+
+	reminder, err := DecodeData[MedicationReminder](item)
+	if err != nil {
+		t.Fatalf("DecodeData failed: %v", err)
+	}
+	if reminder.Medication != "Ибупрофен" || reminder.DelayHours != 8 || !reminder.Repeat {
+		t.Errorf("Unexpected reminder: %+v", reminder)
+	}
+	if len(reminder.NotificationMethods) != 2 {
+		t.Errorf("Expected 2 notification methods, got %d", len(reminder.NotificationMethods))
+	}
+}
+
+func TestDecodeUnknownType(t *testing.T) {
+	item := &types.ResultItem{ID: "x", Type: "weather_forecast", Data: map[string]interface{}{"temp": 20}}
+
+	_, err := Decode(item)
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+}
+
+func TestDecodeTypeMismatch(t *testing.T) {
+	item := &types.ResultItem{
+		ID:   "bad",
+		Type: TypeRecipe,
+		Data: map[string]interface{}{"ingredients": "свекла"},
+	}
+
+	if _, err := Decode(item); err == nil {
+		t.Error("Expected error for mismatched ingredients type")
+	}
+}
+
+func TestRegistryCustomType(t *testing.T) {
+	type Weather struct {
+		Temp Number `json:"temp"`
+		City string `json:"city"`
+	}
+
+	r := NewRegistry()
+	RegisterType[Weather](r, "weather_forecast")
+
+	item := &types.ResultItem{
+		Type: "weather_forecast",
+		Data: map[string]interface{}{"temp": "-5", "city": "Москва"},
+	}
+	decoded, err := r.Decode(item)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	weather := decoded.(*Weather)
+	if weather.Temp != -5 || weather.City != "Москва" {
+		t.Errorf("Unexpected weather: %+v", weather)
+	}
+
+	if got := r.Types(); len(got) != 1 || got[0] != "weather_forecast" {
+		t.Errorf("Expected [weather_forecast], got %v", got)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		in       string
+		amount   float64
+		currency string
+	}{
+		{"89 ₽", 89, "RUB"},
+		{"89 990 ₽", 89990, "RUB"},
+		{"1 299,90 руб.", 1299.9, "RUB"},
+		{"100 р.", 100, "RUB"},
+		{"100 р", 100, "RUB"},
+		{"100р.", 100, "RUB"},
+		{"$19.99", 19.99, "USD"},
+		{"150 EUR", 150, "EUR"},
+		{"1,299.99", 1299.99, ""},
+		{"$1,299", 1299, "USD"},
+		{"$1,299,999.50", 1299999.5, "USD"},
+		{"1,5 €", 1.5, "EUR"},
+		{"4,50 руб.", 4.5, "RUB"},
+		{"12,3456", 12.3456, ""},
+		{"бесплатно", 0, ""},
+	}
+
+	for _, tt := range tests {
+		p := ParsePrice(tt.in)
+		if p.Amount != tt.amount || p.Currency != tt.currency {
+			t.Errorf("ParsePrice(%q) = %v %q, expected %v %q", tt.in, p.Amount, p.Currency, tt.amount, tt.currency)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package results
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// SourceWebSearch идентификатор источника для результатов веб-поиска
+const SourceWebSearch = "web_search"
+
+// TypeWebResult тип ResultItem, в который преобразуются результаты веб-поиска
+const TypeWebResult = "web_result"
+
+// DefaultSimilarityThreshold порог схожести заголовков для дедупликации (0-1)
+const DefaultSimilarityThreshold = 0.8
+
+// ErrInvalidCursor возвращается при некорректном курсоре пагинации
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// ScoreFunc вычисляет оценку результата, если он отсутствует в RankingResult
+type ScoreFunc func(item *types.ResultItem) float64
+
+// ByRelevance оценка по релевантности (fallback по умолчанию)
+func ByRelevance(item *types.ResultItem) float64 {
+	return float64(item.Relevance)
+}
+
+// ByConfidence оценка по уверенности домена
+func ByConfidence(item *types.ResultItem) float64 {
+	return float64(item.Confidence)
+}
+
+// Weighted возвращает оценку как взвешенную сумму Relevance и Confidence
+func Weighted(relevance, confidence float64) ScoreFunc {
+	return func(item *types.ResultItem) float64 {
+		return relevance*float64(item.Relevance) + confidence*float64(item.Confidence)
+	}
+}
+
+// MergeOptions настройки объединения результатов
+type MergeOptions struct {
+	// Score оценка для результатов без ранжирования (по умолчанию ByRelevance)
+	Score ScoreFunc
+	// IgnoreRanking игнорирует RankingResult ответа и сортирует только по Score
+	IgnoreRanking bool
+	// IncludeWebSearch добавляет результаты WebSearch в общий список
+	IncludeWebSearch bool
+	// DisableDedup отключает дедупликацию похожих результатов
+	DisableDedup bool
+	// SimilarityThreshold порог схожести заголовков (0 = DefaultSimilarityThreshold)
+	SimilarityThreshold float64
+	// Filters фильтры, применяемые на стороне клиента
+	Filters *types.AdvancedFilters
+}
+
+// Item результат в объединенном списке
+type Item struct {
+	types.ResultItem
+	DomainID string   // домен-источник (SourceWebSearch для веб-поиска)
+	Score    float64  // итоговая оценка
+	Rank     int      // позиция в объединенном списке (1-based)
+	Ranked   bool     // оценка взята из RankingResult
+	AlsoIn   []string // домены, в которых найдены дубликаты
+
+	order int // исходный порядок для стабильной сортировки
+}
+
+// Merged объединенный и отранжированный список результатов
+type Merged struct {
+	Items      []Item
+	Algorithm  string // алгоритм ранжирования сервера, если использовался
+	Duplicates int    // количество удаленных дубликатов
+	Filtered   int    // количество результатов, отброшенных фильтрами
+}
+
+// Merge объединяет секции ответа в единый список: применяет ранжирование сервера
+// (или opts.Score), удаляет дубликаты между доменами и фильтрует результаты.
+//
+// Пример использования:
+//
+//	merged := results.Merge(resp, results.MergeOptions{
+//		Score:   results.Weighted(0.7, 0.3),
+//		Filters: &types.AdvancedFilters{MinRelevance: 0.5, SortBy: "price"},
+//	})
+//	page, info := merged.Page(1, 10)
+func Merge(resp *types.ExecuteTemplateResponse, opts MergeOptions) *Merged {
+	merged := &Merged{}
+	if resp == nil {
+		return merged
+	}
+	if opts.Score == nil {
+		opts.Score = ByRelevance
+	}
+
+	ranking := make(map[string]types.RankedItem)
+	if resp.Ranking != nil && !opts.IgnoreRanking {
+		merged.Algorithm = resp.Ranking.Algorithm
+		for _, r := range resp.Ranking.Items {
+			ranking[r.ID] = r
+		}
+	}
+
+	items := collect(resp, opts.IncludeWebSearch)
+	for i := range items {
+		it := &items[i]
+		if r, ok := ranking[it.ID]; ok && it.ID != "" {
+			it.Score = float64(r.Score)
+			it.Ranked = true
+			// Rank сервера сохраняется для сортировки, позиция пересчитывается ниже
+			it.Rank = int(r.Rank)
+		} else {
+			it.Score = opts.Score(&it.ResultItem)
+			it.Rank = 0
+		}
+	}
+
+	sortByRanking(items)
+
+	if opts.Filters != nil {
+		before := len(items)
+		items = filterItems(items, opts.Filters)
+		merged.Filtered = before - len(items)
+	}
+
+	if !opts.DisableDedup {
+		threshold := opts.SimilarityThreshold
+		if threshold <= 0 {
+			threshold = DefaultSimilarityThreshold
+		}
+		before := len(items)
+		items = dedupe(items, threshold)
+		merged.Duplicates = before - len(items)
+	}
+
+	if opts.Filters != nil {
+		items = sortAndLimit(items, opts.Filters)
+	}
+
+	for i := range items {
+		items[i].Rank = i + 1
+	}
+	merged.Items = items
+	return merged
+}
+
+// collect разворачивает секции (и веб-поиск) в плоский список
+func collect(resp *types.ExecuteTemplateResponse, includeWeb bool) []Item {
+	var items []Item
+	order := 0
+	for _, section := range resp.Sections {
+		for _, r := range section.Results {
+			items = append(items, Item{ResultItem: r, DomainID: section.DomainID, order: order})
+			order++
+		}
+	}
+	if includeWeb && resp.WebSearch != nil {
+		for i, r := range resp.WebSearch.Results {
+			items = append(items, Item{
+				ResultItem: types.ResultItem{
+					ID:          fmt.Sprintf("web-%d", i+1),
+					Type:        TypeWebResult,
+					Title:       r.Title,
+					Description: r.Snippet,
+					Data:        map[string]interface{}{"url": r.URL},
+					Relevance:   r.Relevance,
+				},
+				DomainID: SourceWebSearch,
+				order:    order,
+			})
+			order++
+		}
+	}
+	return items
+}
+
+// sortByRanking ставит отранжированные сервером результаты первыми (по Rank),
+// остальные — по убыванию оценки; при равенстве сохраняется исходный порядок
+func sortByRanking(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Ranked != b.Ranked {
+			return a.Ranked
+		}
+		if a.Ranked && a.Rank != b.Rank && a.Rank > 0 && b.Rank > 0 {
+			return a.Rank < b.Rank
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.order < b.order
+	})
+}
+
+// dedupe удаляет похожие результаты, оставляя первый (лучший) из группы.
+// Результаты считаются дубликатами при совпадении ID или схожести заголовков
+// не ниже threshold при одинаковом типе.
+func dedupe(items []Item, threshold float64) []Item {
+	kept := make([]Item, 0, len(items))
+	tokens := make([]map[string]struct{}, 0, len(items))
+
+	for _, it := range items {
+		itTokens := titleTokens(it.Title)
+		duplicate := -1
+		for k := range kept {
+			if it.ID != "" && it.ID == kept[k].ID {
+				duplicate = k
+				break
+			}
+			if it.Type == kept[k].Type && jaccard(itTokens, tokens[k]) >= threshold {
+				duplicate = k
+				break
+			}
+		}
+		if duplicate < 0 {
+			kept = append(kept, it)
+			tokens = append(tokens, itTokens)
+			continue
+		}
+		best := &kept[duplicate]
+		if it.DomainID != best.DomainID && !contains(best.AlsoIn, it.DomainID) {
+			best.AlsoIn = append(best.AlsoIn, it.DomainID)
+		}
+	}
+	return kept
+}
+
+// titleTokens нормализует заголовок в множество слов
+func titleTokens(title string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	}) {
+		w = strings.Trim(w, ".")
+		if w != "" {
+			set[w] = struct{}{}
+		}
+	}
+	return set
+}
+
+// jaccard вычисляет коэффициент Жаккара двух множеств
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range a {
+		if _, ok := b[w]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// filterItems отбрасывает результаты, не подходящие под AdvancedFilters.
+// Результаты без даты не отбрасываются фильтром DateRange.
+func filterItems(items []Item, f *types.AdvancedFilters) []Item {
+	filtered := items[:0]
+	for _, it := range items {
+		if len(f.Domains) > 0 && !contains(f.Domains, it.DomainID) {
+			continue
+		}
+		if contains(f.ExcludeDomains, it.DomainID) {
+			continue
+		}
+		if f.MinRelevance > 0 && it.Relevance < f.MinRelevance {
+			continue
+		}
+		if f.DateRange != nil {
+			if ts, ok := itemTime(&it.ResultItem); ok {
+				if f.DateRange.From > 0 && ts < f.DateRange.From {
+					continue
+				}
+				if f.DateRange.To > 0 && ts > f.DateRange.To {
+					continue
+				}
+			}
+		}
+		filtered = append(filtered, it)
+	}
+	return filtered
+}
+
+// sortAndLimit применяет SortBy и MaxResults из AdvancedFilters
+func sortAndLimit(filtered []Item, f *types.AdvancedFilters) []Item {
+	switch f.SortBy {
+	case "price":
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return itemPrice(&filtered[i].ResultItem) < itemPrice(&filtered[j].ResultItem)
+		})
+	case "date":
+		sort.SliceStable(filtered, func(i, j int) bool {
+			ti, _ := itemTime(&filtered[i].ResultItem)
+			tj, _ := itemTime(&filtered[j].ResultItem)
+			return ti > tj
+		})
+	}
+
+	if f.MaxResults > 0 && len(filtered) > int(f.MaxResults) {
+		filtered = filtered[:f.MaxResults]
+	}
+	return filtered
+}
+
+// itemPrice извлекает цену из Data (price, best_price, amount).
+// Результаты без цены сортируются в конец.
+func itemPrice(item *types.ResultItem) float64 {
+	for _, key := range []string{"price", "best_price", "amount"} {
+		switch v := item.Data[key].(type) {
+		case float64:
+			return v
+		case string:
+			if p := ParsePrice(v); p.Amount > 0 {
+				return p.Amount
+			}
+		}
+	}
+	return math.Inf(1)
+}
+
+// itemTime извлекает unix timestamp (секунды) из Data (timestamp, date, created_at)
+func itemTime(item *types.ResultItem) (int64, bool) {
+	for _, key := range []string{"timestamp", "date", "created_at"} {
+		switch v := item.Data[key].(type) {
+		case float64:
+			return int64(v), true
+		case string:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t.Unix(), true
+			}
+			if t, err := time.Parse("2006-01-02", v); err == nil {
+				return t.Unix(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Page возвращает страницу объединенного списка (page 1-based) и информацию о пагинации.
+// Порядок элементов детерминирован, поэтому страницы не пересекаются.
+func (m *Merged) Page(page, pageSize int) ([]Item, *types.PaginationInfo) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = len(m.Items)
+		if pageSize == 0 {
+			pageSize = 1
+		}
+	}
+	return m.slice((page-1)*pageSize, pageSize)
+}
+
+// After возвращает страницу, начиная с курсора NextCursor/PrevCursor предыдущей страницы.
+// Пустой курсор означает начало списка.
+func (m *Merged) After(cursor string, pageSize int) ([]Item, *types.PaginationInfo, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		offset, err = decodeCursor(cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if pageSize <= 0 {
+		pageSize = len(m.Items)
+		if pageSize == 0 {
+			pageSize = 1
+		}
+	}
+	items, info := m.slice(offset, pageSize)
+	return items, info, nil
+}
+
+// slice вырезает страницу и формирует PaginationInfo
+func (m *Merged) slice(offset, pageSize int) ([]Item, *types.PaginationInfo) {
+	total := len(m.Items)
+	if offset > total {
+		offset = total
+	}
+	end := offset + pageSize
+	if end > total {
+		end = total
+	}
+
+	info := &types.PaginationInfo{
+		Page:        int32(offset/pageSize) + 1,
+		PageSize:    int32(pageSize),
+		TotalPages:  int32((total + pageSize - 1) / pageSize),
+		TotalItems:  int64(total),
+		HasNext:     end < total,
+		HasPrevious: offset > 0,
+	}
+	if info.HasNext {
+		info.NextCursor = encodeCursor(end)
+	}
+	if info.HasPrevious {
+		prev := offset - pageSize
+		if prev < 0 {
+			prev = 0
+		}
+		info.PrevCursor = encodeCursor(prev)
+	}
+	return m.Items[offset:end], info
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	value, ok := strings.CutPrefix(string(raw), "offset:")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
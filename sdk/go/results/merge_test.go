@@ -0,0 +1,170 @@
+package results
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+func testResponse() *types.ExecuteTemplateResponse {
+	return &types.ExecuteTemplateResponse{
+		Sections: []types.DomainSection{
+			{
+				DomainID: "commerce",
+				Results: []types.ResultItem{
+					{ID: "milk-1", Type: "product_purchase", Title: "Молоко Простоквашино 3.2%", Relevance: 0.9, Data: map[string]interface{}{"price": "89 ₽"}},
+					{ID: "bread-1", Type: "product_purchase", Title: "Хлеб Бородинский", Relevance: 0.6, Data: map[string]interface{}{"price": "45 ₽"}},
+				},
+			},
+			{
+				DomainID: "delivery",
+				Results: []types.ResultItem{
+					{ID: "milk-2", Type: "product_purchase", Title: "молоко простоквашино 3.2 %", Relevance: 0.7, Data: map[string]interface{}{"price": "95 ₽"}},
+					{ID: "courier-1", Type: "delivery_address", Title: "Курьерская доставка", Relevance: 0.8},
+				},
+			},
+		},
+		WebSearch: &types.WebSearchResult{
+			Results: []types.SearchResult{{Title: "Где купить молоко", URL: "https://example.com", Relevance: 0.3}},
+		},
+	}
+}
+
+func TestMergeFallbackScoreAndDedup(t *testing.T) {
+	merged := Merge(testResponse(), MergeOptions{})
+
+	if len(merged.Items) != 3 {
+		t.Fatalf("Expected 3 items after dedup, got %d", len(merged.Items))
+	}
+	if merged.Duplicates != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", merged.Duplicates)
+	}
+
+	expected := []string{"milk-1", "courier-1", "bread-1"}
+	for i, id := range expected {
+		if merged.Items[i].ID != id {
+			t.Errorf("Expected item %d to be %s, got %s", i, id, merged.Items[i].ID)
+		}
+		if merged.Items[i].Rank != i+1 {
+			t.Errorf("Expected rank %d, got %d", i+1, merged.Items[i].Rank)
+		}
+	}
+	if len(merged.Items[0].AlsoIn) != 1 || merged.Items[0].AlsoIn[0] != "delivery" {
+		t.Errorf("Expected milk-1 also in delivery, got %v", merged.Items[0].AlsoIn)
+	}
+}
+
+func TestMergeUsesRanking(t *testing.T) {
+	resp := testResponse()
+	resp.Ranking = &types.RankingResult{
+		Algorithm: "hybrid",
+		Items: []types.RankedItem{
+			{ID: "bread-1", Score: 0.95, Rank: 1},
+			{ID: "milk-2", Score: 0.9, Rank: 2},
+		},
+	}
+
+	merged := Merge(resp, MergeOptions{IncludeWebSearch: true})
+
+	if merged.Algorithm != "hybrid" {
+		t.Errorf("Expected algorithm hybrid, got %s", merged.Algorithm)
+	}
+	expected := []string{"bread-1", "milk-2", "courier-1", "web-1"}
+	if len(merged.Items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(merged.Items))
+	}
+	for i, id := range expected {
+		if merged.Items[i].ID != id {
+			t.Errorf("Expected item %d to be %s, got %s", i, id, merged.Items[i].ID)
+		}
+	}
+	if !merged.Items[0].Ranked || merged.Items[0].Score != float64(float32(0.95)) {
+		t.Errorf("Expected ranked score 0.95, got %v", merged.Items[0].Score)
+	}
+	if merged.Items[3].DomainID != SourceWebSearch {
+		t.Errorf("Expected web search source, got %s", merged.Items[3].DomainID)
+	}
+}
+
+func TestMergeFilters(t *testing.T) {
+	merged := Merge(testResponse(), MergeOptions{
+		Filters: &types.AdvancedFilters{
+			ExcludeDomains: []string{"commerce"},
+			SortBy:         "price",
+		},
+	})
+
+	// milk-1 исключен вместе с доменом commerce, поэтому milk-2 не считается дубликатом
+	if len(merged.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(merged.Items))
+	}
+	if merged.Items[0].ID != "milk-2" {
+		t.Errorf("Expected priced item first, got %s", merged.Items[0].ID)
+	}
+	if merged.Filtered != 2 {
+		t.Errorf("Expected 2 filtered items, got %d", merged.Filtered)
+	}
+
+	merged = Merge(testResponse(), MergeOptions{
+		Filters: &types.AdvancedFilters{MinRelevance: 0.65, MaxResults: 1},
+	})
+	if len(merged.Items) != 1 || merged.Items[0].ID != "milk-1" {
+		t.Errorf("Expected only milk-1, got %+v", merged.Items)
+	}
+}
+
+func TestMergeSortByPriceWithThousands(t *testing.T) {
+	resp := &types.ExecuteTemplateResponse{Sections: []types.DomainSection{{
+		DomainID: "commerce",
+		Results: []types.ResultItem{
+			{ID: "laptop", Title: "Ноутбук", Data: map[string]interface{}{"price": "$1,299"}},
+			{ID: "phone", Title: "Телефон", Data: map[string]interface{}{"price": "$999.50"}},
+			{ID: "tv", Title: "Телевизор", Data: map[string]interface{}{"price": "$1,099.99"}},
+		},
+	}}}
+
+	merged := Merge(resp, MergeOptions{Filters: &types.AdvancedFilters{SortBy: "price"}})
+	var order []string
+	for _, item := range merged.Items {
+		order = append(order, item.ID)
+	}
+	if len(order) != 3 || order[0] != "phone" || order[1] != "tv" || order[2] != "laptop" {
+		t.Errorf("Expected [phone tv laptop], got %v", order)
+	}
+}
+
+func TestMergedPagination(t *testing.T) {
+	merged := Merge(testResponse(), MergeOptions{IncludeWebSearch: true, DisableDedup: true})
+
+	page, info := merged.Page(1, 2)
+	if len(page) != 2 || !info.HasNext || info.HasPrevious || info.TotalPages != 3 || info.TotalItems != 5 {
+		t.Errorf("Unexpected first page: %d items, %+v", len(page), info)
+	}
+
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		items, info, err := merged.After(cursor, 2)
+		if err != nil {
+			t.Fatalf("After failed: %v", err)
+		}
+		for _, it := range items {
+			if seen[it.ID] {
+				t.Errorf("Item %s returned twice", it.ID)
+			}
+			seen[it.ID] = true
+		}
+		if !info.HasNext {
+			break
+		}
+		cursor = info.NextCursor
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected 5 unique items, got %d", len(seen))
+	}
+
+	if _, _, err := merged.After("not-a-cursor", 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
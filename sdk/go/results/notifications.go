@@ -0,0 +1,41 @@
+package results
+
+// Типы результатов доменов notifications и health
+const (
+	TypeReminder           = "reminder"
+	TypeMedicationReminder = "medication_reminder"
+	TypeMedicationTracking = "medication_tracking"
+)
+
+// Reminder данные результата reminder
+type Reminder struct {
+	ReminderType        string   `json:"reminder_type"` // courier_departure, medication и т.д.
+	OrderID             string   `json:"order_id,omitempty"`
+	Trigger             string   `json:"trigger,omitempty"`
+	Status              string   `json:"status,omitempty"`
+	NotificationMethods []string `json:"notification_methods,omitempty"` // push, sms, voice
+	Priority            string   `json:"priority,omitempty"`
+}
+
+// MedicationReminder данные результата medication_reminder
+type MedicationReminder struct {
+	ReminderType        string   `json:"reminder_type"`
+	Medication          string   `json:"medication"`
+	Dosage              string   `json:"dosage,omitempty"`
+	Time                string   `json:"time,omitempty"`
+	DelayHours          Number   `json:"delay_hours,omitempty"`
+	ScheduledTime       string   `json:"scheduled_time,omitempty"` // RFC 3339
+	Repeat              bool     `json:"repeat,omitempty"`
+	Importance          string   `json:"importance,omitempty"`
+	NotificationMethods []string `json:"notification_methods,omitempty"`
+}
+
+// MedicationTracking данные результата medication_tracking
+type MedicationTracking struct {
+	MedicationName      string   `json:"medication_name"`
+	Schedule            []string `json:"schedule,omitempty"`
+	Completed           []bool   `json:"completed,omitempty"`
+	NextReminder        string   `json:"next_reminder,omitempty"`
+	AdherenceRate       string   `json:"adherence_rate,omitempty"` // "0%"
+	SideEffectsTracking bool     `json:"side_effects_tracking,omitempty"`
+}
@@ -0,0 +1,17 @@
+package results
+
+// Типы результатов домена recipes
+const (
+	TypeRecipe = "recipe"
+)
+
+// Recipe данные результата recipe
+type Recipe struct {
+	Ingredients []string `json:"ingredients,omitempty"`  // ["свекла", "картофель", "капуста"]
+	CookingTime string   `json:"cooking_time,omitempty"` // "2 часа"
+	Difficulty  string   `json:"difficulty,omitempty"`   // легко, средне, сложно
+	Servings    Number   `json:"servings,omitempty"`
+	Calories    Number   `json:"calories,omitempty"`
+	Cuisine     string   `json:"cuisine,omitempty"`
+	Steps       []string `json:"steps,omitempty"`
+}
@@ -0,0 +1,182 @@
+package results
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Number представляет число, которое домены передают как JSON число или строку
+// ("rating": 4.8 и "rating": "4.5"). Нечисловые строки декодируются как 0.
+type Number float64
+
+// UnmarshalJSON декодирует число из JSON числа или строки
+func (n *Number) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		v, _ := parseLeadingNumber(s)
+		*n = Number(v)
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = Number(v)
+	return nil
+}
+
+// Float64 возвращает значение как float64
+func (n Number) Float64() float64 {
+	return float64(n)
+}
+
+// Price представляет цену в формате доменов ("89 ₽", "89990 ₽", 599).
+// Raw сохраняет исходное значение для отображения.
+type Price struct {
+	Raw      string  // исходная строка
+	Amount   float64 // сумма
+	Currency string  // код валюты ISO 4217 (RUB, USD, EUR), если распознан
+}
+
+// currencySymbols соответствие символов валют кодам ISO 4217.
+// Точка сокращения ("руб.", "р.") отбрасывается перед поиском.
+var currencySymbols = map[string]string{
+	"₽":   "RUB",
+	"руб": "RUB",
+	"р":   "RUB",
+	"$":   "USD",
+	"€":   "EUR",
+	"£":   "GBP",
+	"₸":   "KZT",
+}
+
+// ParsePrice разбирает строку цены
+func ParsePrice(s string) Price {
+	p := Price{Raw: s}
+	p.Amount, _ = parseLeadingNumber(s)
+
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsSpace(r) || r == ','
+	}) {
+		field = strings.Trim(field, ".")
+		if field == "" {
+			continue
+		}
+		if code, ok := currencySymbols[strings.ToLower(field)]; ok {
+			p.Currency = code
+			break
+		}
+		if len(field) == 3 && strings.ToUpper(field) == field {
+			p.Currency = field
+			break
+		}
+	}
+	return p
+}
+
+// UnmarshalJSON декодирует цену из строки или числа
+func (p *Price) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*p = ParsePrice(s)
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = Price{Raw: strconv.FormatFloat(v, 'f', -1, 64), Amount: v}
+	return nil
+}
+
+// MarshalJSON кодирует цену в исходную строку
+func (p Price) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Raw)
+}
+
+// String возвращает исходное представление цены
+func (p Price) String() string {
+	return p.Raw
+}
+
+// parseLeadingNumber извлекает первое число из строки, игнорируя пробелы
+// между разрядами ("89 990 ₽"). Запятая считается разделителем разрядов,
+// если после нее встречается точка ("1,299.99") или ровно три цифры
+// ("$1,299"), иначе - десятичной запятой ("4,5").
+func parseLeadingNumber(s string) (float64, bool) {
+	var b strings.Builder
+	started := false
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteRune(r)
+			started = true
+		case started && (r == '.' || r == ','):
+			b.WriteRune(r)
+		case started && (r == ' ' || r == '\u00a0' || r == '\u202f'):
+			// разделитель разрядов
+		case r == '-' && !started:
+			b.WriteRune(r)
+		default:
+			if started {
+				return parseFloat(normalizeCommas(b.String()))
+			}
+			b.Reset()
+		}
+	}
+	if !started {
+		return 0, false
+	}
+	return parseFloat(normalizeCommas(b.String()))
+}
+
+// normalizeCommas удаляет запятые-разделители разрядов и заменяет
+// десятичную запятую точкой
+func normalizeCommas(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != ',' {
+			b.WriteByte(s[i])
+			continue
+		}
+		rest := s[i+1:]
+		digits := 0
+		for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		if strings.IndexByte(rest, '.') < 0 && digits != 3 {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
+
+// parseFloat разбирает число, отбрасывая все после второй десятичной точки
+func parseFloat(s string) (float64, bool) {
+	if first := strings.IndexByte(s, '.'); first >= 0 {
+		if second := strings.IndexByte(s[first+1:], '.'); second >= 0 {
+			s = s[:first+1+second]
+		}
+	}
+	s = strings.TrimRight(s, ".")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
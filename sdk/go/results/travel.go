@@ -0,0 +1,48 @@
+package results
+
+// Типы результатов домена travel
+const (
+	TypeHotel  = "hotel"
+	TypeFlight = "flight"
+	TypeTour   = "tour"
+)
+
+// HotelOffer данные результата hotel
+type HotelOffer struct {
+	Name          string   `json:"name"`
+	Address       string   `json:"address,omitempty"`
+	Distance      string   `json:"distance,omitempty"`
+	Stars         Number   `json:"stars,omitempty"`
+	Rating        Number   `json:"rating,omitempty"`
+	PricePerNight Price    `json:"price_per_night,omitempty"`
+	TotalPrice    Price    `json:"total_price,omitempty"`
+	CheckIn       string   `json:"check_in,omitempty"`
+	CheckOut      string   `json:"check_out,omitempty"`
+	Amenities     []string `json:"amenities,omitempty"`
+	Available     bool     `json:"available,omitempty"`
+}
+
+// FlightOffer данные результата flight
+type FlightOffer struct {
+	Airline       string `json:"airline"`
+	FlightNumber  string `json:"flight_number,omitempty"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	DepartureTime string `json:"departure_time,omitempty"`
+	ArrivalTime   string `json:"arrival_time,omitempty"`
+	Duration      string `json:"duration,omitempty"`
+	Stops         Number `json:"stops,omitempty"`
+	Class         string `json:"class,omitempty"` // economy, business
+	Price         Price  `json:"price"`
+}
+
+// TourOffer данные результата tour
+type TourOffer struct {
+	Destination string   `json:"destination"`
+	Operator    string   `json:"operator,omitempty"`
+	StartDate   string   `json:"start_date,omitempty"`
+	Duration    string   `json:"duration,omitempty"` // "7 ночей"
+	Includes    []string `json:"includes,omitempty"` // перелет, отель, трансфер
+	Rating      Number   `json:"rating,omitempty"`
+	Price       Price    `json:"price"`
+}
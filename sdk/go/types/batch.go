@@ -27,3 +27,10 @@ type BatchMetadata struct {
 	CompletedAt          int64 `json:"completed_at,omitempty"`          // Время завершения (Unix timestamp)
 	TotalProcessingTimeMS int32 `json:"total_processing_time_ms,omitempty"` // Общее время обработки в миллисекундах
 }
+
+// CancelBatchResponse представляет ответ на отмену batch операции
+type CancelBatchResponse struct {
+	BatchID string `json:"batch_id"`
+	Status  string `json:"status"` // cancelled
+	Message string `json:"message,omitempty"`
+}
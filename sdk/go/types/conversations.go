@@ -52,3 +52,19 @@ type MessageResponse struct {
 	TotalMessages    int32    `json:"total_messages,omitempty"`
 }
 
+
+// GetConversationHistoryRequest представляет запрос истории сообщений беседы
+type GetConversationHistoryRequest struct {
+	Limit  int32  `json:"limit,omitempty"`  // максимум 100, по умолчанию 50
+	Offset int32  `json:"offset,omitempty"` // смещение
+	Before string `json:"before,omitempty"` // сообщения до момента времени (RFC3339)
+	After  string `json:"after,omitempty"`  // сообщения после момента времени (RFC3339)
+}
+
+// GetConversationHistoryResponse представляет ответ с историей сообщений беседы
+type GetConversationHistoryResponse struct {
+	Messages []Message `json:"messages"`
+	Total    int32     `json:"total"`
+	Limit    int32     `json:"limit"`
+	Offset   int32     `json:"offset"`
+}
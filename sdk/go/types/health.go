@@ -45,5 +45,6 @@ type ReadinessResponse struct {
 	Checks    ReadinessChecks              `json:"checks"`
 	Components map[string]*ComponentStatus `json:"components,omitempty"` // детальный статус компонентов
 	Capacity  *CapacityInfo                `json:"capacity,omitempty"`    // информация о емкости
+	ExternalServices []*ExternalServiceStatus `json:"external_services,omitempty"` // статус внешних сервисов
 }
 
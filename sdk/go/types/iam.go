@@ -55,6 +55,7 @@ type UserProfile struct {
 	LastName   string   `json:"last_name,omitempty"`
 	Status     string   `json:"status"`
 	Roles      []string `json:"roles"`
+	TenantID   string   `json:"tenant_id,omitempty"`
 	CreatedAt  int64    `json:"created_at"`
 	LastLoginAt int64   `json:"last_login_at,omitempty"`
 }
@@ -201,6 +201,23 @@ type Action struct {
 	ConfirmText string `json:"confirm_text,omitempty"`
 }
 
+// ExecuteActionRequest представляет запрос выполнения действия результата
+type ExecuteActionRequest struct {
+	ActionType string                 `json:"action_type"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Metadata   *RequestMetadata       `json:"metadata,omitempty"`
+}
+
+// ExecuteActionResponse представляет результат выполнения действия
+type ExecuteActionResponse struct {
+	ActionType       string                 `json:"action_type,omitempty"`
+	Status           string                 `json:"status,omitempty"`    // success, pending, failed
+	ResultID         string                 `json:"result_id,omitempty"` // ID созданного объекта (заказа, платежа и т.д.)
+	Message          string                 `json:"message,omitempty"`
+	Data             map[string]interface{} `json:"data,omitempty"` // полные данные ответа домена
+	ResponseMetadata *ResponseMetadata      `json:"response_metadata,omitempty"`
+}
+
 // WebSearchResult представляет результаты веб-поиска
 type WebSearchResult struct {
 	Results      []SearchResult `json:"results,omitempty"`
@@ -0,0 +1,331 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Handler выполняет шаги workflow определенного действия или домена
+type Handler interface {
+	// Execute выполняет шаг. deps содержит результаты шагов, от которых зависит шаг.
+	Execute(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error)
+
+	// Compensate отменяет результат ранее выполненного шага при сбое workflow
+	Compensate(ctx context.Context, step types.WorkflowStep, result *StepResult) error
+}
+
+// HandlerFunc позволяет использовать функцию как Handler без компенсации
+type HandlerFunc func(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error)
+
+// Execute вызывает f(ctx, step, deps)
+func (f HandlerFunc) Execute(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error) {
+	return f(ctx, step, deps)
+}
+
+// Compensate ничего не делает
+func (f HandlerFunc) Compensate(ctx context.Context, step types.WorkflowStep, result *StepResult) error {
+	return nil
+}
+
+// StepEvent описывает изменение статуса шага
+type StepEvent struct {
+	Step   types.WorkflowStep
+	From   string
+	To     string
+	Result *StepResult
+	Err    error
+	Time   time.Time
+}
+
+// StepError содержит ошибку выполнения или компенсации шага
+type StepError struct {
+	Step   int32
+	Action string
+	Err    error
+}
+
+// Error реализует интерфейс error
+func (e *StepError) Error() string {
+	return fmt.Sprintf("workflow step %d (%s) failed: %v", e.Step, e.Action, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// ExecutorConfig содержит конфигурацию Executor
+type ExecutorConfig struct {
+	MaxConcurrency int             // Максимум одновременно выполняемых шагов (0 = без ограничений)
+	Compensate     bool            // Компенсировать завершенные шаги при сбое
+	OnStepChange   func(StepEvent) // Вызывается при каждом изменении статуса шага (опционально)
+}
+
+// Executor выполняет многошаговые workflow из ExecuteTemplateResponse.
+// Шаги, зависимости которых завершены, выполняются параллельно
+// через обработчики, зарегистрированные для Action или Domain шага.
+type Executor struct {
+	mu             sync.RWMutex
+	actionHandlers map[string]Handler
+	domainHandlers map[string]Handler
+	config         ExecutorConfig
+}
+
+// NewExecutor создает новый Executor
+func NewExecutor(config ExecutorConfig) *Executor {
+	return &Executor{
+		actionHandlers: make(map[string]Handler),
+		domainHandlers: make(map[string]Handler),
+		config:         config,
+	}
+}
+
+// HandleAction регистрирует обработчик для действия шага (order_food, process_payment и т.д.).
+// Обработчик действия имеет приоритет над обработчиком домена.
+func (e *Executor) HandleAction(action string, handler Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.actionHandlers[action] = handler
+}
+
+// HandleDomain регистрирует обработчик для всех шагов домена (commerce, payment, delivery и т.д.)
+func (e *Executor) HandleDomain(domain string, handler Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.domainHandlers[domain] = handler
+}
+
+// handlerFor возвращает обработчик шага
+func (e *Executor) handlerFor(step types.WorkflowStep) (Handler, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if h, ok := e.actionHandlers[step.Action]; ok {
+		return h, true
+	}
+	h, ok := e.domainHandlers[step.Domain]
+	return h, ok
+}
+
+// Execute выполняет workflow с начала. Шаги, уже завершенные на сервере
+// (status completed/success), не выполняются повторно.
+func (e *Executor) Execute(ctx context.Context, wf *types.Workflow) (*State, error) {
+	return e.Resume(ctx, wf, nil)
+}
+
+// Resume продолжает выполнение workflow с сохраненного снимка состояния.
+// Завершенные шаги из снимка не выполняются повторно, неудачные выполняются
+// заново. Шаги in_progress не выполняются: если без них workflow не может
+// завершиться, Resume возвращает ErrStepsInProgress и статус running.
+// snapshot == nil эквивалентен Execute.
+//
+// Возвращает итоговое состояние (в том числе при ошибке) для сохранения.
+func (e *Executor) Resume(ctx context.Context, wf *types.Workflow, snapshot *State) (*State, error) {
+	g, err := buildGraph(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	state := newState(g, snapshot)
+
+	var missing []error
+	for _, n := range g.order {
+		if status := state.Steps[n].Status; status == StatusCompleted || status == StatusInProgress {
+			continue
+		}
+		if _, ok := e.handlerFor(g.steps[n]); !ok {
+			missing = append(missing, fmt.Errorf("%w: step %d (action %q, domain %q)",
+				ErrNoHandler, n, g.steps[n].Action, g.steps[n].Domain))
+		}
+	}
+	if len(missing) > 0 {
+		return state, errors.Join(missing...)
+	}
+
+	failure := e.run(ctx, g, state)
+	if failure == nil {
+		var inProgress []int32
+		for _, n := range g.order {
+			if state.Steps[n].Status == StatusInProgress {
+				inProgress = append(inProgress, n)
+			}
+		}
+		if len(inProgress) > 0 {
+			return state, fmt.Errorf("%w: steps %v", ErrStepsInProgress, inProgress)
+		}
+		state.Status = WorkflowCompleted
+		return state, nil
+	}
+
+	for _, n := range g.order {
+		if state.Steps[n].Status == StatusPending {
+			e.setStatus(g, state, n, StatusSkipped, nil)
+		}
+	}
+
+	state.Status = WorkflowFailed
+	if e.config.Compensate {
+		// Компенсация выполняется и после отмены ctx: именно тогда нужно
+		// отменить завершенные шаги
+		if err := e.compensate(context.WithoutCancel(ctx), g, state); err != nil {
+			return state, errors.Join(failure, err)
+		}
+		state.Status = WorkflowCompensated
+	}
+	return state, failure
+}
+
+// stepOutcome результат выполнения шага в горутине
+type stepOutcome struct {
+	step   int32
+	result *StepResult
+	err    error
+}
+
+// run выполняет готовые шаги до завершения workflow или первого сбоя.
+// После сбоя новые шаги не запускаются, выполняющиеся дожидаются завершения.
+func (e *Executor) run(ctx context.Context, g *graph, state *State) error {
+	outcomes := make(chan stepOutcome)
+	running := 0
+	var failure error
+
+	for {
+		if failure == nil && ctx.Err() == nil {
+			for _, n := range e.readySteps(g, state) {
+				if e.config.MaxConcurrency > 0 && running >= e.config.MaxConcurrency {
+					break
+				}
+				e.setStatus(g, state, n, StatusInProgress, nil)
+				running++
+
+				go func(step types.WorkflowStep, deps []StepResult) {
+					result, err := e.executeStep(ctx, step, deps)
+					outcomes <- stepOutcome{step: step.Step, result: result, err: err}
+				}(g.steps[n], e.dependencyResults(g, state, n))
+			}
+		}
+
+		if running == 0 {
+			break
+		}
+
+		out := <-outcomes
+		running--
+		st := state.Steps[out.step]
+		if out.err != nil {
+			st.Error = out.err.Error()
+			e.setStatus(g, state, out.step, StatusFailed, out.err)
+			if failure == nil {
+				failure = &StepError{Step: out.step, Action: st.Action, Err: out.err}
+			}
+			continue
+		}
+		st.Result = out.result
+		e.setStatus(g, state, out.step, StatusCompleted, nil)
+	}
+
+	if failure == nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return failure
+}
+
+// executeStep вызывает обработчик шага, преобразуя panic в ошибку
+func (e *Executor) executeStep(ctx context.Context, step types.WorkflowStep, deps []StepResult) (result *StepResult, err error) {
+	handler, _ := e.handlerFor(step)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+
+	result, err = handler.Execute(ctx, step, deps)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &StepResult{}
+	}
+	result.Step = step.Step
+	if result.ResultID == "" {
+		result.ResultID = step.ResultID
+	}
+	return result, nil
+}
+
+// compensate отменяет завершенные шаги в порядке, обратном порядку завершения.
+// Шаги без обработчика (например, выполненные на сервере) пропускаются.
+func (e *Executor) compensate(ctx context.Context, g *graph, state *State) error {
+	var errs []error
+	for i := len(state.CompletedOrder) - 1; i >= 0; i-- {
+		n := state.CompletedOrder[i]
+		st := state.Steps[n]
+		if st.Status != StatusCompleted {
+			continue
+		}
+		handler, ok := e.handlerFor(g.steps[n])
+		if !ok {
+			continue
+		}
+		if err := handler.Compensate(ctx, g.steps[n], st.Result); err != nil {
+			errs = append(errs, &StepError{Step: n, Action: st.Action, Err: fmt.Errorf("compensation: %w", err)})
+			continue
+		}
+		e.setStatus(g, state, n, StatusCompensated, nil)
+	}
+	return errors.Join(errs...)
+}
+
+// readySteps возвращает шаги в статусе pending, все зависимости которых завершены
+func (e *Executor) readySteps(g *graph, state *State) []int32 {
+	var ready []int32
+	for _, n := range g.order {
+		if state.Steps[n].Status != StatusPending {
+			continue
+		}
+		depsDone := true
+		for _, dep := range g.deps[n] {
+			if state.Steps[dep].Status != StatusCompleted {
+				depsDone = false
+				break
+			}
+		}
+		if depsDone {
+			ready = append(ready, n)
+		}
+	}
+	return ready
+}
+
+// dependencyResults собирает результаты зависимостей шага
+func (e *Executor) dependencyResults(g *graph, state *State, n int32) []StepResult {
+	deps := make([]StepResult, 0, len(g.deps[n]))
+	for _, dep := range g.deps[n] {
+		if result := state.Steps[dep].Result; result != nil {
+			deps = append(deps, *result)
+		}
+	}
+	return deps
+}
+
+// setStatus меняет статус шага и уведомляет OnStepChange
+func (e *Executor) setStatus(g *graph, state *State, n int32, to string, err error) {
+	from, terr := state.transition(n, to)
+	if terr != nil {
+		return
+	}
+	if e.config.OnStepChange != nil {
+		e.config.OnStepChange(StepEvent{
+			Step:   g.steps[n],
+			From:   from,
+			To:     to,
+			Result: state.Steps[n].Result,
+			Err:    err,
+			Time:   time.Now(),
+		})
+	}
+}
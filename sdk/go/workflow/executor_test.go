@@ -0,0 +1,280 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// recordingHandler записывает выполненные и компенсированные шаги
+type recordingHandler struct {
+	mu          sync.Mutex
+	executed    []int32
+	compensated []int32
+	failStep    int32
+}
+
+func (h *recordingHandler) Execute(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error) {
+	if step.Step == h.failStep {
+		return nil, errors.New("boom")
+	}
+	h.mu.Lock()
+	h.executed = append(h.executed, step.Step)
+	h.mu.Unlock()
+	return &StepResult{ResultID: step.Action + "-result"}, nil
+}
+
+func (h *recordingHandler) Compensate(ctx context.Context, step types.WorkflowStep, result *StepResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.compensated = append(h.compensated, step.Step)
+	h.mu.Unlock()
+	return nil
+}
+
+func purchaseWorkflow() *types.Workflow {
+	return &types.Workflow{Steps: []types.WorkflowStep{
+		{Step: 1, Action: "order_food", Domain: "commerce", Status: "pending", ResultID: "order-1"},
+		{Step: 2, Action: "process_payment", Domain: "payment", Status: "pending", DependsOn: []string{"order-1"}},
+		{Step: 3, Action: "set_delivery_address", Domain: "delivery", Status: "pending", DependsOn: []string{"order-1", "step-2"}},
+		{Step: 4, Action: "create_reminders", Domain: "notifications", Status: "pending", DependsOn: []string{"3"}},
+	}}
+}
+
+func TestValidate_Cycle(t *testing.T) {
+	wf := &types.Workflow{Steps: []types.WorkflowStep{
+		{Step: 1, Action: "a", DependsOn: []string{"3"}},
+		{Step: 2, Action: "b", DependsOn: []string{"1"}},
+		{Step: 3, Action: "c", DependsOn: []string{"2"}},
+	}}
+
+	if err := Validate(wf); !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle, got %v", err)
+	}
+}
+
+func TestValidate_MissingDependency(t *testing.T) {
+	wf := &types.Workflow{Steps: []types.WorkflowStep{
+		{Step: 1, Action: "a"},
+		{Step: 2, Action: "b", DependsOn: []string{"payment-456"}},
+	}}
+
+	if err := Validate(wf); !errors.Is(err, ErrMissingDependency) {
+		t.Errorf("Expected ErrMissingDependency, got %v", err)
+	}
+}
+
+func TestExecutor_RunsInDependencyOrder(t *testing.T) {
+	var events []StepEvent
+	exec := NewExecutor(ExecutorConfig{OnStepChange: func(ev StepEvent) { events = append(events, ev) }})
+
+	var order []int32
+	var mu sync.Mutex
+	handler := HandlerFunc(func(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error) {
+		mu.Lock()
+		order = append(order, step.Step)
+		mu.Unlock()
+		if step.Step == 3 && len(deps) != 2 {
+			t.Errorf("Expected 2 dependency results for step 3, got %d", len(deps))
+		}
+		return &StepResult{Data: map[string]interface{}{"ok": true}}, nil
+	})
+	for _, d := range []string{"commerce", "payment", "delivery", "notifications"} {
+		exec.HandleDomain(d, handler)
+	}
+
+	state, err := exec.Execute(context.Background(), purchaseWorkflow())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if state.Status != WorkflowCompleted {
+		t.Errorf("Expected workflow completed, got %s", state.Status)
+	}
+	if len(order) != 4 || order[0] != 1 || order[3] != 4 {
+		t.Errorf("Unexpected execution order: %v", order)
+	}
+	if res, _ := state.Step(1); res.Result.ResultID != "order-1" {
+		t.Errorf("Expected step ResultID to default to order-1, got %q", res.Result.ResultID)
+	}
+	// pending -> in_progress -> completed для каждого шага
+	if len(events) != 8 {
+		t.Errorf("Expected 8 status events, got %d", len(events))
+	}
+}
+
+func TestExecutor_ConcurrentSteps(t *testing.T) {
+	wf := &types.Workflow{Steps: []types.WorkflowStep{
+		{Step: 1, Action: "a"},
+		{Step: 2, Action: "a"},
+		{Step: 3, Action: "a"},
+	}}
+
+	var current, peak atomic.Int32
+	exec := NewExecutor(ExecutorConfig{MaxConcurrency: 2})
+	exec.HandleAction("a", HandlerFunc(func(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error) {
+		n := current.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		current.Add(-1)
+		return nil, nil
+	}))
+
+	if _, err := exec.Execute(context.Background(), wf); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("Expected peak concurrency 2, got %d", peak.Load())
+	}
+}
+
+func TestExecutor_CompensationOnFailure(t *testing.T) {
+	handler := &recordingHandler{failStep: 3}
+	exec := NewExecutor(ExecutorConfig{Compensate: true})
+	exec.HandleAction("order_food", handler)
+	exec.HandleAction("process_payment", handler)
+	exec.HandleAction("set_delivery_address", handler)
+	exec.HandleAction("create_reminders", handler)
+
+	state, err := exec.Execute(context.Background(), purchaseWorkflow())
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != 3 {
+		t.Fatalf("Expected StepError for step 3, got %v", err)
+	}
+	if state.Status != WorkflowCompensated {
+		t.Errorf("Expected workflow compensated, got %s", state.Status)
+	}
+	if st, _ := state.Step(4); st.Status != StatusSkipped {
+		t.Errorf("Expected step 4 skipped, got %s", st.Status)
+	}
+	if len(handler.compensated) != 2 || handler.compensated[0] != 2 || handler.compensated[1] != 1 {
+		t.Errorf("Expected compensation in reverse order [2 1], got %v", handler.compensated)
+	}
+}
+
+func TestExecutor_CompensationAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := &recordingHandler{}
+	exec := NewExecutor(ExecutorConfig{Compensate: true})
+	exec.HandleAction("order_food", handler)
+	exec.HandleAction("process_payment", HandlerFunc(func(ctx context.Context, step types.WorkflowStep, deps []StepResult) (*StepResult, error) {
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	exec.HandleAction("set_delivery_address", handler)
+	exec.HandleAction("create_reminders", handler)
+
+	state, err := exec.Execute(ctx, purchaseWorkflow())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if state.Status != WorkflowCompensated {
+		t.Errorf("Expected workflow compensated, got %s", state.Status)
+	}
+	if len(handler.compensated) != 1 || handler.compensated[0] != 1 {
+		t.Errorf("Expected step 1 to be compensated, got %v", handler.compensated)
+	}
+}
+
+func TestExecutor_ResumeFromSnapshot(t *testing.T) {
+	failing := &recordingHandler{failStep: 3}
+	exec := NewExecutor(ExecutorConfig{})
+	exec.HandleDomain("commerce", failing)
+	exec.HandleDomain("payment", failing)
+	exec.HandleDomain("delivery", failing)
+	exec.HandleDomain("notifications", failing)
+
+	state, err := exec.Execute(context.Background(), purchaseWorkflow())
+	if err == nil {
+		t.Fatal("Expected first run to fail")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var snapshot State
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+
+	healthy := &recordingHandler{}
+	exec.HandleDomain("commerce", healthy)
+	exec.HandleDomain("payment", healthy)
+	exec.HandleDomain("delivery", healthy)
+	exec.HandleDomain("notifications", healthy)
+
+	resumed, err := exec.Resume(context.Background(), purchaseWorkflow(), &snapshot)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if resumed.Status != WorkflowCompleted {
+		t.Errorf("Expected workflow completed, got %s", resumed.Status)
+	}
+	if len(healthy.executed) != 2 || healthy.executed[0] != 3 || healthy.executed[1] != 4 {
+		t.Errorf("Expected only steps [3 4] to run on resume, got %v", healthy.executed)
+	}
+}
+
+func TestExecutor_ResumeKeepsInProgress(t *testing.T) {
+	handler := &recordingHandler{}
+	exec := NewExecutor(ExecutorConfig{})
+	exec.HandleDomain("commerce", handler)
+	exec.HandleDomain("delivery", handler)
+	exec.HandleDomain("notifications", handler)
+
+	wf := purchaseWorkflow()
+	wf.Steps[0].Status = "success"
+	wf.Steps[1].Status = StatusInProgress
+
+	state, err := exec.Execute(context.Background(), wf)
+	if !errors.Is(err, ErrStepsInProgress) {
+		t.Fatalf("Expected ErrStepsInProgress, got %v", err)
+	}
+	if state.Status != WorkflowRunning {
+		t.Errorf("Expected workflow running, got %s", state.Status)
+	}
+	if st, _ := state.Step(2); st.Status != StatusInProgress {
+		t.Errorf("Expected step 2 to stay in_progress, got %s", st.Status)
+	}
+	if st, _ := state.Step(3); st.Status != StatusPending {
+		t.Errorf("Expected step 3 to wait for step 2, got %s", st.Status)
+	}
+	if len(handler.executed) != 0 {
+		t.Errorf("Expected no steps to run, got %v", handler.executed)
+	}
+
+	// Шаг 2 завершился на сервере: продолжаем с сохраненного снимка
+	wf.Steps[1].Status = StatusCompleted
+	state.Steps[2].Status = StatusCompleted
+	resumed, err := exec.Resume(context.Background(), wf, state)
+	if err != nil || resumed.Status != WorkflowCompleted {
+		t.Fatalf("Expected workflow completed, got %v: %v", resumed.Status, err)
+	}
+	if len(handler.executed) != 2 || handler.executed[0] != 3 || handler.executed[1] != 4 {
+		t.Errorf("Expected steps [3 4] to run, got %v", handler.executed)
+	}
+}
+
+func TestExecutor_NoHandler(t *testing.T) {
+	exec := NewExecutor(ExecutorConfig{})
+	if _, err := exec.Execute(context.Background(), purchaseWorkflow()); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected ErrNoHandler, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+var (
+	// ErrDuplicateStep возвращается, если номер шага встречается несколько раз
+	ErrDuplicateStep = errors.New("duplicate workflow step")
+	// ErrMissingDependency возвращается, если зависимость не соответствует ни одному шагу
+	ErrMissingDependency = errors.New("missing workflow dependency")
+	// ErrCycle возвращается, если зависимости шагов образуют цикл
+	ErrCycle = errors.New("workflow dependency cycle")
+	// ErrNoHandler возвращается, если для шага не зарегистрирован обработчик
+	ErrNoHandler = errors.New("no handler for workflow step")
+	// ErrStepsInProgress возвращается Resume, если workflow не может завершиться,
+	// пока выполняются шаги, начатые ранее (в статусе in_progress)
+	ErrStepsInProgress = errors.New("workflow steps are still in progress")
+)
+
+// graph представляет workflow как DAG шагов, проиндексированных по номеру
+type graph struct {
+	steps map[int32]types.WorkflowStep
+	order []int32           // номера шагов по возрастанию
+	deps  map[int32][]int32 // шаг -> шаги, от которых он зависит
+}
+
+// buildGraph строит DAG и проверяет его: уникальность номеров шагов,
+// разрешимость зависимостей и отсутствие циклов.
+//
+// Ссылка в DependsOn разрешается сначала как ResultID другого шага,
+// затем как номер шага ("2", "step-2" или "step:2").
+func buildGraph(wf *types.Workflow) (*graph, error) {
+	g := &graph{
+		steps: make(map[int32]types.WorkflowStep),
+		deps:  make(map[int32][]int32),
+	}
+	if wf == nil {
+		return g, nil
+	}
+
+	var errs []error
+	byResult := make(map[string]int32)
+	for _, step := range wf.Steps {
+		if _, exists := g.steps[step.Step]; exists {
+			errs = append(errs, fmt.Errorf("%w: step %d", ErrDuplicateStep, step.Step))
+			continue
+		}
+		g.steps[step.Step] = step
+		g.order = append(g.order, step.Step)
+		if step.ResultID != "" {
+			byResult[step.ResultID] = step.Step
+		}
+	}
+	sort.Slice(g.order, func(i, j int) bool { return g.order[i] < g.order[j] })
+
+	for _, n := range g.order {
+		for _, ref := range g.steps[n].DependsOn {
+			dep, ok := g.resolve(ref, byResult)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: step %d depends on %q", ErrMissingDependency, n, ref))
+				continue
+			}
+			g.deps[n] = append(g.deps[n], dep)
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		path := make([]string, len(cycle))
+		for i, n := range cycle {
+			path[i] = strconv.Itoa(int(n))
+		}
+		errs = append(errs, fmt.Errorf("%w: %s", ErrCycle, strings.Join(path, " -> ")))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return g, nil
+}
+
+// resolve разрешает ссылку на зависимость в номер шага
+func (g *graph) resolve(ref string, byResult map[string]int32) (int32, bool) {
+	if n, ok := byResult[ref]; ok {
+		return n, true
+	}
+	raw := strings.TrimPrefix(strings.TrimPrefix(ref, "step-"), "step:")
+	n, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if _, ok := g.steps[int32(n)]; !ok {
+		return 0, false
+	}
+	return int32(n), true
+}
+
+// findCycle ищет цикл обходом в глубину и возвращает его путь (nil, если циклов нет)
+func (g *graph) findCycle() []int32 {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	color := make(map[int32]int, len(g.order))
+	var stack []int32
+	var cycle []int32
+
+	var visit func(n int32) bool
+	visit = func(n int32) bool {
+		color[n] = visiting
+		stack = append(stack, n)
+		for _, dep := range g.deps[n] {
+			switch color[dep] {
+			case visiting:
+				for i, s := range stack {
+					if s == dep {
+						cycle = append(append([]int32{}, stack[i:]...), dep)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[n] = visited
+		return false
+	}
+
+	for _, n := range g.order {
+		if color[n] == unvisited && visit(n) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Validate проверяет, что шаги workflow образуют корректный DAG:
+// номера шагов уникальны, все зависимости разрешаются и циклов нет.
+func Validate(wf *types.Workflow) error {
+	_, err := buildGraph(wf)
+	return err
+}
+
+// SortedSteps возвращает копию шагов workflow, отсортированных по номеру шага
+func SortedSteps(wf *types.Workflow) []types.WorkflowStep {
+	if wf == nil || len(wf.Steps) == 0 {
+		return nil
+	}
+	steps := make([]types.WorkflowStep, len(wf.Steps))
+	copy(steps, wf.Steps)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Step < steps[j].Step })
+	return steps
+}
+
+// ReadySteps возвращает шаги в статусе pending, все зависимости которых завершены
+// (по статусам шагов в самом workflow), отсортированные по номеру шага.
+func ReadySteps(wf *types.Workflow) ([]types.WorkflowStep, error) {
+	g, err := buildGraph(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []types.WorkflowStep
+	for _, n := range g.order {
+		step := g.steps[n]
+		if NormalizeStatus(step.Status) != StatusPending {
+			continue
+		}
+		depsDone := true
+		for _, dep := range g.deps[n] {
+			if NormalizeStatus(g.steps[dep].Status) != StatusCompleted {
+				depsDone = false
+				break
+			}
+		}
+		if depsDone {
+			ready = append(ready, step)
+		}
+	}
+	return ready, nil
+}
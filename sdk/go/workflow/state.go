@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Статусы шагов workflow
+const (
+	StatusPending     = "pending"
+	StatusInProgress  = "in_progress"
+	StatusCompleted   = "completed"
+	StatusFailed      = "failed"
+	StatusSkipped     = "skipped"     // не выполнялся из-за сбоя другого шага
+	StatusCompensated = "compensated" // результат отменен компенсацией
+)
+
+// Статусы workflow в целом
+const (
+	WorkflowRunning     = "running"
+	WorkflowCompleted   = "completed"
+	WorkflowFailed      = "failed"
+	WorkflowCompensated = "compensated"
+)
+
+// transitions допустимые переходы статусов шага
+var transitions = map[string][]string{
+	StatusPending:    {StatusInProgress, StatusSkipped},
+	StatusInProgress: {StatusCompleted, StatusFailed},
+	StatusCompleted:  {StatusCompensated},
+}
+
+// CanTransition проверяет, допустим ли переход статуса шага from -> to
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeStatus приводит статус шага из ответа сервера к статусам executor.
+// "success" трактуется как completed, пустой статус - как pending.
+func NormalizeStatus(status string) string {
+	switch status {
+	case "", StatusPending:
+		return StatusPending
+	case "success", StatusCompleted:
+		return StatusCompleted
+	}
+	return status
+}
+
+// StepResult содержит результат выполнения шага
+type StepResult struct {
+	Step     int32                  `json:"step"`
+	ResultID string                 `json:"result_id,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// StepState содержит состояние выполнения шага
+type StepState struct {
+	Step        int32       `json:"step"`
+	Action      string      `json:"action"`
+	Domain      string      `json:"domain"`
+	Status      string      `json:"status"`
+	Result      *StepResult `json:"result,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   int64       `json:"started_at,omitempty"`   // Unix timestamp в миллисекундах
+	CompletedAt int64       `json:"completed_at,omitempty"` // Unix timestamp в миллисекундах
+}
+
+// State представляет снимок выполнения workflow.
+// Сериализуется в JSON для сохранения и последующего Executor.Resume.
+type State struct {
+	Status         string               `json:"status"`
+	Steps          map[int32]*StepState `json:"steps"`
+	CompletedOrder []int32              `json:"completed_order,omitempty"` // порядок завершения шагов (для компенсации)
+	UpdatedAt      int64                `json:"updated_at"`
+}
+
+// Step возвращает состояние шага по номеру
+func (s *State) Step(step int32) (*StepState, bool) {
+	st, ok := s.Steps[step]
+	return st, ok
+}
+
+// Results возвращает результаты завершенных шагов, отсортированные по номеру шага
+func (s *State) Results() []StepResult {
+	var results []StepResult
+	for _, st := range s.Steps {
+		if st.Status == StatusCompleted && st.Result != nil {
+			results = append(results, *st.Result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Step < results[j].Step })
+	return results
+}
+
+// newState создает состояние для графа. Статусы берутся из снимка (если он есть),
+// иначе из статусов шагов в ответе сервера. Неудачные, пропущенные и
+// компенсированные шаги возвращаются в pending для повторного выполнения.
+// Шаги in_progress остаются in_progress: они могли выполниться, поэтому
+// не запускаются повторно, а зависящие от них шаги ждут их завершения.
+func newState(g *graph, snapshot *State) *State {
+	state := &State{
+		Status:    WorkflowRunning,
+		Steps:     make(map[int32]*StepState, len(g.order)),
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+
+	for _, n := range g.order {
+		step := g.steps[n]
+		st := &StepState{
+			Step:   n,
+			Action: step.Action,
+			Domain: step.Domain,
+			Status: NormalizeStatus(step.Status),
+		}
+		if st.Status == StatusCompleted {
+			st.Result = &StepResult{Step: n, ResultID: step.ResultID}
+		}
+		if snapshot != nil {
+			if prev, ok := snapshot.Steps[n]; ok {
+				restored := *prev
+				restored.Action = step.Action
+				restored.Domain = step.Domain
+				st = &restored
+			}
+		}
+		if st.Status != StatusCompleted && st.Status != StatusInProgress {
+			st.Status = StatusPending
+			st.Result = nil
+			st.Error = ""
+			st.StartedAt = 0
+			st.CompletedAt = 0
+		}
+		state.Steps[n] = st
+	}
+
+	if snapshot != nil {
+		for _, n := range snapshot.CompletedOrder {
+			if st, ok := state.Steps[n]; ok && st.Status == StatusCompleted {
+				state.CompletedOrder = append(state.CompletedOrder, n)
+			}
+		}
+	}
+	return state
+}
+
+// transition меняет статус шага с проверкой допустимости перехода
+func (s *State) transition(step int32, to string) (string, error) {
+	st, ok := s.Steps[step]
+	if !ok {
+		return "", fmt.Errorf("unknown workflow step %d", step)
+	}
+	from := st.Status
+	if !CanTransition(from, to) {
+		return from, fmt.Errorf("invalid transition of step %d: %s -> %s", step, from, to)
+	}
+
+	now := time.Now().UnixMilli()
+	st.Status = to
+	switch to {
+	case StatusInProgress:
+		st.StartedAt = now
+	case StatusCompleted:
+		st.CompletedAt = now
+		s.CompletedOrder = append(s.CompletedOrder, step)
+	case StatusFailed:
+		st.CompletedAt = now
+	}
+	s.UpdatedAt = now
+	return from, nil
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// resource описывает коллекцию admin API с CRUD-маршрутами
+type resource[T any] struct {
+	collection string
+	name       string
+	id         func(*T) *string
+	// times возвращает поля created_at/updated_at, если они есть у типа
+	times func(*T) (created, updated *string)
+	// filter отбирает элементы списка по параметрам запроса
+	filter func(r *http.Request, v *T) bool
+	// saved вызывается после записи под s.mu
+	saved func(ctx context.Context, v *T) error
+}
+
+// routes возвращает маршруты list/get/create/update/delete под base
+func (res resource[T]) routes(s *Server, base string) []route {
+	item := base + "/{id}"
+	return []route{
+		{method: http.MethodGet, pattern: base, handle: res.list(s)},
+		{method: http.MethodPost, pattern: base, status: http.StatusCreated, handle: res.create(s)},
+		{method: http.MethodGet, pattern: item, handle: res.get(s)},
+		{method: http.MethodPut, pattern: item, handle: res.update(s)},
+		{method: http.MethodDelete, pattern: item, status: http.StatusNoContent, handle: res.delete(s)},
+	}
+}
+
+func (res resource[T]) notFound(err error) error {
+	return notFound(err, "RESOURCE_NOT_FOUND", res.name+" not found")
+}
+
+func (res resource[T]) list(s *Server) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		items, err := loadAll[T](r.Context(), s.store, res.collection)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]*T, 0, len(items))
+		for _, v := range items {
+			if res.filter == nil || res.filter(r, v) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+}
+
+func (res resource[T]) get(s *Server) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		v := new(T)
+		if err := s.load(r.Context(), res.collection, param(r, "id"), v); err != nil {
+			return nil, res.notFound(err)
+		}
+		return v, nil
+	}
+}
+
+func (res resource[T]) create(s *Server) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		v := new(T)
+		if err := decode(r, v); err != nil {
+			return nil, err
+		}
+		id := res.id(v)
+		if *id == "" {
+			*id = uuid.New().String()
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, err := s.store.Get(r.Context(), res.collection, *id)
+		if err == nil {
+			return nil, &types.ErrorDetail{Code: "DUPLICATE_RESOURCE", Type: "CONFLICT", Message: fmt.Sprintf("%s %s already exists", res.name, *id), Field: "id"}
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+		if res.times != nil {
+			created, updated := res.times(v)
+			*created = ""
+			stamp(created, updated)
+		}
+		return v, res.write(r.Context(), s, *id, v)
+	}
+}
+
+func (res resource[T]) update(s *Server) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		v := new(T)
+		if err := decode(r, v); err != nil {
+			return nil, err
+		}
+		id := param(r, "id")
+		*res.id(v) = id
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		existing := new(T)
+		if err := s.load(r.Context(), res.collection, id, existing); err != nil {
+			return nil, res.notFound(err)
+		}
+		if res.times != nil {
+			created, updated := res.times(v)
+			previous, _ := res.times(existing)
+			*created = *previous
+			stamp(created, updated)
+		}
+		return v, res.write(r.Context(), s, id, v)
+	}
+}
+
+func (res resource[T]) write(ctx context.Context, s *Server, id string, v *T) error {
+	if err := s.save(ctx, res.collection, id, v); err != nil {
+		return err
+	}
+	if res.saved != nil {
+		return res.saved(ctx, v)
+	}
+	return nil
+}
+
+func (res resource[T]) delete(s *Server) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		if err := s.store.Delete(r.Context(), res.collection, param(r, "id")); err != nil {
+			return nil, res.notFound(err)
+		}
+		return nil, nil
+	}
+}
+
+var (
+	promptResource = resource[types.PromptConfig]{
+		collection: colPrompts,
+		name:       "Prompt",
+		id:         func(p *types.PromptConfig) *string { return &p.ID },
+		filter: func(r *http.Request, p *types.PromptConfig) bool {
+			domain := r.URL.Query().Get("domain")
+			return domain == "" || p.Domain == domain
+		},
+	}
+	domainResource = resource[types.DomainConfig]{
+		collection: colDomains,
+		name:       "Domain",
+		id:         func(d *types.DomainConfig) *string { return &d.ID },
+		times:      func(d *types.DomainConfig) (*string, *string) { return &d.CreatedAt, &d.UpdatedAt },
+	}
+	integrationResource = resource[types.IntegrationConfig]{
+		collection: colIntegrations,
+		name:       "Integration",
+		id:         func(i *types.IntegrationConfig) *string { return &i.ID },
+		filter: func(r *http.Request, i *types.IntegrationConfig) bool {
+			typ := r.URL.Query().Get("type")
+			return typ == "" || i.Type == typ
+		},
+	}
+)
+
+// frontendResource активирует сохраненную активную конфигурацию,
+// снимая флаг с остальных
+func (s *Server) frontendResource() resource[types.FrontendConfig] {
+	return resource[types.FrontendConfig]{
+		collection: colFrontendConfigs,
+		name:       "Frontend configuration",
+		id:         func(f *types.FrontendConfig) *string { return &f.ID },
+		times:      func(f *types.FrontendConfig) (*string, *string) { return &f.CreatedAt, &f.UpdatedAt },
+		saved: func(ctx context.Context, f *types.FrontendConfig) error {
+			if !f.Active {
+				return nil
+			}
+			return s.activateFrontendConfig(ctx, f.ID)
+		},
+	}
+}
+
+// message ответ admin API без данных
+func message(text string) map[string]string {
+	return map[string]string{"message": text}
+}
+
+func (s *Server) getAIConfig(r *http.Request) (interface{}, error) {
+	var config types.AIConfig
+	if err := s.load(r.Context(), colSettings, settingsAI, &config); err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (s *Server) updateAIConfig(r *http.Request) (interface{}, error) {
+	var config types.AIConfig
+	if err := decode(r, &config); err != nil {
+		return nil, err
+	}
+	if err := s.save(r.Context(), colSettings, settingsAI, &config); err != nil {
+		return nil, err
+	}
+	return message("AI configuration updated successfully"), nil
+}
+
+// defaultDomains домены initialize-default. Создаются выключенными: у них
+// нет Endpoint, его задает администратор перед включением домена.
+var defaultDomains = []types.DomainConfig{
+	{ID: "commerce", Name: "Покупки", Type: "commerce", Priority: 80, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"купить", "цена", "магазин", "товар", "заказ", "buy", "price", "shop"}},
+	{ID: "recipes", Name: "Рецепты", Type: "recipes", Priority: 60, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"рецепт", "готовить", "блюдо", "ингредиенты", "recipe", "cook"}},
+	{ID: "travel", Name: "Путешествия", Type: "travel", Priority: 70, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"отель", "билет", "путешествие", "бронировать", "hotel", "flight", "travel"}},
+	{ID: "knowledge", Name: "Знания", Type: "knowledge", Priority: 40, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"что такое", "как", "почему", "объясни", "what is", "how", "why"}},
+	{ID: "health", Name: "Здоровье", Type: "health", Priority: 50, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"врач", "симптом", "лекарство", "здоровье", "doctor", "health"}},
+	{ID: "finance", Name: "Финансы", Type: "finance", Priority: 50, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"курс", "кредит", "вклад", "инвестиции", "exchange rate", "loan", "invest"}},
+	{ID: "education", Name: "Образование", Type: "education", Priority: 40, Timeout: 10, RetryCount: 1,
+		Keywords: []string{"курс обучения", "учить", "урок", "экзамен", "learn", "lesson", "course"}},
+}
+
+// InitializeDefaultDomains создает отсутствующие домены по умолчанию
+// (commerce, recipes, travel, knowledge, health, finance, education)
+// и возвращает число созданных
+func (s *Server) InitializeDefaultDomains(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := 0
+	for _, d := range defaultDomains {
+		_, err := s.store.Get(ctx, colDomains, d.ID)
+		if err == nil {
+			continue
+		}
+		if !isNotFound(err) {
+			return created, err
+		}
+		d.Keywords = append([]string(nil), d.Keywords...)
+		stamp(&d.CreatedAt, &d.UpdatedAt)
+		if err := s.save(ctx, colDomains, d.ID, &d); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+func (s *Server) initializeDefaultDomains(r *http.Request) (interface{}, error) {
+	created, err := s.InitializeDefaultDomains(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"message": "Default domains initialized successfully", "domains_created": created}, nil
+}
+
+// domainPart возвращает маршруты GET/PUT части конфигурации домена.
+// Ответы в формате спецификации: {"data": ...} и {"message": ...}.
+func (s *Server) domainPart(name, title string, field func(*types.DomainConfig) interface{}) []route {
+	pattern := apiPrefix + "/admin/domains/{id}/" + name
+	get := func(r *http.Request) (interface{}, error) {
+		var d types.DomainConfig
+		if err := s.load(r.Context(), colDomains, param(r, "id"), &d); err != nil {
+			return nil, domainResource.notFound(err)
+		}
+		return map[string]interface{}{"data": field(&d)}, nil
+	}
+	put := func(r *http.Request) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var d types.DomainConfig
+		if err := s.load(r.Context(), colDomains, param(r, "id"), &d); err != nil {
+			return nil, domainResource.notFound(err)
+		}
+		if err := decode(r, field(&d)); err != nil {
+			return nil, err
+		}
+		stamp(&d.CreatedAt, &d.UpdatedAt)
+		if err := s.save(r.Context(), colDomains, d.ID, &d); err != nil {
+			return nil, err
+		}
+		return message("Domain " + title + " updated successfully"), nil
+	}
+	return []route{
+		{method: http.MethodGet, pattern: pattern, handle: get},
+		{method: http.MethodPut, pattern: pattern, handle: put},
+	}
+}
+
+func (s *Server) getActiveFrontendConfig(r *http.Request) (interface{}, error) {
+	fc, err := s.activeFrontendConfig(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"data": fc}, nil
+}
+
+func (s *Server) setActiveFrontendConfig(r *http.Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.activateFrontendConfig(r.Context(), param(r, "id")); err != nil {
+		return nil, err
+	}
+	return message("Frontend configuration set as active"), nil
+}
+
+// adminVersion отвечает строками, как ожидает AdminClient.GetVersion
+func (s *Server) adminVersion(*http.Request) (interface{}, error) {
+	return map[string]string{
+		"protocol_version": s.config.ProtocolVersion,
+		"server_version":   s.config.ServerVersion,
+		"api_version":      APIVersion,
+		"storage":          fmt.Sprintf("%T", s.store),
+	}, nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/analytics"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Параметры аналитики
+const (
+	defaultEventsLimit = 50
+	maxEventsLimit     = 1000
+	defaultStatsDays   = 7
+	maxTopEvents       = 10
+	statsCacheTTL      = 60 // секунд
+)
+
+func (s *Server) logEvent(r *http.Request) (interface{}, error) {
+	var req types.LogEventRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.EventType == "" {
+		return nil, required("event_type")
+	}
+	event := &types.AnalyticsEvent{
+		ID:        uuid.New().String(),
+		EventType: req.EventType,
+		UserID:    req.UserID,
+		TenantID:  req.TenantID,
+		Data:      req.Data,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if p := requestPrincipal(r); p != nil {
+		if event.UserID == "" {
+			event.UserID = p.UserID
+		}
+		if event.TenantID == "" {
+			event.TenantID = p.TenantID
+		}
+	}
+	if err := s.save(r.Context(), colEvents, event.ID, event); err != nil {
+		return nil, err
+	}
+	return &types.LogEventResponse{EventID: event.ID, Message: "Event logged successfully", Timestamp: event.Timestamp}, nil
+}
+
+func (s *Server) getEvents(r *http.Request) (interface{}, error) {
+	limit, err := queryInt(r, "limit", defaultEventsLimit, maxEventsLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := queryInt(r, "offset", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	events, err := loadAll[types.AnalyticsEvent](r.Context(), s.store, colEvents)
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	filtered := make([]types.AnalyticsEvent, 0, len(events))
+	for _, e := range events {
+		if (query.Get("event_type") == "" || e.EventType == query.Get("event_type")) &&
+			(query.Get("user_id") == "" || e.UserID == query.Get("user_id")) {
+			filtered = append(filtered, *e)
+		}
+	}
+	return &types.GetEventsResponse{
+		Events: page(filtered, offset, limit),
+		Total:  int32(len(filtered)),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}, nil
+}
+
+// getStats считает статистику событий за последние days дней тем же
+// analytics.Aggregator, что и клиентская агрегация SDK
+func (s *Server) getStats(r *http.Request) (interface{}, error) {
+	days, err := queryInt(r, "days", defaultStatsDays, 365)
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		days = defaultStatsDays
+	}
+	events, err := loadAll[types.AnalyticsEvent](r.Context(), s.store, colEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.URL.Query()
+	agg := analytics.NewAggregator(analytics.AggregatorConfig{Days: int32(days), TopEvents: maxTopEvents})
+	for _, e := range events {
+		if (query.Get("user_id") != "" && e.UserID != query.Get("user_id")) ||
+			(query.Get("tenant_id") != "" && e.TenantID != query.Get("tenant_id")) {
+			continue
+		}
+		agg.Add(*e)
+	}
+	return statsResponse{agg.Stats()}, nil
+}
+
+// statsResponse добавляет cache_info в метаданные ответа статистики,
+// чтобы клиент с CacheConfig кэшировал ее на statsCacheTTL
+type statsResponse struct {
+	stats *types.AnalyticsStats
+}
+
+func (s statsResponse) respond(w http.ResponseWriter, r *http.Request) {
+	metadata := middleware.ResponseMetadata(r.Context())
+	metadata.CacheInfo = &types.CacheInfo{CacheTTL: statsCacheTTL}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"metadata": metadata, "data": s.stats})
+}
@@ -0,0 +1,341 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// TokenIssuer значение claim iss токенов сервера
+const TokenIssuer = "nexus-server"
+
+// Типы токенов (claim typ)
+const (
+	tokenAccess  = "access"
+	tokenRefresh = "refresh"
+)
+
+// Роли пользователей
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// passwordIterations число итераций PBKDF2 для паролей
+const passwordIterations = 50000
+
+// minPasswordLength минимальная длина пароля (openapi.yaml)
+const minPasswordLength = 8
+
+// principal аутентифицированный пользователь запроса
+type principal struct {
+	UserID   string
+	Email    string
+	TenantID string
+	Roles    []string
+}
+
+type principalKey struct{}
+
+func withPrincipal(ctx context.Context, p *principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// requestPrincipal возвращает пользователя запроса или nil
+func requestPrincipal(r *http.Request) *principal {
+	p, _ := r.Context().Value(principalKey{}).(*principal)
+	return p
+}
+
+// userID возвращает ID пользователя запроса ("" для анонимного)
+func userID(r *http.Request) string {
+	if p := requestPrincipal(r); p != nil {
+		return p.UserID
+	}
+	return ""
+}
+
+func (p *principal) hasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// userRecord пользователь в хранилище (ключ - нормализованный email)
+type userRecord struct {
+	Profile      types.UserProfile `json:"profile"`
+	TenantID     string            `json:"tenant_id,omitempty"`
+	Bio          string            `json:"bio,omitempty"`
+	PasswordHash string            `json:"password_hash"`
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// authenticate проверяет Bearer-токен в соответствии с access маршрута
+func (s *Server) authenticate(r *http.Request, a access) (*principal, *types.ErrorDetail) {
+	raw := bearerToken(r)
+	if a == accessPublic || (raw == "" && !s.config.RequireAuth && a != accessUser) {
+		return nil, nil
+	}
+	if raw == "" {
+		return nil, &types.ErrorDetail{Code: "AUTHENTICATION_FAILED", Type: "AUTHENTICATION_ERROR", Message: "Bearer token is required"}
+	}
+	claims, detail := s.verifyToken(raw, tokenAccess)
+	if detail != nil {
+		return nil, detail
+	}
+	p := &principal{UserID: claims.String("sub"), Email: claims.String("email"), TenantID: claims.String("tenant_id")}
+	p.Roles = claims.Strings("roles")
+	if a == accessAdmin && s.config.RequireAuth && !p.hasRole(RoleAdmin) {
+		return nil, &types.ErrorDetail{Code: "INSUFFICIENT_PERMISSIONS", Type: "AUTHORIZATION_ERROR", Message: "Admin role is required"}
+	}
+	return p, nil
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func (s *Server) register(r *http.Request) (interface{}, error) {
+	var req types.RegisterUserRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	email := normalizeEmail(req.Email)
+	switch {
+	case email == "":
+		return nil, required("email")
+	case !strings.Contains(email, "@"):
+		return nil, invalid("email", "Invalid email address")
+	case len(req.Password) < minPasswordLength:
+		return nil, &types.ErrorDetail{Code: "FIELD_TOO_SHORT", Type: "VALIDATION_ERROR", Message: fmt.Sprintf("Password must be at least %d characters", minPasswordLength), Field: "password"}
+	}
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{RoleUser}
+	if s.admins[email] {
+		roles = append(roles, RoleAdmin)
+	}
+	user := &userRecord{
+		Profile: types.UserProfile{
+			ID:        uuid.New().String(),
+			Email:     email,
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			Status:    "active",
+			Roles:     roles,
+			CreatedAt: time.Now().Unix(),
+		},
+		TenantID:     req.TenantID,
+		PasswordHash: hash,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.store.Get(r.Context(), colUsers, email); err == nil {
+		return nil, &types.ErrorDetail{Code: "DUPLICATE_RESOURCE", Type: "CONFLICT", Message: "User with this email already exists", Field: "email"}
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+	if err := s.save(r.Context(), colUsers, email, user); err != nil {
+		return nil, err
+	}
+	return &types.RegisterUserResponse{UserID: user.Profile.ID, Message: "User registered successfully"}, nil
+}
+
+func (s *Server) login(r *http.Request) (interface{}, error) {
+	var req types.LoginRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	failed := &types.ErrorDetail{Code: "AUTHENTICATION_FAILED", Type: "AUTHENTICATION_ERROR", Message: "Invalid email or password"}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var user userRecord
+	if err := s.load(r.Context(), colUsers, normalizeEmail(req.Email), &user); err != nil {
+		if isNotFound(err) {
+			return nil, failed
+		}
+		return nil, err
+	}
+	if !checkPassword(user.PasswordHash, req.Password) {
+		return nil, failed
+	}
+	user.Profile.LastLoginAt = time.Now().Unix()
+	if err := s.save(r.Context(), colUsers, user.Profile.Email, &user); err != nil {
+		return nil, err
+	}
+
+	access, err := s.issueToken(&user, tokenAccess, s.config.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.issueToken(&user, tokenRefresh, s.config.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &types.LoginResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int32(s.config.AccessTokenTTL.Seconds()),
+		User:         &user.Profile,
+	}, nil
+}
+
+func (s *Server) refresh(r *http.Request) (interface{}, error) {
+	var req types.RefreshTokenRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.RefreshToken == "" {
+		return nil, required("refresh_token")
+	}
+	claims, detail := s.verifyToken(req.RefreshToken, tokenRefresh)
+	if detail != nil {
+		return nil, detail
+	}
+	var user userRecord
+	if err := s.load(r.Context(), colUsers, claims.String("email"), &user); err != nil || user.Profile.ID != claims.String("sub") {
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		return nil, &types.ErrorDetail{Code: "INVALID_TOKEN", Type: "AUTHENTICATION_ERROR", Message: "User no longer exists"}
+	}
+	access, err := s.issueToken(&user, tokenAccess, s.config.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &types.RefreshTokenResponse{AccessToken: access, TokenType: "Bearer", ExpiresIn: int32(s.config.AccessTokenTTL.Seconds())}, nil
+}
+
+func (s *Server) getProfile(r *http.Request) (interface{}, error) {
+	var user userRecord
+	if err := s.load(r.Context(), colUsers, requestPrincipal(r).Email, &user); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "User not found")
+	}
+	return &user.Profile, nil
+}
+
+func (s *Server) updateProfile(r *http.Request) (interface{}, error) {
+	var req types.UpdateProfileRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var user userRecord
+	email := requestPrincipal(r).Email
+	if err := s.load(r.Context(), colUsers, email, &user); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "User not found")
+	}
+	if req.FirstName != "" {
+		user.Profile.FirstName = req.FirstName
+	}
+	if req.LastName != "" {
+		user.Profile.LastName = req.LastName
+	}
+	if req.Bio != "" {
+		user.Bio = req.Bio
+	}
+	if err := s.save(r.Context(), colUsers, email, &user); err != nil {
+		return nil, err
+	}
+	return &user.Profile, nil
+}
+
+// issueToken выпускает JWT пользователя. Claims совместимы с
+// types.UserProfile: sub, email, given_name, family_name, roles.
+func (s *Server) issueToken(user *userRecord, typ string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.Claims{
+		"iss":         TokenIssuer,
+		"sub":         user.Profile.ID,
+		"email":       user.Profile.Email,
+		"given_name":  user.Profile.FirstName,
+		"family_name": user.Profile.LastName,
+		"roles":       user.Profile.Roles,
+		"typ":         typ,
+		"jti":         uuid.New().String(),
+		"iat":         now.Unix(),
+		"exp":         now.Add(ttl).Unix(),
+	}
+	if user.TenantID != "" {
+		claims["tenant_id"] = user.TenantID
+	}
+	return jwt.Sign(claims, "HS256", s.secret)
+}
+
+// verifyToken проверяет подпись, срок действия, издателя и тип токена
+func (s *Server) verifyToken(raw, typ string) (jwt.Claims, *types.ErrorDetail) {
+	invalid := &types.ErrorDetail{Code: "INVALID_TOKEN", Type: "AUTHENTICATION_ERROR", Message: "Invalid token"}
+	token, err := jwt.Parse(raw)
+	if err != nil || token.Algorithm != "HS256" || token.Verify(s.secret) != nil {
+		return nil, invalid
+	}
+	if err := token.Claims.Validate(jwt.Expectations{Issuer: TokenIssuer}); err != nil {
+		if errors.Is(err, jwt.ErrExpired) {
+			return nil, &types.ErrorDetail{Code: "TOKEN_EXPIRED", Type: "AUTHENTICATION_ERROR", Message: "Token expired"}
+		}
+		return nil, invalid
+	}
+	if token.Claims.String("typ") != typ {
+		return nil, invalid
+	}
+	return token.Claims, nil
+}
+
+// hashPassword возвращает хеш пароля в формате pbkdf2-sha256$<итерации>$<соль>$<ключ>
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := pbkdf2.Key([]byte(password), salt, passwordIterations, 32, sha256.New)
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", passwordIterations, hex.EncodeToString(salt), hex.EncodeToString(key)), nil
+}
+
+func checkPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err1 := hex.DecodeString(parts[2])
+	want, err2 := hex.DecodeString(parts[3])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	got := pbkdf2.Key([]byte(password), salt, iterations, len(want), sha256.New)
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
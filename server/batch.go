@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Ограничения batch
+const (
+	MaxBatchRequests = 100
+	batchConcurrency = 10
+)
+
+// Статусы batch
+const (
+	batchCompleted = "completed"
+	batchCancelled = "cancelled"
+)
+
+// runningBatch выполняющийся batch
+type runningBatch struct {
+	owner  string
+	cancel context.CancelFunc
+}
+
+// batchRecord batch в хранилище
+type batchRecord struct {
+	OwnerID  string               `json:"owner_id,omitempty"`
+	Status   string               `json:"status"`
+	Response *types.BatchResponse `json:"response"`
+}
+
+// executeBatch выполняет запросы batch не более чем по batchConcurrency
+// одновременно. Ответ неудачного запроса - null, как в локальном batch
+// клиента. Пока batch выполняется, его можно отменить через /cancel.
+func (s *Server) executeBatch(r *http.Request) (interface{}, error) {
+	var req types.BatchRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Requests) == 0 {
+		return nil, required("requests")
+	}
+	if len(req.Requests) > MaxBatchRequests {
+		return nil, invalid("requests", fmt.Sprintf("Batch cannot contain more than %d requests", MaxBatchRequests))
+	}
+	if req.BatchID == "" {
+		req.BatchID = uuid.New().String()
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if err := s.startBatch(ctx, req.BatchID, &runningBatch{owner: userID(r), cancel: cancel}); err != nil {
+		return nil, err
+	}
+	defer s.finishBatch(req.BatchID)
+
+	start := time.Now()
+	p := requestPrincipal(r)
+	metadata := middleware.RequestMetadata(r.Context())
+	responses := make([]*types.ExecuteTemplateResponse, len(req.Requests))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range req.Requests {
+		if item == nil {
+			continue
+		}
+		if item.Options == nil && req.BatchOptions != nil {
+			options := *req.BatchOptions
+			item.Options = &options
+		}
+		if item.Metadata == nil {
+			item.Metadata = metadata
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item *types.ExecuteTemplateRequest) {
+			defer func() { <-sem; wg.Done() }()
+			if ctx.Err() != nil {
+				return
+			}
+			if resp, err := s.execute(ctx, p, item); err == nil {
+				responses[i] = resp
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	completed := time.Now()
+	batchMeta := &types.BatchMetadata{
+		TotalRequests:         int32(len(req.Requests)),
+		StartedAt:             start.Unix(),
+		CompletedAt:           completed.Unix(),
+		TotalProcessingTimeMS: int32(completed.Sub(start).Milliseconds()),
+	}
+	for _, resp := range responses {
+		if resp != nil && resp.Status != ExecutionFailed {
+			batchMeta.SuccessfulRequests++
+		} else {
+			batchMeta.FailedRequests++
+		}
+	}
+	result := &types.BatchResponse{BatchID: req.BatchID, Responses: responses, BatchMetadata: batchMeta}
+
+	record := &batchRecord{OwnerID: userID(r), Status: batchCompleted, Response: result}
+	if errors.Is(ctx.Err(), context.Canceled) && r.Context().Err() == nil {
+		record.Status = batchCancelled
+	}
+	if err := s.save(context.WithoutCancel(ctx), colBatches, req.BatchID, record); err != nil {
+		return nil, err
+	}
+	s.dispatch(record.OwnerID, EventBatchCompleted, map[string]interface{}{
+		"batch_id":            result.BatchID,
+		"status":              record.Status,
+		"total_requests":      batchMeta.TotalRequests,
+		"successful_requests": batchMeta.SuccessfulRequests,
+		"failed_requests":     batchMeta.FailedRequests,
+	})
+	return result, nil
+}
+
+// startBatch регистрирует выполняющийся batch; ID должен быть уникальным
+func (s *Server) startBatch(ctx context.Context, id string, batch *runningBatch) error {
+	s.batchesMu.Lock()
+	defer s.batchesMu.Unlock()
+	_, running := s.batches[id]
+	if !running {
+		_, err := s.store.Get(ctx, colBatches, id)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		running = err == nil
+	}
+	if running {
+		return &types.ErrorDetail{Code: "DUPLICATE_RESOURCE", Type: "CONFLICT", Message: "Batch with this ID already exists", Field: "batch_id"}
+	}
+	s.batches[id] = batch
+	return nil
+}
+
+func (s *Server) finishBatch(id string) {
+	s.batchesMu.Lock()
+	delete(s.batches, id)
+	s.batchesMu.Unlock()
+}
+
+func (s *Server) loadBatch(r *http.Request) (*batchRecord, error) {
+	var record batchRecord
+	if err := s.load(r.Context(), colBatches, param(r, "id"), &record); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "Batch not found")
+	}
+	if record.OwnerID != userID(r) {
+		return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "Batch not found"}
+	}
+	return &record, nil
+}
+
+func (s *Server) batchStatus(r *http.Request) (interface{}, error) {
+	record, err := s.loadBatch(r)
+	if err != nil {
+		return nil, err
+	}
+	return record.Response, nil
+}
+
+// cancelBatch отменяет выполняющийся batch. Запросы, которые уже
+// выполняются, завершаются с отменой контекста; batch отвечает
+// исполнившему его клиенту частичным результатом.
+func (s *Server) cancelBatch(r *http.Request) (interface{}, error) {
+	id := param(r, "id")
+	s.batchesMu.Lock()
+	batch, running := s.batches[id]
+	s.batchesMu.Unlock()
+	if running && batch.owner == userID(r) {
+		batch.cancel()
+		return &types.CancelBatchResponse{BatchID: id, Status: batchCancelled, Message: "Batch cancellation requested"}, nil
+	}
+	record, err := s.loadBatch(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, &types.ErrorDetail{Code: "RESOURCE_CONFLICT", Type: "CONFLICT", Message: fmt.Sprintf("Batch is already %s", record.Status)}
+}
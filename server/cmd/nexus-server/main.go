@@ -0,0 +1,140 @@
+// Команда nexus-server запускает эталонный сервер Nexus Protocol.
+//
+// Использование:
+//
+//	nexus-server [-addr :8080] [-storage memory|sql] [-config ./manifests] [-init-domains]
+//
+// Ключ подписи токенов берется из -jwt-secret или переменной окружения
+// NEXUS_JWT_SECRET, порт по умолчанию - из PORT. Хранилище sql работает
+// через database/sql: в сборку входит драйвер SQLite на чистом Go
+// (modernc.org/sqlite, имя "sqlite"), другие драйверы подключаются пустым
+// импортом; -db-driver и -db-dsn передаются в sql.Open.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/configsync"
+
+	"github.com/pro-deploy/nexus-protocol/server"
+	"github.com/pro-deploy/nexus-protocol/server/storage"
+	_ "modernc.org/sqlite"
+)
+
+// Переменные окружения
+const (
+	envPort      = "PORT"
+	envJWTSecret = "NEXUS_JWT_SECRET"
+)
+
+// shutdownTimeout время на завершение активных запросов при остановке
+const shutdownTimeout = 15 * time.Second
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("nexus-server: %v", err)
+	}
+}
+
+func run() error {
+	addr := ":8080"
+	if port := os.Getenv(envPort); port != "" {
+		addr = ":" + port
+	}
+
+	fs := flag.NewFlagSet("nexus-server", flag.ExitOnError)
+	fs.StringVar(&addr, "addr", addr, "адрес HTTP сервера")
+	jwtSecret := fs.String("jwt-secret", os.Getenv(envJWTSecret), "ключ подписи токенов (по умолчанию случайный)")
+	requireAuth := fs.Bool("require-auth", false, "требовать токен для API и роль admin для /admin")
+	adminEmails := fs.String("admin-email", "", "email администраторов через запятую")
+	storageKind := fs.String("storage", "memory", "хранилище: memory или sql")
+	dbDriver := fs.String("db-driver", "sqlite", "драйвер database/sql для -storage sql")
+	dbDSN := fs.String("db-dsn", "nexus.db", "строка подключения для -storage sql")
+	configDir := fs.String("config", "", "каталог манифестов configsync для начальной конфигурации")
+	initDomains := fs.Bool("init-domains", false, "создать домены по умолчанию")
+	fs.Parse(os.Args[1:])
+
+	var store storage.Store
+	switch *storageKind {
+	case "memory":
+		store = storage.NewMemory()
+	case "sql":
+		sqlStore, err := storage.OpenSQL(*dbDriver, *dbDSN)
+		if err != nil {
+			return err
+		}
+		store = sqlStore
+	default:
+		return fmt.Errorf("unknown storage %q", *storageKind)
+	}
+
+	config := server.Config{
+		Store:       store,
+		JWTSecret:   *jwtSecret,
+		RequireAuth: *requireAuth,
+	}
+	for _, email := range strings.Split(*adminEmails, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			config.AdminEmails = append(config.AdminEmails, email)
+		}
+	}
+	srv, err := server.New(config)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *initDomains {
+		created, err := srv.InitializeDefaultDomains(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("created %d default domains", created)
+	}
+	if *configDir != "" {
+		resources, err := configsync.LoadDir(*configDir, configsync.LoadOptions{LookupEnv: os.LookupEnv})
+		if err != nil {
+			return err
+		}
+		if err := srv.Seed(ctx, resources); err != nil {
+			return err
+		}
+		log.Printf("loaded %d resources from %s", len(resources), *configDir)
+	}
+	if *jwtSecret == "" {
+		log.Printf("%s is not set, tokens will not survive a restart", envJWTSecret)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s (storage: %s)", addr, *storageKind)
+		errc <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Параметры истории сообщений
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+)
+
+// conversationRecord беседа в хранилище вместе с сообщениями
+type conversationRecord struct {
+	types.Conversation
+	SystemPrompt string                 `json:"system_prompt,omitempty"`
+	Context      map[string]interface{} `json:"context,omitempty"`
+}
+
+func (s *Server) createConversation(r *http.Request) (interface{}, error) {
+	var req types.CreateConversationRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	conv := &conversationRecord{
+		Conversation: types.Conversation{
+			ID:           uuid.New().String(),
+			UserID:       userID(r),
+			BotID:        req.BotID,
+			Title:        req.Title,
+			Status:       "active",
+			CreatedAt:    now,
+			LastActivity: now,
+		},
+		SystemPrompt: req.SystemPrompt,
+		Context:      req.Context,
+	}
+	if err := s.save(r.Context(), colConversations, conv.ID, conv); err != nil {
+		return nil, err
+	}
+	return &conv.Conversation, nil
+}
+
+// loadConversation возвращает беседу пользователя запроса
+func (s *Server) loadConversation(r *http.Request) (*conversationRecord, error) {
+	var conv conversationRecord
+	if err := s.load(r.Context(), colConversations, param(r, "id"), &conv); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "Conversation not found")
+	}
+	if conv.UserID != userID(r) {
+		return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "Conversation not found"}
+	}
+	return &conv, nil
+}
+
+func (s *Server) getConversation(r *http.Request) (interface{}, error) {
+	conv, err := s.loadConversation(r)
+	if err != nil {
+		return nil, err
+	}
+	conv.Messages = nil
+	return &conv.Conversation, nil
+}
+
+// sendMessage сохраняет сообщение пользователя и отвечает результатом
+// выполнения его текста как шаблона
+func (s *Server) sendMessage(r *http.Request) (interface{}, error) {
+	var req types.SendMessageRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, required("content")
+	}
+	if req.MessageType == "" {
+		req.MessageType = "text"
+	}
+	if _, err := s.loadConversation(r); err != nil {
+		return nil, err
+	}
+
+	userMsg := types.Message{
+		ID:         uuid.New().String(),
+		SenderType: "user",
+		Type:       req.MessageType,
+		Content:    req.Content,
+		Status:     "delivered",
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Metadata:   req.MessageMetadata,
+	}
+
+	p := requestPrincipal(r)
+	execReq := &types.ExecuteTemplateRequest{
+		Query:    req.Content,
+		Context:  &types.UserContext{UserID: userID(r), SessionID: param(r, "id")},
+		Options:  &types.ExecuteOptions{ParallelExecution: true},
+		Metadata: middleware.RequestMetadata(r.Context()),
+	}
+	if p != nil {
+		execReq.Context.TenantID, execReq.Context.Roles = p.TenantID, p.Roles
+	}
+	result, err := s.execute(r.Context(), p, execReq)
+	if err != nil {
+		return nil, err
+	}
+	aiMsg := types.Message{
+		ID:         uuid.New().String(),
+		SenderType: "assistant",
+		Type:       "text",
+		Content:    summarize(result),
+		Status:     "sent",
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Metadata: map[string]interface{}{
+			"execution_id": result.ExecutionID,
+			"status":       result.Status,
+			"sections":     result.Sections,
+		},
+	}
+	if len(result.DomainAnalysis.SelectedDomains) > 0 {
+		aiMsg.Intent = result.DomainAnalysis.SelectedDomains[0].DomainID
+	}
+
+	// Беседа перечитывается под блокировкой: выполнение шаблона могло
+	// занять время, а сообщения могли прийти параллельно
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv, err := s.loadConversation(r)
+	if err != nil {
+		return nil, err
+	}
+	userMsg.ConversationID, aiMsg.ConversationID = conv.ID, conv.ID
+	conv.Messages = append(conv.Messages, userMsg, aiMsg)
+	conv.MessageCount = int32(len(conv.Messages))
+	conv.LastActivity = aiMsg.CreatedAt
+	if err := s.save(r.Context(), colConversations, conv.ID, conv); err != nil {
+		return nil, err
+	}
+	return &types.MessageResponse{
+		ConversationID:     conv.ID,
+		UserMessage:        &userMsg,
+		AIResponse:         &aiMsg,
+		ConversationStatus: conv.Status,
+		TotalMessages:      conv.MessageCount,
+	}, nil
+}
+
+// summarize формирует текст ответа ассистента по результатам выполнения
+func summarize(resp *types.ExecuteTemplateResponse) string {
+	if len(resp.Sections) == 0 {
+		return "Не удалось определить подходящий домен для запроса."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Результатов: %d", resp.Metadata.ResultsCount)
+	for _, sec := range resp.Sections {
+		title := sec.Title
+		if title == "" {
+			title = sec.DomainID
+		}
+		if sec.Status == "success" || sec.Status == "partial" {
+			fmt.Fprintf(&b, "\n%s: %d", title, len(sec.Results))
+			for i, item := range sec.Results {
+				if i == 3 {
+					break
+				}
+				fmt.Fprintf(&b, "\n- %s", item.Title)
+			}
+		} else {
+			fmt.Fprintf(&b, "\n%s: %s", title, sec.Error)
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) conversationHistory(r *http.Request) (interface{}, error) {
+	conv, err := s.loadConversation(r)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(r, "limit", defaultHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := queryInt(r, "offset", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	before, err := queryTime(r, "before")
+	if err != nil {
+		return nil, err
+	}
+	after, err := queryTime(r, "after")
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]types.Message, 0, len(conv.Messages))
+	for _, msg := range conv.Messages {
+		created, _ := time.Parse(time.RFC3339, msg.CreatedAt)
+		if (!before.IsZero() && !created.Before(before)) || (!after.IsZero() && !created.After(after)) {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].CreatedAt < messages[j].CreatedAt })
+	return &types.GetConversationHistoryResponse{
+		Messages: page(messages, offset, limit),
+		Total:    int32(len(messages)),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}, nil
+}
+
+// queryTime разбирает параметр запроса в формате RFC3339
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: fmt.Sprintf("Parameter %s must be RFC3339 time", name), Field: name}
+	}
+	return t, nil
+}
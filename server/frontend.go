@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// activeFrontendConfig возвращает активную конфигурацию фронтенда
+func (s *Server) activeFrontendConfig(ctx context.Context) (*types.FrontendConfig, error) {
+	configs, err := loadAll[types.FrontendConfig](ctx, s.store, colFrontendConfigs)
+	if err != nil {
+		return nil, err
+	}
+	for _, fc := range configs {
+		if fc.Active {
+			return fc, nil
+		}
+	}
+	return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "No active frontend configuration"}
+}
+
+// activateFrontendConfig делает конфигурацию активной, снимая флаг с
+// остальных. Вызывающий держит s.mu.
+func (s *Server) activateFrontendConfig(ctx context.Context, id string) error {
+	configs, err := loadAll[types.FrontendConfig](ctx, s.store, colFrontendConfigs)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, fc := range configs {
+		active := fc.ID == id
+		found = found || active
+		if fc.Active == active {
+			continue
+		}
+		fc.Active = active
+		stamp(&fc.CreatedAt, &fc.UpdatedAt)
+		if err := s.save(ctx, colFrontendConfigs, fc.ID, fc); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "Frontend configuration not found"}
+	}
+	return nil
+}
+
+// getFrontendConfig публичный endpoint активной конфигурации фронтенда
+func (s *Server) getFrontendConfig(r *http.Request) (interface{}, error) {
+	fc, err := s.activeFrontendConfig(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return frontendResponse{fc}, nil
+}
+
+// frontendResponse отвечает с ETag конфигурации и 304 Not Modified,
+// если клиент прислал совпадающий If-None-Match
+type frontendResponse struct {
+	config *types.FrontendConfig
+}
+
+func (f frontendResponse) respond(w http.ResponseWriter, r *http.Request) {
+	data, _ := json.Marshal(f.config)
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	middleware.WriteResponse(w, r, http.StatusOK, f.config)
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/domain"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/jwt"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/protocol"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// remoteTokenTTL время жизни JWT, которым сервер подписывает вызовы домена
+const remoteTokenTTL = 5 * time.Minute
+
+// remoteDomain вызывает доменный микросервис по DomainConfig.Endpoint
+// в формате пакета domain SDK. Реализует domain.Handler и domain.ActionHandler.
+type remoteDomain struct {
+	config *types.DomainConfig
+	client *http.Client
+}
+
+func (d *remoteDomain) Execute(ctx context.Context, req *domain.Request) (*types.DomainSection, error) {
+	var section types.DomainSection
+	if err := d.call(ctx, domain.PathExecute, req.Metadata, req, &section); err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+func (d *remoteDomain) ExecuteAction(ctx context.Context, req *domain.ActionRequest) (*types.ExecuteActionResponse, error) {
+	var resp types.ExecuteActionResponse
+	if err := d.call(ctx, domain.PathActions, req.Metadata, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// call отправляет запрос в конверте протокола, повторяя его до
+// RetryCount раз при сетевых ошибках и ответах 5xx
+func (d *remoteDomain) call(ctx context.Context, path string, metadata *types.RequestMetadata, data, out interface{}) error {
+	if metadata == nil {
+		metadata = types.NewRequestMetadata(client.DefaultProtocolVersion, client.DefaultProtocolVersion)
+	}
+	body, err := json.Marshal(protocol.NewRequestMessage(metadata, data))
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= d.config.RetryCount; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		retry, err := d.do(ctx, path, body, out)
+		if err == nil || !retry {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+func (d *remoteDomain) do(ctx context.Context, path string, body []byte, out interface{}) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(d.config.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := d.authorize(req); err != nil {
+		return false, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("domain %s: %w", d.config.ID, err)
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("domain %s: %w", d.config.ID, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var errResp types.ErrorResponse
+		if json.Unmarshal(payload, &errResp) == nil && errResp.Error.Code != "" {
+			return resp.StatusCode >= 500, &errResp.Error
+		}
+		return resp.StatusCode >= 500, fmt.Errorf("domain %s: unexpected status %d", d.config.ID, resp.StatusCode)
+	}
+	var msg struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return false, fmt.Errorf("domain %s: invalid response: %w", d.config.ID, err)
+	}
+	if len(msg.Data) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(msg.Data, out); err != nil {
+		return false, fmt.Errorf("domain %s: invalid response data: %w", d.config.ID, err)
+	}
+	return false, nil
+}
+
+// authorize добавляет учетные данные по DomainConfig.AuthType
+// (те же ключи AuthConfig, что проверяет domain.Server)
+func (d *remoteDomain) authorize(req *http.Request) error {
+	auth := d.config.AuthConfig
+	switch d.config.AuthType {
+	case "", "none":
+		return nil
+	case "api_key":
+		header := auth["header"]
+		if header == "" {
+			header = domain.DefaultAPIKeyHeader
+		}
+		if strings.EqualFold(header, "Authorization") {
+			req.Header.Set("Authorization", "Bearer "+auth["api_key"])
+		} else {
+			req.Header.Set(header, auth["api_key"])
+		}
+		return nil
+	case "jwt":
+		if auth["secret"] == "" {
+			return fmt.Errorf("domain %s: jwt auth requires auth_config.secret to sign requests", d.config.ID)
+		}
+		now := time.Now()
+		claims := jwt.Claims{
+			"iss": TokenIssuer,
+			"sub": d.config.ID,
+			"iat": now.Unix(),
+			"exp": now.Add(remoteTokenTTL).Unix(),
+		}
+		if auth["issuer"] != "" {
+			claims["iss"] = auth["issuer"]
+		}
+		if auth["audience"] != "" {
+			claims["aud"] = auth["audience"]
+		}
+		token, err := jwt.Sign(claims, "HS256", []byte(auth["secret"]))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	return fmt.Errorf("domain %s: unsupported auth type %q", d.config.ID, d.config.AuthType)
+}
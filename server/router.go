@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+
+	"github.com/pro-deploy/nexus-protocol/server/storage"
+)
+
+// apiPrefix префикс REST API
+const apiPrefix = "/api/" + APIVersion
+
+// Коллекции хранилища
+const (
+	colUsers           = "users"
+	colExecutions      = "executions"
+	colConversations   = "conversations"
+	colEvents          = "analytics_events"
+	colBatches         = "batches"
+	colWebhooks        = "webhooks"
+	colSettings        = "settings"
+	colPrompts         = "prompts"
+	colDomains         = "domains"
+	colIntegrations    = "integrations"
+	colFrontendConfigs = "frontend_configs"
+)
+
+// settingsAI ID документа AIConfig в коллекции settings
+const settingsAI = "ai"
+
+// access требования маршрута к аутентификации
+type access int
+
+const (
+	// accessPublic токен не проверяется
+	accessPublic access = iota
+	// accessOptional токен проверяется, если передан; обязателен при Config.RequireAuth
+	accessOptional
+	// accessUser токен обязателен
+	accessUser
+	// accessAdmin роль admin обязательна при Config.RequireAuth
+	accessAdmin
+)
+
+// route маршрут API. Сегменты шаблона вида {name} доступны через param.
+type route struct {
+	method  string
+	pattern string
+	access  access
+	status  int
+	flat    bool // ответ без конверта протокола (admin API)
+	handle  func(r *http.Request) (interface{}, error)
+}
+
+// code возвращает HTTP код успешного ответа маршрута
+func (rt *route) code() int {
+	if rt.status == 0 {
+		return http.StatusOK
+	}
+	return rt.status
+}
+
+// responder результат маршрута, который пишет ответ сам (SSE, 304)
+type responder interface {
+	respond(w http.ResponseWriter, r *http.Request)
+}
+
+type (
+	routeKey  struct{}
+	paramsKey struct{}
+)
+
+// match находит маршрут запроса. Если путь найден, но метод не
+// поддерживается, выставляет заголовок Allow и возвращает METHOD_NOT_ALLOWED.
+func match(routes []route, w http.ResponseWriter, r *http.Request) (*route, *http.Request, error) {
+	var allowed []string
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := range routes {
+		params, ok := matchPattern(routes[i].pattern, path)
+		if !ok {
+			continue
+		}
+		if routes[i].method != r.Method {
+			allowed = append(allowed, routes[i].method)
+			continue
+		}
+		return &routes[i], r.WithContext(context.WithValue(r.Context(), paramsKey{}, params)), nil
+	}
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		return nil, r, &types.ErrorDetail{Code: "METHOD_NOT_ALLOWED", Type: "VALIDATION_ERROR", Message: "Method not allowed"}
+	}
+	return nil, r, &types.ErrorDetail{Code: "ENDPOINT_NOT_FOUND", Type: "NOT_FOUND", Message: "Endpoint not found"}
+}
+
+func matchPattern(pattern string, path []string) (map[string]string, bool) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(segments) != len(path) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if path[i] == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg[1:len(seg)-1]] = path[i]
+			continue
+		}
+		if seg != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// param возвращает сегмент пути маршрута
+func param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+// queryInt возвращает целый параметр запроса в пределах [0, max]
+// или def, если параметр не указан
+func queryInt(r *http.Request, name string, def, max int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, &types.ErrorDetail{Code: "INVALID_VALUE", Type: "VALIDATION_ERROR", Message: fmt.Sprintf("Parameter %s must be a non-negative integer", name), Field: name}
+	}
+	if max > 0 && n > max {
+		n = max
+	}
+	return n, nil
+}
+
+// page возвращает срез [offset, offset+limit) списка
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+// load читает документ в v
+func (s *Server) load(ctx context.Context, collection, id string, v interface{}) error {
+	data, err := s.store.Get(ctx, collection, id)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// save записывает v как документ
+func (s *Server) save(ctx context.Context, collection, id string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, collection, id, data)
+}
+
+// loadAll читает все документы коллекции в порядке создания
+func loadAll[T any](ctx context.Context, store storage.Store, collection string) ([]*T, error) {
+	docs, err := store.List(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*T, 0, len(docs))
+	for _, data := range docs {
+		v := new(T)
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, fmt.Errorf("decode %s document: %w", collection, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, storage.ErrNotFound)
+}
+
+// notFound заменяет storage.ErrNotFound ошибкой протокола
+func notFound(err error, code, message string) error {
+	if isNotFound(err) {
+		return &types.ErrorDetail{Code: code, Type: "NOT_FOUND", Message: message}
+	}
+	return err
+}
+
+// decode разбирает JSON-тело запроса
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Request body is required"}
+		}
+		return &types.ErrorDetail{Code: "INVALID_FORMAT", Type: "VALIDATION_ERROR", Message: "Invalid request body", Details: err.Error()}
+	}
+	return nil
+}
+
+// required возвращает ошибку отсутствующего обязательного поля
+func required(field string) error {
+	return &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: fmt.Sprintf("Field %s is required", field), Field: field}
+}
+
+// invalid возвращает ошибку недопустимого значения поля
+func invalid(field, message string) error {
+	return &types.ErrorDetail{Code: "INVALID_VALUE", Type: "VALIDATION_ERROR", Message: message, Field: field}
+}
+
+// stamp выставляет created (если пусто) и updated в текущее время RFC3339
+func stamp(created, updated *string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if *created == "" {
+		*created = now
+	}
+	*updated = now
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// apiRoutes маршруты API в конверте протокола
+func (s *Server) apiRoutes() []route {
+	return []route{
+		{method: http.MethodPost, pattern: apiPrefix + "/auth/register", access: accessPublic, status: http.StatusCreated, handle: s.register},
+		{method: http.MethodPost, pattern: apiPrefix + "/auth/login", access: accessPublic, handle: s.login},
+		{method: http.MethodPost, pattern: apiPrefix + "/auth/refresh", access: accessPublic, handle: s.refresh},
+		{method: http.MethodGet, pattern: apiPrefix + "/users/profile", access: accessUser, handle: s.getProfile},
+		{method: http.MethodPut, pattern: apiPrefix + "/users/profile", access: accessUser, handle: s.updateProfile},
+
+		{method: http.MethodPost, pattern: apiPrefix + "/templates/execute", access: accessOptional, handle: s.executeTemplate},
+		{method: http.MethodGet, pattern: apiPrefix + "/templates/status/{id}", access: accessOptional, handle: s.getExecution},
+		{method: http.MethodGet, pattern: apiPrefix + "/templates/stream/{id}", access: accessOptional, handle: s.streamExecution},
+		{method: http.MethodPost, pattern: apiPrefix + "/domains/{domain}/actions/{result}", access: accessOptional, handle: s.executeAction},
+		{method: http.MethodGet, pattern: apiPrefix + "/domains/{domain}/actions/{result}", access: accessOptional, handle: s.executeAction},
+		{method: http.MethodDelete, pattern: apiPrefix + "/domains/{domain}/actions/{result}", access: accessOptional, handle: s.executeAction},
+
+		{method: http.MethodPost, pattern: apiPrefix + "/conversations", access: accessOptional, status: http.StatusCreated, handle: s.createConversation},
+		{method: http.MethodGet, pattern: apiPrefix + "/conversations/{id}", access: accessOptional, handle: s.getConversation},
+		{method: http.MethodPost, pattern: apiPrefix + "/conversations/{id}/messages", access: accessOptional, handle: s.sendMessage},
+		{method: http.MethodGet, pattern: apiPrefix + "/conversations/{id}/history", access: accessOptional, handle: s.conversationHistory},
+
+		{method: http.MethodPost, pattern: apiPrefix + "/analytics/events", access: accessOptional, status: http.StatusCreated, handle: s.logEvent},
+		{method: http.MethodGet, pattern: apiPrefix + "/analytics/events", access: accessOptional, handle: s.getEvents},
+		{method: http.MethodGet, pattern: apiPrefix + "/analytics/stats", access: accessOptional, handle: s.getStats},
+
+		{method: http.MethodPost, pattern: apiPrefix + "/batch/execute", access: accessOptional, handle: s.executeBatch},
+		{method: http.MethodGet, pattern: apiPrefix + "/batch/{id}/status", access: accessOptional, handle: s.batchStatus},
+		{method: http.MethodPost, pattern: apiPrefix + "/batch/{id}/cancel", access: accessOptional, handle: s.cancelBatch},
+
+		{method: http.MethodPost, pattern: apiPrefix + "/webhooks", access: accessOptional, status: http.StatusCreated, handle: s.registerWebhook},
+		{method: http.MethodGet, pattern: apiPrefix + "/webhooks", access: accessOptional, handle: s.listWebhooks},
+		{method: http.MethodDelete, pattern: apiPrefix + "/webhooks/{id}", access: accessOptional, handle: s.deleteWebhook},
+		{method: http.MethodPost, pattern: apiPrefix + "/webhooks/{id}/test", access: accessOptional, handle: s.testWebhook},
+
+		{method: http.MethodGet, pattern: apiPrefix + "/frontend/config", access: accessPublic, handle: s.getFrontendConfig},
+	}
+}
+
+// adminRoutes маршруты admin API. Ответы без конверта протокола, как
+// ожидает client.AdminClient.
+func (s *Server) adminRoutes() []route {
+	admin := apiPrefix + "/admin"
+	routes := []route{
+		{method: http.MethodGet, pattern: admin + "/ai/config", handle: s.getAIConfig},
+		{method: http.MethodPut, pattern: admin + "/ai/config", handle: s.updateAIConfig},
+		{method: http.MethodPost, pattern: admin + "/domains/initialize-default", handle: s.initializeDefaultDomains},
+		{method: http.MethodGet, pattern: admin + "/frontend/active", handle: s.getActiveFrontendConfig},
+		{method: http.MethodPut, pattern: admin + "/frontend/configs/{id}/active", handle: s.setActiveFrontendConfig},
+		{method: http.MethodGet, pattern: admin + "/version", handle: s.adminVersion},
+	}
+	routes = append(routes, promptResource.routes(s, admin+"/prompts")...)
+	routes = append(routes, domainResource.routes(s, admin+"/domains")...)
+	routes = append(routes, integrationResource.routes(s, admin+"/integrations")...)
+	routes = append(routes, s.frontendResource().routes(s, admin+"/frontend/configs")...)
+	routes = append(routes, s.domainPart("keywords", "keywords", func(d *types.DomainConfig) interface{} { return &d.Keywords })...)
+	routes = append(routes, s.domainPart("capabilities", "capabilities", func(d *types.DomainConfig) interface{} { return &d.Capabilities })...)
+	routes = append(routes, s.domainPart("quality-rules", "quality rules", func(d *types.DomainConfig) interface{} { return &d.QualityRules })...)
+	routes = append(routes, s.domainPart("ml-model", "ML model", func(d *types.DomainConfig) interface{} { return &d.MLModel })...)
+	for i := range routes {
+		routes[i].access = accessAdmin
+		routes[i].flat = true
+	}
+	return routes
+}
@@ -0,0 +1,375 @@
+// Package server реализует эталонный сервер Nexus Protocol: REST API из
+// api/rest/openapi.yaml поверх подключаемого хранилища (storage.Store).
+//
+// Сервер не содержит доменной логики: запросы шаблонов классифицируются
+// по ключевым словам доменов (classifier) и выполняются обработчиками,
+// зарегистрированными через RegisterDomain, либо доменными микросервисами
+// по DomainConfig.Endpoint (протокол пакета domain SDK). Это позволяет
+// запускать протокол целиком локально - в интеграционных тестах и демо:
+//
+//	srv, err := server.New(server.Config{JWTSecret: secret})
+//	srv.RegisterDomain(&types.DomainConfig{ID: "recipes", Keywords: []string{"рецепт"}}, recipesHandler)
+//	http.ListenAndServe(":8080", srv)
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/classifier"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/configsync"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/domain"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+
+	"github.com/pro-deploy/nexus-protocol/server/storage"
+)
+
+// APIVersion версия REST API в путях
+const APIVersion = "v1"
+
+// Значения по умолчанию
+const (
+	DefaultAccessTokenTTL  = time.Hour
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+	DefaultExecuteTimeout  = 30 * time.Second
+)
+
+// Config параметры сервера
+type Config struct {
+	// Store хранилище (по умолчанию storage.NewMemory())
+	Store storage.Store
+
+	// ProtocolVersion версия протокола (по умолчанию client.DefaultProtocolVersion)
+	ProtocolVersion string
+
+	// ServerVersion версия сервера в ответах (по умолчанию ProtocolVersion)
+	ServerVersion string
+
+	// JWTSecret ключ подписи токенов (HS256). Если не задан, генерируется
+	// случайный ключ и токены перестают действовать после перезапуска.
+	JWTSecret string
+
+	// AccessTokenTTL и RefreshTokenTTL время жизни токенов
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// RequireAuth требует Bearer-токен для всех API, кроме /auth/* и
+	// /frontend/config, и роль admin для /admin/*. По умолчанию токен
+	// необязателен, а admin API открыт - удобно для тестов и демо.
+	RequireAuth bool
+
+	// AdminEmails пользователи, получающие роль admin при регистрации
+	AdminEmails []string
+
+	// Classifier параметры выбора доменов для запроса
+	Classifier classifier.Config
+
+	// HTTPClient клиент для доменных микросервисов и webhooks
+	// (по умолчанию http.Client без общего таймаута: таймауты задаются
+	// DomainConfig.Timeout и политикой webhook)
+	HTTPClient *http.Client
+}
+
+// Server http.Handler эталонного сервера
+type Server struct {
+	config Config
+	store  storage.Store
+	secret []byte
+	admins map[string]bool
+
+	// mu сериализует изменения "прочитать-изменить-записать" документов
+	mu sync.Mutex
+
+	handlersMu sync.RWMutex
+	handlers   map[string]domain.Handler
+
+	batchesMu sync.Mutex
+	batches   map[string]*runningBatch
+
+	// background фоновые доставки webhooks
+	background sync.WaitGroup
+
+	api, admin []route
+	protocol   http.Handler
+}
+
+// New создает сервер
+func New(config Config) (*Server, error) {
+	if config.Store == nil {
+		config.Store = storage.NewMemory()
+	}
+	if config.ProtocolVersion == "" {
+		config.ProtocolVersion = client.DefaultProtocolVersion
+	}
+	if err := types.ValidateVersion(config.ProtocolVersion); err != nil {
+		return nil, fmt.Errorf("invalid protocol version: %w", err)
+	}
+	if config.ServerVersion == "" {
+		config.ServerVersion = config.ProtocolVersion
+	}
+	if config.AccessTokenTTL <= 0 {
+		config.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if config.RefreshTokenTTL <= 0 {
+		config.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{}
+	}
+
+	secret := []byte(config.JWTSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate jwt secret: %w", err)
+		}
+	}
+	admins := make(map[string]bool, len(config.AdminEmails))
+	for _, email := range config.AdminEmails {
+		admins[normalizeEmail(email)] = true
+	}
+
+	s := &Server{
+		config:   config,
+		store:    config.Store,
+		secret:   secret,
+		admins:   admins,
+		handlers: make(map[string]domain.Handler),
+		batches:  make(map[string]*runningBatch),
+	}
+	s.protocol = middleware.Protocol(middleware.Config{
+		ProtocolVersion: config.ProtocolVersion,
+		ServerVersion:   config.ServerVersion,
+	})(http.HandlerFunc(s.serveAPI))
+	s.api, s.admin = s.apiRoutes(), s.adminRoutes()
+	return s, nil
+}
+
+// Close дожидается фоновых доставок webhooks и закрывает хранилище
+func (s *Server) Close() error {
+	s.background.Wait()
+	return s.store.Close()
+}
+
+// RegisterDomain сохраняет конфигурацию домена (если ее еще нет в
+// хранилище) и регистрирует обработчик, выполняющий его запросы в
+// процессе сервера. Обработчик может реализовать domain.ActionHandler.
+// Домены без обработчика вызываются по DomainConfig.Endpoint.
+func (s *Server) RegisterDomain(config *types.DomainConfig, handler domain.Handler) error {
+	if config == nil || config.ID == "" {
+		return fmt.Errorf("domain config with ID is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("domain %s: handler is required", config.ID)
+	}
+
+	ctx := context.Background()
+	s.mu.Lock()
+	var existing types.DomainConfig
+	err := s.load(ctx, colDomains, config.ID, &existing)
+	if isNotFound(err) {
+		stored := *config
+		stamp(&stored.CreatedAt, &stored.UpdatedAt)
+		err = s.save(ctx, colDomains, stored.ID, &stored)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("domain %s: %w", config.ID, err)
+	}
+
+	s.handlersMu.Lock()
+	s.handlers[config.ID] = handler
+	s.handlersMu.Unlock()
+	return nil
+}
+
+// Seed записывает ресурсы манифестов configsync в хранилище, заменяя
+// существующие с теми же ID
+func (s *Server) Seed(ctx context.Context, resources []*configsync.Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, res := range resources {
+		data, err := json.Marshal(res.Spec)
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", res.Kind, res.ID, err)
+		}
+		var collection string
+		var object interface{}
+		switch res.Kind {
+		case configsync.KindAIConfig:
+			collection, object = colSettings, &types.AIConfig{}
+		case configsync.KindIntegration:
+			collection, object = colIntegrations, &types.IntegrationConfig{}
+		case configsync.KindDomain:
+			collection, object = colDomains, &types.DomainConfig{}
+		case configsync.KindPrompt:
+			collection, object = colPrompts, &types.PromptConfig{}
+		case configsync.KindFrontendConfig:
+			collection, object = colFrontendConfigs, &types.FrontendConfig{}
+		default:
+			return fmt.Errorf("unsupported resource kind %q", res.Kind)
+		}
+		if err := json.Unmarshal(data, object); err != nil {
+			return fmt.Errorf("%s %q: %w", res.Kind, res.ID, err)
+		}
+		id := res.ID
+		if res.Kind == configsync.KindAIConfig {
+			id = settingsAI
+		}
+		if err := s.save(ctx, collection, id, object); err != nil {
+			return fmt.Errorf("%s %q: %w", res.Kind, res.ID, err)
+		}
+		if fc, ok := object.(*types.FrontendConfig); ok && fc.Active {
+			if err := s.activateFrontendConfig(ctx, fc.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ServeHTTP реализует http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch path {
+	case "/health", apiPrefix + "/health":
+		s.serveSystem(w, r, s.health)
+		return
+	case "/ready", apiPrefix + "/ready":
+		s.serveSystem(w, r, s.ready)
+		return
+	case "/version", apiPrefix + "/version":
+		s.serveSystem(w, r, s.version)
+		return
+	}
+
+	var routes []route
+	switch {
+	case strings.HasPrefix(path, apiPrefix+"/admin/"):
+		routes = s.admin
+	case strings.HasPrefix(path, apiPrefix+"/"):
+		routes = s.api
+	}
+	rt, r, err := match(routes, w, r)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	// Учетные данные проверяются до разбора тела запроса
+	p, detail := s.authenticate(r, rt.access)
+	if detail != nil {
+		middleware.WriteError(w, r, detail)
+		return
+	}
+	r = r.WithContext(withPrincipal(r.Context(), p))
+
+	if rt.flat {
+		// Admin API отвечает объектами без конверта, как ожидает client.AdminClient
+		r.Body = http.MaxBytesReader(w, r.Body, middleware.DefaultMaxBodyBytes)
+		data, err := rt.handle(r)
+		if err != nil {
+			middleware.WriteError(w, r, err)
+			return
+		}
+		writeJSON(w, rt.code(), data)
+		return
+	}
+	s.protocol.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, rt)))
+}
+
+// serveAPI выполняет маршрут после проверки метаданных протокола
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	rt := r.Context().Value(routeKey{}).(*route)
+	data, err := rt.handle(r)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+	if resp, ok := data.(responder); ok {
+		resp.respond(w, r)
+		return
+	}
+	middleware.WriteResponse(w, r, rt.code(), data)
+}
+
+// serveSystem обслуживает служебные endpoints без конверта протокола
+func (s *Server) serveSystem(w http.ResponseWriter, r *http.Request, fn func(r *http.Request) (int, interface{})) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		middleware.WriteError(w, r, &types.ErrorDetail{Code: "METHOD_NOT_ALLOWED", Type: "VALIDATION_ERROR", Message: "Method not allowed"})
+		return
+	}
+	status, data := fn(r)
+	writeJSON(w, status, data)
+}
+
+func (s *Server) health(*http.Request) (int, interface{}) {
+	return http.StatusOK, &types.HealthResponse{
+		Status:    "healthy",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Version:   s.config.ServerVersion,
+	}
+}
+
+func (s *Server) ready(r *http.Request) (int, interface{}) {
+	start := time.Now()
+	storageStatus := &types.ComponentStatus{Status: "healthy"}
+	err := s.store.Ping(r.Context())
+	storageStatus.LatencyMS = int32(time.Since(start).Milliseconds())
+
+	resp := &types.ReadinessResponse{
+		Status:     "ready",
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Checks:     types.ReadinessChecks{Database: "ok", AIServices: "ok"},
+		Components: map[string]*types.ComponentStatus{"storage": storageStatus},
+	}
+	if err != nil {
+		storageStatus.Status = "unhealthy"
+		storageStatus.Message = err.Error()
+		resp.Status = "not_ready"
+		resp.Checks.Database = "error"
+		return http.StatusServiceUnavailable, resp
+	}
+	return http.StatusOK, resp
+}
+
+func (s *Server) version(*http.Request) (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{
+		"protocol_version": s.config.ProtocolVersion,
+		"server_version":   s.config.ServerVersion,
+		"api_version":      APIVersion,
+	}
+}
+
+// domainHandler возвращает обработчик домена: зарегистрированный в
+// процессе или удаленный по Endpoint; nil, если вызвать домен нечем
+func (s *Server) domainHandler(config *types.DomainConfig) domain.Handler {
+	s.handlersMu.RLock()
+	h := s.handlers[config.ID]
+	s.handlersMu.RUnlock()
+	if h != nil {
+		return h
+	}
+	if config.Endpoint != "" {
+		return &remoteDomain{config: config, client: s.config.HTTPClient}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
@@ -0,0 +1,399 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pro-deploy/nexus-protocol/sdk/go/client"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/domain"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+type recipes struct{}
+
+func (recipes) Execute(ctx context.Context, req *domain.Request) (*types.DomainSection, error) {
+	if strings.Contains(req.Query, "медленно") {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &types.DomainSection{
+		Title: "Рецепты",
+		Results: []types.ResultItem{
+			{ID: "borsch", Type: "recipe", Title: "Борщ", Relevance: 0.9, Confidence: 0.8,
+				Actions: []types.Action{{Type: "save", Label: "Сохранить"}}},
+			{ID: "shchi", Type: "recipe", Title: "Щи", Relevance: 0.5, Confidence: 0.5},
+		},
+	}, nil
+}
+
+func (recipes) ExecuteAction(ctx context.Context, req *domain.ActionRequest) (*types.ExecuteActionResponse, error) {
+	return &types.ExecuteActionResponse{Status: "success", ResultID: "saved-" + req.ResultID, Message: req.Context.UserID}, nil
+}
+
+// newTestServer запускает сервер с доменом recipes и возвращает клиентов
+// администратора и обычного пользователя
+func newTestServer(t *testing.T, config Config) (*Server, *httptest.Server, *client.Client, *client.Client) {
+	t.Helper()
+	config.JWTSecret = "test-secret"
+	config.AdminEmails = []string{"admin@example.com"}
+	srv, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	err = srv.RegisterDomain(&types.DomainConfig{ID: "recipes", Name: "Рецепты", Enabled: true, Priority: 60, Keywords: []string{"рецепт", "борщ"}}, recipes{})
+	if err != nil {
+		t.Fatalf("RegisterDomain failed: %v", err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Close()
+	})
+	return srv, ts, login(t, ts.URL, "admin@example.com"), login(t, ts.URL, "user@example.com")
+}
+
+func login(t *testing.T, baseURL, email string) *client.Client {
+	t.Helper()
+	ctx := context.Background()
+	c := client.NewClient(client.Config{BaseURL: baseURL})
+	if _, err := c.RegisterUser(ctx, &types.RegisterUserRequest{Email: email, Password: "password123", FirstName: "Test"}); err != nil {
+		t.Fatalf("RegisterUser %s failed: %v", email, err)
+	}
+	if _, err := c.Login(ctx, &types.LoginRequest{Email: email, Password: "password123"}); err != nil {
+		t.Fatalf("Login %s failed: %v", email, err)
+	}
+	return c
+}
+
+func errorCode(err error) string {
+	var detail *types.ErrorDetail
+	if errors.As(err, &detail) {
+		return detail.Code
+	}
+	return ""
+}
+
+func TestAuthAndProfile(t *testing.T) {
+	_, ts, admin, user := newTestServer(t, Config{RequireAuth: true})
+	ctx := context.Background()
+
+	profile, err := user.GetUserProfile(ctx)
+	if err != nil || profile.Email != "user@example.com" || profile.FirstName != "Test" {
+		t.Fatalf("Unexpected profile %+v: %v", profile, err)
+	}
+
+	anonymous := client.NewClient(client.Config{BaseURL: ts.URL})
+	if _, err := anonymous.ExecuteTemplate(ctx, &types.ExecuteTemplateRequest{Query: "рецепт"}); errorCode(err) != "AUTHENTICATION_FAILED" {
+		t.Errorf("Expected AUTHENTICATION_FAILED without token, got %v", err)
+	}
+	if _, err := anonymous.Login(ctx, &types.LoginRequest{Email: "user@example.com", Password: "wrong-password"}); errorCode(err) != "AUTHENTICATION_FAILED" {
+		t.Errorf("Expected AUTHENTICATION_FAILED for wrong password, got %v", err)
+	}
+	if _, err := anonymous.RegisterUser(ctx, &types.RegisterUserRequest{Email: "USER@example.com", Password: "password123"}); errorCode(err) != "DUPLICATE_RESOURCE" {
+		t.Errorf("Expected DUPLICATE_RESOURCE for existing email, got %v", err)
+	}
+
+	if _, err := user.Admin().ListDomains(ctx); errorCode(err) != "INSUFFICIENT_PERMISSIONS" {
+		t.Errorf("Expected INSUFFICIENT_PERMISSIONS for user, got %v", err)
+	}
+	if domains, err := admin.Admin().ListDomains(ctx); err != nil || len(domains) != 1 || domains[0].ID != "recipes" {
+		t.Errorf("Unexpected domains %+v: %v", domains, err)
+	}
+}
+
+func TestExecuteTemplateWithLocalAndRemoteDomains(t *testing.T) {
+	_, _, admin, user := newTestServer(t, Config{RequireAuth: true})
+	ctx := context.Background()
+
+	var remoteCalls int
+	var mu sync.Mutex
+	commerce := domain.HandlerFunc(func(ctx context.Context, req *domain.Request) (*types.DomainSection, error) {
+		mu.Lock()
+		remoteCalls++
+		mu.Unlock()
+		return &types.DomainSection{Results: []types.ResultItem{{ID: "beet", Type: "product", Title: "Свекла", Relevance: 0.7, Confidence: 0.9}}}, nil
+	})
+	config := &types.DomainConfig{
+		ID: "commerce", Name: "Покупки", Enabled: true, Priority: 80, Timeout: 5,
+		Keywords: []string{"купить"}, AuthType: "api_key", AuthConfig: map[string]string{"api_key": "domain-key"},
+	}
+	remote, err := domain.NewServer(commerce, domain.Config{Domain: config})
+	if err != nil {
+		t.Fatalf("domain.NewServer failed: %v", err)
+	}
+	remoteServer := httptest.NewServer(remote)
+	defer remoteServer.Close()
+	config.Endpoint = remoteServer.URL
+	if _, err := admin.Admin().CreateDomain(ctx, config); err != nil {
+		t.Fatalf("CreateDomain failed: %v", err)
+	}
+
+	resp, err := user.ExecuteTemplate(ctx, &types.ExecuteTemplateRequest{Query: "рецепт борща и где купить свеклу"})
+	if err != nil {
+		t.Fatalf("ExecuteTemplate failed: %v", err)
+	}
+	if resp.Status != ExecutionCompleted || len(resp.Sections) != 2 || remoteCalls != 1 {
+		t.Fatalf("Unexpected response status %s, sections %+v, remote calls %d", resp.Status, resp.Sections, remoteCalls)
+	}
+	if resp.Metadata.ResultsCount != 3 || resp.Ranking == nil || len(resp.Ranking.Items) != 3 || resp.Ranking.Items[0].ID != "borsch" {
+		t.Errorf("Unexpected ranking %+v", resp.Ranking)
+	}
+	if resp.ResponseMetadata == nil || resp.ResponseMetadata.ProtocolVersion != client.DefaultProtocolVersion {
+		t.Errorf("Unexpected response metadata %+v", resp.ResponseMetadata)
+	}
+
+	status, err := user.GetExecutionStatus(ctx, resp.ExecutionID)
+	if err != nil || status.ExecutionID != resp.ExecutionID || len(status.Sections) != 2 {
+		t.Errorf("Unexpected execution status %+v: %v", status, err)
+	}
+	if _, err := admin.GetExecutionStatus(ctx, resp.ExecutionID); errorCode(err) != "EXECUTION_NOT_FOUND" {
+		t.Errorf("Expected EXECUTION_NOT_FOUND for another user, got %v", err)
+	}
+
+	var action *types.Action
+	for _, sec := range resp.Sections {
+		if sec.DomainID == "recipes" {
+			action = &sec.Results[0].Actions[0]
+		}
+	}
+	if action == nil || action.URL != "/api/v1/domains/recipes/actions/borsch" {
+		t.Fatalf("Unexpected action %+v", action)
+	}
+	result, err := user.ExecuteAction(ctx, action, nil)
+	if err != nil || result.ResultID != "saved-borsch" || result.ActionType != "save" || result.Message == "" {
+		t.Errorf("Unexpected action result %+v: %v", result, err)
+	}
+}
+
+func TestExecuteTemplateTimeout(t *testing.T) {
+	_, _, _, user := newTestServer(t, Config{})
+	resp, err := user.ExecuteTemplate(context.Background(), &types.ExecuteTemplateRequest{
+		Query:   "рецепт медленно",
+		Options: &types.ExecuteOptions{TimeoutMS: 50},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteTemplate failed: %v", err)
+	}
+	if resp.Status != ExecutionTimeout || resp.Sections[0].Status != sectionTimeout {
+		t.Errorf("Expected timeout, got %s: %+v", resp.Status, resp.Sections)
+	}
+}
+
+func TestConversations(t *testing.T) {
+	_, _, admin, user := newTestServer(t, Config{RequireAuth: true})
+	ctx := context.Background()
+
+	conv, err := user.CreateConversation(ctx, &types.CreateConversationRequest{Title: "Ужин"})
+	if err != nil || conv.Status != "active" {
+		t.Fatalf("Unexpected conversation %+v: %v", conv, err)
+	}
+	msg, err := user.SendMessage(ctx, conv.ID, &types.SendMessageRequest{Content: "рецепт борща"})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if msg.TotalMessages != 2 || msg.AIResponse.Intent != "recipes" || !strings.Contains(msg.AIResponse.Content, "Борщ") {
+		t.Errorf("Unexpected message response %+v", msg.AIResponse)
+	}
+
+	history, err := user.GetConversationHistory(ctx, conv.ID, &types.GetConversationHistoryRequest{Limit: 1, Offset: 1})
+	if err != nil || history.Total != 2 || len(history.Messages) != 1 || history.Messages[0].SenderType != "assistant" {
+		t.Errorf("Unexpected history %+v: %v", history, err)
+	}
+	if _, err := admin.GetConversation(ctx, conv.ID); errorCode(err) != "RESOURCE_NOT_FOUND" {
+		t.Errorf("Expected RESOURCE_NOT_FOUND for another user, got %v", err)
+	}
+}
+
+func TestAnalytics(t *testing.T) {
+	_, _, _, user := newTestServer(t, Config{})
+	ctx := context.Background()
+
+	for _, event := range []string{"search", "search", "purchase"} {
+		if _, err := user.LogEvent(ctx, &types.LogEventRequest{EventType: event}); err != nil {
+			t.Fatalf("LogEvent failed: %v", err)
+		}
+	}
+	if _, err := user.LogEvent(ctx, &types.LogEventRequest{}); errorCode(err) != "MISSING_REQUIRED_FIELD" {
+		t.Errorf("Expected MISSING_REQUIRED_FIELD, got %v", err)
+	}
+
+	events, err := user.GetEvents(ctx, &types.GetEventsRequest{EventType: "search"})
+	if err != nil || events.Total != 2 || events.Events[0].UserID == "" {
+		t.Errorf("Unexpected events %+v: %v", events, err)
+	}
+	stats, err := user.GetStats(ctx, &types.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.TotalEvents != 3 || stats.EventsToday != 3 || stats.TotalUsers != 1 || stats.TopEvents[0].Event != "search" || len(stats.UserActivity) != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if stats.ActiveUsers != 1 || stats.TopEvents[0].Percentage != 66.67 || stats.ConversionMetrics == nil {
+		t.Errorf("Stats differ from analytics.Aggregator: %+v", stats)
+	}
+}
+
+func TestBatchAndWebhooks(t *testing.T) {
+	_, _, _, user := newTestServer(t, Config{RequireAuth: true})
+	ctx := context.Background()
+
+	const secret = "webhook-secret-0123"
+	deliveries := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	if _, err := user.RegisterWebhook(ctx, &types.RegisterWebhookRequest{Config: &types.WebhookConfig{URL: receiver.URL, Events: []string{"batch.completed"}, Secret: "short"}}); errorCode(err) != "FIELD_TOO_SHORT" {
+		t.Errorf("Expected FIELD_TOO_SHORT for short secret, got %v", err)
+	}
+	hook, err := user.RegisterWebhook(ctx, &types.RegisterWebhookRequest{Config: &types.WebhookConfig{URL: receiver.URL, Events: []string{EventBatchCompleted}, Secret: secret}})
+	if err != nil || hook.Status != "active" {
+		t.Fatalf("Unexpected webhook %+v: %v", hook, err)
+	}
+
+	batch, err := user.ExecuteBatch(ctx, &types.BatchRequest{
+		BatchID:  "batch-1",
+		Requests: []*types.ExecuteTemplateRequest{{Query: "рецепт"}, {Query: ""}, {Query: "борщ"}},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if batch.BatchMetadata.TotalRequests != 3 || batch.BatchMetadata.SuccessfulRequests != 2 || batch.Responses[1] != nil {
+		t.Errorf("Unexpected batch %+v", batch.BatchMetadata)
+	}
+	if _, err := user.ExecuteBatch(ctx, &types.BatchRequest{BatchID: "batch-1", Requests: []*types.ExecuteTemplateRequest{{Query: "рецепт"}}}); errorCode(err) != "DUPLICATE_RESOURCE" {
+		t.Errorf("Expected DUPLICATE_RESOURCE for repeated batch_id, got %v", err)
+	}
+	if status, err := user.GetBatchStatus(ctx, "batch-1"); err != nil || len(status.Responses) != 3 {
+		t.Errorf("Unexpected batch status %+v: %v", status, err)
+	}
+	if _, err := user.CancelBatch(ctx, "batch-1"); errorCode(err) != "RESOURCE_CONFLICT" {
+		t.Errorf("Expected RESOURCE_CONFLICT for completed batch, got %v", err)
+	}
+
+	select {
+	case r := <-deliveries:
+		body := <-bodies
+		if r.Header.Get(HeaderSignature) != "sha256="+Sign([]byte(secret), body) || r.Header.Get(HeaderEvent) != EventBatchCompleted {
+			t.Errorf("Unexpected delivery headers %v", r.Header)
+		}
+		var event types.WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil || event.Data["batch_id"] != "batch-1" {
+			t.Errorf("Unexpected webhook event %s: %v", body, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not delivered")
+	}
+
+	list, err := user.ListWebhooks(ctx, &types.ListWebhooksRequest{})
+	if err != nil || list.Total != 1 {
+		t.Fatalf("Unexpected webhooks %+v: %v", list, err)
+	}
+	if _, err := user.DeleteWebhook(ctx, hook.WebhookID); err != nil {
+		t.Errorf("DeleteWebhook failed: %v", err)
+	}
+}
+
+func TestFrontendConfigAndAdmin(t *testing.T) {
+	_, ts, admin, _ := newTestServer(t, Config{RequireAuth: true})
+	ctx := context.Background()
+
+	for _, id := range []string{"light", "dark"} {
+		if _, err := admin.Admin().CreateFrontendConfig(ctx, &types.FrontendConfig{ID: id, Name: id, Theme: id, Active: true}); err != nil {
+			t.Fatalf("CreateFrontendConfig failed: %v", err)
+		}
+	}
+	if err := admin.Admin().SetActiveFrontendConfig(ctx, "light"); err != nil {
+		t.Fatalf("SetActiveFrontendConfig failed: %v", err)
+	}
+	configs, err := admin.Admin().ListFrontendConfigs(ctx)
+	if err != nil || len(configs) != 2 || !configs[0].Active || configs[1].Active {
+		t.Errorf("Expected only light to be active: %+v, %v", configs, err)
+	}
+
+	anonymous := client.NewClient(client.Config{BaseURL: ts.URL})
+	fc, err := anonymous.GetFrontendConfig(ctx)
+	if err != nil || fc.ID != "light" {
+		t.Fatalf("Unexpected frontend config %+v: %v", fc, err)
+	}
+	resp, err := http.Get(ts.URL + "/api/v1/frontend/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/frontend/config", nil)
+	req.Header.Set("If-None-Match", resp.Header.Get("ETag"))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching ETag, got %d", resp.StatusCode)
+	}
+
+	if err := admin.Admin().InitializeDefaultDomains(ctx); err != nil {
+		t.Fatalf("InitializeDefaultDomains failed: %v", err)
+	}
+	domains, err := admin.Admin().ListDomains(ctx)
+	if err != nil || len(domains) != len(defaultDomains) { // recipes уже зарегистрирован
+		t.Errorf("Unexpected domains after initialize-default: %d, %v", len(domains), err)
+	}
+	if err := admin.Admin().DeleteDomain(ctx, "travel"); err != nil {
+		t.Errorf("DeleteDomain failed: %v", err)
+	}
+	if _, err := admin.Admin().GetDomain(ctx, "travel"); errorCode(err) != "RESOURCE_NOT_FOUND" {
+		t.Errorf("Expected RESOURCE_NOT_FOUND after delete, got %v", err)
+	}
+
+	if err := admin.Admin().UpdateAIConfig(ctx, &types.AIConfig{Provider: "local", Model: "llama"}); err != nil {
+		t.Fatalf("UpdateAIConfig failed: %v", err)
+	}
+	if ai, err := admin.Admin().GetAIConfig(ctx); err != nil || ai.Model != "llama" {
+		t.Errorf("Unexpected AI config %+v: %v", ai, err)
+	}
+	if version, err := admin.Admin().GetVersion(ctx); err != nil || version["api_version"] != APIVersion {
+		t.Errorf("Unexpected version %v: %v", version, err)
+	}
+}
+
+func TestSystemEndpoints(t *testing.T) {
+	_, ts, _, user := newTestServer(t, Config{})
+	ctx := context.Background()
+
+	if health, err := user.Health(ctx); err != nil || health.Status != "healthy" {
+		t.Errorf("Unexpected health %+v: %v", health, err)
+	}
+	if ready, err := user.Ready(ctx); err != nil || ready.Status != "ready" {
+		t.Errorf("Unexpected readiness %+v: %v", ready, err)
+	}
+
+	resp, err := http.Post(ts.URL+"/api/v1/templates/status/x", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodGet {
+		t.Errorf("Expected 405 with Allow: GET, got %d %q", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	resp, err = http.Get(ts.URL + "/api/v1/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown endpoint, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory хранилище в памяти процесса. Данные теряются при остановке.
+type Memory struct {
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+// memoryCollection документы коллекции и порядок их создания
+type memoryCollection struct {
+	docs  map[string][]byte
+	order []string
+}
+
+// NewMemory создает пустое хранилище в памяти
+func NewMemory() *Memory {
+	return &Memory{collections: make(map[string]*memoryCollection)}
+}
+
+// Get возвращает копию документа
+func (m *Memory) Get(_ context.Context, collection, id string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c := m.collections[collection]
+	if c == nil {
+		return nil, ErrNotFound
+	}
+	data, ok := c.docs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Put сохраняет копию документа
+func (m *Memory) Put(_ context.Context, collection, id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.collections[collection]
+	if c == nil {
+		c = &memoryCollection{docs: make(map[string][]byte)}
+		m.collections[collection] = c
+	}
+	if _, ok := c.docs[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.docs[id] = append([]byte(nil), data...)
+	return nil
+}
+
+// Delete удаляет документ
+func (m *Memory) Delete(_ context.Context, collection, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.collections[collection]
+	if c == nil {
+		return ErrNotFound
+	}
+	if _, ok := c.docs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(c.docs, id)
+	for i, existing := range c.order {
+		if existing == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// List возвращает копии документов коллекции в порядке создания
+func (m *Memory) List(_ context.Context, collection string) ([][]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c := m.collections[collection]
+	if c == nil {
+		return nil, nil
+	}
+	out := make([][]byte, 0, len(c.order))
+	for _, id := range c.order {
+		out = append(out, append([]byte(nil), c.docs[id]...))
+	}
+	return out, nil
+}
+
+// Ping всегда успешен
+func (m *Memory) Ping(context.Context) error { return nil }
+
+// Close ничего не делает
+func (m *Memory) Close() error { return nil }
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQLTable таблица документов SQL хранилища
+const SQLTable = "nexus_documents"
+
+// SQL хранилище поверх database/sql. Запросы используют плейсхолдеры "?"
+// и рассчитаны на SQLite; драйвер регистрирует приложение (nexus-server
+// подключает modernc.org/sqlite), например:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	store, err := storage.OpenSQL("sqlite", "nexus.db")
+type SQL struct {
+	db *sql.DB
+}
+
+// OpenSQL открывает базу через зарегистрированный драйвер и создает схему
+func OpenSQL(driver, dsn string) (*SQL, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %w", driver, err)
+	}
+	if driver == "sqlite" || driver == "sqlite3" {
+		// SQLite допускает одного писателя: общее соединение исключает SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+	store, err := NewSQL(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// NewSQL создает хранилище поверх открытой базы и создает таблицу
+// документов, если ее нет
+func NewSQL(ctx context.Context, db *sql.DB) (*SQL, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+SQLTable+` (
+		collection TEXT NOT NULL,
+		id TEXT NOT NULL,
+		seq INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (collection, id)
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("create %s table: %w", SQLTable, err)
+	}
+	return &SQL{db: db}, nil
+}
+
+// Get возвращает документ
+func (s *SQL) Get(ctx context.Context, collection, id string) ([]byte, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM `+SQLTable+` WHERE collection = ? AND id = ?`, collection, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", collection, id, err)
+	}
+	return []byte(data), nil
+}
+
+// Put обновляет документ или вставляет новый в конец коллекции
+func (s *SQL) Put(ctx context.Context, collection, id string, data []byte) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %w", collection, id, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE `+SQLTable+` SET data = ? WHERE collection = ? AND id = ?`, string(data), collection, id)
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %w", collection, id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("put %s/%s: %w", collection, id, err)
+	} else if n == 0 {
+		_, err = tx.ExecContext(ctx, `INSERT INTO `+SQLTable+` (collection, id, seq, data)
+			SELECT ?, ?, COALESCE(MAX(seq), 0) + 1, ? FROM `+SQLTable+` WHERE collection = ?`,
+			collection, id, string(data), collection)
+		if err != nil {
+			return fmt.Errorf("put %s/%s: %w", collection, id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Delete удаляет документ
+func (s *SQL) Delete(ctx context.Context, collection, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM `+SQLTable+` WHERE collection = ? AND id = ?`, collection, id)
+	if err != nil {
+		return fmt.Errorf("delete %s/%s: %w", collection, id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete %s/%s: %w", collection, id, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List возвращает документы коллекции в порядке создания
+func (s *SQL) List(ctx context.Context, collection string) ([][]byte, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM `+SQLTable+` WHERE collection = ? ORDER BY seq`, collection)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", collection, err)
+	}
+	defer rows.Close()
+
+	var out [][]byte
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("list %s: %w", collection, err)
+		}
+		out = append(out, []byte(data))
+	}
+	return out, rows.Err()
+}
+
+// Ping проверяет соединение с базой
+func (s *SQL) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close закрывает базу
+func (s *SQL) Close() error {
+	return s.db.Close()
+}
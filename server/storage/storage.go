@@ -0,0 +1,38 @@
+// Package storage определяет хранилище эталонного сервера Nexus.
+//
+// Сервер хранит сущности протокола (пользователей, выполнения, беседы,
+// конфигурации доменов и т.д.) как JSON-документы в именованных
+// коллекциях, поэтому новое хранилище реализует только Store:
+// Memory - для тестов и демо, SQL - поверх database/sql (SQLite).
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound документ не найден
+var ErrNotFound = errors.New("storage: document not found")
+
+// Store хранилище JSON-документов, сгруппированных по коллекциям.
+// Реализации должны быть безопасны для конкурентного использования.
+type Store interface {
+	// Get возвращает документ или ErrNotFound
+	Get(ctx context.Context, collection, id string) ([]byte, error)
+
+	// Put создает или заменяет документ. Замена сохраняет исходный
+	// порядок документа в List.
+	Put(ctx context.Context, collection, id string, data []byte) error
+
+	// Delete удаляет документ или возвращает ErrNotFound
+	Delete(ctx context.Context, collection, id string) error
+
+	// List возвращает документы коллекции в порядке создания
+	List(ctx context.Context, collection string) ([][]byte, error)
+
+	// Ping проверяет доступность хранилища (для /ready)
+	Ping(ctx context.Context) error
+
+	// Close освобождает ресурсы хранилища
+	Close() error
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// testStore проверяет контракт Store; подходит для любой реализации
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := store.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if _, err := store.Get(ctx, "users", "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing document, got %v", err)
+	}
+	if err := store.Delete(ctx, "users", "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting missing document, got %v", err)
+	}
+
+	for _, id := range []string{"c", "a", "b"} {
+		if err := store.Put(ctx, "users", id, []byte(`{"id":"`+id+`"}`)); err != nil {
+			t.Fatalf("Put %s failed: %v", id, err)
+		}
+	}
+	if err := store.Put(ctx, "users", "c", []byte(`{"id":"c","v":2}`)); err != nil {
+		t.Fatalf("Put update failed: %v", err)
+	}
+	if err := store.Put(ctx, "events", "a", []byte(`{}`)); err != nil {
+		t.Fatalf("Put to another collection failed: %v", err)
+	}
+
+	data, err := store.Get(ctx, "users", "c")
+	if err != nil || string(data) != `{"id":"c","v":2}` {
+		t.Errorf("Unexpected document %s: %v", data, err)
+	}
+	data[0] = 'x'
+	if again, _ := store.Get(ctx, "users", "c"); again[0] != '{' {
+		t.Error("Get must return a copy of the document")
+	}
+
+	docs, err := store.List(ctx, "users")
+	if err != nil || len(docs) != 3 {
+		t.Fatalf("Unexpected list %q: %v", docs, err)
+	}
+	// Обновление не меняет порядок создания
+	for i, want := range []string{`{"id":"c","v":2}`, `{"id":"a"}`, `{"id":"b"}`} {
+		if string(docs[i]) != want {
+			t.Errorf("List[%d] = %s, want %s", i, docs[i], want)
+		}
+	}
+
+	if err := store.Delete(ctx, "users", "a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if docs, _ := store.List(ctx, "users"); len(docs) != 2 {
+		t.Errorf("Expected 2 documents after delete, got %d", len(docs))
+	}
+	if docs, _ := store.List(ctx, "missing"); len(docs) != 0 {
+		t.Errorf("Expected empty list for missing collection, got %d", len(docs))
+	}
+
+	// Конкурентные записи в одну коллекцию
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- store.Put(ctx, "concurrent", string(rune('a'+i)), []byte(`{}`))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Concurrent Put failed: %v", err)
+		}
+	}
+	if docs, _ := store.List(ctx, "concurrent"); len(docs) != 20 {
+		t.Errorf("Expected 20 documents after concurrent writes, got %d", len(docs))
+	}
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemory() },
+		"sqlite": func(t *testing.T) Store {
+			store, err := OpenSQL("sqlite", filepath.Join(t.TempDir(), "nexus.db"))
+			if err != nil {
+				t.Fatalf("OpenSQL failed: %v", err)
+			}
+			return store
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			defer store.Close()
+			testStore(t, store)
+		})
+	}
+}
+
+func TestSQLitePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nexus.db")
+	ctx := context.Background()
+
+	store, err := OpenSQL("sqlite", path)
+	if err != nil {
+		t.Fatalf("OpenSQL failed: %v", err)
+	}
+	if err := store.Put(ctx, "users", "a", []byte(`{"id":"a"}`)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	store.Close()
+
+	reopened, err := OpenSQL("sqlite", path)
+	if err != nil {
+		t.Fatalf("OpenSQL failed: %v", err)
+	}
+	defer reopened.Close()
+	if data, err := reopened.Get(ctx, "users", "a"); err != nil || string(data) != `{"id":"a"}` {
+		t.Errorf("Expected document after reopen, got %s: %v", data, err)
+	}
+}
@@ -0,0 +1,461 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/classifier"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/domain"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// Статусы выполнения шаблона (ExecuteTemplateResponse.Status)
+const (
+	ExecutionCompleted = "completed"
+	ExecutionPartial   = "partial"
+	ExecutionFailed    = "failed"
+	ExecutionTimeout   = "timeout"
+)
+
+// sectionTimeout статус секции домена, не ответившего вовремя
+const sectionTimeout = "timeout"
+
+// RankingAlgorithm алгоритм общего ранжирования результатов
+const RankingAlgorithm = "relevance_confidence"
+
+// executionRecord выполнение в хранилище
+type executionRecord struct {
+	UserID   string                         `json:"user_id,omitempty"`
+	Response *types.ExecuteTemplateResponse `json:"response"`
+}
+
+func (s *Server) executeTemplate(r *http.Request) (interface{}, error) {
+	var req types.ExecuteTemplateRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	req.Metadata = middleware.RequestMetadata(r.Context())
+	resp, err := s.execute(r.Context(), requestPrincipal(r), &req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// execute выбирает домены запроса, выполняет их и сохраняет результат
+func (s *Server) execute(ctx context.Context, p *principal, req *types.ExecuteTemplateRequest) (*types.ExecuteTemplateResponse, error) {
+	if req.Query == "" {
+		return nil, &types.ErrorDetail{Code: "MISSING_REQUIRED_FIELD", Type: "VALIDATION_ERROR", Message: "Query cannot be empty", Field: "query"}
+	}
+	start := time.Now()
+
+	domains, err := loadAll[types.DomainConfig](ctx, s.store, colDomains)
+	if err != nil {
+		return nil, err
+	}
+	analysis := classifier.New(filterDomains(domains, req.Filters), s.config.Classifier).Classify(req.Query)
+
+	options := req.Options
+	if options == nil {
+		options = &types.ExecuteOptions{ParallelExecution: true}
+	}
+	timeout := DefaultExecuteTimeout
+	if options.TimeoutMS > 0 {
+		timeout = time.Duration(options.TimeoutMS) * time.Millisecond
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if req.Context == nil && p != nil {
+		req.Context = &types.UserContext{UserID: p.UserID, TenantID: p.TenantID, Roles: p.Roles}
+	}
+	dreq := &domain.Request{
+		ExecutionID: uuid.New().String(),
+		Query:       req.Query,
+		Language:    req.Language,
+		Context:     req.Context,
+		Options:     options,
+		Filters:     req.Filters,
+		Metadata:    req.Metadata,
+	}
+
+	byID := make(map[string]*types.DomainConfig, len(domains))
+	for _, d := range domains {
+		byID[d.ID] = d
+	}
+	sections := make([]types.DomainSection, len(analysis.SelectedDomains))
+	run := func(i int) {
+		sections[i] = s.executeDomain(execCtx, byID[analysis.SelectedDomains[i].DomainID], dreq)
+	}
+	if options.ParallelExecution {
+		var wg sync.WaitGroup
+		for i := range sections {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				run(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range sections {
+			run(i)
+		}
+	}
+
+	resp := &types.ExecuteTemplateResponse{
+		ExecutionID:    dreq.ExecutionID,
+		Status:         executionStatus(sections),
+		QueryType:      queryType(sections),
+		Sections:       sections,
+		Ranking:        rank(sections),
+		DomainAnalysis: analysis,
+		Metadata: &types.ExecutionMetadata{
+			StartedAt:       start.Unix(),
+			CompletedAt:     time.Now().Unix(),
+			TotalTimeMS:     time.Since(start).Milliseconds(),
+			DomainStats:     make(map[string]int32, len(sections)),
+			DomainsExecuted: int32(len(sections)),
+		},
+	}
+	for _, sec := range sections {
+		resp.Metadata.DomainStats[sec.DomainID] = int32(len(sec.Results))
+		resp.Metadata.ResultsCount += int32(len(sec.Results))
+	}
+	if req.Filters != nil && req.Filters.MaxResults > 0 && resp.Ranking != nil && len(resp.Ranking.Items) > int(req.Filters.MaxResults) {
+		resp.Ranking.Items = resp.Ranking.Items[:req.Filters.MaxResults]
+	}
+	resp.ProcessingTimeMS = int32(time.Since(start).Milliseconds())
+
+	record := &executionRecord{Response: resp}
+	if p != nil {
+		record.UserID = p.UserID
+	}
+	if err := s.save(ctx, colExecutions, resp.ExecutionID, record); err != nil {
+		return nil, err
+	}
+
+	event := EventTemplateExecuted
+	if resp.Status == ExecutionFailed || resp.Status == ExecutionTimeout {
+		event = EventErrorOccurred
+	}
+	s.dispatch(record.UserID, event, map[string]interface{}{
+		"execution_id":  resp.ExecutionID,
+		"status":        resp.Status,
+		"query":         req.Query,
+		"results_count": resp.Metadata.ResultsCount,
+	})
+	return resp, nil
+}
+
+// executeDomain выполняет запрос одним доменом. Ошибки домена не прерывают
+// выполнение шаблона и возвращаются в статусе секции.
+func (s *Server) executeDomain(ctx context.Context, config *types.DomainConfig, req *domain.Request) types.DomainSection {
+	start := time.Now()
+	section := types.DomainSection{DomainID: config.ID, Title: config.Name}
+
+	handler := s.domainHandler(config)
+	if handler == nil {
+		section.Status = domain.StatusError
+		section.Error = "Domain has no handler or endpoint"
+		return section
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	type outcome struct {
+		section *types.DomainSection
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{err: fmt.Errorf("domain %s handler panic: %v", config.ID, p)}
+			}
+		}()
+		result, err := handler.Execute(ctx, req)
+		done <- outcome{result, err}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-ctx.Done():
+		o.err = ctx.Err()
+	}
+	section.ResponseTimeMS = int32(time.Since(start).Milliseconds())
+
+	var detail *types.ErrorDetail
+	switch {
+	case errors.Is(o.err, context.DeadlineExceeded) || (errors.As(o.err, &detail) && detail.Code == "TIMEOUT"):
+		section.Status = sectionTimeout
+		section.Error = fmt.Sprintf("Domain %s did not respond in time", config.ID)
+		return section
+	case errors.As(o.err, &detail):
+		section.Status = domain.StatusError
+		section.Error = detail.Message
+		return section
+	case o.err != nil:
+		section.Status = domain.StatusError
+		section.Error = "Domain execution failed"
+		return section
+	}
+
+	if o.section != nil {
+		section.Status = o.section.Status
+		section.Error = o.section.Error
+		section.Results = o.section.Results
+		if o.section.Title != "" {
+			section.Title = o.section.Title
+		}
+	}
+	if section.Status == "" {
+		section.Status = domain.StatusSuccess
+	}
+	section.Results = limitResults(section.Results, req.Options, req.Filters)
+	for i := range section.Results {
+		for j := range section.Results[i].Actions {
+			action := &section.Results[i].Actions[j]
+			if action.URL == "" {
+				action.URL = actionPath(config.ID, section.Results[i].ID)
+				if action.Method == "" {
+					action.Method = http.MethodPost
+				}
+			}
+		}
+	}
+	return section
+}
+
+// filterDomains применяет AdvancedFilters.Domains и ExcludeDomains
+func filterDomains(domains []*types.DomainConfig, filters *types.AdvancedFilters) []*types.DomainConfig {
+	if filters == nil || (len(filters.Domains) == 0 && len(filters.ExcludeDomains) == 0) {
+		return domains
+	}
+	include := make(map[string]bool, len(filters.Domains))
+	for _, id := range filters.Domains {
+		include[id] = true
+	}
+	exclude := make(map[string]bool, len(filters.ExcludeDomains))
+	for _, id := range filters.ExcludeDomains {
+		exclude[id] = true
+	}
+	var out []*types.DomainConfig
+	for _, d := range domains {
+		if (len(include) == 0 || include[d.ID] || include[d.Type]) && !exclude[d.ID] && !exclude[d.Type] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// limitResults применяет min_relevance и max_results_per_domain
+func limitResults(results []types.ResultItem, options *types.ExecuteOptions, filters *types.AdvancedFilters) []types.ResultItem {
+	if filters != nil && filters.MinRelevance > 0 {
+		kept := results[:0]
+		for _, item := range results {
+			if item.Relevance >= filters.MinRelevance {
+				kept = append(kept, item)
+			}
+		}
+		results = kept
+	}
+	if options != nil && options.MaxResultsPerDomain > 0 && len(results) > int(options.MaxResultsPerDomain) {
+		results = results[:options.MaxResultsPerDomain]
+	}
+	return results
+}
+
+// executionStatus сводит статусы секций в статус выполнения
+func executionStatus(sections []types.DomainSection) string {
+	var ok, timeouts int
+	for _, sec := range sections {
+		switch sec.Status {
+		case domain.StatusSuccess:
+			ok++
+		case sectionTimeout:
+			timeouts++
+		}
+	}
+	switch {
+	case ok == len(sections):
+		return ExecutionCompleted
+	case timeouts == len(sections):
+		return ExecutionTimeout
+	case ok == 0 && !anyPartial(sections):
+		return ExecutionFailed
+	}
+	return ExecutionPartial
+}
+
+func anyPartial(sections []types.DomainSection) bool {
+	for _, sec := range sections {
+		if sec.Status == domain.StatusPartial {
+			return true
+		}
+	}
+	return false
+}
+
+// queryType определяет тип запроса по наличию действий в результатах
+func queryType(sections []types.DomainSection) string {
+	var withActions, without int
+	for _, sec := range sections {
+		for _, item := range sec.Results {
+			if len(item.Actions) > 0 {
+				withActions++
+			} else {
+				without++
+			}
+		}
+	}
+	switch {
+	case withActions == 0:
+		return "information_only"
+	case without == 0:
+		return "with_purchases_services"
+	}
+	return "mixed"
+}
+
+// rank строит общий рейтинг результатов по relevance * confidence
+func rank(sections []types.DomainSection) *types.RankingResult {
+	var items []types.RankedItem
+	for _, sec := range sections {
+		for _, item := range sec.Results {
+			items = append(items, types.RankedItem{ID: item.ID, Score: item.Relevance * item.Confidence})
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Score > items[j].Score })
+	for i := range items {
+		items[i].Rank = int32(i + 1)
+	}
+	return &types.RankingResult{Items: items, Algorithm: RankingAlgorithm}
+}
+
+// loadExecution возвращает выполнение, доступное пользователю запроса
+func (s *Server) loadExecution(r *http.Request) (*types.ExecuteTemplateResponse, error) {
+	var record executionRecord
+	if err := s.load(r.Context(), colExecutions, param(r, "id"), &record); err != nil {
+		return nil, notFound(err, "EXECUTION_NOT_FOUND", "Execution not found")
+	}
+	if record.UserID != "" && record.UserID != userID(r) {
+		return nil, &types.ErrorDetail{Code: "EXECUTION_NOT_FOUND", Type: "NOT_FOUND", Message: "Execution not found"}
+	}
+	return record.Response, nil
+}
+
+func (s *Server) getExecution(r *http.Request) (interface{}, error) {
+	return s.loadExecution(r)
+}
+
+func (s *Server) streamExecution(r *http.Request) (interface{}, error) {
+	resp, err := s.loadExecution(r)
+	if err != nil {
+		return nil, err
+	}
+	return eventStream{resp}, nil
+}
+
+// eventStream отдает выполнение как Server-Sent Events: событие
+// domain_result на каждую секцию и execution_complete в конце
+type eventStream struct {
+	resp *types.ExecuteTemplateResponse
+}
+
+func (e eventStream) respond(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	write := func(event string, data interface{}) {
+		payload, _ := json.Marshal(data)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	for _, sec := range e.resp.Sections {
+		write("domain_result", sec)
+	}
+	write("execution_complete", map[string]interface{}{
+		"execution_id":       e.resp.ExecutionID,
+		"status":             e.resp.Status,
+		"processing_time_ms": e.resp.ProcessingTimeMS,
+	})
+}
+
+// actionPath путь действия над результатом домена на этом сервере
+func actionPath(domainID, resultID string) string {
+	return fmt.Sprintf("%s/domains/%s/actions/%s", apiPrefix, url.PathEscape(domainID), url.PathEscape(resultID))
+}
+
+// executeAction передает действие над результатом обработчику домена
+func (s *Server) executeAction(r *http.Request) (interface{}, error) {
+	var config types.DomainConfig
+	if err := s.load(r.Context(), colDomains, param(r, "domain"), &config); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "Domain not found")
+	}
+	actions, ok := s.domainHandler(&config).(domain.ActionHandler)
+	if !ok {
+		return nil, &types.ErrorDetail{Code: "ENDPOINT_NOT_FOUND", Type: "NOT_FOUND", Message: fmt.Sprintf("Domain %s does not support actions", config.ID)}
+	}
+
+	req := &domain.ActionRequest{ResultID: param(r, "result"), Metadata: middleware.RequestMetadata(r.Context())}
+	if r.Method == http.MethodGet || r.Method == http.MethodDelete {
+		req.ActionType = r.URL.Query().Get("action_type")
+		req.Params = make(map[string]interface{})
+		for key, values := range r.URL.Query() {
+			if key != "action_type" && len(values) > 0 {
+				req.Params[key] = values[0]
+			}
+		}
+	} else {
+		var body types.ExecuteActionRequest
+		if err := middleware.Decode(r, &body); err != nil {
+			return nil, err
+		}
+		req.ActionType, req.Params = body.ActionType, body.Params
+	}
+	if req.ActionType == "" {
+		return nil, required("action_type")
+	}
+	if p := requestPrincipal(r); p != nil {
+		req.Context = &types.UserContext{UserID: p.UserID, TenantID: p.TenantID, Roles: p.Roles}
+	}
+
+	ctx := r.Context()
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+	resp, err := actions.ExecuteAction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		resp = &types.ExecuteActionResponse{}
+	}
+	if resp.ActionType == "" {
+		resp.ActionType = req.ActionType
+	}
+	return resp, nil
+}
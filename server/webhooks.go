@@ -0,0 +1,313 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/middleware"
+	"github.com/pro-deploy/nexus-protocol/sdk/go/types"
+)
+
+// События webhooks (api/rest/openapi.yaml)
+const (
+	EventTemplateExecuted = "template_executed"
+	EventBatchCompleted   = "batch_completed"
+	EventErrorOccurred    = "error_occurred"
+)
+
+// Заголовки доставки webhook. Подпись - HMAC-SHA256 тела запроса
+// секретом webhook в hex: "sha256=<hex>".
+const (
+	HeaderSignature = "X-Nexus-Signature"
+	HeaderEvent     = "X-Nexus-Event"
+)
+
+// eventAliases имена событий из примеров SDK, которые принимаются
+// при регистрации наравне с именами протокола
+var eventAliases = map[string]string{
+	"template.completed": EventTemplateExecuted,
+	"template.failed":    EventErrorOccurred,
+	"batch.completed":    EventBatchCompleted,
+}
+
+// Параметры webhooks
+const (
+	minWebhookSecret     = 16
+	defaultWebhooksLimit = 50
+	maxWebhooksLimit     = 100
+	webhookTimeout       = 10 * time.Second
+	defaultWebhookDelay  = 500 * time.Millisecond
+)
+
+// webhookRecord webhook в хранилище
+type webhookRecord struct {
+	types.WebhookInfo
+	OwnerID string `json:"owner_id,omitempty"`
+}
+
+// subscribed сообщает, подписан ли webhook на событие
+func (w *webhookRecord) subscribed(event string) bool {
+	for _, e := range w.Config.Events {
+		if e == event || eventAliases[e] == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) registerWebhook(r *http.Request) (interface{}, error) {
+	var req types.RegisterWebhookRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	config := req.Config
+	if config == nil {
+		return nil, required("config")
+	}
+	if err := validateWebhook(config); err != nil {
+		return nil, err
+	}
+	config.Active = true
+
+	now := time.Now().Unix()
+	hook := &webhookRecord{
+		WebhookInfo: types.WebhookInfo{ID: uuid.New().String(), Config: config, CreatedAt: now, UpdatedAt: now},
+		OwnerID:     userID(r),
+	}
+	if err := s.save(r.Context(), colWebhooks, hook.ID, hook); err != nil {
+		return nil, err
+	}
+	return &types.RegisterWebhookResponse{WebhookID: hook.ID, Status: "active", Message: "Webhook registered successfully"}, nil
+}
+
+func validateWebhook(config *types.WebhookConfig) error {
+	if config.URL == "" {
+		return required("config.url")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("config.url", "Webhook URL must be an absolute http(s) URL")
+	}
+	if len(config.Events) == 0 {
+		return required("config.events")
+	}
+	for _, e := range config.Events {
+		if e != EventTemplateExecuted && e != EventBatchCompleted && e != EventErrorOccurred && eventAliases[e] == "" {
+			return invalid("config.events", fmt.Sprintf("Unknown webhook event %q", e))
+		}
+	}
+	if config.Secret != "" && len(config.Secret) < minWebhookSecret {
+		return &types.ErrorDetail{Code: "FIELD_TOO_SHORT", Type: "VALIDATION_ERROR", Message: fmt.Sprintf("Webhook secret must be at least %d characters", minWebhookSecret), Field: "config.secret"}
+	}
+	return nil
+}
+
+// loadWebhook возвращает webhook пользователя запроса
+func (s *Server) loadWebhook(r *http.Request) (*webhookRecord, error) {
+	var hook webhookRecord
+	if err := s.load(r.Context(), colWebhooks, param(r, "id"), &hook); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "Webhook not found")
+	}
+	if hook.OwnerID != userID(r) {
+		return nil, &types.ErrorDetail{Code: "RESOURCE_NOT_FOUND", Type: "NOT_FOUND", Message: "Webhook not found"}
+	}
+	return &hook, nil
+}
+
+func (s *Server) listWebhooks(r *http.Request) (interface{}, error) {
+	limit, err := queryInt(r, "limit", defaultWebhooksLimit, maxWebhooksLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := queryInt(r, "offset", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	activeOnly := r.URL.Query().Get("active_only") == "true"
+
+	hooks, err := loadAll[webhookRecord](r.Context(), s.store, colWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]types.WebhookInfo, 0, len(hooks))
+	for _, hook := range hooks {
+		if hook.OwnerID != userID(r) || (activeOnly && !hook.Config.Active) {
+			continue
+		}
+		infos = append(infos, hook.WebhookInfo)
+	}
+	return &types.ListWebhooksResponse{
+		Webhooks: page(infos, offset, limit),
+		Total:    int32(len(infos)),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}, nil
+}
+
+func (s *Server) deleteWebhook(r *http.Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hook, err := s.loadWebhook(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Delete(r.Context(), colWebhooks, hook.ID); err != nil {
+		return nil, notFound(err, "RESOURCE_NOT_FOUND", "Webhook not found")
+	}
+	return &types.DeleteWebhookResponse{WebhookID: hook.ID, Status: "deleted", Message: "Webhook deleted successfully"}, nil
+}
+
+// testWebhook синхронно доставляет тестовое событие без повторов
+func (s *Server) testWebhook(r *http.Request) (interface{}, error) {
+	var req types.TestWebhookRequest
+	if err := middleware.Decode(r, &req); err != nil {
+		return nil, err
+	}
+	hook, err := s.loadWebhook(r)
+	if err != nil {
+		return nil, err
+	}
+	event := req.Event
+	if event == "" {
+		event = EventTemplateExecuted
+	}
+	data := req.Data
+	if data == nil {
+		data = map[string]interface{}{"test": true}
+	}
+
+	start := time.Now()
+	code, err := s.deliver(r.Context(), hook, newWebhookEvent(event, data))
+	s.recordDelivery(hook.ID, err == nil)
+	resp := &types.TestWebhookResponse{
+		WebhookID:      hook.ID,
+		Status:         "success",
+		ResponseCode:   int32(code),
+		ResponseTimeMS: int32(time.Since(start).Milliseconds()),
+	}
+	if err != nil {
+		resp.Status, resp.Error = "failed", err.Error()
+	}
+	return resp, nil
+}
+
+func newWebhookEvent(event string, data map[string]interface{}) *types.WebhookEvent {
+	return &types.WebhookEvent{ID: uuid.New().String(), Event: event, Timestamp: time.Now().Unix(), Data: data}
+}
+
+// dispatch доставляет событие активным webhooks пользователя в фоне.
+// Повторы выполняются по RetryPolicy webhook с экспоненциальной задержкой.
+func (s *Server) dispatch(ownerID, event string, data map[string]interface{}) {
+	hooks, err := loadAll[webhookRecord](context.Background(), s.store, colWebhooks)
+	if err != nil {
+		return
+	}
+	for _, hook := range hooks {
+		if hook.OwnerID != ownerID || !hook.Config.Active || !hook.subscribed(event) {
+			continue
+		}
+		hook := hook
+		payload := newWebhookEvent(event, data)
+		s.background.Add(1)
+		go func() {
+			defer s.background.Done()
+			s.recordDelivery(hook.ID, s.deliverWithRetry(hook, payload) == nil)
+		}()
+	}
+}
+
+func (s *Server) deliverWithRetry(hook *webhookRecord, event *types.WebhookEvent) error {
+	policy := hook.Config.RetryPolicy
+	if policy == nil {
+		policy = &types.WebhookRetryPolicy{}
+	}
+	delay := defaultWebhookDelay
+	if policy.InitialDelay > 0 {
+		delay = time.Duration(policy.InitialDelay) * time.Millisecond
+	}
+	backoff := float64(policy.BackoffFactor)
+	if backoff < 1 {
+		backoff = 2
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var code int
+		code, err = s.deliver(context.Background(), hook, event)
+		if err == nil || attempt >= int(policy.MaxRetries) || (code >= 400 && code < 500) {
+			return err
+		}
+		time.Sleep(delay)
+		delay = time.Duration(float64(delay) * backoff)
+		if policy.MaxDelay > 0 && delay > time.Duration(policy.MaxDelay)*time.Millisecond {
+			delay = time.Duration(policy.MaxDelay) * time.Millisecond
+		}
+	}
+}
+
+// deliver отправляет событие и возвращает HTTP код ответа
+func (s *Server) deliver(ctx context.Context, hook *webhookRecord, event *types.WebhookEvent) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range hook.Config.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Event)
+	if hook.Config.Secret != "" {
+		req.Header.Set(HeaderSignature, "sha256="+Sign([]byte(hook.Config.Secret), body))
+	}
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordDelivery обновляет счетчики доставок webhook
+func (s *Server) recordDelivery(id string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx := context.Background()
+	var hook webhookRecord
+	if s.load(ctx, colWebhooks, id, &hook) != nil {
+		return
+	}
+	hook.LastUsedAt = time.Now().Unix()
+	if ok {
+		hook.SuccessCount++
+	} else {
+		hook.ErrorCount++
+	}
+	s.save(ctx, colWebhooks, id, &hook)
+}
+
+// Sign возвращает HMAC-SHA256 тела webhook в hex. Получатель сравнивает
+// его с заголовком X-Nexus-Signature без префикса "sha256=".
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}